@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/connect-up/auth-service/internal/piiscrub"
+)
+
+func TestAnalyticsEventHashesEmail(t *testing.T) {
+	publisher, flush := newTestPublisher(t)
+	h := &ShowcaseHandler{
+		publisher:   publisher,
+		eventsTopic: "analytics-events",
+		scrubber:    piiscrub.NewScrubber(piiscrub.ModeHash, "salt", piiscrub.DefaultKeys, nil),
+	}
+
+	h.publishAnalyticsEvent("alice", "company_contacted", map[string]interface{}{
+		"company_id": "c1",
+		"email":      "Alice@Example.com",
+		"contact":    map[string]interface{}{"phone": "+49 30 1234"},
+	})
+
+	messages := flush()
+	if len(messages) != 1 {
+		t.Fatalf("published %d messages, want 1", len(messages))
+	}
+	if strings.Contains(string(messages[0].Value), "Example.com") || strings.Contains(string(messages[0].Value), "1234") {
+		t.Fatalf("published payload leaks personal data: %s", messages[0].Value)
+	}
+
+	var event struct {
+		UserID    string                 `json:"user_id"`
+		EventData map[string]interface{} `json:"event_data"`
+	}
+	if err := json.Unmarshal(messages[0].Value, &event); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if event.UserID != "alice" || event.EventData["company_id"] != "c1" {
+		t.Errorf("event = %+v, want alice's event with company_id untouched", event)
+	}
+
+	// Hashes are stable and ignore case, so the same address still correlates
+	want := piiscrub.NewScrubber(piiscrub.ModeHash, "salt", piiscrub.DefaultKeys, nil).
+		Scrub(map[string]interface{}{"email": "alice@example.com"})["email"]
+	if email := event.EventData["email"]; email != want || !strings.HasPrefix(email.(string), "sha256:") {
+		t.Errorf("email = %v, want hash %v", email, want)
+	}
+	if phone := event.EventData["contact"].(map[string]interface{})["phone"]; !strings.HasPrefix(phone.(string), "sha256:") {
+		t.Errorf("nested phone = %v, want a hash", phone)
+	}
+}
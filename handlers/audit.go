@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// AuditHandler serves the company and investment audit log
+type AuditHandler struct{}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{}
+}
+
+// GetAuditLog lists audit entries for an entity (admin only)
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	entityType := c.Query("entity_type")
+	entityID := c.Query("entity_id")
+
+	if entityType != models.AuditEntityCompany && entityType != models.AuditEntityInvestment {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_type must be company or investment"})
+		return
+	}
+	if entityID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "entity_id is required"})
+		return
+	}
+
+	limit, offset := utils.ParsePagination(c, 50, 0)
+
+	entries, err := models.GetAuditLog(entityType, entityID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"time"
 
@@ -15,12 +17,19 @@ import (
 
 // AuthHandler handles authentication requests
 type AuthHandler struct {
-	db *sql.DB
+	db        *sql.DB
+	publisher *utils.AsyncPublisher
+	userTopic string
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(db *sql.DB) *AuthHandler {
-	return &AuthHandler{db: db}
+// NewAuthHandler creates a new auth handler. New accounts are published to
+// the user-updated topic so the matchmaker creates their profile.
+func NewAuthHandler(db *sql.DB, publisher *utils.AsyncPublisher) *AuthHandler {
+	return &AuthHandler{
+		db:        db,
+		publisher: publisher,
+		userTopic: utils.GetEnv("KAFKA_USER_UPDATED_TOPIC", "user-updated"),
+	}
 }
 
 // Register handles user registration
@@ -31,14 +40,6 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Check if user already exists
-	var existingUser models.User
-	err := h.db.QueryRow("SELECT id FROM users WHERE email = $1", req.Email).Scan(&existingUser.ID)
-	if err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
-		return
-	}
-
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -50,34 +51,25 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	userID := uuid.New().String()
 	now := time.Now()
 	
+	// The unique index on email decides concurrent registrations for the same address
 	_, err = h.db.Exec(`
-		INSERT INTO users (id, email, password, first_name, last_name, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, userID, req.Email, hashedPassword, req.FirstName, req.LastName, now, now)
+		INSERT INTO users (id, email, password, first_name, last_name, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, userID, req.Email, hashedPassword, req.FirstName, req.LastName, models.RoleUser, now, now)
 	
 	if err != nil {
+		if models.IsUniqueViolation(err) {
+			c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
-	// Generate tokens
-	accessToken, err := utils.GenerateAccessToken(userID, req.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
-		return
-	}
-
-	refreshToken, err := utils.GenerateRefreshToken(userID, req.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
-		return
-	}
-
-	// Store refresh token in Redis
-	ctx := context.Background()
-	err = utils.StoreRefreshToken(ctx, userID, refreshToken, 7*24*time.Hour)
+	// Start a session and issue tokens
+	accessToken, refreshToken, sessionID, err := h.startSession(c, userID, req.Email, models.RoleUser)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store refresh token"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
 
@@ -87,10 +79,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Email:     req.Email,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		Role:      models.RoleUser,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
+	// Let the matchmaker create the user's profile
+	h.publishAccountUpdated(c.Request.Context(), user)
+
 	response := models.AuthResponse{
 		User:         user,
 		AccessToken:  accessToken,
@@ -99,7 +95,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		ExpiresIn:    900, // 15 minutes in seconds
 	}
 
-	c.JSON(http.StatusCreated, response)
+	respondWithTokens(c, http.StatusCreated, response, sessionID, req.UseCookies)
 }
 
 // Login handles user login
@@ -113,9 +109,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// Get user from database
 	var user models.User
 	err := h.db.QueryRow(`
-		SELECT id, email, password, first_name, last_name, created_at, updated_at
+		SELECT id, email, password, first_name, last_name, role, created_at, updated_at
 		FROM users WHERE email = $1
-	`, req.Email).Scan(&user.ID, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	`, req.Email).Scan(&user.ID, &user.Email, &user.Password, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 	
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
@@ -128,24 +124,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.Email)
+	// Start a session and issue tokens
+	accessToken, refreshToken, sessionID, err := h.startSession(c, user.ID, user.Email, user.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
-		return
-	}
-
-	refreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
-		return
-	}
-
-	// Store refresh token in Redis
-	ctx := context.Background()
-	err = utils.StoreRefreshToken(ctx, user.ID, refreshToken, 7*24*time.Hour)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store refresh token"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
 		return
 	}
 
@@ -157,7 +139,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		ExpiresIn:    900, // 15 minutes in seconds
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondWithTokens(c, http.StatusOK, response, sessionID, req.UseCookies)
 }
 
 // Logout handles user logout
@@ -169,12 +151,17 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	// Delete refresh token from Redis
-	ctx := context.Background()
-	err := utils.DeleteRefreshToken(ctx, userID.(string))
-	if err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("Failed to delete refresh token: %v\n", err)
+	// Revoke the current session and delete its refresh token from Redis
+	sessionID := c.GetString("session_id")
+	if sessionID != "" {
+		if err := h.revokeSession(userID.(string), sessionID); err != nil && err != sql.ErrNoRows {
+			// Log error but don't fail the request
+			log.Printf("Failed to revoke session: %v", err)
+		}
+	}
+
+	if utils.AuthCookies().Enabled {
+		utils.ClearAuthCookies(c)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
@@ -182,12 +169,23 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 // RefreshToken handles token refresh
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	// Cookie-auth clients may send no body at all
 	var req models.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	fromCookie := false
+	if req.RefreshToken == "" && utils.AuthCookies().Enabled {
+		req.RefreshToken, _ = c.Cookie(utils.RefreshTokenCookie)
+		fromCookie = req.RefreshToken != ""
+	}
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
 	// Validate refresh token
 	claims, err := utils.ValidateToken(req.RefreshToken)
 	if err != nil {
@@ -195,9 +193,20 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Check if refresh token exists in Redis
+	if fromCookie && !utils.CSRFSafe(c, claims.SessionID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid CSRF token"})
+		return
+	}
+
+	// Check the session is still active and the refresh token is its current one
+	active, err := models.IsSessionActive(claims.UserID, claims.SessionID)
+	if err != nil || !active {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
 	ctx := context.Background()
-	storedToken, err := utils.GetRefreshToken(ctx, claims.UserID)
+	storedToken, err := utils.GetRefreshToken(ctx, claims.UserID, claims.SessionID)
 	if err != nil || storedToken != req.RefreshToken {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
 		return
@@ -206,35 +215,40 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// Get user from database
 	var user models.User
 	err = h.db.QueryRow(`
-		SELECT id, email, first_name, last_name, created_at, updated_at
+		SELECT id, email, first_name, last_name, role, created_at, updated_at
 		FROM users WHERE id = $1
-	`, claims.UserID).Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	`, claims.UserID).Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 	
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
 		return
 	}
 
-	// Generate new tokens
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.Email)
+	// Generate new tokens within the same session
+	accessToken, err := utils.GenerateAccessToken(user.ID, user.Email, user.Role, claims.SessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
 		return
 	}
 
-	refreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email)
+	refreshToken, err := utils.GenerateRefreshToken(user.ID, user.Email, claims.SessionID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate refresh token"})
 		return
 	}
 
-	// Store new refresh token in Redis
-	err = utils.StoreRefreshToken(ctx, user.ID, refreshToken, 7*24*time.Hour)
+	// Store new refresh token in Redis and rotate it on the session
+	err = utils.StoreRefreshToken(ctx, user.ID, claims.SessionID, refreshToken, utils.RefreshTokenTTL)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store refresh token"})
 		return
 	}
 
+	if err := models.RotateSession(claims.SessionID, utils.HashToken(refreshToken), time.Now().Add(utils.RefreshTokenTTL)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+		return
+	}
+
 	response := models.AuthResponse{
 		User:         user,
 		AccessToken:  accessToken,
@@ -243,7 +257,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		ExpiresIn:    900, // 15 minutes in seconds
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondWithTokens(c, http.StatusOK, response, claims.SessionID, fromCookie)
 }
 
 // GetProfile returns the current user's profile
@@ -258,9 +272,9 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	// Get user from database
 	var user models.User
 	err := h.db.QueryRow(`
-		SELECT id, email, first_name, last_name, created_at, updated_at
+		SELECT id, email, first_name, last_name, role, created_at, updated_at
 		FROM users WHERE id = $1
-	`, userID).Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	`, userID).Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 	
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -272,4 +286,97 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response)
-} 
\ No newline at end of file
+}
+
+// ListSessions returns the current user's active sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessions, err := models.GetActiveSessions(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sessions"})
+		return
+	}
+
+	currentSessionID := c.GetString("session_id")
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession revokes one of the current user's sessions
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessionID := c.Param("id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	if err := h.revokeSession(userID.(string), sessionID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
+
+// startSession records a new session for the request's device and issues its
+// tokens, returning them with the session id
+func (h *AuthHandler) startSession(c *gin.Context, userID, email, role string) (string, string, string, error) {
+	sessionID := uuid.New().String()
+
+	accessToken, err := utils.GenerateAccessToken(userID, email, role, sessionID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken(userID, email, sessionID)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	session := models.Session{
+		ID:        sessionID,
+		UserID:    userID,
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := models.CreateSession(&session, utils.HashToken(refreshToken)); err != nil {
+		return "", "", "", err
+	}
+
+	// Store refresh token in Redis
+	ctx := context.Background()
+	if err := utils.StoreRefreshToken(ctx, userID, sessionID, refreshToken, utils.RefreshTokenTTL); err != nil {
+		return "", "", "", err
+	}
+
+	return accessToken, refreshToken, sessionID, nil
+}
+
+// revokeSession deactivates a session and invalidates its refresh token
+func (h *AuthHandler) revokeSession(userID, sessionID string) error {
+	if err := models.RevokeSession(userID, sessionID); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	return utils.DeleteRefreshToken(ctx, userID, sessionID)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// respondWithTokens sends an auth response. Browser clients that asked for
+// cookie auth get their tokens as HttpOnly cookies instead of in the body,
+// along with the CSRF token they must echo on state-changing requests.
+func respondWithTokens(c *gin.Context, status int, response models.AuthResponse, sessionID string, useCookies bool) {
+	config := utils.AuthCookies()
+	if useCookies && config.Enabled {
+		utils.SetAuthCookies(c, response.AccessToken, response.RefreshToken, sessionID)
+		response.RefreshToken = ""
+		if config.AccessToken {
+			response.AccessToken = ""
+		}
+		response.CSRFToken = utils.CSRFToken(sessionID)
+	}
+
+	c.JSON(status, response)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetJWKS publishes the public keys access tokens can be verified with, so
+// downstream services don't need the signing key. The key set is empty
+// unless JWT_ALGORITHM=RS256.
+func (h *AuthHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": utils.JWKS()})
+}
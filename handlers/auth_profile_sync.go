@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// publishAccountUpdated publishes an account-only user-updated event for a
+// user, from which the matchmaker creates a minimal profile or refreshes the
+// name on the profile it has
+func (h *AuthHandler) publishAccountUpdated(ctx context.Context, user models.User) {
+	if h.publisher == nil {
+		return
+	}
+
+	event := models.UserUpdatedEvent{
+		UserID: user.ID,
+		Profile: models.UserProfile{
+			UserID:    user.ID,
+			Name:      strings.TrimSpace(user.FirstName + " " + user.LastName),
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		Timestamp:   time.Now(),
+		AccountOnly: true,
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal account update for user %s: %v", user.ID, err)
+		return
+	}
+
+	h.publisher.PublishContext(ctx, kafka.Message{
+		Topic: h.userTopic,
+		Key:   []byte(user.ID),
+		Value: data,
+	})
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// registerAndSync registers Ada Lovelace through the auth handler and feeds
+// the user-updated event it publishes to the matchmaker, as the consumer
+// would. It returns the new user's id and the event.
+func registerAndSync(t *testing.T, service *matchmaker.Service) (string, models.UserUpdatedEvent) {
+	t.Helper()
+	if err := utils.InitJWT(); err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+	mock := newTestDB(t)
+	publisher, flush := newTestPublisher(t)
+	h := NewAuthHandler(models.DB, publisher)
+
+	mock.ExpectExec(`INSERT INTO users`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO sessions`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "last_used_at"}).AddRow(time.Now(), time.Now()))
+
+	router := gin.New()
+	router.POST("/register", h.Register)
+	req := httptest.NewRequest(http.MethodPost, "/register",
+		strings.NewReader(`{"email":"ada@example.com","password":"secret123","first_name":"Ada","last_name":"Lovelace"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp models.AuthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	messages := flush()
+	if len(messages) != 1 || string(messages[0].Key) != resp.User.ID {
+		t.Fatalf("published %d messages, want one user-updated event for the new user", len(messages))
+	}
+	var event models.UserUpdatedEvent
+	if err := json.Unmarshal(messages[0].Value, &event); err != nil {
+		t.Fatalf("unmarshal event: %v", err)
+	}
+	if err := service.ProcessUserUpdate(context.Background(), event); err != nil {
+		t.Fatalf("ProcessUserUpdate: %v", err)
+	}
+	return resp.User.ID, event
+}
+
+func TestRegistrationCreatesMatchmakingProfile(t *testing.T) {
+	newTestRedis(t)
+	service := newTestMatchmaker(t)
+	ctx := context.Background()
+	userID, event := registerAndSync(t, service)
+
+	profile, err := service.GetUserProfile(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.Name != "Ada Lovelace" || !profile.IsSearchable() || profile.Visibility != models.ProfileVisibilityPublic {
+		t.Errorf("profile = %+v, want a public, searchable profile named Ada Lovelace", profile)
+	}
+
+	// A later account edit renames the profile without dropping what the user added
+	profile.Skills = []string{"math"}
+	if err := service.StoreUserProfile(ctx, *profile); err != nil {
+		t.Fatalf("StoreUserProfile: %v", err)
+	}
+	event.Profile.Name = "Ada King"
+	event.Timestamp = event.Timestamp.Add(time.Second)
+	if err := service.ProcessUserUpdate(ctx, event); err != nil {
+		t.Fatalf("ProcessUserUpdate: %v", err)
+	}
+	profile, err = service.GetUserProfile(ctx, userID)
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.Name != "Ada King" || len(profile.Skills) != 1 || profile.Skills[0] != "math" {
+		t.Errorf("profile after account edit = %+v, want renamed with skills kept", profile)
+	}
+}
+
+func TestResubmittingProfileAfterRegistrationSkipsRecompute(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	userID, _ := registerAndSync(t, h.matchmakerService)
+
+	// The profile body carries no name, so the stored one comes from the account
+	body := `{"user_id": "` + userID + `", "tags": ["fintech"], "industries": ["finance"], "experience": 8, "location": "Berlin"}`
+	submit := func() map[string]interface{} {
+		t.Helper()
+		rec := serve(t, userID, models.RoleUser, http.MethodPost, "/profiles", "/profiles", strings.NewReader(body), h.CreateUserProfile)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return resp
+	}
+
+	if first := submit(); first["recomputed"] != true {
+		t.Fatalf("first submit = %v, want recomputed", first)
+	}
+	if second := submit(); second["recomputed"] != false {
+		t.Errorf("second submit = %v, want the unchanged profile not recomputed", second)
+	}
+
+	profile, err := h.matchmakerService.GetUserProfile(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("GetUserProfile: %v", err)
+	}
+	if profile.Name != "Ada Lovelace" {
+		t.Errorf("name = %q, want the account name kept", profile.Name)
+	}
+}
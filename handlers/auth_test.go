@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestListSessionsMarksCurrent(t *testing.T) {
+	mock := newTestDB(t)
+	h := &AuthHandler{}
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM sessions`).WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "last_used_at", "expires_at"}).
+			AddRow("s-phone", "alice", "phone", "10.0.0.1", now, now, now.Add(time.Hour)).
+			AddRow("s-laptop", "alice", "laptop", "10.0.0.2", now, now, now.Add(time.Hour)))
+
+	router := gin.New()
+	router.GET("/sessions", asUser("alice", models.RoleUser), func(c *gin.Context) {
+		c.Set("session_id", "s-laptop")
+		h.ListSessions(c)
+	})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sessions", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Sessions []models.Session `json:"sessions"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(resp.Sessions) != 2 || resp.Sessions[0].Current || !resp.Sessions[1].Current {
+		t.Errorf("sessions = %+v, want only s-laptop current", resp.Sessions)
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	const sessionID = "7d7b6f0e-2f7a-4b8e-9a39-6f2b7e9c1d10"
+	server := newTestRedis(t)
+	mock := newTestDB(t)
+	h := &AuthHandler{}
+
+	if err := utils.StoreRefreshToken(context.Background(), "alice", sessionID, "refresh", time.Hour); err != nil {
+		t.Fatalf("StoreRefreshToken: %v", err)
+	}
+
+	// Another user's session matches no row of theirs
+	mock.ExpectExec(`UPDATE sessions SET is_active = false`).WithArgs(sessionID, "mallory").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	rec := serve(t, "mallory", models.RoleUser, http.MethodDelete, "/sessions/:id", "/sessions/"+sessionID, nil, h.RevokeSession)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("other user status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if !server.Exists(utils.RefreshTokenKey("alice", sessionID)) {
+		t.Fatal("another user's revoke deleted the refresh token")
+	}
+
+	mock.ExpectExec(`UPDATE sessions SET is_active = false`).WithArgs(sessionID, "alice").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	rec = serve(t, "alice", models.RoleUser, http.MethodDelete, "/sessions/:id", "/sessions/"+sessionID, nil, h.RevokeSession)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if server.Exists(utils.RefreshTokenKey("alice", sessionID)) {
+		t.Error("refresh token survived revoking its session")
+	}
+
+	rec = serve(t, "alice", models.RoleUser, http.MethodDelete, "/sessions/:id", "/sessions/not-a-session", nil, h.RevokeSession)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("malformed id status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestConcurrentRegistrationConflicts(t *testing.T) {
+	if err := utils.InitJWT(); err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+	newTestRedis(t)
+	mock := newTestDB(t)
+	mock.MatchExpectationsInOrder(false)
+	h := NewAuthHandler(models.DB, nil)
+
+	// The unique index admits the first insert and rejects the second
+	mock.ExpectExec(`INSERT INTO users`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO users`).WillReturnError(&pq.Error{Code: "23505", Constraint: "users_email_key"})
+	mock.ExpectQuery(`INSERT INTO sessions`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "last_used_at"}).AddRow(time.Now(), time.Now()))
+
+	router := gin.New()
+	router.POST("/register", h.Register)
+
+	const body = `{"email":"ada@example.com","password":"secret123","first_name":"Ada","last_name":"Lovelace"}`
+	codes := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, code := range codes {
+		switch code {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		}
+	}
+	if created != 1 || conflicts != 1 {
+		t.Errorf("status codes = %v, want one 201 and one 409", codes)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// Reasons ValidateToken gives for rejecting a token
+const (
+	tokenInvalidMissing   = "missing"
+	tokenInvalidMalformed = "malformed"
+	tokenInvalidExpired   = "expired"
+	tokenInvalidRevoked   = "revoked"
+)
+
+// ValidateToken reports whether the bearer access token is still valid and
+// returns its claims, for gateways and frontends that need a cheap check.
+// A token is revoked once its login session is logged out or revoked.
+func (h *AuthHandler) ValidateToken(c *gin.Context) {
+	tokenString, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || tokenString == "" {
+		respondTokenInvalid(c, tokenInvalidMissing)
+		return
+	}
+
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		reason := tokenInvalidMalformed
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			reason = tokenInvalidExpired
+		}
+		respondTokenInvalid(c, reason)
+		return
+	}
+
+	if claims.SessionID != "" {
+		active, err := models.IsSessionActive(claims.UserID, claims.SessionID)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to check token revocation"})
+			return
+		}
+		if !active {
+			respondTokenInvalid(c, tokenInvalidRevoked)
+			return
+		}
+	}
+
+	response := gin.H{
+		"valid":      true,
+		"user_id":    claims.UserID,
+		"email":      claims.Email,
+		"role":       claims.Role,
+		"session_id": claims.SessionID,
+	}
+	if claims.ExpiresAt != nil {
+		response["expires_at"] = claims.ExpiresAt.Time
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// respondTokenInvalid rejects a token validation with the reason it failed
+func respondTokenInvalid(c *gin.Context, reason string) {
+	c.JSON(http.StatusUnauthorized, gin.H{
+		"valid":  false,
+		"reason": reason,
+		"error":  "Invalid token",
+	})
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestValidateTokenEndpoint(t *testing.T) {
+	// Registered before Setenv so it runs after the environment is restored
+	t.Cleanup(func() { utils.InitJWT() })
+	t.Setenv("JWT_SECRET", "test-secret")
+	if err := utils.InitJWT(); err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+	mock := newTestDB(t)
+	h := &AuthHandler{}
+
+	valid, err := utils.GenerateAccessToken("alice", "alice@example.com", models.RoleUser, "s-live")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	revoked, err := utils.GenerateAccessToken("alice", "alice@example.com", models.RoleUser, "s-revoked")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.Claims{
+		UserID: "alice",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "auth-service",
+			IssuedAt:  jwt.NewNumericDate(past.Add(-15 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(past),
+		},
+	}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign expired token: %v", err)
+	}
+
+	activeSession := func(sessionID string, active bool) {
+		mock.ExpectQuery(`SELECT is_active AND expires_at > CURRENT_TIMESTAMP`).WithArgs(sessionID, "alice").
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(active))
+	}
+
+	tests := []struct {
+		name       string
+		header     string
+		session    string
+		active     bool
+		wantStatus int
+		wantReason string
+	}{
+		{"valid", "Bearer " + valid, "s-live", true, http.StatusOK, ""},
+		{"revoked", "Bearer " + revoked, "s-revoked", false, http.StatusUnauthorized, tokenInvalidRevoked},
+		{"expired", "Bearer " + expired, "", false, http.StatusUnauthorized, tokenInvalidExpired},
+		{"malformed", "Bearer not-a-jwt", "", false, http.StatusUnauthorized, tokenInvalidMalformed},
+		{"missing", "", "", false, http.StatusUnauthorized, tokenInvalidMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.session != "" {
+				activeSession(tt.session, tt.active)
+			}
+
+			router := gin.New()
+			router.GET("/auth/validate", h.ValidateToken)
+			req := httptest.NewRequest(http.MethodGet, "/auth/validate", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			var resp struct {
+				Valid     bool      `json:"valid"`
+				Reason    string    `json:"reason"`
+				UserID    string    `json:"user_id"`
+				Role      string    `json:"role"`
+				ExpiresAt time.Time `json:"expires_at"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if resp.Valid != (tt.wantStatus == http.StatusOK) || resp.Reason != tt.wantReason {
+				t.Errorf("valid = %v, reason = %q; want reason %q", resp.Valid, resp.Reason, tt.wantReason)
+			}
+			if resp.Valid && (resp.UserID != "alice" || resp.Role != models.RoleUser || !resp.ExpiresAt.After(time.Now())) {
+				t.Errorf("claims = %+v, want alice's with a future expiry", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// companyBatchRequest lists the companies to fetch, at most 100 per request
+type companyBatchRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// GetCompaniesBatch returns several companies in one request, in the order
+// the ids were given. Ids that are unknown, deleted, or private to another
+// user are listed under "missing".
+func (h *ShowcaseHandler) GetCompaniesBatch(c *gin.Context) {
+	var req companyBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must list between 1 and 100 company ids"})
+		return
+	}
+
+	found, err := h.loadCompanies(c, req.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve companies"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	companies := make([]*models.Company, 0, len(req.IDs))
+	missing := []string{}
+	for _, id := range req.IDs {
+		company, ok := found[id]
+		// Private companies are only visible to their creator
+		if !ok || (!company.IsPublic && company.CreatedBy != userID) {
+			missing = append(missing, id)
+			continue
+		}
+		companies = append(companies, company)
+	}
+
+	h.redactFinancials(c, companies...)
+
+	c.JSON(http.StatusOK, gin.H{
+		"companies": companies,
+		"missing":   missing,
+	})
+}
+
+// loadCompanies looks each id up in the profile cache, loads the misses from
+// the database in one query, and caches what it loaded. Malformed ids are
+// skipped rather than sent to the database.
+func (h *ShowcaseHandler) loadCompanies(c *gin.Context, ids []string) (map[string]*models.Company, error) {
+	found := make(map[string]*models.Company, len(ids))
+
+	var wanted []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		wanted = append(wanted, id)
+	}
+	if len(wanted) == 0 {
+		return found, nil
+	}
+
+	misses := wanted
+	if h.redisClient != nil {
+		keys := make([]string, len(wanted))
+		for i, id := range wanted {
+			keys[i] = utils.CompanyKey(id)
+		}
+
+		// A failed lookup just sends every id to the database
+		if cached, err := h.redisClient.MGet(c.Request.Context(), keys...).Result(); err == nil {
+			misses = nil
+			for i, value := range cached {
+				var company models.Company
+				data, ok := value.(string)
+				if !ok || json.Unmarshal([]byte(data), &company) != nil {
+					misses = append(misses, wanted[i])
+					continue
+				}
+				found[wanted[i]] = &company
+			}
+		}
+	}
+	if len(misses) == 0 {
+		return found, nil
+	}
+
+	loaded, err := models.GetCompaniesByIDs(misses)
+	if err != nil {
+		return nil, err
+	}
+	for _, company := range loaded {
+		h.cacheCompanyProfile(company)
+		found[company.ID] = company
+	}
+
+	return found, nil
+}
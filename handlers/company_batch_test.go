@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestGetCompaniesBatchMixesCachedAndUncached(t *testing.T) {
+	const (
+		cachedID   = "0b9c5c0e-1d6f-4f43-9a8e-3f1e2c7d5a01"
+		uncachedID = "0b9c5c0e-1d6f-4f43-9a8e-3f1e2c7d5a02"
+		unknownID  = "0b9c5c0e-1d6f-4f43-9a8e-3f1e2c7d5a03"
+		privateID  = "0b9c5c0e-1d6f-4f43-9a8e-3f1e2c7d5a04"
+	)
+	server := newTestRedis(t)
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{redisClient: utils.RedisClient, companyTTL: time.Minute}
+
+	cached, _ := json.Marshal(models.Company{ID: cachedID, Name: "Cached Co", CreatedBy: "owner-1", IsPublic: true})
+	server.Set(utils.CompanyKey(cachedID), string(cached))
+
+	// Only the cache misses reach the database, in one query
+	now := time.Now()
+	mock.ExpectQuery(`WHERE id = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]string{uncachedID, unknownID, privateID})).
+		WillReturnRows(companyRow(uncachedID, "Loaded from the database").
+			AddRow(privateID, "Stealth", "", "", 0, "", "", "", 0, 0.0, "", 0.0, 0.0, now, now, "someone-else", false, "{}", "stealth"))
+
+	mock.ExpectQuery(`FROM access_grants`).WillReturnRows(sqlmock.NewRows([]string{"company_id"}))
+
+	body := `{"ids": ["` + uncachedID + `", "` + cachedID + `", "` + unknownID + `", "` + privateID + `", "not-a-uuid"]}`
+	rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/companies/batch", "/companies/batch", strings.NewReader(body), h.GetCompaniesBatch)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Companies []models.Company `json:"companies"`
+		Missing   []string         `json:"missing"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(resp.Companies) != 2 || resp.Companies[0].ID != uncachedID || resp.Companies[1].ID != cachedID {
+		t.Fatalf("companies = %+v, want the uncached then the cached company in request order", resp.Companies)
+	}
+	want := []string{unknownID, privateID, "not-a-uuid"}
+	if strings.Join(resp.Missing, ",") != strings.Join(want, ",") {
+		t.Errorf("missing = %v, want %v", resp.Missing, want)
+	}
+
+	// What was loaded is now cached
+	if _, err := utils.RedisClient.Get(context.Background(), utils.CompanyKey(uncachedID)).Result(); err != nil {
+		t.Errorf("loaded company was not cached: %v", err)
+	}
+}
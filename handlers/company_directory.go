@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// DirectoryCacheMetrics counts public directory requests by cache outcome
+type DirectoryCacheMetrics struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// GetCompanyDirectory serves a page of the public company directory with
+// industry and funding stage facets, cached in Redis for a short time
+func (h *ShowcaseHandler) GetCompanyDirectory(c *gin.Context) {
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	ctx := c.Request.Context()
+	var cacheKey string
+	if version, ok := h.directoryCacheVersion(ctx); ok {
+		cacheKey = utils.CompanyDirectoryPageKey(version, limit, offset)
+		if cached, err := h.redisClient.Get(ctx, cacheKey).Bytes(); err == nil {
+			h.directoryHits.Add(1)
+			c.Data(http.StatusOK, "application/json; charset=utf-8", cached)
+			return
+		}
+	}
+	h.directoryMisses.Add(1)
+
+	directory, err := models.GetCompanyDirectory(limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company directory"})
+		return
+	}
+
+	// Pages are shared by every visitor, so financials are always withheld
+	for _, company := range directory.Companies {
+		company.RedactFinancials()
+	}
+
+	data, err := json.Marshal(directory)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company directory"})
+		return
+	}
+
+	if cacheKey != "" {
+		h.redisClient.Set(ctx, cacheKey, data, h.directoryTTL)
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// GetCompanyFacets returns the industries and funding stages of public
+// companies with their counts, cached alongside the directory pages
+func (h *ShowcaseHandler) GetCompanyFacets(c *gin.Context) {
+	ctx := c.Request.Context()
+	var cacheKey string
+	if version, ok := h.directoryCacheVersion(ctx); ok {
+		cacheKey = utils.CompanyFacetsKey(version)
+		if cached, err := h.redisClient.Get(ctx, cacheKey).Bytes(); err == nil {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", cached)
+			return
+		}
+	}
+
+	facets, err := models.GetCompanyFacets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company facets"})
+		return
+	}
+
+	data, err := json.Marshal(facets)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company facets"})
+		return
+	}
+
+	if cacheKey != "" {
+		h.redisClient.Set(ctx, cacheKey, data, h.directoryTTL)
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// directoryCacheVersion returns the current directory cache version, or
+// false if the cache can't be used. The version key is bumped whenever a
+// company changes and cached entries are keyed by the version they were
+// built from, so a bump invalidates them all at once. Callers read it
+// before the database so a concurrent change can't be cached under the new
+// version.
+func (h *ShowcaseHandler) directoryCacheVersion(ctx context.Context) (string, bool) {
+	if h.redisClient == nil {
+		return "", false
+	}
+
+	version, err := h.redisClient.Get(ctx, utils.CompanyDirectoryVersionKey()).Result()
+	if err != nil && err != redis.Nil {
+		log.Printf("Failed to read company directory cache version: %v", err)
+		return "", false
+	}
+	return version, true
+}
+
+// DirectoryCacheMetrics returns a snapshot of the public directory cache counters
+func (h *ShowcaseHandler) DirectoryCacheMetrics() DirectoryCacheMetrics {
+	return DirectoryCacheMetrics{
+		Hits:   h.directoryHits.Load(),
+		Misses: h.directoryMisses.Load(),
+	}
+}
+
+// invalidateCompanyDirectory drops every cached directory page
+func invalidateCompanyDirectory(redisClient *redis.Client) {
+	if redisClient == nil {
+		return
+	}
+
+	if err := redisClient.Incr(context.Background(), utils.CompanyDirectoryVersionKey()).Err(); err != nil {
+		log.Printf("Failed to invalidate company directory cache: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+var companyColumns = []string{"id", "name", "description", "industry", "founded_year", "headquarters",
+	"website", "logo_url", "employee_count", "revenue", "funding_stage", "total_funding", "valuation",
+	"created_at", "updated_at", "created_by", "is_public", "tags", "slug"}
+
+// companyRow returns a companies row for a public company owned by owner-1
+func companyRow(id, description string) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows(companyColumns).AddRow(id, "Acme", description, "aerospace", 2015, "Berlin",
+		"", "", 40, 0.0, "seed", 0.0, 0.0, now, now, "owner-1", true, "{}", "acme")
+}
+
+// expectDirectory expects one uncached directory page and its facets
+func expectDirectory(mock sqlmock.Sqlmock, description string) {
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM companies WHERE is_public = true`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`ORDER BY created_at DESC, id\s+LIMIT \$1 OFFSET \$2`).WithArgs(20, 0).
+		WillReturnRows(companyRow("c1", description))
+	mock.ExpectQuery(`COALESCE\(industry, ''\), COUNT\(\*\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"industry", "count"}).AddRow("aerospace", 1))
+	mock.ExpectQuery(`COALESCE\(funding_stage, ''\), COUNT\(\*\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"funding_stage", "count"}).AddRow("seed", 1))
+}
+
+func TestCompanyDirectoryInvalidatedByUpdate(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{
+		redisClient:  utils.RedisClient,
+		moderator:    contentfilter.NewModerator(nil, contentfilter.PolicyOff),
+		directoryTTL: time.Minute,
+	}
+	directory := func() string {
+		t.Helper()
+		rec := serve(t, "", "", http.MethodGet, "/directory", "/directory", nil, h.GetCompanyDirectory)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("directory status = %d: %s", rec.Code, rec.Body)
+		}
+		return rec.Body.String()
+	}
+
+	expectDirectory(mock, "Rockets")
+	directory()
+	if body := directory(); !strings.Contains(body, "Rockets") {
+		t.Fatalf("cached directory = %s, want the original description", body)
+	}
+	if metrics := h.DirectoryCacheMetrics(); metrics.Hits != 1 || metrics.Misses != 1 {
+		t.Fatalf("metrics = %+v, want 1 hit and 1 miss", metrics)
+	}
+
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs("c1").WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectExec(`UPDATE companies SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO audit_log`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("audit-1", time.Now()))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`INSERT INTO company_activities`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("activity-1", time.Now()))
+
+	body := `{"name": "Acme", "description": "Reusable rockets", "industry": "aerospace", "founded_year": 2015,
+		"headquarters": "Berlin", "employee_count": 40, "funding_stage": "seed", "is_public": true}`
+	rec := serve(t, "owner-1", models.RoleUser, http.MethodPut, "/companies/:id", "/companies/c1", strings.NewReader(body), h.UpdateCompany)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d: %s", rec.Code, rec.Body)
+	}
+
+	// The update bumped the cache version, so the next page comes from the database
+	expectDirectory(mock, "Reusable rockets")
+	if body := directory(); !strings.Contains(body, "Reusable rockets") {
+		t.Errorf("directory after update = %s, want the new description", body)
+	}
+	if metrics := h.DirectoryCacheMetrics(); metrics.Hits != 1 || metrics.Misses != 2 {
+		t.Errorf("metrics = %+v, want 1 hit and 2 misses", metrics)
+	}
+}
+
+func TestCompanyFacetsCountsAndCache(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{redisClient: utils.RedisClient, directoryTTL: time.Minute}
+
+	// Seeded public companies: two fintech at seed, one health at series_a
+	mock.ExpectQuery(`COALESCE\(industry, ''\), COUNT\(\*\)\s+FROM companies\s+WHERE is_public = true AND deleted_at IS NULL\s+GROUP BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"industry", "count"}).AddRow("fintech", 2).AddRow("health", 1))
+	mock.ExpectQuery(`COALESCE\(funding_stage, ''\), COUNT\(\*\)\s+FROM companies\s+WHERE is_public = true AND deleted_at IS NULL\s+GROUP BY 1`).
+		WillReturnRows(sqlmock.NewRows([]string{"funding_stage", "count"}).AddRow("seed", 2).AddRow("series_a", 1))
+
+	want := models.CompanyFacets{
+		Industries:    []models.CompanyFacet{{Value: "fintech", Count: 2}, {Value: "health", Count: 1}},
+		FundingStages: []models.CompanyFacet{{Value: "seed", Count: 2}, {Value: "series_a", Count: 1}},
+	}
+	// The second request is served from the cache; sqlmock fails any further query
+	for i := 0; i < 2; i++ {
+		rec := serve(t, "", "", http.MethodGet, "/facets", "/facets", nil, h.GetCompanyFacets)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var got models.CompanyFacets
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("request %d facets = %+v, want %+v", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetCompanyFinancialHistory returns a company's revenue, funding and
+// valuation over time for charting (?date_from=&date_to=, inclusive). Only
+// the owner, admins and investors with data room access may see it.
+func (h *ShowcaseHandler) GetCompanyFinancialHistory(c *gin.Context) {
+	company, err := models.GetCompanyByID(c.Param("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	// Private companies are only visible to their creator
+	userID := c.GetString("user_id")
+	isOwner := company.CreatedBy == userID
+	if !company.IsPublic && !isOwner && !utils.IsAdmin(c) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	if !isOwner && !utils.IsAdmin(c) {
+		granted, err := models.GrantedCompanyIDs(userID, []string{company.ID})
+		if err != nil {
+			log.Printf("Failed to check data room access for user %s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve financial history"})
+			return
+		}
+		if !granted[company.ID] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this company's financials"})
+			return
+		}
+	}
+
+	var from, to time.Time
+	if raw := c.Query("date_from"); raw != "" {
+		if from, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date_from must be a date in YYYY-MM-DD format"})
+			return
+		}
+	}
+	if raw := c.Query("date_to"); raw != "" {
+		if to, err = time.Parse("2006-01-02", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date_to must be a date in YYYY-MM-DD format"})
+			return
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date_from must not be after date_to"})
+		return
+	}
+
+	history, err := models.GetCompanyFinancialHistory(company.ID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve financial history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"company_id": company.ID,
+		"history":    history,
+	})
+}
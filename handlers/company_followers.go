@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// followCompanyRequest is the optional body for following a company
+type followCompanyRequest struct {
+	Anonymous bool `json:"anonymous"`
+}
+
+// FollowCompany makes the current user follow a company. Anonymous follows
+// appear in the owner's follower list without the follower's identity.
+func (h *ShowcaseHandler) FollowCompany(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.Param("id")
+
+	var req followCompanyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	company, ok := h.visibleCompany(c, companyID)
+	if !ok {
+		return
+	}
+
+	if err := models.FollowCompany(company.ID, userID, req.Anonymous); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow company"})
+		return
+	}
+
+	h.publishAnalyticsEvent(userID, "company_followed", map[string]interface{}{
+		"company_id": company.ID,
+		"anonymous":  req.Anonymous,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Company followed",
+		"anonymous": req.Anonymous,
+	})
+}
+
+// UnfollowCompany removes the current user's follow of a company
+func (h *ShowcaseHandler) UnfollowCompany(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.Param("id")
+
+	if err := models.UnfollowCompany(companyID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not following this company"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow company"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Company unfollowed"})
+}
+
+// GetCompanyFollowers lists a company's followers. Only the company owner or
+// an admin may see the list.
+func (h *ShowcaseHandler) GetCompanyFollowers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	companyID := c.Param("id")
+
+	company, err := models.GetCompanyByID(companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	if company.CreatedBy != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this company's followers"})
+		return
+	}
+
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	followers, err := models.GetCompanyFollowers(companyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve followers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"followers": followers,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// visibleCompany loads a company the current user is allowed to see, writing
+// a 404 or 500 response and reporting false otherwise
+func (h *ShowcaseHandler) visibleCompany(c *gin.Context, companyID string) (*models.Company, bool) {
+	company, err := models.GetCompanyByID(companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return nil, false
+	}
+
+	// Private companies are only visible to their creator
+	if !company.IsPublic && company.CreatedBy != c.GetString("user_id") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return nil, false
+	}
+	return company, true
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestCompanyFollowersOwnerOnly(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{}
+	followed := time.Now()
+
+	// The owner sees named followers and a redacted anonymous one
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectQuery(`FROM company_followers f`).WithArgs("c1", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "first_name", "last_name", "anonymous", "created_at"}).
+			AddRow("fan-1", "Ada", "Lovelace", false, followed).
+			AddRow("fan-2", "Secret", "Admirer", true, followed))
+
+	rec := serve(t, "owner-1", models.RoleUser, http.MethodGet, "/companies/:id/followers", "/companies/c1/followers", nil, h.GetCompanyFollowers)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner: status = %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Followers []models.CompanyFollower `json:"followers"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(body.Followers) != 2 {
+		t.Fatalf("got %d followers, want 2", len(body.Followers))
+	}
+	if named := body.Followers[0]; named.UserID != "fan-1" || named.Name != "Ada Lovelace" {
+		t.Errorf("named follower = %+v", named)
+	}
+	if anonymous := body.Followers[1]; !anonymous.Anonymous || anonymous.UserID != "" || anonymous.Name != "" {
+		t.Errorf("anonymous follower = %+v, want no identity", anonymous)
+	}
+
+	// Anyone else is turned away before the followers are read
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	rec = serve(t, "fan-1", models.RoleUser, http.MethodGet, "/companies/:id/followers", "/companies/c1/followers", nil, h.GetCompanyFollowers)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("non-owner: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+
+	// Admins may look too
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectQuery(`FROM company_followers f`).WithArgs("c1", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "first_name", "last_name", "anonymous", "created_at"}))
+	rec = serve(t, "admin-1", models.RoleAdmin, http.MethodGet, "/companies/:id/followers", "/companies/c1/followers", nil, h.GetCompanyFollowers)
+	if rec.Code != http.StatusOK {
+		t.Errorf("admin: status = %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// companyImportBatchSize is the number of rows inserted per transaction
+const companyImportBatchSize = 100
+
+// companyImportResult reports the outcome of importing a single CSV row
+type companyImportResult struct {
+	Line      int    `json:"line"`
+	CompanyID string `json:"company_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// pendingCompanyRow is a validated row waiting to be inserted
+type pendingCompanyRow struct {
+	line    int
+	company *models.Company
+}
+
+// ImportCompanies creates companies from an uploaded CSV file (admin only).
+// The first row must be a header naming the company columns; rows are
+// streamed, validated, and inserted in batches.
+func (h *ShowcaseHandler) ImportCompanies(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV file is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header row is required"})
+		return
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header must include a name column"})
+		return
+	}
+
+	var results []companyImportResult
+	var batch []pendingCompanyRow
+	created, failed := 0, 0
+	defer func() {
+		if created > 0 {
+			invalidateCompanyDirectory(h.redisClient)
+		}
+	}()
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		companies := make([]*models.Company, len(batch))
+		for i, row := range batch {
+			companies[i] = row.company
+		}
+
+		rowErrors, err := models.CreateCompanies(companies)
+		if err != nil {
+			return err
+		}
+
+		for i, row := range batch {
+			result := companyImportResult{Line: row.line, Name: row.company.Name}
+			if rowErrors[i] != nil {
+				result.Error = "Failed to create company"
+				failed++
+			} else {
+				result.CompanyID = row.company.ID
+				created++
+				h.recordCompanyActivity(row.company.ID, userID.(string), "company_created", map[string]interface{}{
+					"company_name": row.company.Name,
+					"source":       "csv_import",
+				})
+				h.webhooks.Dispatch(models.WebhookEventCompanyCreated, row.company)
+			}
+			results = append(results, result)
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			parseErr, ok := err.(*csv.ParseError)
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read uploaded file", "results": results})
+				return
+			}
+			results = append(results, companyImportResult{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			failed++
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		company, err := parseCompanyRecord(record, columns)
+		if err == nil {
+			err = models.ValidateCompany(company)
+		}
+		if err == nil {
+			err = h.moderateCompany(company)
+		}
+		if err != nil {
+			results = append(results, companyImportResult{Line: line, Name: company.Name, Error: err.Error()})
+			failed++
+			continue
+		}
+
+		company.CreatedBy = userID.(string)
+		batch = append(batch, pendingCompanyRow{line: line, company: company})
+
+		if len(batch) >= companyImportBatchSize {
+			if err := flush(); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import companies", "results": results})
+				return
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import companies", "results": results})
+		return
+	}
+
+	h.publishAnalyticsEvent(userID.(string), "companies_imported", map[string]interface{}{
+		"created": created,
+		"failed":  failed,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"created": created,
+		"failed":  failed,
+		"results": results,
+	})
+}
+
+// parseCompanyRecord maps a CSV record onto a company using the header column positions
+func parseCompanyRecord(record []string, columns map[string]int) (*models.Company, error) {
+	company := &models.Company{}
+
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	company.Name = field("name")
+	company.Description = field("description")
+	company.Industry = field("industry")
+	company.Headquarters = field("headquarters")
+	company.Website = field("website")
+	company.LogoURL = field("logo_url")
+	company.FundingStage = field("funding_stage")
+
+	var err error
+	if company.FoundedYear, err = parseIntField(field("founded_year"), "founded_year"); err != nil {
+		return company, err
+	}
+	if company.EmployeeCount, err = parseIntField(field("employee_count"), "employee_count"); err != nil {
+		return company, err
+	}
+	if company.Revenue, err = parseFloatField(field("revenue"), "revenue"); err != nil {
+		return company, err
+	}
+	if company.TotalFunding, err = parseFloatField(field("total_funding"), "total_funding"); err != nil {
+		return company, err
+	}
+	if company.Valuation, err = parseFloatField(field("valuation"), "valuation"); err != nil {
+		return company, err
+	}
+
+	// Tags share one cell, separated by semicolons
+	if value := field("tags"); value != "" {
+		company.Tags = strings.Split(value, ";")
+	}
+
+	if value := field("is_public"); value != "" {
+		if company.IsPublic, err = strconv.ParseBool(value); err != nil {
+			return company, fmt.Errorf("is_public must be true or false")
+		}
+	}
+
+	return company, nil
+}
+
+func parseIntField(value, name string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", name)
+	}
+	return n, nil
+}
+
+func parseFloatField(value, name string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return n, nil
+}
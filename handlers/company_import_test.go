@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/models"
+)
+
+// expectCompanyInsert expects one company to be inserted inside an import
+// transaction, with id
+func expectCompanyInsert(mock sqlmock.Sqlmock, id string) {
+	mock.ExpectExec(`SAVEPOINT company_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`pg_advisory_xact_lock`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT slug FROM companies`).WillReturnRows(sqlmock.NewRows([]string{"slug"}))
+	mock.ExpectQuery(`INSERT INTO companies`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(id, time.Now(), time.Now()))
+	mock.ExpectExec(`INSERT INTO company_financials_history`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT company_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestImportCompaniesReportsBadRowAndImportsTheRest(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{moderator: contentfilter.NewModerator(nil, contentfilter.PolicyOff)}
+
+	csvData := "name,industry,founded_year\n" +
+		"Acme,fintech,2015\n" +
+		"Broken,fintech,soon\n" +
+		"Globex,health,2019\n"
+
+	mock.ExpectBegin()
+	expectCompanyInsert(mock, "c-acme")
+	expectCompanyInsert(mock, "c-globex")
+	mock.ExpectCommit()
+	for range 2 {
+		mock.ExpectQuery(`INSERT INTO company_activities`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("a", time.Now()))
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "companies.csv")
+	part.Write([]byte(csvData))
+	form.Close()
+
+	router := gin.New()
+	router.POST("/import", asUser("admin-1", models.RoleAdmin), h.ImportCompanies)
+	req := httptest.NewRequest(http.MethodPost, "/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Created int                   `json:"created"`
+		Failed  int                   `json:"failed"`
+		Results []companyImportResult `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Created != 2 || resp.Failed != 1 {
+		t.Fatalf("created %d, failed %d; want 2 and 1", resp.Created, resp.Failed)
+	}
+
+	var bad *companyImportResult
+	for i := range resp.Results {
+		if resp.Results[i].Error != "" {
+			bad = &resp.Results[i]
+		}
+	}
+	if bad == nil || bad.Line != 3 || bad.Name != "Broken" || bad.Error != "founded_year must be an integer" {
+		t.Errorf("bad row result = %+v, want line 3 Broken with a founded_year error", bad)
+	}
+}
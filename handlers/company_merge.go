@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// MergeCompanies folds a duplicate company (merge_from_id) into the company
+// in the path, moving its investments, followers, data room grants,
+// financial history, intro requests and activity over and soft-deleting it
+// (admin only)
+func (h *ShowcaseHandler) MergeCompanies(c *gin.Context) {
+	userID := c.GetString("user_id")
+	targetID := c.Param("id")
+
+	var req struct {
+		MergeFromID string `json:"merge_from_id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, source, err := models.MergeCompanies(targetID, req.MergeFromID, userID)
+	if err != nil {
+		switch err {
+		case models.ErrMergeSameCompany:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A company cannot be merged into itself"})
+		case sql.ErrNoRows:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge companies"})
+		}
+		return
+	}
+
+	// The source must stop resolving by id or slug
+	h.invalidateCompanyCache(target.ID)
+	h.invalidateCompanyCache(source.ID)
+	if source.Slug != "" && h.redisClient != nil {
+		h.redisClient.Del(context.Background(), utils.CompanySlugKey(source.Slug))
+	}
+
+	h.recordCompanyActivity(target.ID, userID, "company_merged", map[string]interface{}{
+		"merged_from_id": source.ID,
+		"merged_from":    source.Name,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Companies merged",
+		"company":        target,
+		"merged_from_id": source.ID,
+	})
+}
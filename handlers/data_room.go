@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// dataRoomGrantRequest names the investor to give data room access
+type dataRoomGrantRequest struct {
+	InvestorID string `json:"investor_id" binding:"required,uuid"`
+}
+
+// GrantDataRoomAccess lets an investor see a company's financials (owner or admin)
+func (h *ShowcaseHandler) GrantDataRoomAccess(c *gin.Context) {
+	var req dataRoomGrantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	company, ok := h.ownedCompany(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	investorExists, err := models.UserExists(req.InvestorID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant access"})
+		return
+	}
+	if !investorExists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Investor not found"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if err := models.GrantCompanyAccess(company.ID, req.InvestorID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant access"})
+		return
+	}
+
+	h.recordCompanyActivity(company.ID, userID, "data_room_access_granted", map[string]interface{}{
+		"investor_id": req.InvestorID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data room access granted"})
+}
+
+// RevokeDataRoomAccess removes an investor's access to a company's financials (owner or admin)
+func (h *ShowcaseHandler) RevokeDataRoomAccess(c *gin.Context) {
+	company, ok := h.ownedCompany(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	userID := c.GetString("user_id")
+	investorID := c.Param("investor_id")
+	if err := models.RevokeCompanyAccess(company.ID, investorID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Access grant not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke access"})
+		return
+	}
+
+	h.recordCompanyActivity(company.ID, userID, "data_room_access_revoked", map[string]interface{}{
+		"investor_id": investorID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data room access revoked"})
+}
+
+// GetDataRoomAccess lists the investors with access to a company's financials (owner or admin)
+func (h *ShowcaseHandler) GetDataRoomAccess(c *gin.Context) {
+	company, ok := h.ownedCompany(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	grants, err := models.GetCompanyAccessGrants(company.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve access grants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"grants": grants})
+}
+
+// ownedCompany loads a company the current user owns or administers, writing
+// a 403, 404, or 500 response and reporting false otherwise
+func (h *ShowcaseHandler) ownedCompany(c *gin.Context, companyID string) (*models.Company, bool) {
+	company, err := models.GetCompanyByID(companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return nil, false
+	}
+
+	if company.CreatedBy != c.GetString("user_id") && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to manage this company's data room"})
+		return nil, false
+	}
+	return company, true
+}
+
+// redactFinancials withholds financial fields from companies the viewer has
+// no data room access to. Owners and admins see everything; if grants can't
+// be read, financials are withheld rather than exposed.
+func (h *ShowcaseHandler) redactFinancials(c *gin.Context, companies ...*models.Company) {
+	if utils.IsAdmin(c) {
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		for _, company := range companies {
+			company.RedactFinancials()
+		}
+		return
+	}
+
+	var others []string
+	for _, company := range companies {
+		if company.CreatedBy != userID {
+			others = append(others, company.ID)
+		}
+	}
+	if len(others) == 0 {
+		return
+	}
+
+	granted, err := models.GrantedCompanyIDs(userID, others)
+	if err != nil {
+		log.Printf("Failed to check data room access for user %s: %v", userID, err)
+		granted = nil
+	}
+
+	for _, company := range companies {
+		if company.CreatedBy == userID || granted[company.ID] {
+			continue
+		}
+		company.RedactFinancials()
+	}
+}
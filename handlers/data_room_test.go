@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestGetCompanyRedactsFinancialsWithoutDataRoomAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		granted  bool
+		redacted bool
+	}{
+		{"granted investor", "investor-1", true, false},
+		{"investor without access", "investor-2", false, true},
+		{"owner", "owner-1", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newTestDB(t)
+			h := &ShowcaseHandler{}
+			now := time.Now()
+
+			mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+				WillReturnRows(sqlmock.NewRows(companyColumns).AddRow("c1", "Acme", "Rockets", "aerospace", 2015, "Berlin",
+					"", "", 40, 1200000.0, "series_a", 5000000.0, 30000000.0, now, now, "owner-1", true, "{}", "acme"))
+			if tt.caller != "owner-1" {
+				grants := sqlmock.NewRows([]string{"company_id"})
+				if tt.granted {
+					grants.AddRow("c1")
+				}
+				mock.ExpectQuery(`FROM access_grants\s+WHERE investor_id = \$1 AND company_id = ANY\(\$2\)`).
+					WithArgs(tt.caller, `{"c1"}`).WillReturnRows(grants)
+			}
+
+			rec := serve(t, tt.caller, models.RoleUser, http.MethodGet, "/companies/:id", "/companies/c1", nil, h.GetCompany)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var company models.Company
+			if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			// Basics are shown either way
+			if company.Name != "Acme" || company.Industry != "aerospace" {
+				t.Errorf("company basics = %q, %q", company.Name, company.Industry)
+			}
+			if tt.redacted {
+				if !company.FinancialsRedacted || company.Revenue != 0 || company.Valuation != 0 || company.TotalFunding != 0 {
+					t.Errorf("financials shown without access: %+v", company)
+				}
+			} else if company.FinancialsRedacted || company.Revenue != 1200000 || company.Valuation != 30000000 {
+				t.Errorf("financials withheld: revenue %v, valuation %v, redacted %v", company.Revenue, company.Valuation, company.FinancialsRedacted)
+			}
+		})
+	}
+}
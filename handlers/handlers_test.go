@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/internal/schema"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	if err := schema.Load(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestRedis points utils.RedisClient at an in-memory Redis for the
+// duration of the test
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	previous := utils.RedisClient
+	utils.RedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		utils.RedisClient.Close()
+		utils.RedisClient = previous
+	})
+	return server
+}
+
+// newTestDB points models.DB at a mock database for the duration of the
+// test, failing it if any expectation set on the mock goes unmet
+func newTestDB(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	previous := models.DB
+	models.DB = db
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+		models.DB = previous
+	})
+	return mock
+}
+
+// newTestMatchmaker creates a matchmaker service whose Kafka broker is never
+// reached by the code under test
+func newTestMatchmaker(t *testing.T) *matchmaker.Service {
+	t.Helper()
+	service := matchmaker.NewService([]string{"localhost:9092"}, "test-topic")
+	t.Cleanup(func() { service.Close() })
+	return service
+}
+
+// recordingWriter keeps every Kafka message written to it
+type recordingWriter struct {
+	mu       sync.Mutex
+	messages []kafka.Message
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// newTestPublisher creates a publisher whose messages are kept in memory;
+// flush returns everything published so far
+func newTestPublisher(t *testing.T) (publisher *utils.AsyncPublisher, flush func() []kafka.Message) {
+	t.Helper()
+	writer := &recordingWriter{}
+	publisher = utils.NewAsyncPublisher(writer, utils.AsyncPublisherConfig{FlushInterval: time.Hour})
+	t.Cleanup(func() { publisher.Close(context.Background()) })
+	return publisher, func() []kafka.Message {
+		t.Helper()
+		if err := publisher.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+		writer.mu.Lock()
+		defer writer.mu.Unlock()
+		return append([]kafka.Message(nil), writer.messages...)
+	}
+}
+
+// newTestConnection creates a connection without a socket, whose queued
+// messages can be read from its send channel
+func newTestConnection(userID string) *WebSocketConnection {
+	return &WebSocketConnection{
+		userID:         userID,
+		send:           make(chan []byte, 16),
+		overflowPolicy: SendOverflowDropNew,
+	}
+}
+
+// asUser stands in for AuthMiddleware, authenticating every request as
+// userID with role
+func asUser(userID, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("user_role", role)
+		c.Next()
+	}
+}
+
+// serve runs one request through handler as userID with role
+func serve(t *testing.T, userID, role, method, route, target string, body io.Reader, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	router := gin.New()
+	router.Handle(method, route, asUser(userID, role), handler)
+	req := httptest.NewRequest(method, target, body)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
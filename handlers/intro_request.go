@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// UserNotifier sends a real-time notification to a user wherever they are connected
+type UserNotifier func(userID string, message map[string]interface{})
+
+// SetUserNotifier sets how users are notified in real time, e.g. of intro requests
+func (h *ShowcaseHandler) SetUserNotifier(notifier UserNotifier) {
+	h.notifyUser = notifier
+}
+
+// notify sends a notification of type eventType to a user, if a notifier is set
+func (h *ShowcaseHandler) notify(userID, eventType string, intro *models.IntroRequest) {
+	if h.notifyUser == nil {
+		return
+	}
+	h.notifyUser(userID, map[string]interface{}{
+		"type":          eventType,
+		"intro_request": intro,
+		"timestamp":     time.Now().Unix(),
+	})
+}
+
+// introRequestBody is the optional body for requesting an introduction
+type introRequestBody struct {
+	Message string `json:"message" binding:"max=2000"`
+}
+
+// introResponseBody accepts or declines an intro request
+type introResponseBody struct {
+	Status string `json:"status" binding:"required,oneof=accepted declined"`
+}
+
+// CreateIntroRequest asks for an introduction to a company's owner.
+// Repeating it returns the request already made.
+func (h *ShowcaseHandler) CreateIntroRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req introRequestBody
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	message, err := h.moderator.Apply(req.Message)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	company, ok := h.visibleCompany(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if company.CreatedBy == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot request an introduction to your own company"})
+		return
+	}
+
+	intro := models.IntroRequest{
+		CompanyID:  company.ID,
+		InvestorID: userID,
+		Message:    message,
+	}
+	created, err := models.CreateIntroRequest(&intro)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request introduction"})
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, intro)
+		return
+	}
+
+	h.publishAnalyticsEvent(userID, "intro_requested", map[string]interface{}{
+		"company_id":       company.ID,
+		"intro_request_id": intro.ID,
+	})
+	h.notify(intro.OwnerID, "intro_requested", &intro)
+
+	c.JSON(http.StatusCreated, intro)
+}
+
+// RespondToIntroRequest accepts or declines an intro request to a company
+// the current user owns. Accepting lets the investor and owner message each other.
+func (h *ShowcaseHandler) RespondToIntroRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req introResponseBody
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intro, err := models.GetIntroRequest(c.Param("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Intro request not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve intro request"})
+		return
+	}
+
+	// Only the owner can answer; anyone else can't tell the request exists
+	if intro.OwnerID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Intro request not found"})
+		return
+	}
+
+	intro, err = models.RespondToIntroRequest(intro.ID, req.Status, userID)
+	if err != nil {
+		if err == models.ErrIntroAlreadyAnswered {
+			c.JSON(http.StatusConflict, gin.H{"error": "Intro request was already answered"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to respond to intro request"})
+		return
+	}
+
+	eventType := "intro_" + intro.Status
+	h.publishAnalyticsEvent(userID, eventType, map[string]interface{}{
+		"company_id":       intro.CompanyID,
+		"intro_request_id": intro.ID,
+		"investor_id":      intro.InvestorID,
+	})
+	h.notify(intro.InvestorID, eventType, intro)
+
+	c.JSON(http.StatusOK, intro)
+}
+
+// GetSentIntroRequests lists the intro requests the current user has made
+func (h *ShowcaseHandler) GetSentIntroRequests(c *gin.Context) {
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	intros, err := models.GetSentIntroRequests(c.GetString("user_id"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve intro requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"intro_requests": intros,
+		"limit":          limit,
+		"offset":         offset,
+	})
+}
+
+// GetReceivedIntroRequests lists the intro requests made to the companies
+// the current user owns (?status=pending|accepted|declined)
+func (h *ShowcaseHandler) GetReceivedIntroRequests(c *gin.Context) {
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	status := c.Query("status")
+	switch status {
+	case "", models.IntroStatusPending, models.IntroStatusAccepted, models.IntroStatusDeclined:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, accepted or declined"})
+		return
+	}
+
+	intros, err := models.GetReceivedIntroRequests(c.GetString("user_id"), status, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve intro requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"intro_requests": intros,
+		"limit":          limit,
+		"offset":         offset,
+	})
+}
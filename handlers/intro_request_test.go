@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/models"
+)
+
+var introRequestColumns = []string{"id", "company_id", "name", "created_by", "investor_id", "first_name", "last_name",
+	"message", "status", "responded_at", "created_at"}
+
+// introRow returns an intro_requests row from investor-1 to owner-1's company c1
+func introRow(status string, respondedAt interface{}) *sqlmock.Rows {
+	return sqlmock.NewRows(introRequestColumns).AddRow("intro-1", "c1", "Acme", "owner-1", "investor-1", "Ada", "Lovelace",
+		"Would love to chat", status, respondedAt, time.Now())
+}
+
+func TestIntroRequestAcceptEnablesMessaging(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	ctx := context.Background()
+
+	type notification struct{ userID, eventType string }
+	var notified []notification
+	h := &ShowcaseHandler{moderator: contentfilter.NewModerator(nil, contentfilter.PolicyOff)}
+	h.SetUserNotifier(func(userID string, message map[string]interface{}) {
+		notified = append(notified, notification{userID, message["type"].(string)})
+	})
+	ws := &WebSocketHandler{matchmakerService: newTestMatchmaker(t), messagingPolicy: parseMessagingPolicy(MessagingPolicyMatches)}
+
+	// Before any intro, the investor and owner aren't matched and can't message
+	mock.ExpectQuery(`FROM intro_requests`).WithArgs("investor-1", "owner-1", models.IntroStatusAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	if ws.canMessage(ctx, "investor-1", "owner-1") {
+		t.Fatal("investor could message the owner without an accepted intro")
+	}
+
+	// The investor asks for an introduction
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectQuery(`INSERT INTO intro_requests`).WithArgs("c1", "investor-1", "Would love to chat").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("intro-1"))
+	mock.ExpectQuery(`WHERE i.company_id = \$1 AND i.investor_id = \$2`).WithArgs("c1", "investor-1").
+		WillReturnRows(introRow(models.IntroStatusPending, nil))
+
+	rec := serve(t, "investor-1", models.RoleUser, http.MethodPost, "/companies/:id/intro", "/companies/c1/intro",
+		strings.NewReader(`{"message": "Would love to chat"}`), h.CreateIntroRequest)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("request status = %d: %s", rec.Code, rec.Body)
+	}
+
+	// The owner accepts it
+	mock.ExpectQuery(`WHERE i.id = \$1`).WithArgs("intro-1").WillReturnRows(introRow(models.IntroStatusPending, nil))
+	mock.ExpectExec(`UPDATE intro_requests SET status = \$1`).
+		WithArgs(models.IntroStatusAccepted, "owner-1", "intro-1", models.IntroStatusPending).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`WHERE i.id = \$1`).WithArgs("intro-1").WillReturnRows(introRow(models.IntroStatusAccepted, time.Now()))
+
+	rec = serve(t, "owner-1", models.RoleUser, http.MethodPut, "/intro-requests/:id", "/intro-requests/intro-1",
+		strings.NewReader(`{"status": "accepted"}`), h.RespondToIntroRequest)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("accept status = %d: %s", rec.Code, rec.Body)
+	}
+	var intro models.IntroRequest
+	if err := json.Unmarshal(rec.Body.Bytes(), &intro); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if intro.Status != models.IntroStatusAccepted || intro.RespondedAt == nil {
+		t.Errorf("intro = %+v, want accepted with a response time", intro)
+	}
+
+	want := []notification{{"owner-1", "intro_requested"}, {"investor-1", "intro_accepted"}}
+	if len(notified) != len(want) || notified[0] != want[0] || notified[1] != want[1] {
+		t.Errorf("notifications = %v, want %v", notified, want)
+	}
+
+	// Now they may message each other despite not being matched
+	mock.ExpectQuery(`FROM intro_requests`).WithArgs("investor-1", "owner-1", models.IntroStatusAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	if !ws.canMessage(ctx, "investor-1", "owner-1") {
+		t.Error("accepted intro did not enable messaging")
+	}
+}
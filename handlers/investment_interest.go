@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// expressInterestRequest is the optional body for expressing investment interest
+type expressInterestRequest struct {
+	Amount   *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Currency string   `json:"currency" binding:"omitempty,len=3,alpha"`
+	Message  string   `json:"message" binding:"max=2000"`
+}
+
+// ExpressInvestmentInterest records the current user's non-binding interest
+// in a company. Repeating it returns the interest already recorded.
+func (h *ShowcaseHandler) ExpressInvestmentInterest(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req expressInterestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	company, ok := h.visibleCompany(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if company.CreatedBy == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot express interest in your own company"})
+		return
+	}
+
+	interest := models.InvestmentInterest{
+		CompanyID:  company.ID,
+		InvestorID: userID,
+		Amount:     req.Amount,
+		Currency:   strings.ToUpper(req.Currency),
+		Message:    req.Message,
+	}
+	created, err := models.ExpressInvestmentInterest(&interest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record investment interest"})
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, interest)
+		return
+	}
+
+	h.publishAnalyticsEvent(userID, "investment_interest_expressed", map[string]interface{}{
+		"company_id":  company.ID,
+		"interest_id": interest.ID,
+		"amount":      interest.Amount,
+		"currency":    interest.Currency,
+	})
+
+	c.JSON(http.StatusCreated, interest)
+}
+
+// GetSentInvestmentInterests lists the interest the current user has expressed
+func (h *ShowcaseHandler) GetSentInvestmentInterests(c *gin.Context) {
+	userID := c.GetString("user_id")
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	interests, err := models.GetSentInvestmentInterests(userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investment interest"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetReceivedInvestmentInterests lists the interest investors have expressed
+// in the companies the current user owns
+func (h *ShowcaseHandler) GetReceivedInvestmentInterests(c *gin.Context) {
+	userID := c.GetString("user_id")
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	interests, err := models.GetReceivedInvestmentInterests(userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investment interest"})
+		return
+	}
+
+	h.publishAnalyticsEvent(userID, "investment_interest_viewed", map[string]interface{}{
+		"count": len(interests),
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"interests": interests,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import "github.com/connect-up/auth-service/models"
+
+// undisclosedInvestor is shown in place of an anonymous investor's identity
+const undisclosedInvestor = "Undisclosed investor"
+
+// redactAnonymousInvestors hides anonymous investors from a company's
+// investor list. The company owner and admins see every investor; other
+// viewers only see their own anonymous investments unredacted.
+func redactAnonymousInvestors(investments []models.Investment, viewerID, ownerID string, isAdmin bool) {
+	if isAdmin || (viewerID != "" && viewerID == ownerID) {
+		return
+	}
+
+	for i := range investments {
+		investment := &investments[i]
+		if !investment.Anonymous || (viewerID != "" && investment.InvestorID == viewerID) {
+			continue
+		}
+		investment.InvestorID = ""
+		investment.InvestorName = undisclosedInvestor
+		investment.Notes = ""
+	}
+}
+
+// redactAnonymousActivity hides who made anonymous investments in a company's
+// activity feed, with the same exceptions as redactAnonymousInvestors
+func redactAnonymousActivity(activities []*models.CompanyActivity, viewerID, ownerID string, isAdmin bool) {
+	if isAdmin || (viewerID != "" && viewerID == ownerID) {
+		return
+	}
+
+	for _, activity := range activities {
+		anonymous, _ := activity.Details["anonymous"].(bool)
+		if activity.Action != "investment_created" || !anonymous || (viewerID != "" && activity.ActorID == viewerID) {
+			continue
+		}
+		activity.ActorID = ""
+	}
+}
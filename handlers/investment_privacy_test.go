@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestRedactAnonymousInvestors(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewerID string
+		isAdmin  bool
+		wantID   string
+	}{
+		{"company owner", "owner-1", false, "investor-1"},
+		{"the investor", "investor-1", false, "investor-1"},
+		{"admin", "admin-1", true, "investor-1"},
+		{"third party", "investor-2", false, ""},
+		{"anonymous visitor", "", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			investments := []models.Investment{
+				{ID: "i1", InvestorID: "investor-1", InvestorName: "Ada", Notes: "side letter", Anonymous: true},
+				{ID: "i2", InvestorID: "investor-3", InvestorName: "Grace"},
+			}
+			redactAnonymousInvestors(investments, tt.viewerID, "owner-1", tt.isAdmin)
+
+			anonymous := investments[0]
+			if anonymous.InvestorID != tt.wantID {
+				t.Errorf("anonymous investor id = %q, want %q", anonymous.InvestorID, tt.wantID)
+			}
+			if tt.wantID == "" && (anonymous.InvestorName != undisclosedInvestor || anonymous.Notes != "") {
+				t.Errorf("redacted investment = %+v, want an undisclosed investor without notes", anonymous)
+			}
+			if tt.wantID != "" && anonymous.InvestorName != "Ada" {
+				t.Errorf("investor name = %q, want Ada", anonymous.InvestorName)
+			}
+			if public := investments[1]; public.InvestorID != "investor-3" || public.InvestorName != "Grace" {
+				t.Errorf("public investment = %+v, want it left as is", public)
+			}
+		})
+	}
+}
+
+func TestCompanyActivityHidesAnonymousInvestors(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewerID string
+		role     string
+		wantID   string
+	}{
+		{"company owner", "owner-1", models.RoleUser, "investor-1"},
+		{"the investor", "investor-1", models.RoleUser, "investor-1"},
+		{"admin", "admin-1", models.RoleAdmin, "investor-1"},
+		{"third party", "investor-2", models.RoleUser, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newTestDB(t)
+			h := &ShowcaseHandler{}
+
+			now := time.Now()
+			mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+				WillReturnRows(companyRow("c1", "Rockets"))
+			mock.ExpectQuery(`FROM company_activities`).WithArgs("c1", 20, 0).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "actor_id", "action", "details", "created_at"}).
+					AddRow("a1", "c1", "investor-1", "investment_created", []byte(`{"investment_id":"i1","anonymous":true}`), now).
+					AddRow("a2", "c1", "investor-3", "investment_created", []byte(`{"investment_id":"i2","anonymous":false}`), now))
+
+			rec := serve(t, tt.viewerID, tt.role, http.MethodGet, "/companies/:id/activity", "/companies/c1/activity", nil, h.GetCompanyActivity)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			var resp struct {
+				Activities []models.CompanyActivity `json:"activities"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if len(resp.Activities) != 2 {
+				t.Fatalf("got %d activities, want 2", len(resp.Activities))
+			}
+			if got := resp.Activities[0].ActorID; got != tt.wantID {
+				t.Errorf("anonymous investment actor = %q, want %q", got, tt.wantID)
+			}
+			if got := resp.Activities[1].ActorID; got != "investor-3" {
+				t.Errorf("public investment actor = %q, want investor-3", got)
+			}
+		})
+	}
+}
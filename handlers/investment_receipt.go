@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetInvestmentReceipt renders an investment summary as a PDF. It is
+// available to the investor, the company's owner, and admins.
+func (h *ShowcaseHandler) GetInvestmentReceipt(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	investment, err := models.GetInvestmentByID(c.Param("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investment"})
+		return
+	}
+
+	company, err := models.GetCompanyByID(investment.CompanyID)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	isOwner := company != nil && company.CreatedBy == userID
+	if investment.InvestorID != userID && !isOwner && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to view this investment"})
+		return
+	}
+
+	companyName := "Unknown company"
+	if company != nil {
+		companyName = company.Name
+	}
+
+	receipt, err := renderInvestmentReceipt(investment, companyName, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate receipt"})
+		return
+	}
+
+	filename := fmt.Sprintf("investment-%s.pdf", investment.ID)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", receipt)
+}
+
+// renderInvestmentReceipt lays out a one-page investment summary. It renders
+// into memory so a failure can still be reported as a JSON error.
+func renderInvestmentReceipt(investment *models.Investment, companyName string, generatedAt time.Time) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Investment receipt", true)
+	pdf.SetCreator("ConnectUp", true)
+	// The core fonts are cp1252; translate so accented names render
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 12, "Investment Receipt", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, "Investment ID: "+investment.ID, "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	rows := [][2]string{
+		{"Company", companyName},
+		{"Amount", fmt.Sprintf("%.2f %s", investment.Amount, investment.Currency)},
+		{"Currency", investment.Currency},
+		{"Round", investmentLabel(investment.Round)},
+		{"Type", investmentLabel(investment.InvestmentType)},
+		{"Date", investment.Date.Format("January 2, 2006")},
+		{"Status", investmentLabel(investment.Status)},
+	}
+
+	pdf.SetTextColor(0, 0, 0)
+	for _, row := range rows {
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(40, 9, row[0], "B", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 9, tr(row[1]), "B", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.SetTextColor(100, 100, 100)
+	pdf.CellFormat(0, 6, "Generated "+generatedAt.UTC().Format("2006-01-02 15:04 MST"), "", 1, "L", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// investmentLabel turns a stored value such as "series_a" into "Series A"
+func investmentLabel(value string) string {
+	words := strings.Fields(strings.ReplaceAll(value, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	if len(words) == 0 {
+		return "-"
+	}
+	return strings.Join(words, " ")
+}
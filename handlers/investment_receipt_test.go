@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestInvestmentReceiptPDF(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{}
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM investments WHERE id = \$1`).WithArgs("inv-1").
+		WillReturnRows(sqlmock.NewRows(investmentColumns).AddRow("inv-1", "c1", "investor-1", 250000.0, "EUR",
+			"equity", "series_a", now, models.InvestmentStatusCompleted, "", false, now, now))
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+
+	rec := serve(t, "investor-1", models.RoleUser, http.MethodGet, "/investments/:id/receipt", "/investments/inv-1/receipt", nil, h.GetInvestmentReceipt)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.Contains(got, `filename="investment-inv-1.pdf"`) {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) || rec.Body.Len() < 500 {
+		t.Errorf("body is not a PDF document (%d bytes)", rec.Body.Len())
+	}
+
+	mock.ExpectQuery(`FROM investments WHERE id = \$1`).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(investmentColumns))
+	rec = serve(t, "investor-1", models.RoleUser, http.MethodGet, "/investments/:id/receipt", "/investments/missing/receipt", nil, h.GetInvestmentReceipt)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("missing investment: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// matchExportFlushRows is the number of rows written between flushes to the client
+const matchExportFlushRows = 100
+
+// matchExportHeader names the columns of a CSV match export
+var matchExportHeader = []string{
+	"match_id", "other_user_id", "score", "common_tags", "common_skills",
+	"status", "your_status", "mutual", "created_at",
+}
+
+// matchExportRow is one match as seen from the exporting user
+type matchExportRow struct {
+	MatchID      string    `json:"match_id"`
+	OtherUserID  string    `json:"other_user_id"`
+	Score        float64   `json:"score"`
+	CommonTags   []string  `json:"common_tags"`
+	CommonSkills []string  `json:"common_skills"`
+	Status       string    `json:"status"`
+	YourStatus   string    `json:"your_status"`
+	Mutual       bool      `json:"mutual"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// newMatchExportRow builds the export row for a match from userID's side
+func newMatchExportRow(match models.Match, userID string) matchExportRow {
+	row := matchExportRow{
+		MatchID:      match.ID,
+		OtherUserID:  match.UserID2,
+		Score:        match.Score,
+		CommonTags:   match.CommonTags,
+		CommonSkills: match.CommonSkills,
+		Status:       match.Status,
+		YourStatus:   match.User1Status,
+		Mutual:       match.Mutual,
+		CreatedAt:    match.CreatedAt,
+	}
+	if match.UserID2 == userID {
+		row.OtherUserID = match.UserID1
+		row.YourStatus = match.User2Status
+	}
+	return row
+}
+
+// csvFormulaPrefixes are the leading characters that make a spreadsheet
+// evaluate a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// csvText escapes a text cell so a spreadsheet shows it rather than
+// evaluating it, by prefixing a quote to values that would start a formula
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvRecord formats the row in matchExportHeader order; list columns are
+// separated by semicolons, matching the company import format. Text columns
+// hold user-entered tags and skills, so they are escaped with csvText.
+func (r matchExportRow) csvRecord() []string {
+	return []string{
+		csvText(r.MatchID),
+		csvText(r.OtherUserID),
+		strconv.FormatFloat(r.Score, 'f', 4, 64),
+		csvText(strings.Join(r.CommonTags, ";")),
+		csvText(strings.Join(r.CommonSkills, ";")),
+		csvText(r.Status),
+		csvText(r.YourStatus),
+		strconv.FormatBool(r.Mutual),
+		r.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ExportMatches streams a user's matches as CSV or JSON (?format=csv|json).
+// Only the user themselves or an admin may export.
+func (h *MatchmakerHandler) ExportMatches(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.export_forbidden")})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.export_format")})
+		return
+	}
+
+	matches, err := h.matchmakerService.GetMatchesForUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_retrieve_failed")})
+		return
+	}
+
+	filename := fmt.Sprintf("matches-%s.%s", userID, format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		writeMatchExportJSON(c, matches, userID)
+		return
+	}
+	writeMatchExportCSV(c, matches, userID)
+}
+
+// writeMatchExportCSV writes the header and one record per match, flushing
+// periodically so large exports reach the client as they are written
+func writeMatchExportCSV(c *gin.Context, matches []models.Match, userID string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(matchExportHeader); err != nil {
+		return
+	}
+
+	for i, match := range matches {
+		if err := writer.Write(newMatchExportRow(match, userID).csvRecord()); err != nil {
+			return
+		}
+		if (i+1)%matchExportFlushRows == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+	}
+
+	writer.Flush()
+	c.Writer.Flush()
+}
+
+// writeMatchExportJSON writes the matches as a JSON array one element at a time
+func writeMatchExportJSON(c *gin.Context, matches []models.Match, userID string) {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	c.Writer.WriteString("[")
+	for i, match := range matches {
+		if i > 0 {
+			c.Writer.WriteString(",")
+		}
+		data, err := json.Marshal(newMatchExportRow(match, userID))
+		if err != nil {
+			return
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return
+		}
+		if (i+1)%matchExportFlushRows == 0 {
+			c.Writer.Flush()
+		}
+	}
+	c.Writer.WriteString("]")
+	c.Writer.Flush()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestExportMatchesCSV(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	match := models.Match{
+		ID:           "m1",
+		UserID1:      "bob",
+		UserID2:      "alice",
+		Score:        0.8125,
+		CommonTags:   []string{"fintech", "ai"},
+		CommonSkills: []string{"go"},
+		Status:       models.MatchStatusPending,
+		User1Status:  models.MatchStatusAccepted,
+		User2Status:  models.MatchStatusPending,
+		CreatedAt:    created,
+	}
+	if err := h.matchmakerService.StoreMatch(context.Background(), match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/matches/:user_id/export", "/matches/alice/export?format=csv", nil, h.ExportMatches)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("content type = %q, want text/csv", got)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row: %v", len(records), records)
+	}
+	if !reflect.DeepEqual(records[0], matchExportHeader) {
+		t.Errorf("header = %v, want %v", records[0], matchExportHeader)
+	}
+
+	// The row is seen from alice's side: bob is the other user and her own
+	// response is the pending one
+	want := []string{"m1", "bob", "0.8125", "fintech;ai", "go", models.MatchStatusPending, models.MatchStatusPending, "false", "2024-03-01T12:00:00Z"}
+	if !reflect.DeepEqual(records[1], want) {
+		t.Errorf("row = %v, want %v", records[1], want)
+	}
+}
+
+func TestExportMatchesCSVEscapesFormulas(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	match := models.Match{
+		ID:           "m1",
+		UserID1:      "alice",
+		UserID2:      "bob",
+		Score:        0.5,
+		CommonTags:   []string{"=HYPERLINK(\"http://evil.example\")", "ai"},
+		CommonSkills: []string{"@SUM(A1:A9)"},
+		Status:       models.MatchStatusPending,
+		User1Status:  models.MatchStatusPending,
+		User2Status:  models.MatchStatusPending,
+		CreatedAt:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := h.matchmakerService.StoreMatch(context.Background(), match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/matches/:user_id/export", "/matches/alice/export?format=csv", nil, h.ExportMatches)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row: %v", len(records), records)
+	}
+
+	if got, want := records[1][3], `'=HYPERLINK("http://evil.example");ai`; got != want {
+		t.Errorf("common_tags = %q, want %q", got, want)
+	}
+	if got, want := records[1][4], "'@SUM(A1:A9)"; got != want {
+		t.Errorf("common_skills = %q, want %q", got, want)
+	}
+}
+
+func TestCSVTextEscapesFormulaPrefixes(t *testing.T) {
+	tests := map[string]string{
+		"=1+1": "'=1+1",
+		"+1":   "'+1",
+		"-1":   "'-1",
+		"@cmd": "'@cmd",
+		"\tx":  "'\tx",
+		"\rx":  "'\rx",
+		"go":   "go",
+		"a=b":  "a=b",
+		"":     "",
+	}
+	for in, want := range tests {
+		if got := csvText(in); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestExportMatchesForAnotherUserIsForbidden(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+
+	rec := serve(t, "mallory", models.RoleUser, http.MethodGet, "/matches/:user_id/export", "/matches/alice/export", nil, h.ExportMatches)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
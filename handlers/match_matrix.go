@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetCompatibilityMatrix scores every pair in a group of users, such as an
+// event's attendees (organizers and admins only)
+func (h *MatchmakerHandler) GetCompatibilityMatrix(c *gin.Context) {
+	var req struct {
+		UserIDs       []string `json:"user_ids" binding:"required"`
+		WeightProfile string   `json:"weight_profile"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	weights, err := h.matchmakerService.WeightProfile(req.WeightProfile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.unknown_weight_profile")})
+		return
+	}
+
+	matrix, err := h.matchmakerService.CompatibilityMatrix(c.Request.Context(), req.UserIDs, weights)
+	switch {
+	case errors.Is(err, matchmaker.ErrMatrixTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.matrix_too_large", h.matchmakerService.MaxMatrixUsers())})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profiles_retrieve_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetNetwork returns the users reachable through a user's mutual matches that
+// they aren't matched with yet, strongest path first (?depth=&limit=). Only
+// the user themselves or an admin may look at a network; private profiles
+// are left out.
+func (h *MatchmakerHandler) GetNetwork(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.network_forbidden")})
+		return
+	}
+	ctx := c.Request.Context()
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(matchmaker.DefaultNetworkDepth)))
+	if err != nil || depth < matchmaker.DefaultNetworkDepth || depth > matchmaker.MaxNetworkDepth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.network_depth", matchmaker.DefaultNetworkDepth, matchmaker.MaxNetworkDepth)})
+		return
+	}
+
+	limit, _ := utils.ParsePagination(c, h.matchmakerService.MatchPageSize(0), 0)
+
+	found, err := h.matchmakerService.SecondDegreeConnections(ctx, userID, depth)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.connections_failed")})
+		return
+	}
+
+	// Profiles that expired, were made private, or opted out of discovery
+	// drop out of the results
+	connections := []matchmaker.NetworkConnection{}
+	for _, connection := range found {
+		if len(connections) == limit {
+			break
+		}
+		profile, err := h.matchmakerService.GetUserProfile(ctx, connection.UserID)
+		if err != nil || profile.Visibility == models.ProfileVisibilityPrivate || !profile.IsSearchable() {
+			continue
+		}
+		connections = append(connections, connection)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"connections": connections,
+		"total":       len(connections),
+		"depth":       depth,
+		"limit":       limit,
+	})
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/internal/schema"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// PreviewMatches estimates how many matches a profile would get, with a
+// sample of the best (?sample=, up to MaxPreviewSampleSize), without storing
+// the profile or any match
+func (h *MatchmakerHandler) PreviewMatches(c *gin.Context) {
+	var req models.MatchRequest
+	if !bindValidated(c, schema.Profile, &req, utils.T(c, "error.invalid_body")) {
+		return
+	}
+
+	profile, ok := h.profileFromRequest(c, req)
+	if !ok {
+		return
+	}
+
+	sampleSize, err := strconv.Atoi(c.DefaultQuery("sample", strconv.Itoa(matchmaker.DefaultPreviewSampleSize)))
+	if err != nil || sampleSize < 0 {
+		sampleSize = matchmaker.DefaultPreviewSampleSize
+	}
+	sampleSize = min(sampleSize, matchmaker.MaxPreviewSampleSize)
+
+	preview, err := h.matchmakerService.PreviewMatches(c.Request.Context(), profile, utils.Locale(c), sampleSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_find_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
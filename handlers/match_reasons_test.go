@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestMatchDetailsReasonMentionsCommonSkills(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+
+	for _, profile := range []models.UserProfile{
+		{UserID: "alice", Skills: []string{"go", "sql"}, Location: "Berlin"},
+		{UserID: "bob", Skills: []string{"go", "design"}, Location: "Lisbon", Experience: 10},
+	} {
+		if err := h.matchmakerService.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", profile.UserID, err)
+		}
+	}
+	matches := []models.Match{
+		{ID: "m-ab", UserID1: "alice", UserID2: "bob"},
+		// Its users' profiles are gone, so the stored common skills explain it
+		{ID: "m-cd", UserID1: "carol", UserID2: "dave", CommonSkills: []string{"rust"}},
+	}
+	if err := h.matchmakerService.StoreMatches(ctx, matches); err != nil {
+		t.Fatalf("StoreMatches: %v", err)
+	}
+
+	tests := []struct {
+		matchID, caller, want string
+	}{
+		{"m-ab", "alice", "Common skills: go"},
+		{"m-cd", "carol", "Common skills: rust"},
+	}
+	for _, tt := range tests {
+		rec := serve(t, tt.caller, models.RoleUser, http.MethodGet, "/matches/details/:match_id", "/matches/details/"+tt.matchID, nil, h.GetMatchDetails)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s status = %d: %s", tt.matchID, rec.Code, rec.Body)
+		}
+		var resp struct {
+			Match models.Match `json:"match"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if !strings.Contains(resp.Match.Reason, tt.want) {
+			t.Errorf("%s reason = %q, want it to mention %q", tt.matchID, resp.Match.Reason, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// StartMatchRebuild starts recomputing every user's matches in the
+// background, e.g. after a scoring change (admin only)
+func (h *MatchmakerHandler) StartMatchRebuild(c *gin.Context) {
+	job, err := h.matchmakerService.StartRebuild(c.Request.Context(), c.GetString("user_id"))
+	switch {
+	case errors.Is(err, matchmaker.ErrRebuildRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": utils.T(c, "error.rebuild_running")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.rebuild_failed")})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"job": job})
+}
+
+// GetMatchRebuild reports the progress of the latest match rebuild (admin only)
+func (h *MatchmakerHandler) GetMatchRebuild(c *gin.Context) {
+	job, err := h.matchmakerService.RebuildStatus(c.Request.Context())
+	switch {
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.rebuild_not_found")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.rebuild_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// CancelMatchRebuild stops the running match rebuild after the profile it is
+// on; matches already rebuilt are kept (admin only)
+func (h *MatchmakerHandler) CancelMatchRebuild(c *gin.Context) {
+	job, err := h.matchmakerService.CancelRebuild(c.Request.Context())
+	switch {
+	case errors.Is(err, matchmaker.ErrRebuildNotRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": utils.T(c, "error.rebuild_not_running")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.rebuild_failed")})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Match rebuild cancellation requested",
+		"job":     job,
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetRecommendations returns a page of the best-scoring profiles the user has
+// no match with yet (?limit=&offset=). Private profiles are never recommended.
+func (h *MatchmakerHandler) GetRecommendations(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.matches_forbidden")})
+		return
+	}
+	ctx := c.Request.Context()
+
+	limit, offset := utils.ParsePagination(c, h.matchmakerService.MatchPageSize(0), 0)
+
+	userProfile, err := h.matchmakerService.GetUserProfile(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	}
+
+	matched, err := h.matchmakerService.MatchedUserIDs(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_retrieve_failed")})
+		return
+	}
+
+	profiles, err := h.matchmakerService.CandidateProfiles(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profiles_retrieve_failed")})
+		return
+	}
+
+	weights := h.matchmakerService.WeightsFor(userProfile)
+
+	locale := utils.Locale(c)
+	recommendations := []models.MatchScore{}
+	for _, profile := range profiles {
+		if profile.UserID == userID || matched[profile.UserID] ||
+			profile.Visibility == models.ProfileVisibilityPrivate {
+			continue
+		}
+
+		if !h.matchmakerService.CanMatch(userProfile, &profile) {
+			continue
+		}
+
+		score := h.matchmakerService.CalculateMatchScore(userProfile, &profile, weights)
+		if score > h.matchmakerService.MatchThreshold(userProfile, &profile) {
+			boosted, raw := h.matchmakerService.BoostedScore(score, &profile)
+			candidate := models.MatchScore{
+				UserID:   profile.UserID,
+				Score:    boosted,
+				RawScore: raw,
+				Reason:   h.matchmakerService.MatchReason(locale, userProfile, &profile),
+			}
+			h.matchmakerService.PresentMatchScore(&candidate)
+			recommendations = append(recommendations, candidate)
+		}
+	}
+
+	// Sort by score descending, breaking ties by user id so pages are stable
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].UserID < recommendations[j].UserID
+	})
+
+	total := len(recommendations)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	c.JSON(http.StatusOK, gin.H{
+		"recommendations": recommendations[start:end],
+		"total":           total,
+		"limit":           limit,
+		"offset":          offset,
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestRecommendationsExcludeMatchedProfiles(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+
+	for _, profile := range []models.UserProfile{
+		{UserID: "alice"},
+		{UserID: "bob"},
+		{UserID: "carol"},
+		{UserID: "dave", Visibility: models.ProfileVisibilityPrivate},
+	} {
+		profile.Tags = []string{"fintech"}
+		profile.Industries = []string{"finance"}
+		profile.Experience = 8
+		profile.Location = "Berlin"
+		if err := h.matchmakerService.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", profile.UserID, err)
+		}
+	}
+
+	// A rejected match still counts as already matched
+	match := models.Match{ID: "m1", UserID1: "bob", UserID2: "alice", User1Status: models.MatchStatusPending, User2Status: models.MatchStatusRejected}
+	if err := h.matchmakerService.StoreMatch(ctx, match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/recommendations/:user_id", "/recommendations/alice", nil, h.GetRecommendations)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Recommendations []models.MatchScore `json:"recommendations"`
+		Total           int                 `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Recommendations) != 1 || resp.Recommendations[0].UserID != "carol" {
+		t.Errorf("recommendations = %+v, want only carol", resp.Recommendations)
+	}
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// RescoreMatches re-scores a user's existing matches against their current
+// profile, keeping both users' responses (the user or admin only)
+func (h *MatchmakerHandler) RescoreMatches(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.rescore_forbidden")})
+		return
+	}
+
+	matches, err := h.matchmakerService.RescoreMatches(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	case errors.Is(err, matchmaker.ErrMatchesBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": utils.T(c, "error.matches_busy")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.rescore_failed")})
+		return
+	}
+
+	h.matchmakerService.ExplainMatches(c.Request.Context(), utils.Locale(c), matches)
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"total":   len(matches),
+	})
+}
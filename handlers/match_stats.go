@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetMatchStats returns aggregate matchmaking statistics (admin only)
+func (h *MatchmakerHandler) GetMatchStats(c *gin.Context) {
+	stats, err := h.matchmakerService.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.stats_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// maxBulkStatusUpdates caps how many matches one bulk status update may change
+const maxBulkStatusUpdates = 100
+
+// Outcomes of one item in a bulk match status update
+const (
+	bulkStatusUpdated   = "updated"
+	bulkStatusNotFound  = "not_found"
+	bulkStatusForbidden = "forbidden"
+	bulkStatusDuplicate = "duplicate"
+	bulkStatusFailed    = "failed"
+)
+
+// bulkStatusResult reports what happened to one item of a bulk status update
+type bulkStatusResult struct {
+	MatchID string `json:"match_id"`
+	Status  string `json:"status"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkUpdateMatchStatus sets the authenticated user's status on several
+// matches at once. Items are applied best-effort: matches the user isn't
+// part of or that don't exist are reported and skipped, and the rest are
+// stored together.
+func (h *MatchmakerHandler) BulkUpdateMatchStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": utils.T(c, "error.unauthenticated")})
+		return
+	}
+
+	var req struct {
+		Updates []struct {
+			MatchID string `json:"match_id" binding:"required"`
+			Status  string `json:"status" binding:"required,oneof=pending accepted rejected"`
+		} `json:"updates" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Updates) > maxBulkStatusUpdates {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.bulk_too_large", maxBulkStatusUpdates)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now()
+	results := make([]bulkStatusResult, len(req.Updates))
+	seen := make(map[string]bool, len(req.Updates))
+	var matches []models.Match
+	var stored []int
+
+	for i, update := range req.Updates {
+		result := &results[i]
+		*result = bulkStatusResult{MatchID: update.MatchID, Status: update.Status}
+
+		if seen[update.MatchID] {
+			result.Outcome, result.Error = bulkStatusDuplicate, utils.T(c, "error.match_duplicate")
+			continue
+		}
+		seen[update.MatchID] = true
+
+		match, err := h.matchmakerService.GetMatch(ctx, update.MatchID)
+		if err != nil {
+			result.Outcome, result.Error = bulkStatusNotFound, utils.T(c, "error.match_not_found")
+			continue
+		}
+		if err := match.SetUserStatus(userID, update.Status); err != nil {
+			result.Outcome, result.Error = bulkStatusForbidden, utils.T(c, "error.match_forbidden")
+			continue
+		}
+		match.UpdatedAt = now
+
+		matches = append(matches, *match)
+		stored = append(stored, i)
+	}
+
+	outcome, errMsg := bulkStatusUpdated, ""
+	if len(matches) > 0 {
+		if err := h.matchmakerService.StoreMatches(ctx, matches); err != nil {
+			outcome, errMsg = bulkStatusFailed, utils.T(c, "error.match_update_failed")
+		} else {
+			for _, match := range matches {
+				h.matchmakerService.NotifyMatchChanged(ctx, matchmaker.MatchEventUpdated, match)
+			}
+		}
+	}
+
+	updated := 0
+	for _, i := range stored {
+		results[i].Outcome, results[i].Error = outcome, errMsg
+		if outcome == bulkStatusUpdated {
+			updated++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"updated": updated,
+		"failed":  len(results) - updated,
+	})
+}
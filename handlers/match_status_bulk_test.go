@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestBulkUpdateMatchStatusSkipsUnauthorizedMatches(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+
+	matches := []models.Match{
+		{ID: "m-ab", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusPending, User2Status: models.MatchStatusPending},
+		{ID: "m-cd", UserID1: "carol", UserID2: "dave", User1Status: models.MatchStatusPending, User2Status: models.MatchStatusPending},
+	}
+	if err := h.matchmakerService.StoreMatches(ctx, matches); err != nil {
+		t.Fatalf("StoreMatches: %v", err)
+	}
+
+	body := `{"updates": [
+		{"match_id": "m-ab", "status": "accepted"},
+		{"match_id": "m-cd", "status": "rejected"},
+		{"match_id": "m-missing", "status": "accepted"}
+	]}`
+	rec := serve(t, "alice", models.RoleUser, http.MethodPut, "/matches/status/bulk", "/matches/status/bulk",
+		strings.NewReader(body), h.BulkUpdateMatchStatus)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Results []bulkStatusResult `json:"results"`
+		Updated int                `json:"updated"`
+		Failed  int                `json:"failed"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Updated != 1 || resp.Failed != 2 || len(resp.Results) != 3 {
+		t.Fatalf("response = %+v, want 1 updated and 2 failed", resp)
+	}
+	for i, want := range []string{bulkStatusUpdated, bulkStatusForbidden, bulkStatusNotFound} {
+		if resp.Results[i].Outcome != want {
+			t.Errorf("result %s outcome = %q, want %q", resp.Results[i].MatchID, resp.Results[i].Outcome, want)
+		}
+	}
+
+	ab, err := h.matchmakerService.GetMatch(ctx, "m-ab")
+	if err != nil {
+		t.Fatalf("GetMatch(m-ab): %v", err)
+	}
+	if ab.User1Status != models.MatchStatusAccepted || ab.User2Status != models.MatchStatusPending {
+		t.Errorf("m-ab statuses = %s/%s, want alice accepted and bob still pending", ab.User1Status, ab.User2Status)
+	}
+
+	cd, err := h.matchmakerService.GetMatch(ctx, "m-cd")
+	if err != nil {
+		t.Fatalf("GetMatch(m-cd): %v", err)
+	}
+	if cd.User1Status != models.MatchStatusPending || cd.User2Status != models.MatchStatusPending {
+		t.Errorf("m-cd statuses = %s/%s, want it untouched by a non-participant", cd.User1Status, cd.User2Status)
+	}
+}
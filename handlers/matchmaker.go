@@ -1,7 +1,8 @@
 package handlers
 
 import (
-	"encoding/json"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
@@ -9,50 +10,65 @@ import (
 	"strings"
 	"time"
 
+	"github.com/connect-up/auth-service/internal/contentfilter"
 	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/internal/schema"
 	"github.com/connect-up/auth-service/models"
 	"github.com/connect-up/auth-service/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 )
 
 type MatchmakerHandler struct {
 	matchmakerService *matchmaker.Service
+	moderator         *contentfilter.Moderator
 }
 
-func NewMatchmakerHandler(matchmakerService *matchmaker.Service) *MatchmakerHandler {
+func NewMatchmakerHandler(matchmakerService *matchmaker.Service, moderator *contentfilter.Moderator) *MatchmakerHandler {
 	return &MatchmakerHandler{
 		matchmakerService: matchmakerService,
+		moderator:         moderator,
 	}
 }
 
 // CreateUserProfile creates a new user profile for matchmaking
 func (h *MatchmakerHandler) CreateUserProfile(c *gin.Context) {
 	var req models.MatchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindValidated(c, schema.Profile, &req, utils.T(c, "error.invalid_body")) {
 		return
 	}
 
-	profile := models.UserProfile{
-		UserID:     req.UserID,
-		Tags:       req.Tags,
-		Industries: req.Industries,
-		Experience: req.Experience,
-		Interests:  req.Interests,
-		Location:   req.Location,
-		Bio:        req.Bio,
-		Skills:     req.Skills,
+	profile, ok := h.profileFromRequest(c, req)
+	if !ok {
+		return
 	}
 
-	if err := h.matchmakerService.StoreUserProfile(c.Request.Context(), profile); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user profile"})
+	changed, err := h.matchmakerService.StoreUserProfileIfChanged(c.Request.Context(), profile)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profile_create_failed")})
+		return
+	}
+
+	// Resubmitting an identical profile can't change its matches, so skip the full scan
+	if !changed {
+		existing, err := h.matchmakerService.GetMatchesForUser(c.Request.Context(), req.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_retrieve_failed")})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":       "User profile unchanged",
+			"matches_found": len(existing),
+			"recomputed":    false,
+		})
 		return
 	}
 
 	// Trigger match finding
-	matches, err := h.matchmakerService.FindMatches(c.Request.Context(), req.UserID)
+	matches, err := h.matchmakerService.FindMatchesLimit(c.Request.Context(), req.UserID, req.MaxMatches)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find matches"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_find_failed")})
 		return
 	}
 
@@ -61,57 +77,282 @@ func (h *MatchmakerHandler) CreateUserProfile(c *gin.Context) {
 		if err := h.matchmakerService.StoreMatch(c.Request.Context(), match); err != nil {
 			continue
 		}
+		h.matchmakerService.NotifyAutoAccepted(c.Request.Context(), match)
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "User profile created successfully",
 		"matches_found": len(matches),
+		"recomputed": true,
 	})
 }
 
+// profileFromRequest builds the profile a request describes, moderating its
+// bio and checking its weight profile and preference weights. Only the user
+// themselves or an admin may describe a user's profile. On failure it
+// responds 403 or 400 and returns false.
+func (h *MatchmakerHandler) profileFromRequest(c *gin.Context, req models.MatchRequest) (models.UserProfile, bool) {
+	if c.GetString("user_id") != req.UserID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.profile_forbidden")})
+		return models.UserProfile{}, false
+	}
+
+	bio, err := h.moderator.Apply(req.Bio)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.content_rejected")})
+		return models.UserProfile{}, false
+	}
+
+	profile := models.UserProfile{
+		UserID:     req.UserID,
+		Tags:       req.Tags,
+		Industries: req.Industries,
+		Experience: req.Experience,
+		Interests:  req.Interests,
+		Location:   req.Location,
+		Bio:        bio,
+		Skills:     req.Skills,
+		Visibility: req.Visibility,
+		Seeking:    req.Seeking,
+		Incognito:  req.Incognito,
+	}
+	if profile.Visibility == "" {
+		profile.Visibility = models.ProfileVisibilityPublic
+	}
+
+	profile.WeightProfile = strings.ToLower(req.WeightProfile)
+	if profile.WeightProfile == "" {
+		profile.WeightProfile = matchmaker.DefaultWeightProfile
+	}
+	if _, err := h.matchmakerService.WeightProfile(profile.WeightProfile); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.unknown_weight_profile")})
+		return models.UserProfile{}, false
+	}
+
+	if profile.PreferenceWeights, err = matchmaker.NormalizePreferenceWeights(req.PreferenceWeights); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.preference_weights", err)})
+		return models.UserProfile{}, false
+	}
+
+	return profile, true
+}
+
 // GetUserProfile retrieves a user profile
 func (h *MatchmakerHandler) GetUserProfile(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.user_id_required")})
+		return
+	}
+
+	profile, err := h.matchmakerService.GetUserProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	}
+
+	// Owners and admins see everything, including completeness; everyone else
+	// gets the view allowed by the profile's visibility
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		profile = redactUserProfile(profile)
+		if profile == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+			return
+		}
+		h.recordProfileView(c.Request.Context(), userID, c.GetString("user_id"))
+		c.JSON(http.StatusOK, gin.H{"profile": profile})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profile":      profile,
+		"completeness": models.ProfileCompleteness(profile),
+	})
+}
+
+// GetProfileCompleteness scores how complete a user's profile is and lists
+// the high-impact fields still missing (the user or admin only)
+func (h *MatchmakerHandler) GetProfileCompleteness(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.completeness_forbidden")})
 		return
 	}
 
 	profile, err := h.matchmakerService.GetUserProfile(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User profile not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":        userID,
+		"score":          models.ProfileCompleteness(profile),
+		"missing_fields": models.MissingProfileFields(profile),
+	})
+}
+
+// redactUserProfile returns the view of a profile other users may see, or nil if it is withheld
+func redactUserProfile(profile *models.UserProfile) *models.UserProfile {
+	switch profile.Visibility {
+	case models.ProfileVisibilityPrivate:
+		return nil
+	case models.ProfileVisibilityLimited:
+		return &models.UserProfile{
+			UserID:     profile.UserID,
+			Tags:       profile.Tags,
+			Visibility: profile.Visibility,
+		}
+	default:
+		public := *profile
+		public.Boost = 0
+		public.Incognito = false
+		public.PausedUntil = nil
+		public.PreferenceWeights = nil
+		return &public
+	}
+}
+
+// SetProfileBoost sets how strongly a user's profile is surfaced in other
+// users' match lists (admin only)
+func (h *MatchmakerHandler) SetProfileBoost(c *gin.Context) {
+	var req struct {
+		Boost float64 `json:"boost" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.matchmakerService.SetBoost(c.Request.Context(), c.Param("user_id"), req.Boost)
+	switch {
+	case errors.Is(err, matchmaker.ErrBoostOutOfRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.boost_out_of_range", h.matchmakerService.MaxBoost())})
+		return
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profile_update_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": profile.UserID,
+		"boost":   h.matchmakerService.Boost(profile),
+	})
+}
+
+// SetProfileSearchable opts a profile in to or out of other users' matches,
+// search results and recommendations (the user or admin only). The user's
+// existing matches are kept.
+func (h *MatchmakerHandler) SetProfileSearchable(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.searchable_forbidden")})
+		return
+	}
+
+	var req struct {
+		Searchable *bool `json:"searchable" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.matchmakerService.SetSearchable(c.Request.Context(), userID, *req.Searchable)
+	switch {
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profile_update_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    profile.UserID,
+		"searchable": profile.IsSearchable(),
+	})
+}
+
+// PauseMatching stops a profile from getting or appearing in new matches
+// until the given time (the user or admin only). Existing matches stay
+// visible.
+func (h *MatchmakerHandler) PauseMatching(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.pause_forbidden")})
+		return
+	}
+
+	var req struct {
+		Until time.Time `json:"until" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.pause_in_past")})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"profile": profile})
+	profile, err := h.matchmakerService.PauseMatching(c.Request.Context(), userID, req.Until)
+	h.respondPause(c, profile, err)
+}
+
+// ResumeMatching lifts a profile's pause (the user or admin only)
+func (h *MatchmakerHandler) ResumeMatching(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.pause_forbidden")})
+		return
+	}
+
+	profile, err := h.matchmakerService.ResumeMatching(c.Request.Context(), userID)
+	h.respondPause(c, profile, err)
+}
+
+func (h *MatchmakerHandler) respondPause(c *gin.Context, profile *models.UserProfile, err error) {
+	switch {
+	case errors.Is(err, redis.Nil):
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profile_update_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":      profile.UserID,
+		"paused":       profile.Paused(time.Now()),
+		"paused_until": profile.PausedUntil,
+	})
 }
 
 // GetMatches retrieves matches for a user
 func (h *MatchmakerHandler) GetMatches(c *gin.Context) {
 	userID := c.Param("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.user_id_required")})
+		return
+	}
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.matches_forbidden")})
 		return
 	}
 
 	// Get query parameters for filtering
 	status := c.Query("status")
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		offset = 0
-	}
+	// Page size is independent of how many matches are stored for the user
+	limit, offset := utils.ParsePagination(c, h.matchmakerService.MatchPageSize(0), 0)
 
 	matches, err := h.matchmakerService.GetMatchesForUser(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve matches"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.matches_retrieve_failed")})
 		return
 	}
 
@@ -126,31 +367,57 @@ func (h *MatchmakerHandler) GetMatches(c *gin.Context) {
 		matches = filteredMatches
 	}
 
-	// Apply pagination
 	total := len(matches)
-	if offset >= total {
+	var nextCursor string
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		// Cursor pagination: resume strictly after the last (score, id) seen,
+		// so matches added or removed between pages don't shift the results
+		cursorScore, cursorID, err := decodeMatchCursor(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.invalid_cursor")})
+			return
+		}
+
+		start := sort.Search(len(matches), func(i int) bool {
+			return matchAfterCursor(matches[i], cursorScore, cursorID)
+		})
+		matches = matches[start:]
+	} else if offset >= total {
 		matches = []models.Match{}
 	} else {
-		end := offset + limit
-		if end > total {
-			end = total
-		}
-		matches = matches[offset:end]
+		matches = matches[offset:]
+	}
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+		last := matches[len(matches)-1]
+		nextCursor = encodeMatchCursor(last.Score, last.ID)
 	}
 
+	h.matchmakerService.ExplainMatches(c.Request.Context(), utils.Locale(c), matches)
+
 	response := models.MatchResponse{
-		Matches: matches,
-		Total:   total,
+		Matches:    matches,
+		Total:      total,
+		NextCursor: nextCursor,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// UpdateMatchStatus updates the status of a match
+// UpdateMatchStatus records the authenticated user's response to a match.
+// The match becomes mutual once both users have accepted it.
 func (h *MatchmakerHandler) UpdateMatchStatus(c *gin.Context) {
 	matchID := c.Param("match_id")
 	if matchID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Match ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.match_id_required")})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": utils.T(c, "error.unauthenticated")})
 		return
 	}
 
@@ -164,28 +431,25 @@ func (h *MatchmakerHandler) UpdateMatchStatus(c *gin.Context) {
 	}
 
 	// Get the match from Redis
-	key := "match:" + matchID
-	data, err := utils.RedisClient.Get(c.Request.Context(), key).Result()
+	match, err := h.matchmakerService.GetMatch(c.Request.Context(), matchID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.match_not_found")})
 		return
 	}
 
-	var match models.Match
-	if err := json.Unmarshal([]byte(data), &match); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse match data"})
+	// Update the caller's side of the match
+	if err := match.SetUserStatus(userID.(string), req.Status); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.match_forbidden")})
 		return
 	}
-
-	// Update status
-	match.Status = req.Status
 	match.UpdatedAt = time.Now()
 
 	// Store updated match
-	if err := h.matchmakerService.StoreMatch(c.Request.Context(), match); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update match"})
+	if err := h.matchmakerService.StoreMatch(c.Request.Context(), *match); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.match_update_failed")})
 		return
 	}
+	h.matchmakerService.NotifyMatchChanged(c.Request.Context(), matchmaker.MatchEventUpdated, *match)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Match status updated successfully",
@@ -193,28 +457,104 @@ func (h *MatchmakerHandler) UpdateMatchStatus(c *gin.Context) {
 	})
 }
 
-// GetMatchDetails retrieves details of a specific match
+// UndoMatchStatus reverts the authenticated user's last status change on a
+// match, if it was made within the undo window
+func (h *MatchmakerHandler) UndoMatchStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": utils.T(c, "error.unauthenticated")})
+		return
+	}
+
+	match, err := h.matchmakerService.GetMatch(c.Request.Context(), c.Param("match_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.match_not_found")})
+		return
+	}
+
+	if err := match.UndoUserStatus(userID.(string), h.matchmakerService.UndoWindow(), time.Now()); err != nil {
+		switch err {
+		case models.ErrNotMatchParticipant:
+			c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.match_forbidden")})
+		case models.ErrUndoWindowExpired:
+			c.JSON(http.StatusConflict, gin.H{"error": utils.T(c, "error.undo_expired")})
+		default:
+			c.JSON(http.StatusConflict, gin.H{"error": utils.T(c, "error.nothing_to_undo")})
+		}
+		return
+	}
+	match.UpdatedAt = time.Now()
+
+	if err := h.matchmakerService.StoreMatch(c.Request.Context(), *match); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.match_update_failed")})
+		return
+	}
+	h.matchmakerService.NotifyMatchChanged(c.Request.Context(), matchmaker.MatchEventUpdated, *match)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Match status change undone",
+		"match":   match,
+	})
+}
+
+// GetConnections retrieves a user's mutual matches, where both users accepted
+func (h *MatchmakerHandler) GetConnections(c *gin.Context) {
+	userID := c.Param("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.user_id_required")})
+		return
+	}
+	if c.GetString("user_id") != userID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.matches_forbidden")})
+		return
+	}
+
+	matches, err := h.matchmakerService.GetMutualMatches(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.connections_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MatchResponse{
+		Matches: matches,
+		Total:   len(matches),
+	})
+}
+
+// GetMatchDetails retrieves details of a specific match. Anyone but its two
+// users or an admin is told it doesn't exist.
 func (h *MatchmakerHandler) GetMatchDetails(c *gin.Context) {
 	matchID := c.Param("match_id")
 	if matchID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Match ID is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.match_id_required")})
 		return
 	}
 
-	key := "match:" + matchID
-	data, err := utils.RedisClient.Get(c.Request.Context(), key).Result()
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+	match, err := h.matchmakerService.GetMatch(c.Request.Context(), matchID)
+	callerID := c.GetString("user_id")
+	if err != nil || (match.UserID1 != callerID && match.UserID2 != callerID && !utils.IsAdmin(c)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.match_not_found")})
 		return
 	}
 
-	var match models.Match
-	if err := json.Unmarshal([]byte(data), &match); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse match data"})
+	explained := []models.Match{*match}
+	h.matchmakerService.ExplainMatches(c.Request.Context(), utils.Locale(c), explained)
+
+	c.JSON(http.StatusOK, gin.H{"match": explained[0]})
+}
+
+// GetMatchDiagnostics explains how a user's candidates score and where they
+// are filtered out (admin only)
+func (h *MatchmakerHandler) GetMatchDiagnostics(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	diagnostics, err := h.matchmakerService.Diagnose(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"match": match})
+	c.JSON(http.StatusOK, diagnostics)
 }
 
 // SearchMatches searches for matches based on criteria
@@ -224,21 +564,46 @@ func (h *MatchmakerHandler) SearchMatches(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if c.GetString("user_id") != criteria.UserID && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.matches_forbidden")})
+		return
+	}
 
-	// Get all profiles
-	profiles, err := h.matchmakerService.GetAllUserProfiles(c.Request.Context())
+	// Get the candidate profiles
+	profiles, err := h.matchmakerService.CandidateProfiles(c.Request.Context(), criteria.UserID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve profiles"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.profiles_retrieve_failed")})
 		return
 	}
 
+	locale := utils.Locale(c)
 	var matches []models.MatchScore
 	userProfile, err := h.matchmakerService.GetUserProfile(c.Request.Context(), criteria.UserID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User profile not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": utils.T(c, "error.profile_not_found")})
 		return
 	}
 
+	// The request's weight profile wins over the user's own weights
+	weights := h.matchmakerService.WeightsFor(userProfile)
+	if criteria.WeightProfile != "" {
+		weights, err = h.matchmakerService.WeightProfile(criteria.WeightProfile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.T(c, "error.unknown_weight_profile")})
+			return
+		}
+	}
+
+	// Presence is shared across instances through Redis
+	var online map[string]bool
+	if criteria.OnlyOnline {
+		online, err = utils.OnlineUserIDs(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": utils.T(c, "error.presence_unavailable")})
+			return
+		}
+	}
+
 	for _, profile := range profiles {
 		if profile.UserID == criteria.UserID {
 			continue // Skip self
@@ -248,14 +613,25 @@ func (h *MatchmakerHandler) SearchMatches(c *gin.Context) {
 		if !h.matchesCriteria(&profile, &criteria) {
 			continue
 		}
+		if criteria.OnlyOnline && !online[profile.UserID] {
+			continue
+		}
+
+		if !h.matchmakerService.CanMatch(userProfile, &profile) {
+			continue
+		}
 
-		score := h.matchmakerService.CalculateMatchScore(userProfile, &profile)
-		if score > 0.3 { // Minimum threshold
-			matches = append(matches, models.MatchScore{
-				UserID: profile.UserID,
-				Score:  score,
-				Reason: h.generateMatchReason(userProfile, &profile),
-			})
+		score := h.matchmakerService.CalculateMatchScore(userProfile, &profile, weights)
+		if score > h.matchmakerService.MatchThreshold(userProfile, &profile) {
+			boosted, raw := h.matchmakerService.BoostedScore(score, &profile)
+			candidate := models.MatchScore{
+				UserID:   profile.UserID,
+				Score:    boosted,
+				RawScore: raw,
+				Reason:   h.matchmakerService.MatchReason(locale, userProfile, &profile),
+			}
+			h.matchmakerService.PresentMatchScore(&candidate)
+			matches = append(matches, candidate)
 		}
 	}
 
@@ -337,46 +713,36 @@ func (h *MatchmakerHandler) matchesCriteria(profile *models.UserProfile, criteri
 	return true
 }
 
-// generateMatchReason generates a reason for the match
-func (h *MatchmakerHandler) generateMatchReason(profile1, profile2 *models.UserProfile) string {
-	var reasons []string
-
-	// Check common tags
-	commonTags := h.matchmakerService.FindCommonTags(profile1.Tags, profile2.Tags)
-	if len(commonTags) > 0 {
-		reasons = append(reasons, fmt.Sprintf("Common interests: %s", strings.Join(commonTags, ", ")))
-	}
-
-	// Check common skills
-	commonSkills := h.matchmakerService.FindCommonSkills(profile1.Skills, profile2.Skills)
-	if len(commonSkills) > 0 {
-		reasons = append(reasons, fmt.Sprintf("Common skills: %s", strings.Join(commonSkills, ", ")))
-	}
+// encodeMatchCursor encodes a position in the (score desc, id asc) match ordering
+func encodeMatchCursor(score float64, matchID string) string {
+	raw := strconv.FormatFloat(score, 'g', -1, 64) + "|" + matchID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
 
-	// Check experience compatibility
-	expDiff := abs(profile1.Experience - profile2.Experience)
-	if expDiff <= 2 {
-		reasons = append(reasons, "Similar experience level")
+// decodeMatchCursor decodes a cursor produced by encodeMatchCursor
+func decodeMatchCursor(cursor string) (float64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", err
 	}
 
-	// Check location
-	if profile1.Location != "" && profile2.Location != "" {
-		if strings.ToLower(profile1.Location) == strings.ToLower(profile2.Location) {
-			reasons = append(reasons, "Same location")
-		}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return 0, "", fmt.Errorf("malformed cursor")
 	}
 
-	if len(reasons) == 0 {
-		return "Good overall compatibility"
+	score, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, "", err
 	}
 
-	return strings.Join(reasons, "; ")
+	return score, parts[1], nil
 }
 
-// abs returns the absolute value of an integer
-func abs(x int) int {
-	if x < 0 {
-		return -x
+// matchAfterCursor reports whether a match sorts after the cursor position
+func matchAfterCursor(match models.Match, score float64, matchID string) bool {
+	if match.Score != score {
+		return match.Score < score
 	}
-	return x
-} 
\ No newline at end of file
+	return match.ID > matchID
+}
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func newTestMatchmakerHandler(t *testing.T) *MatchmakerHandler {
+	t.Helper()
+	newTestRedis(t)
+	return NewMatchmakerHandler(newTestMatchmaker(t), contentfilter.NewModerator(nil, contentfilter.PolicyOff))
+}
+
+func TestMatchesOwnerVersusOtherUser(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	match := models.Match{ID: "m1", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted}
+	if err := h.matchmakerService.StoreMatch(context.Background(), match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		route        string
+		target       string
+		handler      gin.HandlerFunc
+		caller, role string
+		want         int
+	}{
+		{"matches owner", "/matches/:user_id", "/matches/alice", h.GetMatches, "alice", models.RoleUser, http.StatusOK},
+		{"matches other user", "/matches/:user_id", "/matches/alice", h.GetMatches, "mallory", models.RoleUser, http.StatusForbidden},
+		{"matches admin", "/matches/:user_id", "/matches/alice", h.GetMatches, "root", models.RoleAdmin, http.StatusOK},
+		{"connections owner", "/connections/:user_id", "/connections/bob", h.GetConnections, "bob", models.RoleUser, http.StatusOK},
+		{"connections other user", "/connections/:user_id", "/connections/bob", h.GetConnections, "mallory", models.RoleUser, http.StatusForbidden},
+		{"recommendations other user", "/recommendations/:user_id", "/recommendations/alice", h.GetRecommendations, "mallory", models.RoleUser, http.StatusForbidden},
+		{"details participant", "/matches/details/:match_id", "/matches/details/m1", h.GetMatchDetails, "bob", models.RoleUser, http.StatusOK},
+		{"details other user", "/matches/details/:match_id", "/matches/details/m1", h.GetMatchDetails, "mallory", models.RoleUser, http.StatusNotFound},
+		{"details admin", "/matches/details/:match_id", "/matches/details/m1", h.GetMatchDetails, "root", models.RoleAdmin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(t, tt.caller, tt.role, http.MethodGet, tt.route, tt.target, nil, tt.handler)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestCreateUserProfileForAnotherUserIsForbidden(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	body := `{"user_id": "alice", "tags": ["fintech"], "industries": ["finance"], "experience": 8, "location": "Berlin"}`
+
+	rec := serve(t, "mallory", models.RoleUser, http.MethodPost, "/profiles", "/profiles", strings.NewReader(body), h.CreateUserProfile)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+	}
+	if _, err := h.matchmakerService.GetUserProfile(context.Background(), "alice"); err == nil {
+		t.Error("profile was stored for another user")
+	}
+
+	rec = serve(t, "mallory", models.RoleUser, http.MethodPost, "/preview", "/preview", strings.NewReader(body), h.PreviewMatches)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("preview status = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+	}
+
+	rec = serve(t, "alice", models.RoleUser, http.MethodPost, "/profiles", "/profiles", strings.NewReader(body), h.CreateUserProfile)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("owner status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+}
+
+func TestMatchCursorSurvivesHigherScoredInsert(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+	store := func(id string, score float64) {
+		t.Helper()
+		if err := h.matchmakerService.StoreMatch(ctx, models.Match{ID: id, UserID1: "alice", UserID2: "user-" + id, Score: score}); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+	store("m1", 0.9)
+	store("m2", 0.8)
+	store("m3", 0.8)
+	store("m4", 0.6)
+
+	page := func(query string) models.MatchResponse {
+		t.Helper()
+		rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/matches/:user_id", "/matches/alice?"+query, nil, h.GetMatches)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var resp models.MatchResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return resp
+	}
+
+	first := page("limit=2")
+	if got := responseMatchIDs(first); !reflect.DeepEqual(got, []string{"m1", "m2"}) || first.NextCursor == "" {
+		t.Fatalf("first page = %v (cursor %q), want m1, m2 and a cursor", got, first.NextCursor)
+	}
+
+	// A new best match would shift an offset page; the cursor resumes after m2
+	store("m0", 0.95)
+	second := page("limit=2&cursor=" + first.NextCursor)
+	if got := responseMatchIDs(second); !reflect.DeepEqual(got, []string{"m3", "m4"}) {
+		t.Errorf("second page = %v, want m3, m4 with nothing repeated or skipped", got)
+	}
+	if second.NextCursor != "" {
+		t.Errorf("last page has cursor %q", second.NextCursor)
+	}
+}
+
+func responseMatchIDs(resp models.MatchResponse) []string {
+	ids := make([]string, len(resp.Matches))
+	for i, match := range resp.Matches {
+		ids[i] = match.ID
+	}
+	return ids
+}
+
+func TestMinCommonAttributesHidesHighScoringPairSharingNothing(t *testing.T) {
+	t.Setenv("MATCH_MIN_COMMON_ATTRIBUTES", "1")
+	h := newTestMatchmakerHandler(t)
+	service := h.matchmakerService
+	ctx := context.Background()
+
+	// Same industry, experience and location, but no tag or skill in common
+	alice := models.UserProfile{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Industries: []string{"finance"}, Experience: 5, Location: "Berlin"}
+	carol := models.UserProfile{UserID: "carol", Tags: []string{"health"}, Skills: []string{"rust"}, Industries: []string{"finance"}, Experience: 5, Location: "Berlin"}
+	for _, profile := range []models.UserProfile{alice, carol} {
+		if err := service.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", profile.UserID, err)
+		}
+	}
+
+	weights, err := service.WeightProfile("")
+	if err != nil {
+		t.Fatalf("WeightProfile: %v", err)
+	}
+	if score := service.CalculateMatchScore(&alice, &carol, weights); score <= 0.3 {
+		t.Fatalf("score = %.2f, want it above the 0.3 threshold", score)
+	}
+	if common := len(service.FindCommonTags(alice.Tags, carol.Tags)) + len(service.FindCommonSkills(alice.Skills, carol.Skills)); common != 0 {
+		t.Fatalf("pair shares %d tags or skills, want none", common)
+	}
+
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	for _, match := range matches {
+		if match.UserID1 == "carol" || match.UserID2 == "carol" {
+			t.Errorf("FindMatches matched a pair sharing no tags or skills: %+v", match)
+		}
+	}
+
+	rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/search", "/search", strings.NewReader(`{"user_id": "alice"}`), h.SearchMatches)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("search status = %d: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Matches []models.MatchScore `json:"matches"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode search: %v", err)
+	}
+	for _, match := range body.Matches {
+		if match.UserID == "carol" {
+			t.Errorf("SearchMatches returned a pair sharing no tags or skills: %+v", match)
+		}
+	}
+}
+
+func TestCreateUserProfileTwiceDoesNotRecompute(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+	store := func(userID string) {
+		t.Helper()
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Industries: []string{"finance"}, Experience: 8, Location: "Berlin"}
+		if err := h.matchmakerService.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", userID, err)
+		}
+	}
+	create := func() map[string]interface{} {
+		t.Helper()
+		body := `{"user_id": "alice", "tags": ["fintech"], "industries": ["finance"], "experience": 8, "location": "Berlin"}`
+		rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/profiles", "/profiles", strings.NewReader(body), h.CreateUserProfile)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return resp
+	}
+
+	store("bob")
+	first := create()
+	if first["recomputed"] != true || first["matches_found"] != 1.0 {
+		t.Fatalf("first submit = %v, want recomputed with 1 match", first)
+	}
+
+	// A new candidate would be matched by a recompute, so the unchanged
+	// count shows the second submit skipped it
+	store("carol")
+	second := create()
+	if second["recomputed"] != false || second["matches_found"] != 1.0 {
+		t.Errorf("second submit = %v, want not recomputed with the existing 1 match", second)
+	}
+}
+
+func TestUndoMatchStatusWithinAndAfterWindow(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+	store := func(id string, changedAt time.Time) {
+		t.Helper()
+		match := models.Match{
+			ID: id, UserID1: "alice", UserID2: "bob",
+			User1Status: models.MatchStatusRejected, User2Status: models.MatchStatusAccepted,
+			StatusHistory: []models.MatchStatusChange{
+				{UserID: "alice", From: models.MatchStatusPending, To: models.MatchStatusRejected, ChangedAt: changedAt},
+			},
+		}
+		if err := h.matchmakerService.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+	store("recent", time.Now().Add(-time.Minute))
+	store("stale", time.Now().Add(-time.Hour))
+
+	rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/matches/:match_id/undo", "/matches/recent/undo", nil, h.UndoMatchStatus)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("undo within window: status = %d: %s", rec.Code, rec.Body)
+	}
+	match, err := h.matchmakerService.GetMatch(ctx, "recent")
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	if match.User1Status != models.MatchStatusPending || len(match.StatusHistory) != 0 {
+		t.Errorf("after undo: user1 status %q, history %v; want pending with no history", match.User1Status, match.StatusHistory)
+	}
+
+	rec = serve(t, "alice", models.RoleUser, http.MethodPost, "/matches/:match_id/undo", "/matches/stale/undo", nil, h.UndoMatchStatus)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("undo after window: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if match, _ := h.matchmakerService.GetMatch(ctx, "stale"); match.User1Status != models.MatchStatusRejected {
+		t.Errorf("expired undo changed user1 status to %q", match.User1Status)
+	}
+
+	// Only participants may undo, and bob has made no change to undo
+	rec = serve(t, "mallory", models.RoleUser, http.MethodPost, "/matches/:match_id/undo", "/matches/stale/undo", nil, h.UndoMatchStatus)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("non-participant: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	rec = serve(t, "bob", models.RoleUser, http.MethodPost, "/matches/:match_id/undo", "/matches/stale/undo", nil, h.UndoMatchStatus)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("nothing to undo: status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
+
+func TestSearchMatchesOnlyOnline(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Industries: []string{"finance"}, Experience: 8, Location: "Berlin"}
+		if err := h.matchmakerService.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", userID, err)
+		}
+	}
+	if err := utils.MarkOnline(ctx, "carol"); err != nil {
+		t.Fatalf("MarkOnline: %v", err)
+	}
+
+	search := func(body string) []string {
+		t.Helper()
+		rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/search", "/search", strings.NewReader(body), h.SearchMatches)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+		}
+		var resp struct {
+			Matches []models.MatchScore `json:"matches"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		ids := make([]string, len(resp.Matches))
+		for i, match := range resp.Matches {
+			ids[i] = match.UserID
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	if got := search(`{"user_id": "alice", "industries": ["finance"]}`); !reflect.DeepEqual(got, []string{"bob", "carol"}) {
+		t.Errorf("search = %v, want bob and carol", got)
+	}
+	// Combined with the other criteria, only the online candidate is left
+	if got := search(`{"user_id": "alice", "industries": ["finance"], "only_online": true}`); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Errorf("online search = %v, want only carol", got)
+	}
+}
+
+func TestNonSearchableProfileIsNotSuggested(t *testing.T) {
+	h := newTestMatchmakerHandler(t)
+	ctx := context.Background()
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Industries: []string{"finance"}, Experience: 8, Location: "Berlin"}
+		if err := h.matchmakerService.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile(%s): %v", userID, err)
+		}
+	}
+	existing := models.Match{ID: "m1", UserID1: "carol", UserID2: "dave", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted}
+	if err := h.matchmakerService.StoreMatch(ctx, existing); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	// Only carol or an admin may hide carol
+	rec := serve(t, "mallory", models.RoleUser, http.MethodPut, "/profiles/:user_id/searchable", "/profiles/carol/searchable",
+		strings.NewReader(`{"searchable": false}`), h.SetProfileSearchable)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("other user: status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	rec = serve(t, "carol", models.RoleUser, http.MethodPut, "/profiles/:user_id/searchable", "/profiles/carol/searchable",
+		strings.NewReader(`{"searchable": false}`), h.SetProfileSearchable)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("owner: status = %d: %s", rec.Code, rec.Body)
+	}
+
+	matches, err := h.matchmakerService.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].UserID2 != "bob" {
+		t.Errorf("alice's matches = %v, want only bob", matches)
+	}
+
+	// Carol still sees the match she already had
+	own, err := h.matchmakerService.GetMatchesForUser(ctx, "carol")
+	if err != nil {
+		t.Fatalf("GetMatchesForUser: %v", err)
+	}
+	if len(own) != 1 || own[0].ID != "m1" {
+		t.Errorf("carol's matches = %v, want her existing match", own)
+	}
+}
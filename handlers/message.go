@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// MessageHandler serves a user's chat message history
+type MessageHandler struct{}
+
+// NewMessageHandler creates a new message handler
+func NewMessageHandler() *MessageHandler {
+	return &MessageHandler{}
+}
+
+// SearchMessages full-text searches the authenticated user's messages (?q=&peer_id=&limit=&offset=)
+func (h *MessageHandler) SearchMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	results, err := models.SearchMessages(userID.(string), query, models.MessageSearchFilter{
+		PeerID: c.Query("peer_id"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+
+	messages := make([]*models.Message, len(results))
+	for i, result := range results {
+		messages[i] = &result.Message
+	}
+	if err := models.AttachReactionSummaries(messages); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"messages": results,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// GetMessage retrieves a single message; only its sender or receiver may read it
+func (h *MessageHandler) GetMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	messageID := c.Param("id")
+	if _, err := uuid.Parse(messageID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+		return
+	}
+
+	message, err := models.GetMessageByID(messageID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve message"})
+		return
+	}
+
+	if message.SenderID != userID.(string) && message.ReceiverID != userID.(string) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to read this message"})
+		return
+	}
+
+	if err := models.AttachReactionSummaries([]*models.Message{message}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
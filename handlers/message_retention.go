@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// SetConversationRetention overrides how long the messages between the
+// authenticated user and a peer are kept; either participant may set it
+func (h *MessageHandler) SetConversationRetention(c *gin.Context) {
+	userID, peerID, ok := conversationPeer(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		RetentionDays *int `json:"retention_days" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if *req.RetentionDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "retention_days must be 0 (keep forever) or more"})
+		return
+	}
+
+	retention := &models.ConversationRetention{
+		UserID:        userID,
+		PeerID:        peerID,
+		RetentionDays: *req.RetentionDays,
+		UpdatedBy:     userID,
+	}
+	if err := models.SetConversationRetention(retention); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update conversation retention"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"retention": retention})
+}
+
+// ClearConversationRetention removes the retention override for the
+// conversation with a peer, so the default policy applies again
+func (h *MessageHandler) ClearConversationRetention(c *gin.Context) {
+	userID, peerID, ok := conversationPeer(c)
+	if !ok {
+		return
+	}
+
+	if err := models.ClearConversationRetention(userID, peerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Conversation has no retention override"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear conversation retention"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Conversation retention cleared"})
+}
+
+// conversationPeer returns the authenticated user and the :peer_id they are
+// talking to, writing an error response and returning false if either is invalid
+func conversationPeer(c *gin.Context) (string, string, bool) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return "", "", false
+	}
+
+	peerID := c.Param("peer_id")
+	if _, err := uuid.Parse(peerID); err != nil || peerID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid peer ID"})
+		return "", "", false
+	}
+	return userID, peerID, true
+}
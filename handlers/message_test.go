@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+const testMessageID = "3f0c9a52-6a5e-4d0b-8f0e-2b1f6c1d7a11"
+
+// expectMessage expects GetMessageByID to load a message from bob to alice
+func expectMessage(mock sqlmock.Sqlmock) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM messages\s+WHERE id = \$1 AND deleted_at IS NULL`).WithArgs(testMessageID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id", "content", "content_nonce", "message_type", "is_read", "created_at", "updated_at", "seq"}).
+			AddRow(testMessageID, "bob", "alice", "See you at the demo day", nil, "text", true, now, now, 3))
+}
+
+func TestGetMessageAsReceiver(t *testing.T) {
+	mock := newTestDB(t)
+	expectMessage(mock)
+	mock.ExpectQuery(`FROM message_reactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"message_id", "emoji", "count", "user_ids"}))
+
+	h := NewMessageHandler()
+	rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/messages/:id", "/messages/"+testMessageID, nil, h.GetMessage)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Message models.Message `json:"message"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Message.ID != testMessageID || !resp.Message.IsRead || resp.Message.MessageType != "text" {
+		t.Errorf("message = %+v, want the read text message", resp.Message)
+	}
+}
+
+func TestGetMessageAsAnotherUser(t *testing.T) {
+	mock := newTestDB(t)
+	expectMessage(mock)
+
+	h := NewMessageHandler()
+	rec := serve(t, "mallory", models.RoleUser, http.MethodGet, "/messages/:id", "/messages/"+testMessageID, nil, h.GetMessage)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+	}
+}
+
+func TestGetMessageMalformedID(t *testing.T) {
+	newTestDB(t)
+
+	h := NewMessageHandler()
+	rec := serve(t, "alice", models.RoleUser, http.MethodGet, "/messages/:id", "/messages/not-a-uuid", nil, h.GetMessage)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
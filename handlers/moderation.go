@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// ModerationHandler handles content reports and their review
+type ModerationHandler struct {
+	redisClient *redis.Client
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(redisClient *redis.Client) *ModerationHandler {
+	return &ModerationHandler{redisClient: redisClient}
+}
+
+// CreateReport reports a message, company, or user for moderation
+func (h *ModerationHandler) CreateReport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	found, err := models.ReportTargetExists(req.TargetType, req.TargetID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report"})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reported content not found"})
+		return
+	}
+
+	report := models.Report{
+		ReporterID: userID.(string),
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+	}
+
+	if err := models.CreateReport(&report); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// ListReports lists reports for review (admin only)
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	status := c.Query("status")
+
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	reports, err := models.ListReports(status, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// UpdateReportStatus moves a report to reviewed, actioned, or back to open (admin only).
+// Actioning a report soft-deletes the reported message or company.
+func (h *ModerationHandler) UpdateReportStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.UpdateReportStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := models.GetReportByID(c.Param("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve report"})
+		return
+	}
+
+	if !models.CanTransitionReport(report.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Cannot move report from %s to %s", report.Status, req.Status)})
+		return
+	}
+
+	if err := models.UpdateReportStatus(report, req.Status, userID.(string)); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusConflict, gin.H{"error": "Report was modified concurrently"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report"})
+		return
+	}
+
+	// Hidden companies must not be served from cache
+	if report.Status == models.ReportStatusActioned && report.TargetType == models.ReportTargetCompany && h.redisClient != nil {
+		h.redisClient.Del(context.Background(), utils.CompanyKey(report.TargetID))
+		invalidateCompanyDirectory(h.redisClient)
+	}
+
+	c.JSON(http.StatusOK, report)
+}
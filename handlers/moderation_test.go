@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+const (
+	testReportID  = "5b0c3f7e-1d2a-4c4b-8f55-0e9a7d6c1b21"
+	testCompanyID = "9e4f2a61-7c3b-4d8e-a1f0-3b6d5c2e8f47"
+)
+
+var reportColumns = []string{"id", "reporter_id", "target_type", "target_id", "reason", "status",
+	"reviewed_by", "reviewed_at", "created_at", "updated_at"}
+
+func TestReportCreateAndResolve(t *testing.T) {
+	server := newTestRedis(t)
+	mock := newTestDB(t)
+	h := NewModerationHandler(utils.RedisClient)
+	now := time.Now()
+
+	utils.RedisClient.Set(context.Background(), utils.CompanyKey(testCompanyID), "{}", 0)
+
+	mock.ExpectQuery(`SELECT EXISTS\(SELECT 1 FROM companies`).WithArgs(testCompanyID).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`INSERT INTO reports`).WithArgs("alice", models.ReportTargetCompany, testCompanyID, "spam").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "updated_at"}).
+			AddRow(testReportID, models.ReportStatusOpen, now, now))
+
+	body := `{"target_type": "company", "target_id": "` + testCompanyID + `", "reason": "spam"}`
+	rec := serve(t, "alice", models.RoleUser, http.MethodPost, "/reports", "/reports", strings.NewReader(body), h.CreateReport)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", rec.Code, rec.Body)
+	}
+	var created models.Report
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if created.ID != testReportID || created.Status != models.ReportStatusOpen || created.ReporterID != "alice" {
+		t.Fatalf("created report = %+v", created)
+	}
+
+	// Actioning the report hides the company in the same transaction
+	mock.ExpectQuery(`FROM reports WHERE id = \$1`).WithArgs(testReportID).
+		WillReturnRows(sqlmock.NewRows(reportColumns).
+			AddRow(testReportID, "alice", models.ReportTargetCompany, testCompanyID, "spam", models.ReportStatusOpen, nil, nil, now, now))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE reports SET status`).WithArgs(models.ReportStatusActioned, "admin-1", testReportID, models.ReportStatusOpen).
+		WillReturnRows(sqlmock.NewRows([]string{"reviewed_at", "updated_at"}).AddRow(now, now))
+	mock.ExpectExec(`UPDATE companies SET deleted_at`).WithArgs(testCompanyID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rec = serve(t, "admin-1", models.RoleAdmin, http.MethodPut, "/reports/:id", "/reports/"+testReportID,
+		strings.NewReader(`{"status": "actioned"}`), h.UpdateReportStatus)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("resolve status = %d: %s", rec.Code, rec.Body)
+	}
+	var resolved models.Report
+	json.Unmarshal(rec.Body.Bytes(), &resolved)
+	if resolved.Status != models.ReportStatusActioned || resolved.ReviewedBy != "admin-1" || resolved.ReviewedAt == nil {
+		t.Errorf("resolved report = %+v", resolved)
+	}
+	if server.Exists(utils.CompanyKey(testCompanyID)) {
+		t.Error("hidden company is still cached")
+	}
+
+	// Actioned reports are final
+	mock.ExpectQuery(`FROM reports WHERE id = \$1`).WithArgs(testReportID).
+		WillReturnRows(sqlmock.NewRows(reportColumns).
+			AddRow(testReportID, "alice", models.ReportTargetCompany, testCompanyID, "spam", models.ReportStatusActioned, "admin-1", now, now, now))
+	rec = serve(t, "admin-1", models.RoleAdmin, http.MethodPut, "/reports/:id", "/reports/"+testReportID,
+		strings.NewReader(`{"status": "open"}`), h.UpdateReportStatus)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("reopen status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+}
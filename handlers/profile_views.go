@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// GetProfileViewers lists the distinct users who recently viewed a profile,
+// most recent first (?limit=&offset=). Only the profile's owner may see it.
+func (h *MatchmakerHandler) GetProfileViewers(c *gin.Context) {
+	userID := c.Param("user_id")
+	if c.GetString("user_id") != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": utils.T(c, "error.viewers_forbidden")})
+		return
+	}
+
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	viewers, err := models.GetProfileViewers(userID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": utils.T(c, "error.viewers_failed")})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"viewers": viewers,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// recordProfileView notes that viewerID looked at profileID, unless the
+// viewer is anonymous or browsing incognito. Failures are logged; they never
+// fail the profile request.
+func (h *MatchmakerHandler) recordProfileView(ctx context.Context, profileID, viewerID string) {
+	if viewerID == "" || viewerID == profileID || !models.DatabaseAvailable() {
+		return
+	}
+	if _, err := uuid.Parse(profileID); err != nil {
+		return
+	}
+
+	if viewer, err := h.matchmakerService.GetUserProfile(ctx, viewerID); err == nil && viewer.Incognito {
+		return
+	}
+
+	if err := models.RecordProfileView(profileID, viewerID); err != nil {
+		log.Printf("Failed to record profile view of %s by %s: %v", profileID, viewerID, err)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/connect-up/auth-service/internal/schema"
+)
+
+// bindValidated checks the request body against the named JSON Schema
+// before binding it to obj. On failure it responds 400 with message and,
+// when the body broke the schema, every violation, and returns false.
+func bindValidated(c *gin.Context, name string, obj interface{}, message string) bool {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return false
+	}
+
+	violations, err := schema.Validate(name, body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return false
+	}
+	if len(violations) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message, "violations": violations})
+		return false
+	}
+
+	if err := binding.JSON.BindBody(body, obj); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return false
+	}
+	return true
+}
@@ -5,13 +5,21 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/internal/piiscrub"
+	"github.com/connect-up/auth-service/internal/schema"
+	"github.com/connect-up/auth-service/internal/webhook"
 	"github.com/connect-up/auth-service/models"
 	"github.com/connect-up/auth-service/utils"
 )
@@ -19,16 +27,34 @@ import (
 // ShowcaseHandler handles showcase-related requests
 type ShowcaseHandler struct {
 	db          *sql.DB
-	kafkaWriter *kafka.Writer
-	redisClient *utils.RedisClient
+	publisher   *utils.AsyncPublisher
+	eventsTopic string
+	redisClient *redis.Client
+	webhooks    *webhook.Dispatcher
+	moderator   *contentfilter.Moderator
+	scrubber    *piiscrub.Scrubber
+
+	companyTTL      time.Duration
+	directoryTTL    time.Duration
+	directoryHits   atomic.Uint64
+	directoryMisses atomic.Uint64
+
+	// Real-time notifications, e.g. over WebSocket; nil when off
+	notifyUser UserNotifier
 }
 
 // NewShowcaseHandler creates a new showcase handler
-func NewShowcaseHandler(db *sql.DB, kafkaWriter *kafka.Writer, redisClient *utils.RedisClient) *ShowcaseHandler {
+func NewShowcaseHandler(db *sql.DB, publisher *utils.AsyncPublisher, redisClient *redis.Client, webhooks *webhook.Dispatcher, moderator *contentfilter.Moderator) *ShowcaseHandler {
 	return &ShowcaseHandler{
-		db:          db,
-		kafkaWriter: kafkaWriter,
-		redisClient: redisClient,
+		db:           db,
+		publisher:    publisher,
+		eventsTopic:  utils.GetEnv("KAFKA_ANALYTICS_TOPIC", "analytics_events"),
+		redisClient:  redisClient,
+		webhooks:     webhooks,
+		moderator:    moderator,
+		scrubber:     piiscrub.NewScrubberFromEnv(),
+		companyTTL:   utils.CacheTTLs().Company,
+		directoryTTL: utils.CacheTTLs().CompanyDirectory,
 	}
 }
 
@@ -46,8 +72,15 @@ func (h *ShowcaseHandler) CreateCompany(c *gin.Context) {
 	// In production, you should check for admin/investor role
 
 	var company models.Company
-	if err := c.ShouldBindJSON(&company); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindValidated(c, schema.Company, &company, "Invalid request body") {
+		return
+	}
+	if err := models.ValidateCompany(&company); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.moderateCompany(&company); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -67,9 +100,14 @@ func (h *ShowcaseHandler) CreateCompany(c *gin.Context) {
 		"company_id":   company.ID,
 		"company_name": company.Name,
 	})
+	h.recordCompanyActivity(company.ID, userID.(string), "company_created", map[string]interface{}{
+		"company_name": company.Name,
+	})
+	h.webhooks.Dispatch(models.WebhookEventCompanyCreated, company)
 
 	// Cache the company profile
 	h.cacheCompanyProfile(&company)
+	invalidateCompanyDirectory(h.redisClient)
 
 	c.JSON(http.StatusCreated, company)
 }
@@ -82,9 +120,35 @@ func (h *ShowcaseHandler) GetCompany(c *gin.Context) {
 		return
 	}
 
+	h.serveCompany(c, companyID)
+}
+
+// GetCompanyBySlug retrieves a company by its URL slug
+func (h *ShowcaseHandler) GetCompanyBySlug(c *gin.Context) {
+	slug := strings.ToLower(c.Param("slug"))
+
+	companyID, err := h.resolveCompanySlug(slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	h.serveCompany(c, companyID)
+}
+
+// serveCompany writes a company profile, from cache when possible
+func (h *ShowcaseHandler) serveCompany(c *gin.Context, companyID string) {
 	// Try to get from cache first
 	cachedCompany, err := h.getCachedCompanyProfile(companyID)
 	if err == nil && cachedCompany != nil {
+		h.trackVisitorEvent(c, "company_viewed", map[string]interface{}{
+			"company_id": cachedCompany.ID,
+		})
+		h.redactFinancials(c, cachedCompany)
 		c.JSON(http.StatusOK, cachedCompany)
 		return
 	}
@@ -104,12 +168,12 @@ func (h *ShowcaseHandler) GetCompany(c *gin.Context) {
 	h.cacheCompanyProfile(company)
 
 	// Track analytics
-	if userID, exists := c.Get("user_id"); exists {
-		h.publishAnalyticsEvent(userID.(string), "company_viewed", map[string]interface{}{
-			"company_id": company.ID,
-		})
-	}
+	h.trackVisitorEvent(c, "company_viewed", map[string]interface{}{
+		"company_id": company.ID,
+	})
 
+	// The cache keeps the full profile; only the response is redacted
+	h.redactFinancials(c, company)
 	c.JSON(http.StatusOK, company)
 }
 
@@ -141,26 +205,37 @@ func (h *ShowcaseHandler) UpdateCompany(c *gin.Context) {
 	}
 
 	var company models.Company
-	if err := c.ShouldBindJSON(&company); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindValidated(c, schema.Company, &company, "Invalid request body") {
+		return
+	}
+	if err := models.ValidateCompany(&company); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.moderateCompany(&company); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	company.ID = companyID
 	company.UpdatedAt = time.Now()
 
-	if err := models.UpdateCompany(&company); err != nil {
+	if err := models.UpdateCompany(&company, userID.(string)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update company"})
 		return
 	}
 
-	// Invalidate cache
+	// Invalidate cache; a regenerated slug must stop resolving to this company
 	h.invalidateCompanyCache(companyID)
+	if company.Slug != existingCompany.Slug && h.redisClient != nil {
+		h.redisClient.Del(context.Background(), utils.CompanySlugKey(existingCompany.Slug))
+	}
 
 	// Publish to Kafka
 	h.publishAnalyticsEvent(userID.(string), "company_updated", map[string]interface{}{
 		"company_id": company.ID,
 	})
+	h.recordCompanyActivity(company.ID, userID.(string), "company_updated", nil)
 
 	c.JSON(http.StatusOK, company)
 }
@@ -171,34 +246,42 @@ func (h *ShowcaseHandler) SearchCompanies(c *gin.Context) {
 	industry := c.Query("industry")
 	fundingStage := c.Query("funding_stage")
 
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20
+	// tags=climate,ai matches any tag; add tags_match=all to require every tag
+	var tags []string
+	if tagsParam := c.Query("tags"); tagsParam != "" {
+		tags = models.NormalizeCompanyTags(strings.Split(tagsParam, ","))
 	}
+	matchAllTags := c.Query("tags_match") == "all"
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	// mode=prefix is meant for autocomplete: name prefixes and near-miss typos;
+	// mode=fulltext matches words and ranks by relevance
+	mode := c.DefaultQuery("mode", models.CompanySearchSubstring)
+	switch mode {
+	case models.CompanySearchSubstring, models.CompanySearchPrefix, models.CompanySearchFullText:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be substring, prefix or fulltext"})
+		return
 	}
 
-	companies, err := models.SearchCompanies(query, industry, fundingStage, limit, offset)
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	companies, err := models.SearchCompanies(query, mode, industry, fundingStage, tags, matchAllTags, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search companies"})
 		return
 	}
 
 	// Track search analytics
-	if userID, exists := c.Get("user_id"); exists {
-		h.publishAnalyticsEvent(userID.(string), "company_search", map[string]interface{}{
-			"query":         query,
-			"industry":      industry,
-			"funding_stage": fundingStage,
-			"results_count": len(companies),
-		})
-	}
+	h.trackVisitorEvent(c, "company_search", map[string]interface{}{
+		"query":         query,
+		"mode":          mode,
+		"industry":      industry,
+		"funding_stage": fundingStage,
+		"tags":          tags,
+		"results_count": len(companies),
+	})
+
+	h.redactFinancials(c, companies...)
 
 	c.JSON(http.StatusOK, gin.H{
 		"companies": companies,
@@ -217,8 +300,7 @@ func (h *ShowcaseHandler) CreateInvestment(c *gin.Context) {
 	}
 
 	var investment models.Investment
-	if err := c.ShouldBindJSON(&investment); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+	if !bindValidated(c, schema.Investment, &investment, "Invalid request body") {
 		return
 	}
 
@@ -228,7 +310,7 @@ func (h *ShowcaseHandler) CreateInvestment(c *gin.Context) {
 	investment.UpdatedAt = time.Now()
 
 	// Create investment in database
-	if err := h.createInvestment(&investment); err != nil {
+	if err := models.CreateInvestment(&investment, userID.(string)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create investment"})
 		return
 	}
@@ -240,27 +322,183 @@ func (h *ShowcaseHandler) CreateInvestment(c *gin.Context) {
 		"amount":        investment.Amount,
 		"currency":      investment.Currency,
 	})
+	h.recordCompanyActivity(investment.CompanyID, userID.(string), "investment_created", map[string]interface{}{
+		"investment_id": investment.ID,
+		"round":         investment.Round,
+		"anonymous":     investment.Anonymous,
+	})
+	h.webhooks.Dispatch(models.WebhookEventInvestmentCreated, investment)
 
 	c.JSON(http.StatusCreated, investment)
 }
 
-// GetInvestments retrieves investments for a company
+// UpdateInvestmentStatus moves an investment to completed or cancelled (investor or admin only)
+func (h *ShowcaseHandler) UpdateInvestmentStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req struct {
+		Status string `json:"status" binding:"required,oneof=pending completed cancelled"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	investment, err := models.GetInvestmentByID(c.Param("id"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Investment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investment"})
+		return
+	}
+
+	if investment.InvestorID != userID.(string) && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to update this investment"})
+		return
+	}
+
+	if !models.CanTransitionInvestment(investment.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Cannot move investment from %s to %s", investment.Status, req.Status)})
+		return
+	}
+
+	if err := models.UpdateInvestmentStatus(investment, req.Status, userID.(string)); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusConflict, gin.H{"error": "Investment was modified concurrently"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update investment"})
+		return
+	}
+
+	h.publishAnalyticsEvent(userID.(string), "investment_status_changed", map[string]interface{}{
+		"investment_id": investment.ID,
+		"status":        investment.Status,
+	})
+
+	c.JSON(http.StatusOK, investment)
+}
+
+// TransferCompanyOwnership hands a company to another user (owner or admin only)
+func (h *ShowcaseHandler) TransferCompanyOwnership(c *gin.Context) {
+	companyID := c.Param("id")
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req struct {
+		NewOwnerID string `json:"new_owner_id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	company, err := models.GetCompanyByID(companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	if company.CreatedBy != userID.(string) && !utils.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not authorized to transfer this company"})
+		return
+	}
+
+	ownerExists, err := models.UserExists(req.NewOwnerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer company"})
+		return
+	}
+	if !ownerExists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "New owner not found"})
+		return
+	}
+
+	if err := models.TransferCompanyOwnership(companyID, req.NewOwnerID, userID.(string)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer company"})
+		return
+	}
+
+	h.invalidateCompanyCache(companyID)
+	h.recordCompanyActivity(companyID, userID.(string), "company_transferred", map[string]interface{}{
+		"new_owner_id": req.NewOwnerID,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Company ownership transferred"})
+}
+
+// GetInvestments retrieves investments for a company. Anonymous investors
+// are shown as undisclosed unless the caller is the company owner, the
+// investor themselves, or an admin.
 func (h *ShowcaseHandler) GetInvestments(c *gin.Context) {
-	companyID := c.Param("company_id")
+	companyID := c.Param("id")
 	if companyID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Company ID is required"})
 		return
 	}
 
-	investments, err := h.getInvestmentsByCompany(companyID)
+	company, ok := h.visibleCompany(c, companyID)
+	if !ok {
+		return
+	}
+
+	investments, err := h.getInvestmentsByCompany(company.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investments"})
 		return
 	}
 
+	redactAnonymousInvestors(investments, c.GetString("user_id"), company.CreatedBy, utils.IsAdmin(c))
+
 	c.JSON(http.StatusOK, gin.H{"investments": investments})
 }
 
+// ListInvestments lists investments with filters. Investors see their own
+// investments; admins see everyone's.
+func (h *ShowcaseHandler) ListInvestments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	filter, err := parseInvestmentFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !utils.IsAdmin(c) {
+		filter.InvestorID = userID.(string)
+	}
+
+	investments, err := models.FilterInvestments(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve investments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"investments": investments,
+		"total":       len(investments),
+		"limit":       filter.Limit,
+		"offset":      filter.Offset,
+	})
+}
+
 // GetUserInvestments retrieves investments made by a user
 func (h *ShowcaseHandler) GetUserInvestments(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -278,6 +516,75 @@ func (h *ShowcaseHandler) GetUserInvestments(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"investments": investments})
 }
 
+// GetPortfolio returns the authenticated investor's portfolio summary
+func (h *ShowcaseHandler) GetPortfolio(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	summary, err := models.GetPortfolioSummary(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute portfolio"})
+		return
+	}
+
+	// Normalize totals to the base currency, leaving unconvertible amounts in their own currency
+	summary.BaseCurrency = utils.BaseCurrency
+	for _, total := range summary.Totals {
+		converted, err := utils.Convert(total.Amount, total.Currency, utils.BaseCurrency)
+		if err != nil {
+			summary.Unconverted = append(summary.Unconverted, total)
+			continue
+		}
+		summary.TotalInBase += converted
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetCompanyActivity retrieves a company's activity feed
+func (h *ShowcaseHandler) GetCompanyActivity(c *gin.Context) {
+	companyID := c.Param("id")
+	if companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Company ID is required"})
+		return
+	}
+
+	company, err := models.GetCompanyByID(companyID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company"})
+		return
+	}
+
+	// Private companies are only visible to their creator
+	userID, _ := c.Get("user_id")
+	if !company.IsPublic && company.CreatedBy != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	limit, offset := utils.ParsePagination(c, 20, 0)
+
+	activities, err := models.GetCompanyActivity(companyID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve company activity"})
+		return
+	}
+	redactAnonymousActivity(activities, c.GetString("user_id"), company.CreatedBy, utils.IsAdmin(c))
+
+	c.JSON(http.StatusOK, gin.H{
+		"activities": activities,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
 // Analytics tracking
 func (h *ShowcaseHandler) TrackEvent(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -309,22 +616,9 @@ func (h *ShowcaseHandler) TrackEvent(c *gin.Context) {
 
 // Helper methods
 
-func (h *ShowcaseHandler) createInvestment(investment *models.Investment) error {
-	query := `
-		INSERT INTO investments (company_id, investor_id, amount, currency, investment_type, round, date, status, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at, updated_at
-	`
-
-	return h.db.QueryRow(query,
-		investment.CompanyID, investment.InvestorID, investment.Amount, investment.Currency,
-		investment.InvestmentType, investment.Round, investment.Date, investment.Status, investment.Notes,
-	).Scan(&investment.ID, &investment.CreatedAt, &investment.UpdatedAt)
-}
-
 func (h *ShowcaseHandler) getInvestmentsByCompany(companyID string) ([]models.Investment, error) {
 	query := `
-		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, created_at, updated_at
+		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, anonymous, created_at, updated_at
 		FROM investments
 		WHERE company_id = $1
 		ORDER BY date DESC
@@ -342,7 +636,7 @@ func (h *ShowcaseHandler) getInvestmentsByCompany(companyID string) ([]models.In
 		err := rows.Scan(
 			&investment.ID, &investment.CompanyID, &investment.InvestorID, &investment.Amount,
 			&investment.Currency, &investment.InvestmentType, &investment.Round, &investment.Date,
-			&investment.Status, &investment.Notes, &investment.CreatedAt, &investment.UpdatedAt,
+			&investment.Status, &investment.Notes, &investment.Anonymous, &investment.CreatedAt, &investment.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -355,7 +649,7 @@ func (h *ShowcaseHandler) getInvestmentsByCompany(companyID string) ([]models.In
 
 func (h *ShowcaseHandler) getInvestmentsByUser(userID string) ([]models.Investment, error) {
 	query := `
-		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, created_at, updated_at
+		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, anonymous, created_at, updated_at
 		FROM investments
 		WHERE investor_id = $1
 		ORDER BY date DESC
@@ -373,7 +667,7 @@ func (h *ShowcaseHandler) getInvestmentsByUser(userID string) ([]models.Investme
 		err := rows.Scan(
 			&investment.ID, &investment.CompanyID, &investment.InvestorID, &investment.Amount,
 			&investment.Currency, &investment.InvestmentType, &investment.Round, &investment.Date,
-			&investment.Status, &investment.Notes, &investment.CreatedAt, &investment.UpdatedAt,
+			&investment.Status, &investment.Notes, &investment.Anonymous, &investment.CreatedAt, &investment.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -385,29 +679,77 @@ func (h *ShowcaseHandler) getInvestmentsByUser(userID string) ([]models.Investme
 }
 
 func (h *ShowcaseHandler) publishAnalyticsEvent(userID, eventType string, eventData map[string]interface{}) {
-	if h.kafkaWriter == nil {
+	h.publishVisitorEvent(userID, "", eventType, eventData)
+}
+
+// trackVisitorEvent publishes an event for whoever made the request: the
+// signed-in user, or the anonymous id set by utils.AnonymousIDMiddleware
+func (h *ShowcaseHandler) trackVisitorEvent(c *gin.Context, eventType string, eventData map[string]interface{}) {
+	userID := c.GetString("user_id")
+	anonID := utils.AnonymousID(c)
+	if userID == "" && anonID == "" {
+		return
+	}
+	h.publishVisitorEvent(userID, anonID, eventType, eventData)
+}
+
+// publishVisitorEvent publishes an analytics event for a user or, when userID
+// is empty, an anonymous visitor; user_id is null for anonymous events.
+// Personal data in eventData is scrubbed first.
+func (h *ShowcaseHandler) publishVisitorEvent(userID, anonID, eventType string, eventData map[string]interface{}) {
+	if h.publisher == nil {
 		return
 	}
 
 	event := map[string]interface{}{
-		"user_id":    userID,
+		"user_id":    nil,
 		"event_type": eventType,
-		"event_data": eventData,
+		"event_data": h.scrubber.Scrub(eventData),
 		"timestamp":  time.Now().Unix(),
 	}
+	key := userID
+	if userID != "" {
+		event["user_id"] = userID
+	} else {
+		event["anon_id"] = anonID
+		key = anonID
+	}
 
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		return
 	}
 
-	h.kafkaWriter.WriteMessages(context.Background(), kafka.Message{
-		Topic: "analytics_events",
-		Key:   []byte(userID),
+	h.publisher.Publish(kafka.Message{
+		Topic: h.eventsTopic,
+		Key:   []byte(key),
 		Value: eventJSON,
 	})
 }
 
+func (h *ShowcaseHandler) recordCompanyActivity(companyID, actorID, action string, details map[string]interface{}) {
+	activity := models.CompanyActivity{
+		CompanyID: companyID,
+		ActorID:   actorID,
+		Action:    action,
+		Details:   details,
+	}
+
+	if err := models.RecordCompanyActivity(&activity); err != nil {
+		log.Printf("Failed to record company activity: %v", err)
+	}
+}
+
+// moderateCompany runs a company's free-text fields through the content filter
+func (h *ShowcaseHandler) moderateCompany(company *models.Company) error {
+	var err error
+	if company.Name, err = h.moderator.Apply(company.Name); err != nil {
+		return err
+	}
+	company.Description, err = h.moderator.Apply(company.Description)
+	return err
+}
+
 func (h *ShowcaseHandler) cacheCompanyProfile(company *models.Company) {
 	if h.redisClient == nil {
 		return
@@ -418,8 +760,7 @@ func (h *ShowcaseHandler) cacheCompanyProfile(company *models.Company) {
 		return
 	}
 
-	// Cache for 1 hour
-	h.redisClient.Set(fmt.Sprintf("company:%s", company.ID), string(companyJSON), time.Hour)
+	h.redisClient.Set(context.Background(), utils.CompanyKey(company.ID), string(companyJSON), h.companyTTL)
 }
 
 func (h *ShowcaseHandler) getCachedCompanyProfile(companyID string) (*models.Company, error) {
@@ -427,7 +768,7 @@ func (h *ShowcaseHandler) getCachedCompanyProfile(companyID string) (*models.Com
 		return nil, fmt.Errorf("redis not available")
 	}
 
-	companyJSON, err := h.redisClient.Get(fmt.Sprintf("company:%s", companyID))
+	companyJSON, err := h.redisClient.Get(context.Background(), utils.CompanyKey(companyID)).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -440,10 +781,83 @@ func (h *ShowcaseHandler) getCachedCompanyProfile(companyID string) (*models.Com
 	return &company, nil
 }
 
+// resolveCompanySlug returns the id of the company using a slug, caching the
+// lookup for as long as company profiles are cached
+func (h *ShowcaseHandler) resolveCompanySlug(slug string) (string, error) {
+	if h.redisClient != nil {
+		if companyID, err := h.redisClient.Get(context.Background(), utils.CompanySlugKey(slug)).Result(); err == nil {
+			return companyID, nil
+		}
+	}
+
+	companyID, err := models.GetCompanyIDBySlug(slug)
+	if err != nil {
+		return "", err
+	}
+
+	if h.redisClient != nil {
+		h.redisClient.Set(context.Background(), utils.CompanySlugKey(slug), companyID, h.companyTTL)
+	}
+	return companyID, nil
+}
+
 func (h *ShowcaseHandler) invalidateCompanyCache(companyID string) {
 	if h.redisClient == nil {
 		return
 	}
 
-	h.redisClient.Del(fmt.Sprintf("company:%s", companyID))
+	h.redisClient.Del(context.Background(), utils.CompanyKey(companyID))
+	invalidateCompanyDirectory(h.redisClient)
+}
+
+// parseInvestmentFilter validates the investment listing query parameters
+func parseInvestmentFilter(c *gin.Context) (models.InvestmentFilter, error) {
+	filter := models.InvestmentFilter{
+		Round:          c.Query("round"),
+		Status:         c.Query("status"),
+		InvestmentType: c.Query("investment_type"),
+		Limit:          20,
+	}
+
+	switch filter.Status {
+	case "", models.InvestmentStatusPending, models.InvestmentStatusCompleted, models.InvestmentStatusCancelled:
+	default:
+		return filter, fmt.Errorf("status must be pending, completed, or cancelled")
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **time.Time
+	}{{"date_from", &filter.DateFrom}, {"date_to", &filter.DateTo}} {
+		if raw := c.Query(param.name); raw != "" {
+			date, err := time.Parse("2006-01-02", raw)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be a date in YYYY-MM-DD format", param.name)
+			}
+			*param.value = &date
+		}
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateFrom.After(*filter.DateTo) {
+		return filter, fmt.Errorf("date_from must not be after date_to")
+	}
+
+	for _, param := range []struct {
+		name  string
+		value **float64
+	}{{"min_amount", &filter.MinAmount}, {"max_amount", &filter.MaxAmount}} {
+		if raw := c.Query(param.name); raw != "" {
+			amount, err := strconv.ParseFloat(raw, 64)
+			if err != nil || amount < 0 {
+				return filter, fmt.Errorf("%s must be a non-negative number", param.name)
+			}
+			*param.value = &amount
+		}
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, fmt.Errorf("min_amount must not exceed max_amount")
+	}
+
+	filter.Limit, filter.Offset = utils.ParsePagination(c, 20, 0)
+
+	return filter, nil
 }
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+var investmentColumns = []string{"id", "company_id", "investor_id", "amount", "currency", "investment_type",
+	"round", "date", "status", "notes", "anonymous", "created_at", "updated_at"}
+
+func TestListInvestmentsCombinesFilters(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`WHERE 1 = 1 AND investor_id = \$1 AND round = \$2 AND status = \$3 AND date >= \$4 AND amount >= \$5 AND amount <= \$6 ORDER BY date DESC, id LIMIT \$7 OFFSET \$8`).
+		WithArgs("investor-1", "seed", models.InvestmentStatusCompleted, from, 1000.0, 50000.0, 5, 0).
+		WillReturnRows(sqlmock.NewRows(investmentColumns))
+
+	// A non-admin's listing is limited to their own investments whatever they ask for
+	rec := serve(t, "investor-1", models.RoleUser, http.MethodGet, "/investments",
+		"/investments?round=seed&status=completed&date_from=2025-01-01&min_amount=1000&max_amount=50000&limit=5", nil, h.ListInvestments)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	for _, query := range []string{
+		"min_amount=5000&max_amount=100",
+		"date_from=2025-06-01&date_to=2025-01-01",
+		"status=lost",
+		"date_from=yesterday",
+	} {
+		rec := serve(t, "investor-1", models.RoleUser, http.MethodGet, "/investments", "/investments?"+query, nil, h.ListInvestments)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAnonymousCompanyViewPublishesEvent(t *testing.T) {
+	mock := newTestDB(t)
+	publisher, flush := newTestPublisher(t)
+	h := &ShowcaseHandler{publisher: publisher, eventsTopic: "analytics_events"}
+
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+
+	router := gin.New()
+	router.GET("/companies/:id", utils.OptionalAuthMiddleware(), utils.AnonymousIDMiddleware(), h.GetCompany)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/companies/c1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+
+	var anonID string
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == utils.AnonymousIDCookie {
+			anonID = cookie.Value
+		}
+	}
+	if anonID == "" {
+		t.Fatal("no anonymous id cookie was set")
+	}
+
+	messages := flush()
+	if len(messages) != 1 {
+		t.Fatalf("published %d events, want 1", len(messages))
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal(messages[0].Value, &event); err != nil {
+		t.Fatalf("unmarshal event: %v", err)
+	}
+	if event["event_type"] != "company_viewed" || event["user_id"] != nil || event["anon_id"] != anonID {
+		t.Errorf("event = %v, want company_viewed with a null user_id and anon_id %s", event, anonID)
+	}
+	if string(messages[0].Key) != anonID {
+		t.Errorf("message key = %q, want the anonymous id", messages[0].Key)
+	}
+}
+
+func TestCompanyCacheUsesConfiguredTTL(t *testing.T) {
+	server := newTestRedis(t)
+	t.Setenv("COMPANY_CACHE_TTL", "17m")
+	previous := utils.CacheTTLs()
+	utils.SetCacheTTLs(utils.LoadCacheTTLConfig())
+	t.Cleanup(func() { utils.SetCacheTTLs(previous) })
+
+	h := NewShowcaseHandler(nil, nil, utils.RedisClient, nil, nil)
+	h.cacheCompanyProfile(&models.Company{ID: "c1", Name: "Rockets"})
+
+	if ttl := server.TTL(utils.CompanyKey("c1")); ttl != 17*time.Minute {
+		t.Errorf("company cache TTL = %v, want 17m", ttl)
+	}
+}
+
+func TestGetCompanyBySlugCachesLookup(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{redisClient: utils.RedisClient, companyTTL: time.Minute}
+
+	// The first lookup resolves the slug and loads the company; the second
+	// is served from the cache without touching the database
+	mock.ExpectQuery(`SELECT id FROM companies WHERE slug = \$1 AND deleted_at IS NULL`).WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("c1"))
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+
+	for i := 0; i < 2; i++ {
+		rec := serve(t, "owner-1", models.RoleUser, http.MethodGet, "/companies/slug/:slug", "/companies/slug/ACME", nil, h.GetCompanyBySlug)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("lookup %d: status = %d: %s", i+1, rec.Code, rec.Body)
+		}
+		var company models.Company
+		if err := json.Unmarshal(rec.Body.Bytes(), &company); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if company.ID != "c1" || company.Slug != "acme" {
+			t.Errorf("lookup %d: company %s with slug %q, want c1 with acme", i+1, company.ID, company.Slug)
+		}
+	}
+
+	mock.ExpectQuery(`SELECT id FROM companies WHERE slug = \$1`).WithArgs("nobody").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	rec := serve(t, "owner-1", models.RoleUser, http.MethodGet, "/companies/slug/:slug", "/companies/slug/nobody", nil, h.GetCompanyBySlug)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown slug: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestCompanyReadBackCanBeUpdated(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{moderator: contentfilter.NewModerator(nil, contentfilter.PolicyOff)}
+
+	// A company with no founding year or tags, as most are on creation
+	now := time.Now()
+	row := func() *sqlmock.Rows {
+		return sqlmock.NewRows(companyColumns).AddRow("c1", "Acme", "", "", 0, "",
+			"", "", 0, 0.0, "", 0.0, 0.0, now, now, "owner-1", true, "{}", "acme")
+	}
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").WillReturnRows(row())
+	rec := serve(t, "owner-1", models.RoleUser, http.MethodGet, "/companies/:id", "/companies/c1", nil, h.GetCompany)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get status = %d: %s", rec.Code, rec.Body)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `"founded_year":0`) {
+		t.Fatalf("body = %s, want founded_year 0", body)
+	}
+
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").WillReturnRows(row())
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs("c1").WillReturnRows(row())
+	// Nothing changed, so nothing is audited
+	mock.ExpectExec(`UPDATE companies SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`INSERT INTO company_activities`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("activity-1", now))
+
+	rec = serve(t, "owner-1", models.RoleUser, http.MethodPut, "/companies/:id", "/companies/c1", strings.NewReader(body), h.UpdateCompany)
+	if rec.Code != http.StatusOK {
+		t.Errorf("putting back the GET body: status = %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestCompanyActivityServesPagesUpToConfiguredCap(t *testing.T) {
+	mock := newTestDB(t)
+	h := &ShowcaseHandler{}
+	utils.SetMaxPageSize(250)
+	t.Cleanup(func() { utils.SetMaxPageSize(utils.DefaultMaxPageSize) })
+
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c1").
+		WillReturnRows(companyRow("c1", "Rockets"))
+	mock.ExpectQuery(`FROM company_activities`).WithArgs("c1", 200, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "company_id", "actor_id", "action", "details", "created_at"}))
+
+	rec := serve(t, "owner-1", models.RoleUser, http.MethodGet, "/companies/:id/activity", "/companies/c1/activity?limit=200", nil, h.GetCompanyActivity)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Limit int `json:"limit"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Limit != 200 {
+		t.Errorf("limit = %d, want 200 with PAGINATION_MAX_LIMIT at 250", resp.Limit)
+	}
+}
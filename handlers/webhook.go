@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/webhook"
+	"github.com/connect-up/auth-service/models"
+)
+
+// WebhookHandler handles webhook subscription management
+type WebhookHandler struct{}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{}
+}
+
+// CreateWebhook registers a webhook. The signing secret is only returned in this response.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	hook := models.Webhook{
+		URL:       req.URL,
+		Events:    req.Events,
+		Secret:    secret,
+		CreatedBy: userID.(string),
+	}
+
+	if err := models.CreateWebhook(&hook); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, hook)
+}
+
+// ListWebhooks lists registered webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := models.ListWebhooks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks})
+}
+
+// DeleteWebhook removes a webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := models.DeleteWebhook(c.Param("id")); err != nil {
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted"})
+}
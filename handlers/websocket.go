@@ -1,105 +1,255 @@
 package handlers
 
 import (
+	"compress/flate"
 	"context"
+	"database/sql"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/connect-up/auth-service/internal/contentfilter"
+	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/internal/push"
+	"github.com/connect-up/auth-service/internal/tracing"
 	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // In production, implement proper origin checking
-	},
+// newUpgrader builds the WebSocket upgrader, negotiating permessage-deflate when enabled
+func newUpgrader(enableCompression bool) websocket.Upgrader {
+	return websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return true // In production, implement proper origin checking
+		},
+		EnableCompression: enableCompression,
+	}
 }
 
 // WebSocketConnection represents a WebSocket connection
 type WebSocketConnection struct {
-	conn   *websocket.Conn
-	userID string
-	send   chan []byte
-	mu     sync.Mutex
+	conn         *websocket.Conn
+	userID       string
+	sessionID    string
+	connectionID string
+	send         chan []byte
+	mu           sync.Mutex
+
+	// What gives way when send is full; see enqueue
+	overflowPolicy string
+	sendMu         sync.Mutex
+	closeOnce      sync.Once
+
+	// Messages smaller than this are sent uncompressed
+	compressionMinSize int
+
+	// Queued messages are coalesced into frames of up to this many bytes;
+	// batchBuf is reused between frames and only touched by writePump
+	writeBatchSize int
+	batchBuf       []byte
+
+	// lastActive is the Unix time of the last frame received from the client
+	lastActive atomic.Int64
+
+	// Feed topics the client subscribed to; guarded by mu
+	subscriptions map[string]bool
 }
 
 // WebSocketHandler handles WebSocket connections and messaging
 type WebSocketHandler struct {
 	connections map[string]*WebSocketConnection
 	mu          sync.RWMutex
-	kafkaWriter *kafka.Writer
+	publisher   *utils.AsyncPublisher
+	chatTopic   string
 	kafkaReader *kafka.Reader
-	db          *models.DB
+	db          *sql.DB
+
+	matchmakerService *matchmaker.Service
+
+	// Cross-instance delivery via Redis pub/sub
+	redisClient *redis.Client
+	instanceID  string
+
+	typingMu       sync.Mutex
+	typingStates   map[string]typingState
+	typingThrottle time.Duration
+
+	// Chat messages already delivered, kept this long so the copy consumed
+	// from Kafka is skipped; held in Redis when there is a client, else here
+	chatDedupeTTL  time.Duration
+	deliveredMu    sync.Mutex
+	deliveredChats map[string]time.Time
+
+	upgrader           websocket.Upgrader
+	compressionEnabled bool
+	compressionLevel   int
+	compressionMinSize int
+	writeBatchSize     int
+
+	sendBufferSize     int
+	sendOverflowPolicy string
+
+	shuttingDown   bool
+	reconnectDelay time.Duration
+	shutdownGrace  time.Duration
+
+	reconnectTokenTTL time.Duration
+
+	moderator *contentfilter.Moderator
+
+	messagingPolicy string
+
+	// Push notifications for chat messages to offline users; nil when off
+	pushDigester *push.Digester
+
+	// Consumes match-created events for the match feed; nil when off
+	matchFeedReader *kafka.Reader
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(kafkaWriter *kafka.Writer, kafkaReader *kafka.Reader, db *models.DB) *WebSocketHandler {
+func NewWebSocketHandler(publisher *utils.AsyncPublisher, kafkaReader *kafka.Reader, db *sql.DB, matchmakerService *matchmaker.Service, redisClient *redis.Client, moderator *contentfilter.Moderator) *WebSocketHandler {
+	compressionEnabled := utils.GetEnvBool("WS_COMPRESSION_ENABLED", true)
+
 	handler := &WebSocketHandler{
-		connections: make(map[string]*WebSocketConnection),
-		kafkaWriter: kafkaWriter,
-		kafkaReader: kafkaReader,
-		db:          db,
+		connections:        make(map[string]*WebSocketConnection),
+		publisher:          publisher,
+		chatTopic:          utils.GetEnv("KAFKA_CHAT_TOPIC", "chat-messages"),
+		kafkaReader:        kafkaReader,
+		db:                 db,
+		matchmakerService:  matchmakerService,
+		redisClient:        redisClient,
+		instanceID:         uuid.New().String(),
+		typingStates:       make(map[string]typingState),
+		typingThrottle:     utils.GetEnvDuration("WS_TYPING_THROTTLE", time.Second),
+		chatDedupeTTL:      utils.GetEnvDuration("WS_CHAT_DEDUPE_TTL", 10*time.Minute),
+		deliveredChats:     make(map[string]time.Time),
+		upgrader:           newUpgrader(compressionEnabled),
+		compressionEnabled: compressionEnabled,
+		compressionLevel:   utils.GetEnvInt("WS_COMPRESSION_LEVEL", flate.BestSpeed),
+		compressionMinSize: utils.GetEnvInt("WS_COMPRESSION_MIN_SIZE", 512),
+		writeBatchSize:     utils.GetEnvInt("WS_WRITE_BATCH_SIZE", 16*1024),
+		sendBufferSize:     max(utils.GetEnvInt("WS_SEND_BUFFER_SIZE", 256), 1),
+		sendOverflowPolicy: parseSendOverflowPolicy(utils.GetEnv("WS_SEND_OVERFLOW_POLICY", SendOverflowClose)),
+		reconnectDelay:     utils.GetEnvDuration("WS_RECONNECT_DELAY", 5*time.Second),
+		shutdownGrace:      utils.GetEnvDuration("WS_SHUTDOWN_GRACE_PERIOD", 2*time.Second),
+		reconnectTokenTTL:  utils.GetEnvDuration("WS_RECONNECT_TOKEN_TTL", 2*time.Minute),
+		moderator:          moderator,
+		messagingPolicy:    parseMessagingPolicy(utils.GetEnv("WS_MESSAGING_POLICY", MessagingPolicyOpen)),
 	}
 
 	// Start Kafka consumer for chat messages
 	go handler.startKafkaConsumer()
 
+	// Start Redis relay for users connected to other instances
+	go handler.startRedisRelay()
+
+	// Keep this instance's users in the shared presence set
+	go handler.startPresenceRefresh()
+
 	return handler
 }
 
-// HandleWebSocket handles WebSocket connections
+// HandleWebSocket handles WebSocket connections. Initial connections require a
+// JWT; a client reconnecting may instead present the reconnection token it
+// was issued on its previous connection via the reconnect_token query parameter.
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	// Get user ID from context (set by auth middleware)
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	userID := c.GetString("user_id")
+	sessionID := c.GetString("session_id")
+	var resumedFrom string
+
+	if userID == "" {
+		state, err := h.redeemReconnectToken(c.Request.Context(), c.Query("reconnect_token"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+		userID = state.UserID
+		sessionID = state.SessionID
+		resumedFrom = state.ConnectionID
+	}
+
+	// Refuse new handshakes while draining for shutdown
+	h.mu.RLock()
+	shuttingDown := h.shuttingDown
+	h.mu.RUnlock()
+	if shuttingDown {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
 		return
 	}
 
 	// Upgrade HTTP connection to WebSocket
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
 		return
 	}
 
+	if h.compressionEnabled {
+		if err := conn.SetCompressionLevel(h.compressionLevel); err != nil {
+			log.Printf("Invalid WebSocket compression level: %v", err)
+		}
+	}
+
 	// Create WebSocket connection
 	wsConn := &WebSocketConnection{
-		conn:   conn,
-		userID: userID.(string),
-		send:   make(chan []byte, 256),
+		conn:               conn,
+		userID:             userID,
+		sessionID:          sessionID,
+		connectionID:       uuid.New().String(),
+		send:               make(chan []byte, h.sendBufferSize),
+		overflowPolicy:     h.sendOverflowPolicy,
+		compressionMinSize: h.compressionMinSize,
+		writeBatchSize:     h.writeBatchSize,
 	}
+	wsConn.lastActive.Store(time.Now().Unix())
 
 	// Register connection
 	h.mu.Lock()
-	h.connections[userID.(string)] = wsConn
+	h.connections[userID] = wsConn
 	h.mu.Unlock()
 
+	if err := utils.MarkOnline(c.Request.Context(), userID); err != nil {
+		log.Printf("Failed to mark user %s online: %v", userID, err)
+	}
+
 	// Start goroutines for reading and writing
 	go wsConn.writePump()
 	go wsConn.readPump(h)
 
 	// Send welcome message
 	welcomeMsg := map[string]interface{}{
-		"type":      "connection_established",
-		"user_id":   userID.(string),
-		"timestamp": time.Now().Unix(),
+		"type":          "connection_established",
+		"user_id":       userID,
+		"connection_id": wsConn.connectionID,
+		"timestamp":     time.Now().Unix(),
+	}
+	if resumedFrom != "" {
+		welcomeMsg["type"] = "connection_restored"
+		welcomeMsg["previous_connection_id"] = resumedFrom
+	}
+	if token := h.issueReconnectToken(wsConn); token != "" {
+		welcomeMsg["reconnect_token"] = token
+		welcomeMsg["reconnect_token_expires_in"] = h.reconnectTokenExpiry()
 	}
 
 	welcomeJSON, _ := json.Marshal(welcomeMsg)
-	wsConn.send <- welcomeJSON
+	wsConn.enqueue(welcomeJSON)
 }
 
 // readPump pumps messages from the WebSocket connection to the hub
 func (c *WebSocketConnection) readPump(h *WebSocketHandler) {
 	defer func() {
-		h.unregisterConnection(c.userID)
+		h.unregisterConnection(c)
 		c.conn.Close()
 	}()
 
@@ -118,6 +268,7 @@ func (c *WebSocketConnection) readPump(h *WebSocketHandler) {
 			}
 			break
 		}
+		c.lastActive.Store(time.Now().Unix())
 
 		// Parse message
 		var msgData map[string]interface{}
@@ -134,11 +285,19 @@ func (c *WebSocketConnection) readPump(h *WebSocketHandler) {
 
 		switch msgType {
 		case "chat_message":
-			h.handleChatMessage(c.userID, msgData)
+			ctx, span := tracing.Tracer().Start(context.Background(), "websocket.chat_message")
+			h.handleChatMessage(ctx, c.userID, msgData)
+			span.End()
 		case "typing":
 			h.handleTypingEvent(c.userID, msgData)
 		case "read_receipt":
 			h.handleReadReceipt(c.userID, msgData)
+		case "reaction":
+			h.handleReaction(c.userID, msgData)
+		case "subscribe":
+			h.handleSubscription(c, msgData, true)
+		case "unsubscribe":
+			h.handleSubscription(c, msgData, false)
 		case "ping":
 			// Send pong response
 			pongMsg := map[string]interface{}{
@@ -146,7 +305,7 @@ func (c *WebSocketConnection) readPump(h *WebSocketHandler) {
 				"timestamp": time.Now().Unix(),
 			}
 			pongJSON, _ := json.Marshal(pongMsg)
-			c.send <- pongJSON
+			c.enqueue(pongJSON)
 		}
 	}
 }
@@ -168,14 +327,18 @@ func (c *WebSocketConnection) writePump() {
 				return
 			}
 
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
-			if err := w.Close(); err != nil {
-				return
+			// A message drained while batching that didn't fit is carried
+			// into the next frame so ordering is preserved
+			for message != nil {
+				next, closed, err := c.writeBatch(message)
+				if err != nil {
+					return
+				}
+				if closed {
+					c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+					return
+				}
+				message = next
 			}
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
@@ -186,8 +349,59 @@ func (c *WebSocketConnection) writePump() {
 	}
 }
 
+// writeBatch writes message as one text frame, coalescing any messages
+// already queued on the send channel while they fit within writeBatchSize.
+// Coalesced messages are separated by newlines. It returns a drained message
+// that didn't fit, and whether the send channel was closed while draining.
+func (c *WebSocketConnection) writeBatch(message []byte) (next []byte, closed bool, err error) {
+	frame := message
+	if len(message) < c.writeBatchSize {
+		// Queued messages may be shared with other connections, so batches
+		// are built in a private buffer rather than appended in place
+		batched := false
+	drain:
+		for {
+			select {
+			case queued, ok := <-c.send:
+				if !ok {
+					closed = true
+					break drain
+				}
+				if len(frame)+1+len(queued) > c.writeBatchSize {
+					next = queued
+					break drain
+				}
+				if !batched {
+					c.batchBuf = append(c.batchBuf[:0], message...)
+					batched = true
+				}
+				c.batchBuf = append(c.batchBuf, '\n')
+				c.batchBuf = append(c.batchBuf, queued...)
+				frame = c.batchBuf
+			default:
+				break drain
+			}
+		}
+	}
+
+	// Only compress frames large enough to benefit; this is a no-op
+	// unless the client negotiated permessage-deflate
+	c.conn.EnableWriteCompression(len(frame) >= c.compressionMinSize)
+
+	w, err := c.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return nil, false, err
+	}
+	w.Write(frame)
+
+	if err := w.Close(); err != nil {
+		return nil, false, err
+	}
+	return next, closed, nil
+}
+
 // handleChatMessage handles incoming chat messages
-func (h *WebSocketHandler) handleChatMessage(senderID string, msgData map[string]interface{}) {
+func (h *WebSocketHandler) handleChatMessage(ctx context.Context, senderID string, msgData map[string]interface{}) {
 	receiverID, exists := msgData["receiver_id"].(string)
 	if !exists {
 		return
@@ -198,6 +412,26 @@ func (h *WebSocketHandler) handleChatMessage(senderID string, msgData map[string
 		return
 	}
 
+	if !h.canMessage(ctx, senderID, receiverID) {
+		h.sendToUser(senderID, map[string]interface{}{
+			"type":        "message_rejected",
+			"receiver_id": receiverID,
+			"reason":      "messaging is limited to accepted matches",
+			"timestamp":   time.Now().Unix(),
+		})
+		return
+	}
+
+	content, err := h.moderator.Apply(content)
+	if err != nil {
+		h.sendToUser(senderID, map[string]interface{}{
+			"type":      "message_rejected",
+			"reason":    err.Error(),
+			"timestamp": time.Now().Unix(),
+		})
+		return
+	}
+
 	// Create message object
 	message := models.Message{
 		SenderID:    senderID,
@@ -210,25 +444,28 @@ func (h *WebSocketHandler) handleChatMessage(senderID string, msgData map[string
 	}
 
 	// Save message to database
-	if err := h.saveMessage(&message); err != nil {
+	if err := h.saveMessage(ctx, &message); err != nil {
 		log.Printf("Failed to save message: %v", err)
 		return
 	}
 
-	// Publish to Kafka
-	h.publishChatMessage(&message)
+	// Deliver to a receiver connected here before publishing, so the copy
+	// consumed from Kafka is recognised as delivered
+	delivered := h.deliverChatMessage(ctx, &message)
 
-	// Send to receiver if online
-	h.sendToUser(receiverID, map[string]interface{}{
-		"type":      "chat_message",
-		"message":   message,
-		"timestamp": time.Now().Unix(),
-	})
+	// Publish to Kafka, which reaches receivers connected to other instances
+	h.publishChatMessage(ctx, &message)
+
+	// Let a receiver who isn't connected anywhere know by push
+	if !delivered {
+		h.notifyOffline(ctx, &message)
+	}
 
 	// Send confirmation to sender
 	h.sendToUser(senderID, map[string]interface{}{
 		"type":       "message_sent",
 		"message_id": message.ID,
+		"seq":        message.Seq,
 		"timestamp":  time.Now().Unix(),
 	})
 }
@@ -245,8 +482,12 @@ func (h *WebSocketHandler) handleTypingEvent(userID string, msgData map[string]i
 		return
 	}
 
-	// Send typing indicator to receiver
-	h.sendToUser(receiverID, map[string]interface{}{
+	if !h.shouldForwardTyping(userID, receiverID, isTyping) {
+		return
+	}
+
+	// Send typing indicator to receiver, wherever they are connected
+	h.routeToUser(receiverID, map[string]interface{}{
 		"type":      "typing_indicator",
 		"user_id":   userID,
 		"is_typing": isTyping,
@@ -276,6 +517,57 @@ func (h *WebSocketHandler) handleReadReceipt(userID string, msgData map[string]i
 	})
 }
 
+// handleReaction adds or removes (with "remove": true) an emoji reaction and
+// tells the other participant. Only the message's sender and receiver may react.
+func (h *WebSocketHandler) handleReaction(userID string, msgData map[string]interface{}) {
+	messageID, exists := msgData["message_id"].(string)
+	if !exists {
+		return
+	}
+	if _, err := uuid.Parse(messageID); err != nil {
+		return
+	}
+
+	emoji, exists := msgData["emoji"].(string)
+	if !exists {
+		return
+	}
+	remove, _ := msgData["remove"].(bool)
+
+	var peerID string
+	var err error
+	if remove {
+		peerID, err = models.RemoveMessageReaction(messageID, userID, emoji)
+	} else {
+		peerID, err = models.AddMessageReaction(messageID, userID, emoji)
+	}
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			// Unknown message, or nothing to remove
+		case models.ErrNotConversationParticipant, models.ErrInvalidReaction:
+			h.sendToUser(userID, map[string]interface{}{
+				"type":       "reaction_rejected",
+				"message_id": messageID,
+				"reason":     err.Error(),
+				"timestamp":  time.Now().Unix(),
+			})
+		default:
+			log.Printf("Failed to update reaction on message %s: %v", messageID, err)
+		}
+		return
+	}
+
+	h.routeToUser(peerID, map[string]interface{}{
+		"type":       "reaction",
+		"message_id": messageID,
+		"user_id":    userID,
+		"emoji":      emoji,
+		"removed":    remove,
+		"timestamp":  time.Now().Unix(),
+	})
+}
+
 // startKafkaConsumer starts consuming chat messages from Kafka
 func (h *WebSocketHandler) startKafkaConsumer() {
 	for {
@@ -286,31 +578,41 @@ func (h *WebSocketHandler) startKafkaConsumer() {
 			continue
 		}
 
-		// Parse message
-		var msgData map[string]interface{}
-		if err := json.Unmarshal(m.Value, &msgData); err != nil {
-			log.Printf("Failed to parse Kafka message: %v", err)
-			continue
-		}
+		h.handleKafkaMessage(ctx, m)
+	}
+}
 
-		// Handle different message types
-		msgType, exists := msgData["type"].(string)
-		if !exists {
-			continue
-		}
+// handleKafkaMessage dispatches one consumed message under a consumer span
+// that continues the producer's trace
+func (h *WebSocketHandler) handleKafkaMessage(ctx context.Context, m kafka.Message) {
+	ctx, span := tracing.StartConsume(ctx, &m)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	// Parse message
+	var msgData map[string]interface{}
+	if err = json.Unmarshal(m.Value, &msgData); err != nil {
+		log.Printf("Failed to parse Kafka message: %v", err)
+		return
+	}
 
-		switch msgType {
-		case "chat_message":
-			h.broadcastChatMessage(msgData)
-		case "user_status":
-			h.broadcastUserStatus(msgData)
-		}
+	// Handle different message types
+	msgType, exists := msgData["type"].(string)
+	if !exists {
+		return
+	}
+
+	switch msgType {
+	case "chat_message":
+		h.broadcastChatMessage(ctx, msgData)
+	case "user_status":
+		h.broadcastUserStatus(ctx, msgData)
 	}
 }
 
 // publishChatMessage publishes a chat message to Kafka
-func (h *WebSocketHandler) publishChatMessage(message *models.Message) {
-	if h.kafkaWriter == nil {
+func (h *WebSocketHandler) publishChatMessage(ctx context.Context, message *models.Message) {
+	if h.publisher == nil {
 		return
 	}
 
@@ -325,15 +627,16 @@ func (h *WebSocketHandler) publishChatMessage(message *models.Message) {
 		return
 	}
 
-	h.kafkaWriter.WriteMessages(context.Background(), kafka.Message{
-		Topic: "chat-messages",
-		Key:   []byte(message.SenderID),
+	// Keying by conversation keeps each conversation's messages in order
+	h.publisher.PublishContext(ctx, kafka.Message{
+		Topic: h.chatTopic,
+		Key:   []byte(chatConversationKey(message.SenderID, message.ReceiverID)),
 		Value: msgJSON,
 	})
 }
 
 // broadcastChatMessage broadcasts a chat message to relevant users
-func (h *WebSocketHandler) broadcastChatMessage(msgData map[string]interface{}) {
+func (h *WebSocketHandler) broadcastChatMessage(ctx context.Context, msgData map[string]interface{}) {
 	message, exists := msgData["message"].(map[string]interface{})
 	if !exists {
 		return
@@ -344,71 +647,117 @@ func (h *WebSocketHandler) broadcastChatMessage(msgData map[string]interface{})
 		return
 	}
 
-	// Send to receiver
-	h.sendToUser(receiverID, msgData)
+	// Skip messages already delivered, by the sending instance or an
+	// earlier delivery of this event
+	if messageID, _ := message["id"].(string); messageID != "" && !h.claimChatDelivery(ctx, messageID) {
+		return
+	}
+
+	// Send to receiver, wherever they are connected
+	h.routeToUser(receiverID, msgData)
 }
 
-// broadcastUserStatus broadcasts user status changes
-func (h *WebSocketHandler) broadcastUserStatus(msgData map[string]interface{}) {
+// broadcastUserStatus broadcasts user status changes to the user's accepted matches
+func (h *WebSocketHandler) broadcastUserStatus(ctx context.Context, msgData map[string]interface{}) {
 	userID, exists := msgData["user_id"].(string)
 	if !exists {
 		return
 	}
 
-	// Broadcast to all connected users (or implement more sophisticated logic)
-	h.mu.RLock()
-	for _, conn := range h.connections {
-		if conn.userID != userID {
-			conn.send <- []byte(fmt.Sprintf(`{"type":"user_status","user_id":"%s","status":"%s"}`,
-				userID, msgData["status"]))
-		}
+	// Presence is only shared with accepted matches; no matches means no broadcast
+	matchUserIDs, err := h.matchmakerService.GetAcceptedMatchUserIDs(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to look up matches for user status broadcast: %v", err)
+		return
+	}
+
+	for _, matchUserID := range matchUserIDs {
+		h.sendToUser(matchUserID, map[string]interface{}{
+			"type":    "user_status",
+			"user_id": userID,
+			"status":  msgData["status"],
+		})
 	}
-	h.mu.RUnlock()
 }
 
-// sendToUser sends a message to a specific user
-func (h *WebSocketHandler) sendToUser(userID string, message map[string]interface{}) {
+// sendToUser sends a message to a specific user, reporting whether they are
+// connected to this instance
+func (h *WebSocketHandler) sendToUser(userID string, message map[string]interface{}) bool {
 	h.mu.RLock()
 	conn, exists := h.connections[userID]
 	h.mu.RUnlock()
 
 	if !exists {
-		return
+		return false
 	}
 
 	messageJSON, err := json.Marshal(message)
 	if err != nil {
-		return
+		return true
 	}
 
-	conn.send <- messageJSON
+	conn.enqueue(messageJSON)
+	return true
 }
 
-// unregisterConnection removes a connection from the handler
-func (h *WebSocketHandler) unregisterConnection(userID string) {
+// unregisterConnection removes a connection from the handler. A user who has
+// already reconnected keeps their newer connection and stays online.
+func (h *WebSocketHandler) unregisterConnection(conn *WebSocketConnection) {
 	h.mu.Lock()
-	delete(h.connections, userID)
+	current, exists := h.connections[conn.userID]
+	replaced := exists && current != conn
+	if !replaced {
+		delete(h.connections, conn.userID)
+	}
 	h.mu.Unlock()
 
+	if replaced {
+		return
+	}
+
+	if err := utils.MarkOffline(context.Background(), conn.userID); err != nil {
+		log.Printf("Failed to mark user %s offline: %v", conn.userID, err)
+	}
+
 	// Broadcast user offline status
-	h.broadcastUserStatus(map[string]interface{}{
-		"user_id": userID,
+	h.broadcastUserStatus(context.Background(), map[string]interface{}{
+		"user_id": conn.userID,
 		"status":  "offline",
 	})
 }
 
-// saveMessage saves a message to the database
-func (h *WebSocketHandler) saveMessage(message *models.Message) error {
+// saveMessage saves a message to the database, numbering it after the last
+// number given out in its conversation. The conversation's counter row is
+// locked by the increment, so concurrent sends get consecutive numbers, and
+// a number freed by a retention purge is never given out again.
+func (h *WebSocketHandler) saveMessage(ctx context.Context, message *models.Message) error {
 	query := `
-		INSERT INTO messages (sender_id, receiver_id, content, message_type, is_read, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id
+		WITH next AS (
+			INSERT INTO conversation_sequences (user_low, user_high, last_seq)
+			VALUES (LEAST($1::uuid, $2::uuid), GREATEST($1::uuid, $2::uuid), 1)
+			ON CONFLICT (user_low, user_high) DO UPDATE SET last_seq = conversation_sequences.last_seq + 1
+			RETURNING last_seq
+		)
+		INSERT INTO messages (sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, seq)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, last_seq FROM next
+		RETURNING id, seq
 	`
 
-	return h.db.QueryRow(query,
-		message.SenderID, message.ReceiverID, message.Content, message.MessageType,
+	// Only the stored copy is encrypted; message keeps the plaintext for delivery
+	content, nonce, err := models.SealMessageContent(message.Content)
+	if err != nil {
+		return err
+	}
+	// A nil []byte would be stored as an empty bytea, which reads as encrypted
+	var nonceArg interface{}
+	if nonce != nil {
+		nonceArg = nonce
+	}
+
+	return h.db.QueryRowContext(ctx, query,
+		message.SenderID, message.ReceiverID, content, nonceArg, message.MessageType,
 		message.IsRead, message.CreatedAt, message.UpdatedAt,
-	).Scan(&message.ID)
+	).Scan(&message.ID, &message.Seq)
 }
 
 // markMessageAsRead marks a message as read
@@ -422,17 +771,70 @@ func (h *WebSocketHandler) markMessageAsRead(messageID string) error {
 	return err
 }
 
-// GetOnlineUsers returns a list of online users
+// Shutdown notifies every connected client that the server is going away, stops
+// accepting new connections, gives queued writes a grace period to flush, and
+// then closes the remaining connections
+func (h *WebSocketHandler) Shutdown(ctx context.Context) {
+	h.mu.Lock()
+	h.shuttingDown = true
+	connections := make([]*WebSocketConnection, 0, len(h.connections))
+	for _, conn := range h.connections {
+		connections = append(connections, conn)
+	}
+	h.mu.Unlock()
+
+	notice, _ := json.Marshal(map[string]interface{}{
+		"type":               "server_shutting_down",
+		"reconnect_delay_ms": h.reconnectDelay.Milliseconds(),
+		"timestamp":          time.Now().Unix(),
+	})
+
+	for _, conn := range connections {
+		if !conn.enqueue(notice) {
+			log.Printf("Send buffer full, skipping shutdown notice for user: %s", conn.userID)
+		}
+	}
+
+	select {
+	case <-time.After(h.shutdownGrace):
+	case <-ctx.Done():
+	}
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, conn := range connections {
+		conn.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+		conn.conn.Close()
+	}
+}
+
+// GetOnlineUsers returns a page of the users connected to this instance,
+// ordered by user id (?limit=&cursor=). Admins see everyone; other users only
+// see their accepted matches, the same audience presence updates go to.
 func (h *WebSocketHandler) GetOnlineUsers(c *gin.Context) {
-	h.mu.RLock()
-	onlineUsers := make([]string, 0, len(h.connections))
-	for userID := range h.connections {
-		onlineUsers = append(onlineUsers, userID)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > maxOnlineUsersPage {
+		limit = 50
 	}
-	h.mu.RUnlock()
+
+	var visible map[string]bool
+	if !utils.IsAdmin(c) {
+		matchUserIDs, err := h.matchmakerService.GetAcceptedMatchUserIDs(c.Request.Context(), c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve online users"})
+			return
+		}
+		visible = make(map[string]bool, len(matchUserIDs))
+		for _, id := range matchUserIDs {
+			visible[id] = true
+		}
+	}
+
+	onlineUsers := h.onlineUsers(visible)
+	page, nextCursor := pageOnlineUsers(onlineUsers, c.Query("cursor"), limit)
 
 	c.JSON(http.StatusOK, gin.H{
-		"online_users": onlineUsers,
+		"online_users": page,
 		"count":        len(onlineUsers),
+		"next_cursor":  nextCursor,
 	})
 }
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestWriteBatchCoalescesQueuedMessagesInOrder(t *testing.T) {
+	server, client := dialTestSocket(t, false, websocket.DefaultDialer)
+
+	conn := newTestConnection("alice")
+	conn.conn = server
+	conn.writeBatchSize = 64
+
+	large := `{"type":"chat","content":"` + strings.Repeat("x", 100) + `"}`
+	for _, message := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`, large, `{"n":4}`} {
+		conn.enqueue([]byte(message))
+	}
+	go conn.writePump()
+
+	// Queued messages share a frame until the next would overflow it; the
+	// oversized message goes out alone, and order is kept throughout
+	for _, want := range []string{"{\"n\":1}\n{\"n\":2}\n{\"n\":3}", large, `{"n":4}`} {
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		_, got, err := client.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("frame = %.40q, want %.40q", got, want)
+		}
+	}
+}
+
+func BenchmarkWriteFlushing(b *testing.B) {
+	for _, bench := range []struct {
+		name      string
+		batchSize int
+	}{
+		{"per-message", 0},
+		{"batched", 16 * 1024},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			benchmarkWriteFlushing(b, bench.batchSize)
+		})
+	}
+}
+
+// benchmarkWriteFlushing measures writing bursts of 64 queued chat messages
+func benchmarkWriteFlushing(b *testing.B, batchSize int) {
+	const burst = 64
+	server, client := dialTestSocket(b, false, websocket.DefaultDialer)
+	go func() {
+		for {
+			_, r, err := client.NextReader()
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, r)
+		}
+	}()
+
+	conn := newTestConnection("alice")
+	conn.conn = server
+	conn.send = make(chan []byte, burst)
+	conn.writeBatchSize = batchSize
+
+	messages := make([][]byte, burst)
+	for i := range messages {
+		messages[i] = []byte(fmt.Sprintf(`{"type":"chat","sender_id":"bob","content":"message %d"}`, i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, message := range messages {
+			conn.send <- message
+		}
+		for len(conn.send) > 0 {
+			message := <-conn.send
+			for message != nil {
+				next, _, err := conn.writeBatch(message)
+				if err != nil {
+					b.Fatalf("write: %v", err)
+				}
+				message = next
+			}
+		}
+	}
+}
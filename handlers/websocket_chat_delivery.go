@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// chatDedupePruneSize is how many delivered messages are remembered locally
+// before expired ones are swept out
+const chatDedupePruneSize = 1024
+
+// chatConversationKey is the Kafka key for a conversation's messages, the
+// same whichever side sent them
+func chatConversationKey(userA, userB string) string {
+	if userB < userA {
+		userA, userB = userB, userA
+	}
+	return userA + ":" + userB
+}
+
+// deliverChatMessage sends a chat message to its receiver if they are
+// connected to this instance, reporting whether it was delivered. Receivers
+// elsewhere get it from the copy published to Kafka.
+func (h *WebSocketHandler) deliverChatMessage(ctx context.Context, message *models.Message) bool {
+	h.mu.RLock()
+	_, local := h.connections[message.ReceiverID]
+	h.mu.RUnlock()
+
+	if !local || !h.claimChatDelivery(ctx, message.ID) {
+		return false
+	}
+
+	return h.sendToUser(message.ReceiverID, map[string]interface{}{
+		"type":      "chat_message",
+		"message":   message,
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// claimChatDelivery records that a chat message is being delivered, reporting
+// false if it already was within the dedupe window. The record is shared
+// through Redis so the instance consuming the Kafka copy sees it; if Redis
+// can't be reached the message is delivered, as a duplicate beats a loss.
+func (h *WebSocketHandler) claimChatDelivery(ctx context.Context, messageID string) bool {
+	if h.redisClient != nil {
+		claimed, err := h.redisClient.SetNX(ctx, utils.WSChatDeliveredKey(messageID), h.instanceID, h.chatDedupeTTL).Result()
+		if err != nil {
+			log.Printf("Failed to record delivery of chat message %s: %v", messageID, err)
+			return true
+		}
+		return claimed
+	}
+
+	now := time.Now()
+	h.deliveredMu.Lock()
+	defer h.deliveredMu.Unlock()
+
+	if deliveredAt, exists := h.deliveredChats[messageID]; exists && now.Sub(deliveredAt) < h.chatDedupeTTL {
+		return false
+	}
+	if len(h.deliveredChats) >= chatDedupePruneSize {
+		for id, deliveredAt := range h.deliveredChats {
+			if now.Sub(deliveredAt) >= h.chatDedupeTTL {
+				delete(h.deliveredChats, id)
+			}
+		}
+	}
+	h.deliveredChats[messageID] = now
+	return true
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestLocalChatMessageIsDeliveredOnce(t *testing.T) {
+	for _, shared := range []bool{true, false} {
+		name := "in-memory dedupe"
+		if shared {
+			name = "redis dedupe"
+		}
+		t.Run(name, func(t *testing.T) {
+			newTestRedis(t)
+			var redisClient *redis.Client
+			if shared {
+				redisClient = utils.RedisClient
+			}
+			publisher, flush := newTestPublisher(t)
+			bob := newTestConnection("bob")
+			h := &WebSocketHandler{
+				connections:    map[string]*WebSocketConnection{"bob": bob},
+				redisClient:    redisClient,
+				publisher:      publisher,
+				chatTopic:      "chat-messages",
+				instanceID:     "instance-1",
+				chatDedupeTTL:  time.Minute,
+				deliveredChats: make(map[string]time.Time),
+			}
+			ctx := context.Background()
+
+			// The path handleChatMessage takes once the message is saved
+			message := &models.Message{ID: "msg-1", SenderID: "alice", ReceiverID: "bob", Content: "hi", CreatedAt: time.Now()}
+			if !h.deliverChatMessage(ctx, message) {
+				t.Fatal("local receiver was not delivered the message")
+			}
+			h.publishChatMessage(ctx, message)
+
+			// This instance consumes its own published copy, and Kafka may redeliver it
+			published := flush()
+			if len(published) != 1 || string(published[0].Key) != chatConversationKey("bob", "alice") {
+				t.Fatalf("published %d messages, want one keyed by the conversation", len(published))
+			}
+			h.handleKafkaMessage(ctx, published[0])
+			h.handleKafkaMessage(ctx, published[0])
+
+			frames := 0
+			for msg := nextFrame(t, bob); msg != nil; msg = nextFrame(t, bob) {
+				if msg["type"] == "chat_message" {
+					frames++
+				}
+			}
+			if frames != 1 {
+				t.Errorf("bob received the message %d times, want once", frames)
+			}
+		})
+	}
+}
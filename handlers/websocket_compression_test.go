@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// recordingConn keeps a copy of every byte read from the network
+type recordingConn struct {
+	net.Conn
+	mu   sync.Mutex
+	read bytes.Buffer
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.mu.Lock()
+	c.read.Write(p[:n])
+	c.mu.Unlock()
+	return n, err
+}
+
+// frameHeaders returns the first byte of each frame read after the handshake
+func (c *recordingConn) frameHeaders(t *testing.T) []byte {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.read.Bytes()
+	end := bytes.Index(data, []byte("\r\n\r\n"))
+	if end < 0 {
+		t.Fatal("no handshake response recorded")
+	}
+	data = data[end+4:]
+
+	var headers []byte
+	for len(data) >= 2 {
+		headers = append(headers, data[0])
+		length, offset := int(data[1]&0x7f), 2
+		switch length {
+		case 126:
+			length, offset = int(data[2])<<8|int(data[3]), 4
+		case 127:
+			t.Fatal("unexpectedly large frame")
+		}
+		data = data[min(offset+length, len(data)):]
+	}
+	return headers
+}
+
+func TestWriteCompressesLargeMessagesOnly(t *testing.T) {
+	var recorded *recordingConn
+	dialer := &websocket.Dialer{
+		EnableCompression: true,
+		NetDial: func(network, addr string) (net.Conn, error) {
+			conn, err := net.Dial(network, addr)
+			if err != nil {
+				return nil, err
+			}
+			recorded = &recordingConn{Conn: conn}
+			return recorded, nil
+		},
+	}
+	server, client := dialTestSocket(t, true, dialer)
+
+	conn := newTestConnection("alice")
+	conn.conn = server
+	conn.compressionMinSize = 512
+	go conn.writePump()
+
+	large := `{"type":"chat","content":"` + strings.Repeat("compressible ", 100) + `"}`
+	small := `{"type":"pong"}`
+	conn.enqueue([]byte(large))
+	conn.enqueue([]byte(small))
+
+	for _, want := range []string{large, small} {
+		client.SetReadDeadline(time.Now().Add(time.Second))
+		_, got, err := client.ReadMessage()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(got) != want {
+			t.Fatalf("round trip changed the message: got %d bytes, want %d", len(got), len(want))
+		}
+	}
+
+	// RSV1 marks a frame compressed with permessage-deflate
+	const rsv1 = 0x40
+	headers := recorded.frameHeaders(t)
+	if len(headers) != 2 {
+		t.Fatalf("read %d frames, want 2", len(headers))
+	}
+	if headers[0]&rsv1 == 0 {
+		t.Error("large message was sent uncompressed")
+	}
+	if headers[1]&rsv1 != 0 {
+		t.Error("message below the minimum size was compressed")
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/connect-up/auth-service/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// FeedTopicMatches is the feed of the user's match lifecycle events:
+// match_created, match_updated and match_removed
+const FeedTopicMatches = "matches"
+
+// feedTopics are the topics a connection may subscribe to
+var feedTopics = map[string]bool{
+	FeedTopicMatches: true,
+}
+
+// setSubscribed subscribes the connection to a topic, or unsubscribes it
+func (c *WebSocketConnection) setSubscribed(topic string, on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !on {
+		delete(c.subscriptions, topic)
+		return
+	}
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]bool)
+	}
+	c.subscriptions[topic] = true
+}
+
+// subscribed reports whether the connection is subscribed to a topic
+func (c *WebSocketConnection) subscribed(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscriptions[topic]
+}
+
+// handleSubscription handles a subscribe or unsubscribe frame, acknowledging
+// it on the connection it arrived on. Subscriptions last for the connection;
+// a client that reconnects subscribes again.
+func (h *WebSocketHandler) handleSubscription(c *WebSocketConnection, msgData map[string]interface{}, subscribe bool) {
+	topic, _ := msgData["topic"].(string)
+
+	reply := map[string]interface{}{
+		"type":      "subscribed",
+		"topic":     topic,
+		"timestamp": time.Now().Unix(),
+	}
+	if !subscribe {
+		reply["type"] = "unsubscribed"
+	}
+	if !feedTopics[topic] {
+		reply["type"] = "subscription_rejected"
+		reply["reason"] = "unknown topic"
+	} else {
+		c.setSubscribed(topic, subscribe)
+	}
+
+	replyJSON, err := json.Marshal(reply)
+	if err != nil {
+		return
+	}
+	c.enqueue(replyJSON)
+}
+
+// routeToSubscriber delivers a message to a user only if their connection,
+// on this instance or another, is subscribed to the topic
+func (h *WebSocketHandler) routeToSubscriber(userID, topic string, message map[string]interface{}) {
+	h.mu.RLock()
+	conn, local := h.connections[userID]
+	h.mu.RUnlock()
+
+	if local || h.redisClient == nil {
+		if local && conn.subscribed(topic) {
+			h.sendToUser(userID, message)
+		}
+		return
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	h.relay(relayEnvelope{
+		Origin:  h.instanceID,
+		UserID:  userID,
+		Payload: payload,
+		Topic:   topic,
+	})
+}
+
+// NotifyMatchChanged pushes a match lifecycle event to both users of the
+// match if they are subscribed to the matches feed
+func (h *WebSocketHandler) NotifyMatchChanged(ctx context.Context, event string, match models.Match) {
+	for _, pair := range [][2]string{{match.UserID1, match.UserID2}, {match.UserID2, match.UserID1}} {
+		h.routeToSubscriber(pair[0], FeedTopicMatches, map[string]interface{}{
+			"type":      event,
+			"match_id":  match.ID,
+			"user_id":   pair[1],
+			"match":     match,
+			"timestamp": time.Now().Unix(),
+		})
+	}
+}
+
+// SetMatchFeedReader sets the reader of match-created events and starts
+// consuming them for the matches feed. All instances should share one
+// consumer group: the instance that reads an event relays it to whichever
+// instance holds each user's connection.
+func (h *WebSocketHandler) SetMatchFeedReader(reader *kafka.Reader) {
+	h.matchFeedReader = reader
+	go h.startMatchFeedConsumer()
+}
+
+// startMatchFeedConsumer consumes match-created events for the matches feed
+func (h *WebSocketHandler) startMatchFeedConsumer() {
+	for {
+		ctx := context.Background()
+		m, err := h.matchFeedReader.ReadMessage(ctx)
+		if err != nil {
+			log.Printf("Kafka read error on match feed: %v", err)
+			continue
+		}
+
+		h.handleMatchCreated(ctx, m)
+	}
+}
+
+// handleMatchCreated pushes one match-created event to the matches feed
+func (h *WebSocketHandler) handleMatchCreated(ctx context.Context, m kafka.Message) {
+	ctx, span := tracing.StartConsume(ctx, &m)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	var match models.Match
+	if err = json.Unmarshal(m.Value, &match); err != nil {
+		log.Printf("Failed to parse match-created event: %v", err)
+		return
+	}
+
+	h.NotifyMatchChanged(ctx, "match_created", match)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// nextFrame returns the next queued message on a connection, or nil if there is none
+func nextFrame(t *testing.T, conn *WebSocketConnection) map[string]interface{} {
+	t.Helper()
+	select {
+	case frame := <-conn.send:
+		var msg map[string]interface{}
+		if err := json.Unmarshal(frame, &msg); err != nil {
+			t.Fatalf("unmarshal frame: %v", err)
+		}
+		return msg
+	default:
+		return nil
+	}
+}
+
+func TestMatchFeedReachesOnlySubscribers(t *testing.T) {
+	alice, bob := newTestConnection("alice"), newTestConnection("bob")
+	h := &WebSocketHandler{connections: map[string]*WebSocketConnection{"alice": alice, "bob": bob}}
+
+	h.handleSubscription(alice, map[string]interface{}{"topic": FeedTopicMatches}, true)
+	h.handleSubscription(bob, map[string]interface{}{"topic": FeedTopicMatches}, true)
+	h.handleSubscription(bob, map[string]interface{}{"topic": FeedTopicMatches}, false)
+	if msg := nextFrame(t, alice); msg["type"] != "subscribed" {
+		t.Fatalf("alice's ack = %v, want subscribed", msg)
+	}
+	if msg := nextFrame(t, bob); msg["type"] != "subscribed" {
+		t.Fatalf("bob's first ack = %v, want subscribed", msg)
+	}
+	if msg := nextFrame(t, bob); msg["type"] != "unsubscribed" {
+		t.Fatalf("bob's second ack = %v, want unsubscribed", msg)
+	}
+
+	value, err := json.Marshal(models.Match{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.8})
+	if err != nil {
+		t.Fatalf("marshal match: %v", err)
+	}
+	h.handleMatchCreated(context.Background(), kafka.Message{Value: value})
+
+	msg := nextFrame(t, alice)
+	if msg["type"] != "match_created" || msg["match_id"] != "m1" || msg["user_id"] != "bob" {
+		t.Errorf("alice got %v, want match_created for m1 with bob", msg)
+	}
+	if msg := nextFrame(t, bob); msg != nil {
+		t.Errorf("unsubscribed bob got %v", msg)
+	}
+}
+
+func TestSubscribeToUnknownTopicIsRejected(t *testing.T) {
+	alice := newTestConnection("alice")
+	h := &WebSocketHandler{connections: map[string]*WebSocketConnection{"alice": alice}}
+
+	h.handleSubscription(alice, map[string]interface{}{"topic": "everything"}, true)
+	if msg := nextFrame(t, alice); msg["type"] != "subscription_rejected" || msg["topic"] != "everything" {
+		t.Errorf("reply = %v, want subscription_rejected", msg)
+	}
+	if alice.subscribed("everything") {
+		t.Error("connection subscribed to an unknown topic")
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// NotifyUser sends a message to a user wherever they are connected
+func (h *WebSocketHandler) NotifyUser(userID string, message map[string]interface{}) {
+	h.routeToUser(userID, message)
+}
+
+// NotifyMatchAccepted tells both users of a match, wherever they are
+// connected, that it was accepted on their behalf
+func (h *WebSocketHandler) NotifyMatchAccepted(ctx context.Context, match models.Match) {
+	for _, pair := range [][2]string{{match.UserID1, match.UserID2}, {match.UserID2, match.UserID1}} {
+		h.routeToUser(pair[0], map[string]interface{}{
+			"type":          "match_accepted",
+			"match_id":      match.ID,
+			"user_id":       pair[1],
+			"score_percent": match.ScorePercent,
+			"auto_accepted": match.AutoAccepted,
+			"timestamp":     time.Now().Unix(),
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// Messaging policies for direct messages
+const (
+	MessagingPolicyOpen    = "open"    // any authenticated user may message any other
+	MessagingPolicyMatches = "matches" // only users with a mutually accepted match or accepted intro request may message each other
+)
+
+// parseMessagingPolicy reads a policy name, falling back to open messaging
+func parseMessagingPolicy(value string) string {
+	switch value {
+	case "", MessagingPolicyOpen:
+		return MessagingPolicyOpen
+	case MessagingPolicyMatches:
+		return MessagingPolicyMatches
+	default:
+		log.Printf("Unknown WS_MESSAGING_POLICY %q, using %q", value, MessagingPolicyOpen)
+		return MessagingPolicyOpen
+	}
+}
+
+// canMessage reports whether the messaging policy lets senderID message
+// receiverID. Under the matches policy a company owner who accepted an
+// investor's intro request counts as matched with them, and a failed lookup
+// refuses the message.
+func (h *WebSocketHandler) canMessage(ctx context.Context, senderID, receiverID string) bool {
+	if h.messagingPolicy != MessagingPolicyMatches {
+		return true
+	}
+
+	if h.matchmakerService != nil {
+		matched, err := h.matchmakerService.HasMutualMatch(ctx, senderID, receiverID)
+		if err != nil {
+			log.Printf("Failed to check match between %s and %s: %v", senderID, receiverID, err)
+		} else if matched {
+			return true
+		}
+	}
+
+	introduced, err := models.HasAcceptedIntro(senderID, receiverID)
+	if err != nil {
+		log.Printf("Failed to check intro requests between %s and %s: %v", senderID, receiverID, err)
+		return false
+	}
+	return introduced
+}
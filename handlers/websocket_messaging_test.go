@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestOpenMessagingAllowsAnyone(t *testing.T) {
+	h := &WebSocketHandler{messagingPolicy: parseMessagingPolicy("")}
+
+	if !h.canMessage(context.Background(), "alice", "stranger") {
+		t.Error("open messaging refused a message between unmatched users")
+	}
+}
+
+func TestMatchOnlyMessaging(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	ctx := context.Background()
+
+	service := newTestMatchmaker(t)
+	match := models.Match{ID: "m1", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted}
+	if err := service.StoreMatch(ctx, match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	alice := newTestConnection("alice")
+	h := &WebSocketHandler{
+		connections:       map[string]*WebSocketConnection{"alice": alice},
+		matchmakerService: service,
+		messagingPolicy:   parseMessagingPolicy(MessagingPolicyMatches),
+	}
+
+	if !h.canMessage(ctx, "alice", "bob") {
+		t.Error("matches policy refused a message between mutually matched users")
+	}
+
+	// Without a match, an accepted intro request is the only other way in
+	expectNoIntro := func() {
+		mock.ExpectQuery(`FROM intro_requests`).WithArgs("alice", "carol", models.IntroStatusAccepted).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	}
+	expectNoIntro()
+	if h.canMessage(ctx, "alice", "carol") {
+		t.Error("matches policy allowed a message between unmatched users")
+	}
+
+	expectNoIntro()
+	h.handleChatMessage(ctx, "alice", map[string]interface{}{"receiver_id": "carol", "content": "hi"})
+	select {
+	case frame := <-alice.send:
+		var msg map[string]interface{}
+		if err := json.Unmarshal(frame, &msg); err != nil {
+			t.Fatalf("unmarshal frame: %v", err)
+		}
+		if msg["type"] != "message_rejected" || msg["receiver_id"] != "carol" {
+			t.Errorf("frame = %v, want message_rejected for carol", msg)
+		}
+	default:
+		t.Fatal("sender got no rejection frame")
+	}
+}
+
+func TestRecomputedMatchKeepsRejection(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	ctx := context.Background()
+
+	// Any score would be accepted on both users' behalf for a new pair
+	t.Setenv("MATCH_AUTO_ACCEPT_THRESHOLD", "0.01")
+	service := newTestMatchmaker(t)
+	for _, userID := range []string{"alice", "bob"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5}
+		if err := service.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("StoreUserProfile %s: %v", userID, err)
+		}
+	}
+
+	// bob already rejected the match computed for him
+	rejected := models.Match{ID: "m1", UserID1: "bob", UserID2: "alice", User1Status: models.MatchStatusRejected, User2Status: models.MatchStatusPending}
+	if err := service.StoreMatch(ctx, rejected); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want the one with bob", len(matches))
+	}
+	if match := matches[0]; match.AutoAccepted || match.User1Status != models.MatchStatusPending || match.User2Status != models.MatchStatusRejected {
+		t.Errorf("recomputed match = %+v, want alice pending and bob rejected", match)
+	}
+	if err := service.StoreMatch(ctx, matches[0]); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	h := &WebSocketHandler{matchmakerService: service, messagingPolicy: parseMessagingPolicy(MessagingPolicyMatches)}
+	mock.ExpectQuery(`FROM intro_requests`).WithArgs("alice", "bob", models.IntroStatusAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	if h.canMessage(ctx, "alice", "bob") {
+		t.Error("matches policy allowed a message after bob rejected the match")
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// maxOnlineUsersPage caps the page size of the online users listing
+const maxOnlineUsersPage = 200
+
+// onlineUser is one entry in the online users listing
+type onlineUser struct {
+	UserID     string    `json:"user_id"`
+	LastActive time.Time `json:"last_active"`
+}
+
+// onlineUsers returns the users connected to this instance, sorted by user id.
+// A nil visible set includes everyone; otherwise only the listed users.
+func (h *WebSocketHandler) onlineUsers(visible map[string]bool) []onlineUser {
+	h.mu.RLock()
+	users := make([]onlineUser, 0, len(h.connections))
+	for userID, conn := range h.connections {
+		if visible != nil && !visible[userID] {
+			continue
+		}
+		users = append(users, onlineUser{
+			UserID:     userID,
+			LastActive: time.Unix(conn.lastActive.Load(), 0).UTC(),
+		})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool { return users[i].UserID < users[j].UserID })
+	return users
+}
+
+// pageOnlineUsers returns up to limit users after cursor, the user id the
+// previous page ended on, and the cursor for the next page ("" on the last)
+func pageOnlineUsers(users []onlineUser, cursor string, limit int) ([]onlineUser, string) {
+	start := sort.Search(len(users), func(i int) bool { return users[i].UserID > cursor })
+	end := min(start+limit, len(users))
+
+	page := users[start:end]
+	if end == len(users) {
+		return page, ""
+	}
+	return page, page[len(page)-1].UserID
+}
+
+// startPresenceRefresh re-marks every user connected to this instance as
+// online so their presence entries don't expire while they stay connected
+func (h *WebSocketHandler) startPresenceRefresh() {
+	interval := utils.PresenceRefreshInterval()
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.mu.RLock()
+		userIDs := make([]string, 0, len(h.connections))
+		for userID := range h.connections {
+			userIDs = append(userIDs, userID)
+		}
+		h.mu.RUnlock()
+
+		if err := utils.MarkOnline(context.Background(), userIDs...); err != nil {
+			log.Printf("Failed to refresh presence: %v", err)
+		}
+	}
+}
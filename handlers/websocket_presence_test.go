@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// onlineUsersPage is the GetOnlineUsers response
+type onlineUsersPage struct {
+	OnlineUsers []onlineUser `json:"online_users"`
+	Count       int          `json:"count"`
+	NextCursor  string       `json:"next_cursor"`
+}
+
+func getOnlineUsers(t *testing.T, h *WebSocketHandler, userID, role, query string) onlineUsersPage {
+	t.Helper()
+	rec := serve(t, userID, role, http.MethodGet, "/online-users", "/online-users"+query, nil, h.GetOnlineUsers)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var page onlineUsersPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return page
+}
+
+func onlineUserIDs(users []onlineUser) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.UserID
+	}
+	return ids
+}
+
+func TestOnlineUsersScopedToMatches(t *testing.T) {
+	newTestRedis(t)
+	service := newTestMatchmaker(t)
+	ctx := context.Background()
+	for _, match := range []models.Match{
+		{ID: "m1", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted},
+		{ID: "m2", UserID1: "carol", UserID2: "alice", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusPending},
+	} {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+
+	h := &WebSocketHandler{connections: map[string]*WebSocketConnection{}, matchmakerService: service}
+	for _, userID := range []string{"alice", "bob", "carol", "dave"} {
+		h.connections[userID] = newTestConnection(userID)
+	}
+
+	// A non-admin sees only their accepted matches, not a pending one or strangers
+	page := getOnlineUsers(t, h, "alice", models.RoleUser, "")
+	if ids := onlineUserIDs(page.OnlineUsers); len(ids) != 1 || ids[0] != "bob" || page.Count != 1 {
+		t.Errorf("alice sees %v (count %d), want only bob", ids, page.Count)
+	}
+	if page := getOnlineUsers(t, h, "dave", models.RoleUser, ""); len(page.OnlineUsers) != 0 {
+		t.Errorf("dave sees %v, want nobody", onlineUserIDs(page.OnlineUsers))
+	}
+
+	// An admin sees everyone, a page at a time
+	page = getOnlineUsers(t, h, "root", models.RoleAdmin, "?limit=3")
+	if ids := onlineUserIDs(page.OnlineUsers); len(ids) != 3 || ids[0] != "alice" || page.Count != 4 || page.NextCursor != "carol" {
+		t.Fatalf("admin first page = %v (count %d, cursor %q)", ids, page.Count, page.NextCursor)
+	}
+	page = getOnlineUsers(t, h, "root", models.RoleAdmin, "?limit=3&cursor=carol")
+	if ids := onlineUserIDs(page.OnlineUsers); len(ids) != 1 || ids[0] != "dave" || page.NextCursor != "" {
+		t.Errorf("admin second page = %v (cursor %q), want dave and no cursor", ids, page.NextCursor)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/connect-up/auth-service/internal/push"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// SetPushDigester sets where push notifications for offline users' chat
+// messages are sent; nil turns them off
+func (h *WebSocketHandler) SetPushDigester(digester *push.Digester) {
+	h.pushDigester = digester
+}
+
+// notifyOffline queues a push notification for a chat message whose receiver
+// isn't connected here. Receivers online on another instance get the message
+// from there and aren't notified.
+func (h *WebSocketHandler) notifyOffline(ctx context.Context, message *models.Message) {
+	if h.pushDigester == nil {
+		return
+	}
+
+	if h.redisClient != nil {
+		online, err := utils.IsOnline(ctx, message.ReceiverID)
+		if err != nil {
+			log.Printf("Failed to check presence for push notification: %v", err)
+			return
+		}
+		if online {
+			return
+		}
+	}
+
+	prefs, err := models.GetNotificationPreferences(message.ReceiverID)
+	if err != nil {
+		log.Printf("Failed to load notification preferences for user %s: %v", message.ReceiverID, err)
+		return
+	}
+	if !prefs.PushEnabled {
+		return
+	}
+
+	pending := push.PendingMessage{
+		MessageID: message.ID,
+		SenderID:  message.SenderID,
+		CreatedAt: message.CreatedAt,
+	}
+	if prefs.ShowPreviews {
+		pending.Preview = h.pushDigester.Preview(message.Content)
+	}
+
+	if err := h.pushDigester.Enqueue(ctx, message.ReceiverID, pending); err != nil {
+		log.Printf("Failed to queue push notification for user %s: %v", message.ReceiverID, err)
+	}
+}
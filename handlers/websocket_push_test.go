@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/internal/push"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestOfflineReceiverGetsPushNotification(t *testing.T) {
+	newTestRedis(t)
+	mock := newTestDB(t)
+	ctx := context.Background()
+
+	// Notifications are sent in the background
+	sent := make(chan push.Notification, 4)
+	h := &WebSocketHandler{redisClient: utils.RedisClient}
+	h.SetPushDigester(push.NewDigester(push.NotifierFunc(func(ctx context.Context, n push.Notification) error {
+		sent <- n
+		return nil
+	}), 0, 5))
+	expectNone := func(why string) {
+		t.Helper()
+		select {
+		case n := <-sent:
+			t.Errorf("%s: %+v", why, n)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	message := func(receiverID string) *models.Message {
+		return &models.Message{ID: "msg-" + receiverID, SenderID: "alice", ReceiverID: receiverID, Content: "Lunch tomorrow?", CreatedAt: time.Now()}
+	}
+	preferences := func(userID string, pushEnabled, previews bool) {
+		mock.ExpectQuery(`FROM notification_preferences\s+WHERE user_id = \$1`).WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"push_enabled", "show_previews", "updated_at"}).AddRow(pushEnabled, previews, time.Now()))
+	}
+
+	// bob is connected to some instance, so he gets the message there
+	if err := utils.MarkOnline(ctx, "bob"); err != nil {
+		t.Fatalf("MarkOnline: %v", err)
+	}
+	h.notifyOffline(ctx, message("bob"))
+	expectNone("online receiver was notified")
+
+	preferences("carol", true, true)
+	h.notifyOffline(ctx, message("carol"))
+	select {
+	case n := <-sent:
+		if n.UserID != "carol" || n.SenderID != "alice" || n.MessageID != "msg-carol" || n.Preview != "Lunch…" {
+			t.Errorf("notification = %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("offline receiver got no notification")
+	}
+
+	// Receivers who turned push off aren't notified
+	preferences("dave", false, true)
+	h.notifyOffline(ctx, message("dave"))
+	expectNone("receiver with push off was notified")
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// errInvalidReconnectToken is returned for unknown, expired, or already used reconnection tokens
+var errInvalidReconnectToken = errors.New("invalid or expired reconnection token")
+
+// reconnectState is the connection state stored behind a reconnection token
+type reconnectState struct {
+	UserID       string `json:"user_id"`
+	SessionID    string `json:"session_id,omitempty"`
+	ConnectionID string `json:"connection_id"`
+}
+
+// reconnectTokenKey returns the Redis key for a reconnection token. Only the
+// token's hash is stored so a Redis dump can't be replayed.
+func reconnectTokenKey(token string) string {
+	return utils.WSReconnectKey(utils.HashToken(token))
+}
+
+// issueReconnectToken creates a short-lived, single-use token the client can
+// present on its next handshake instead of a full JWT
+func (h *WebSocketHandler) issueReconnectToken(conn *WebSocketConnection) string {
+	if h.redisClient == nil {
+		return ""
+	}
+
+	token := uuid.New().String() + uuid.New().String()
+	state, err := json.Marshal(reconnectState{
+		UserID:       conn.userID,
+		SessionID:    conn.sessionID,
+		ConnectionID: conn.connectionID,
+	})
+	if err != nil {
+		return ""
+	}
+
+	if err := h.redisClient.Set(context.Background(), reconnectTokenKey(token), state, h.reconnectTokenTTL).Err(); err != nil {
+		log.Printf("Failed to store reconnection token: %v", err)
+		return ""
+	}
+
+	return token
+}
+
+// redeemReconnectToken consumes a reconnection token and returns the state it
+// was issued for. Tokens whose login session has since been revoked are rejected.
+func (h *WebSocketHandler) redeemReconnectToken(ctx context.Context, token string) (*reconnectState, error) {
+	if h.redisClient == nil || token == "" {
+		return nil, errInvalidReconnectToken
+	}
+
+	data, err := h.redisClient.GetDel(ctx, reconnectTokenKey(token)).Result()
+	if err != nil {
+		return nil, errInvalidReconnectToken
+	}
+
+	var state reconnectState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, errInvalidReconnectToken
+	}
+
+	if state.SessionID != "" {
+		active, err := models.IsSessionActive(state.UserID, state.SessionID)
+		if err != nil || !active {
+			return nil, errInvalidReconnectToken
+		}
+	}
+
+	return &state, nil
+}
+
+// reconnectTokenExpiry returns the token lifetime advertised to clients
+func (h *WebSocketHandler) reconnectTokenExpiry() int64 {
+	return int64(h.reconnectTokenTTL / time.Second)
+}
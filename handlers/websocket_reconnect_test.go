@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestReconnectTokens(t *testing.T) {
+	server := newTestRedis(t)
+	mock := newTestDB(t)
+	ctx := context.Background()
+	h := &WebSocketHandler{redisClient: utils.RedisClient, reconnectTokenTTL: time.Minute}
+	conn := &WebSocketConnection{userID: "alice", sessionID: "s-1", connectionID: "conn-1"}
+
+	expectSession := func(active bool) {
+		mock.ExpectQuery(`FROM sessions WHERE id = \$1 AND user_id = \$2`).WithArgs("s-1", "alice").
+			WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(active))
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		token := h.issueReconnectToken(conn)
+		expectSession(true)
+		state, err := h.redeemReconnectToken(ctx, token)
+		if err != nil {
+			t.Fatalf("redeem: %v", err)
+		}
+		if state.UserID != "alice" || state.ConnectionID != "conn-1" {
+			t.Errorf("state = %+v, want alice's connection", state)
+		}
+
+		// Tokens are single use
+		if _, err := h.redeemReconnectToken(ctx, token); err != errInvalidReconnectToken {
+			t.Errorf("second redeem = %v, want errInvalidReconnectToken", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token := h.issueReconnectToken(conn)
+		server.FastForward(2 * time.Minute)
+		if _, err := h.redeemReconnectToken(ctx, token); err != errInvalidReconnectToken {
+			t.Errorf("redeem = %v, want errInvalidReconnectToken", err)
+		}
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		token := h.issueReconnectToken(conn)
+		expectSession(false)
+		if _, err := h.redeemReconnectToken(ctx, token); err != errInvalidReconnectToken {
+			t.Errorf("redeem = %v, want errInvalidReconnectToken", err)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, err := h.redeemReconnectToken(ctx, "made-up"); err != errInvalidReconnectToken {
+			t.Errorf("redeem = %v, want errInvalidReconnectToken", err)
+		}
+	})
+
+	// Only the token's hash is stored
+	token := h.issueReconnectToken(conn)
+	if server.Exists(utils.WSReconnectKey(token)) || !server.Exists(reconnectTokenKey(token)) {
+		t.Error("reconnection token stored in the clear")
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// relayEnvelope wraps a frame published for delivery on whichever instance holds the user's connection
+type relayEnvelope struct {
+	Origin  string          `json:"origin"`
+	UserID  string          `json:"user_id"`
+	Payload json.RawMessage `json:"payload"`
+
+	// Topic, when set, limits delivery to a connection subscribed to it
+	Topic string `json:"topic,omitempty"`
+}
+
+// typingState tracks the last typing indicator forwarded from a sender to a receiver
+type typingState struct {
+	isTyping bool
+	sentAt   time.Time
+}
+
+// routeToUser delivers a message to a user connected to this instance, or
+// publishes it over Redis so the instance holding their connection can
+func (h *WebSocketHandler) routeToUser(userID string, message map[string]interface{}) {
+	h.mu.RLock()
+	_, local := h.connections[userID]
+	h.mu.RUnlock()
+
+	if local || h.redisClient == nil {
+		h.sendToUser(userID, message)
+		return
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	h.relay(relayEnvelope{
+		Origin:  h.instanceID,
+		UserID:  userID,
+		Payload: payload,
+	})
+}
+
+// relay publishes a frame over Redis for the instance holding the user's connection
+func (h *WebSocketHandler) relay(envelope relayEnvelope) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return
+	}
+
+	if err := h.redisClient.Publish(context.Background(), utils.WSRelayChannel(), data).Err(); err != nil {
+		log.Printf("Failed to relay message via Redis: %v", err)
+	}
+}
+
+// startRedisRelay delivers frames published by other instances to locally connected users
+func (h *WebSocketHandler) startRedisRelay() {
+	if h.redisClient == nil {
+		return
+	}
+
+	sub := h.redisClient.Subscribe(context.Background(), utils.WSRelayChannel())
+	defer sub.Close()
+
+	for msg := range sub.Channel() {
+		var envelope relayEnvelope
+		if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+			log.Printf("Failed to parse relayed message: %v", err)
+			continue
+		}
+
+		if envelope.Origin == h.instanceID {
+			continue
+		}
+
+		h.mu.RLock()
+		conn, exists := h.connections[envelope.UserID]
+		h.mu.RUnlock()
+
+		if exists && (envelope.Topic == "" || conn.subscribed(envelope.Topic)) {
+			conn.enqueue([]byte(envelope.Payload))
+		}
+	}
+}
+
+// shouldForwardTyping throttles typing indicators per sender/receiver pair.
+// State changes are always forwarded; repeats of the same state are forwarded
+// at most once per throttle interval. The state lives only in memory.
+func (h *WebSocketHandler) shouldForwardTyping(senderID, receiverID string, isTyping bool) bool {
+	key := senderID + ":" + receiverID
+	now := time.Now()
+
+	h.typingMu.Lock()
+	defer h.typingMu.Unlock()
+
+	last, exists := h.typingStates[key]
+	if exists && last.isTyping == isTyping && now.Sub(last.sentAt) < h.typingThrottle {
+		return false
+	}
+
+	if isTyping {
+		h.typingStates[key] = typingState{isTyping: true, sentAt: now}
+	} else {
+		delete(h.typingStates, key)
+	}
+
+	return true
+}
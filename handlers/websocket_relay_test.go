@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// newTestRelayHandler creates a handler for one instance relaying through the
+// shared test Redis
+func newTestRelayHandler(instanceID string, connections ...*WebSocketConnection) *WebSocketHandler {
+	h := &WebSocketHandler{
+		connections:    make(map[string]*WebSocketConnection),
+		redisClient:    utils.RedisClient,
+		instanceID:     instanceID,
+		typingStates:   make(map[string]typingState),
+		typingThrottle: time.Minute,
+	}
+	for _, conn := range connections {
+		h.connections[conn.userID] = conn
+	}
+	return h
+}
+
+func TestTypingIndicatorCrossesInstances(t *testing.T) {
+	server := newTestRedis(t)
+	bob := newTestConnection("bob")
+	instanceA := newTestRelayHandler("instance-a")
+	instanceB := newTestRelayHandler("instance-b", bob)
+
+	go instanceB.startRedisRelay()
+	deadline := time.Now().Add(time.Second)
+	for server.PubSubNumSub(utils.WSRelayChannel())[utils.WSRelayChannel()] == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("instance B never subscribed to the relay channel")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Alice is connected to instance A, Bob to instance B
+	instanceA.handleTypingEvent("alice", map[string]interface{}{"receiver_id": "bob", "is_typing": true})
+
+	select {
+	case frame := <-bob.send:
+		var msg map[string]interface{}
+		if err := json.Unmarshal(frame, &msg); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if msg["type"] != "typing_indicator" || msg["user_id"] != "alice" || msg["is_typing"] != true {
+			t.Fatalf("bob got %v, want alice typing", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bob never got the typing indicator relayed from instance A")
+	}
+
+	// Repeats within the throttle interval are dropped; a state change isn't
+	instanceA.handleTypingEvent("alice", map[string]interface{}{"receiver_id": "bob", "is_typing": true})
+	instanceA.handleTypingEvent("alice", map[string]interface{}{"receiver_id": "bob", "is_typing": false})
+
+	select {
+	case frame := <-bob.send:
+		var msg map[string]interface{}
+		json.Unmarshal(frame, &msg)
+		if msg["is_typing"] != false {
+			t.Fatalf("bob got %v, want the repeat throttled and only the stop forwarded", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("bob never got alice's stop-typing indicator")
+	}
+	select {
+	case frame := <-bob.send:
+		t.Errorf("bob got an extra frame %s", frame)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
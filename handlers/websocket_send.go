@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"log"
+)
+
+// Overflow policies for a connection whose send buffer is full
+const (
+	SendOverflowDropOldest = "drop_oldest" // discard the oldest queued message to make room
+	SendOverflowDropNew    = "drop_new"    // discard the message being sent
+	SendOverflowClose      = "close"       // close the connection; the client reconnects and catches up from history
+)
+
+// parseSendOverflowPolicy reads an overflow policy name, falling back to closing the connection
+func parseSendOverflowPolicy(value string) string {
+	switch value {
+	case "", SendOverflowClose:
+		return SendOverflowClose
+	case SendOverflowDropOldest, SendOverflowDropNew:
+		return value
+	default:
+		log.Printf("Unknown WS_SEND_OVERFLOW_POLICY %q, using %q", value, SendOverflowClose)
+		return SendOverflowClose
+	}
+}
+
+// enqueue queues message for writePump without ever blocking. When the send
+// buffer is full the connection's overflow policy decides what gives way. It
+// reports whether message was queued.
+func (c *WebSocketConnection) enqueue(message []byte) bool {
+	select {
+	case c.send <- message:
+		return true
+	default:
+	}
+
+	switch c.overflowPolicy {
+	case SendOverflowDropOldest:
+		// Serialized so concurrent senders don't each evict a message for
+		// one free slot; writePump draining concurrently only makes room
+		c.sendMu.Lock()
+		defer c.sendMu.Unlock()
+		for {
+			select {
+			case c.send <- message:
+				return true
+			default:
+			}
+			select {
+			case <-c.send:
+			default:
+			}
+		}
+	case SendOverflowDropNew:
+		return false
+	default:
+		c.closeOnce.Do(func() {
+			log.Printf("Send buffer full, closing slow connection for user: %s", c.userID)
+			// readPump sees the closed connection and unregisters it
+			c.conn.Close()
+		})
+		return false
+	}
+}
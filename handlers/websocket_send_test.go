@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+// drain returns every message queued on a connection
+func drain(conn *WebSocketConnection) []string {
+	var queued []string
+	for len(conn.send) > 0 {
+		queued = append(queued, string(<-conn.send))
+	}
+	return queued
+}
+
+func TestSendOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantQueued bool
+		want       []string
+	}{
+		{SendOverflowDropOldest, true, []string{"2", "3"}},
+		{SendOverflowDropNew, false, []string{"1", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			conn := &WebSocketConnection{userID: "alice", send: make(chan []byte, 2), overflowPolicy: tt.policy}
+			conn.enqueue([]byte("1"))
+			conn.enqueue([]byte("2"))
+
+			// Runs in a goroutine so a blocking send fails the test instead of hanging it
+			queued := make(chan bool, 1)
+			go func() { queued <- conn.enqueue([]byte("3")) }()
+			select {
+			case got := <-queued:
+				if got != tt.wantQueued {
+					t.Errorf("enqueue on a full buffer = %v, want %v", got, tt.wantQueued)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("enqueue blocked on a full buffer")
+			}
+
+			if got := drain(conn); len(got) != len(tt.want) || got[0] != tt.want[0] || got[1] != tt.want[1] {
+				t.Errorf("queued = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendOverflowClosesSlowConnection(t *testing.T) {
+	server, client := newTestSocket(t, false)
+	conn := &WebSocketConnection{conn: server, userID: "alice", send: make(chan []byte, 1), overflowPolicy: SendOverflowClose}
+
+	if !conn.enqueue([]byte("1")) {
+		t.Fatal("enqueue refused a message with room in the buffer")
+	}
+	if conn.enqueue([]byte("2")) {
+		t.Error("enqueue queued a message on a full buffer")
+	}
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, _, err := client.ReadMessage(); err == nil {
+		t.Error("slow connection still open after its buffer overflowed")
+	}
+}
+
+func TestParseSendOverflowPolicy(t *testing.T) {
+	for value, want := range map[string]string{
+		"":                     SendOverflowClose,
+		SendOverflowDropOldest: SendOverflowDropOldest,
+		SendOverflowDropNew:    SendOverflowDropNew,
+		"bogus":                SendOverflowClose,
+	} {
+		if got := parseSendOverflowPolicy(value); got != want {
+			t.Errorf("parseSendOverflowPolicy(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
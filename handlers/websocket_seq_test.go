@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// conversationCounterQuery matches the insert that takes its number from the
+// conversation's counter row
+const conversationCounterQuery = `INSERT INTO conversation_sequences .* ON CONFLICT \(user_low, user_high\) DO UPDATE SET last_seq = conversation_sequences.last_seq \+ 1 .* INSERT INTO messages`
+
+func TestConcurrentSendsGetSequentialNumbers(t *testing.T) {
+	mock := newTestDB(t)
+	h := &WebSocketHandler{db: models.DB}
+
+	// The counter row lock serialises the sends, so each increment returns
+	// the next number in the order the statements run
+	const sends = 5
+	for seq := 1; seq <= sends; seq++ {
+		mock.ExpectQuery(conversationCounterQuery).
+			WithArgs("alice", "bob", sqlmock.AnyArg(), sqlmock.AnyArg(), "text", false, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}).AddRow("msg", seq))
+	}
+
+	seqs := make([]int, sends)
+	var wg sync.WaitGroup
+	for i := 0; i < sends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			message := models.Message{SenderID: "alice", ReceiverID: "bob", Content: "hi", MessageType: "text", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+			if err := h.saveMessage(context.Background(), &message); err != nil {
+				t.Errorf("saveMessage: %v", err)
+				return
+			}
+			seqs[i] = int(message.Seq)
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Ints(seqs)
+	for i, seq := range seqs {
+		if seq != i+1 {
+			t.Fatalf("sequence numbers = %v, want 1 to %d without gaps or repeats", seqs, sends)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSaveMessageTakesNumberFromConversationCounter(t *testing.T) {
+	mock := newTestDB(t)
+	h := &WebSocketHandler{db: models.DB}
+
+	// Messages 1 to 41 were purged, so none remain to take a maximum from;
+	// the counter still continues after them
+	mock.ExpectQuery(conversationCounterQuery).
+		WithArgs("alice", "bob", sqlmock.AnyArg(), sqlmock.AnyArg(), "text", false, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "seq"}).AddRow("msg", 42))
+
+	message := models.Message{SenderID: "alice", ReceiverID: "bob", Content: "hi", MessageType: "text", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := h.saveMessage(context.Background(), &message); err != nil {
+		t.Fatalf("saveMessage: %v", err)
+	}
+	if message.ID != "msg" || message.Seq != 42 {
+		t.Errorf("message id, seq = %q, %d; want msg, 42", message.ID, message.Seq)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
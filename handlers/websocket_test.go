@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// newTestSocket connects a WebSocket client to a server-side connection,
+// with permessage-deflate negotiated when compression is set
+func newTestSocket(t *testing.T, compression bool) (server, client *websocket.Conn) {
+	t.Helper()
+	return dialTestSocket(t, compression, &websocket.Dialer{EnableCompression: compression})
+}
+
+// dialTestSocket connects dialer to a server-side connection whose upgrader
+// offers compression when set
+func dialTestSocket(t testing.TB, compression bool, dialer *websocket.Dialer) (server, client *websocket.Conn) {
+	t.Helper()
+	upgrader := newUpgrader(compression)
+	accepted := make(chan *websocket.Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		accepted <- conn
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	server = <-accepted
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return server, client
+}
+
+// readJSON reads the next message from a client, failing the test after a second
+func readJSON(t *testing.T, client *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	var msg map[string]interface{}
+	if err := client.ReadJSON(&msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func TestBroadcastUserStatusReachesOnlyAcceptedMatches(t *testing.T) {
+	newTestRedis(t)
+	service := newTestMatchmaker(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	matches := []models.Match{
+		{ID: "m-ab", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted, CreatedAt: now},
+		{ID: "m-cd", UserID1: "carol", UserID2: "dave", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted, CreatedAt: now},
+	}
+	for _, match := range matches {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+
+	bob, carol := newTestConnection("bob"), newTestConnection("carol")
+	h := &WebSocketHandler{
+		connections:       map[string]*WebSocketConnection{"bob": bob, "carol": carol},
+		matchmakerService: service,
+	}
+
+	h.broadcastUserStatus(ctx, map[string]interface{}{"user_id": "alice", "status": "online"})
+
+	select {
+	case frame := <-bob.send:
+		var msg map[string]interface{}
+		if err := json.Unmarshal(frame, &msg); err != nil {
+			t.Fatalf("unmarshal frame: %v", err)
+		}
+		if msg["type"] != "user_status" || msg["user_id"] != "alice" || msg["status"] != "online" {
+			t.Errorf("bob got %v, want alice's online status", msg)
+		}
+	default:
+		t.Fatal("bob, an accepted match, got no status frame")
+	}
+
+	if len(carol.send) != 0 {
+		t.Errorf("carol, who is not matched with alice, got %d frames", len(carol.send))
+	}
+}
+
+func TestShutdownNotifiesClientsBeforeClosing(t *testing.T) {
+	server, client := newTestSocket(t, false)
+	conn := newTestConnection("alice")
+	conn.conn = server
+	go conn.writePump()
+
+	h := &WebSocketHandler{
+		connections:    map[string]*WebSocketConnection{"alice": conn},
+		reconnectDelay: 3 * time.Second,
+		shutdownGrace:  50 * time.Millisecond,
+	}
+	h.Shutdown(context.Background())
+
+	msg := readJSON(t, client)
+	if msg["type"] != "server_shutting_down" || msg["reconnect_delay_ms"] != float64(3000) {
+		t.Fatalf("first message = %v, want a shutdown notice with a 3000ms reconnect delay", msg)
+	}
+
+	_, _, err := client.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("after the notice got %v, want a going-away close", err)
+	}
+
+	if !h.shuttingDown {
+		t.Error("handler still accepts new connections")
+	}
+}
@@ -0,0 +1,135 @@
+package contentfilter
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// ErrContentRejected is returned when flagged content is rejected rather than masked
+var ErrContentRejected = errors.New("content contains disallowed language")
+
+// Policy decides what happens to flagged content
+type Policy string
+
+// Content filter policies
+const (
+	PolicyOff    Policy = "off"    // content is never checked
+	PolicyReject Policy = "reject" // flagged content is refused
+	PolicyMask   Policy = "mask"   // flagged terms are replaced with asterisks
+)
+
+// Match is a flagged term's position in the checked text, in bytes
+type Match struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Filter finds disallowed terms in text. Implementations must be safe for concurrent use.
+type Filter interface {
+	Check(text string) []Match
+}
+
+// DenylistFilter flags whole words that appear in a list, ignoring case
+type DenylistFilter struct {
+	terms map[string]struct{}
+}
+
+// NewDenylistFilter creates a filter for the given words
+func NewDenylistFilter(words []string) *DenylistFilter {
+	terms := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			terms[word] = struct{}{}
+		}
+	}
+	return &DenylistFilter{terms: terms}
+}
+
+// Check returns every denylisted word in text
+func (f *DenylistFilter) Check(text string) []Match {
+	var matches []Match
+	start := -1
+	for i, r := range text + " " {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWordRune && start < 0:
+			start = i
+		case !isWordRune && start >= 0:
+			word := strings.ToLower(text[start:i])
+			if _, ok := f.terms[word]; ok {
+				matches = append(matches, Match{Term: word, Start: start, End: i})
+			}
+			start = -1
+		}
+	}
+	return matches
+}
+
+// Moderator applies a Filter to user-generated content under a Policy.
+// A nil Moderator lets all content through.
+type Moderator struct {
+	filter Filter
+	policy Policy
+}
+
+// NewModerator creates a moderator
+func NewModerator(filter Filter, policy Policy) *Moderator {
+	return &Moderator{filter: filter, policy: policy}
+}
+
+// NewModeratorFromEnv builds a denylist moderator from CONTENT_FILTER_POLICY
+// (off, reject, or mask) and the words in CONTENT_FILTER_WORDS (comma
+// separated) and CONTENT_FILTER_WORDS_FILE (one word per line)
+func NewModeratorFromEnv() *Moderator {
+	policy := Policy(strings.ToLower(utils.GetEnv("CONTENT_FILTER_POLICY", string(PolicyMask))))
+	switch policy {
+	case PolicyOff, PolicyReject, PolicyMask:
+	default:
+		log.Printf("Unknown CONTENT_FILTER_POLICY %q, masking flagged content", policy)
+		policy = PolicyMask
+	}
+
+	words := strings.Split(os.Getenv("CONTENT_FILTER_WORDS"), ",")
+	if path := os.Getenv("CONTENT_FILTER_WORDS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Failed to read content filter words file: %v", err)
+		} else {
+			words = append(words, strings.Split(string(data), "\n")...)
+		}
+	}
+
+	return NewModerator(NewDenylistFilter(words), policy)
+}
+
+// Apply checks text and returns it unchanged if clean, masked under
+// PolicyMask, or ErrContentRejected under PolicyReject
+func (m *Moderator) Apply(text string) (string, error) {
+	if m == nil || m.policy == PolicyOff || text == "" {
+		return text, nil
+	}
+
+	matches := m.filter.Check(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+	if m.policy == PolicyReject {
+		return "", ErrContentRejected
+	}
+
+	var masked strings.Builder
+	last := 0
+	for _, match := range matches {
+		masked.WriteString(text[last:match.Start])
+		masked.WriteString(strings.Repeat("*", len([]rune(text[match.Start:match.End]))))
+		last = match.End
+	}
+	masked.WriteString(text[last:])
+	return masked.String(), nil
+}
@@ -0,0 +1,43 @@
+package contentfilter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestModeratorPolicies(t *testing.T) {
+	filter := NewDenylistFilter([]string{"darn", " Heck "})
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		text    string
+		want    string
+		wantErr error
+	}{
+		{"clean under reject", PolicyReject, "Great pitch deck", "Great pitch deck", nil},
+		{"flagged under reject", PolicyReject, "What the heck is this", "", ErrContentRejected},
+		{"clean under mask", PolicyMask, "Great pitch deck", "Great pitch deck", nil},
+		{"flagged under mask", PolicyMask, "Darn, what the HECK!", "****, what the ****!", nil},
+		{"part of a longer word", PolicyMask, "Heckler was darning socks", "Heckler was darning socks", nil},
+		{"flagged under off", PolicyOff, "darn", "darn", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewModerator(filter, tt.policy).Apply(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Apply = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilModeratorAllowsEverything(t *testing.T) {
+	var moderator *Moderator
+	if got, err := moderator.Apply("darn"); err != nil || got != "darn" {
+		t.Errorf("Apply = %q, %v; want the text unchanged", got, err)
+	}
+}
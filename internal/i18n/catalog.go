@@ -0,0 +1,134 @@
+package i18n
+
+// catalogs maps locale -> message key -> message template. Every key must
+// exist in DefaultLocale; other locales may omit keys and fall back to it.
+var catalogs = map[string]map[string]string{
+	"en": {
+		// Match reasons
+		"reason.common_tags":        "Common interests: %s",
+		"reason.common_skills":      "Common skills: %s",
+		"reason.shared_interests":   "Shared interests: %s",
+		"reason.similar_experience": "Similar experience level",
+		"reason.same_location":      "Same location",
+		"reason.good_compatibility": "Good overall compatibility",
+
+		// Errors
+		"error.unauthenticated":          "User not authenticated",
+		"error.auth_header_required":     "Authorization header required",
+		"error.auth_header_invalid":      "Invalid authorization header format",
+		"error.invalid_token":            "Invalid token",
+		"error.csrf_invalid":             "Missing or invalid CSRF token",
+		"error.admin_required":           "Admin access required",
+		"error.organizer_required":       "Organizer or admin access required",
+		"error.service_unavailable":      "Service temporarily unavailable",
+		"error.body_too_large":           "Request body too large",
+		"error.body_unreadable":          "Failed to read request body",
+		"error.invalid_body":             "Invalid request body",
+		"error.user_id_required":         "User ID is required",
+		"error.match_id_required":        "Match ID is required",
+		"error.profile_not_found":        "User profile not found",
+		"error.match_not_found":          "Match not found",
+		"error.unknown_weight_profile":   "Unknown weight profile",
+		"error.invalid_cursor":           "Invalid cursor",
+		"error.export_format":            "format must be csv or json",
+		"error.profile_forbidden":        "Not authorized to submit a profile for this user",
+		"error.matches_forbidden":        "Not authorized to view these matches",
+		"error.match_forbidden":          "Not authorized to update this match",
+		"error.match_duplicate":          "Match listed more than once; only its first update was applied",
+		"error.bulk_too_large":           "At most %d matches can be updated at once",
+		"error.export_forbidden":         "Not authorized to export these matches",
+		"error.completeness_forbidden":   "Not authorized to view this profile's completeness",
+		"error.undo_expired":             "The status change can no longer be undone",
+		"error.nothing_to_undo":          "No status change to undo",
+		"error.profile_create_failed":    "Failed to create user profile",
+		"error.matches_find_failed":      "Failed to find matches",
+		"error.matches_retrieve_failed":  "Failed to retrieve matches",
+		"error.match_update_failed":      "Failed to update match",
+		"error.profiles_retrieve_failed": "Failed to retrieve profiles",
+		"error.presence_unavailable":     "Online status is unavailable right now",
+		"error.connections_failed":       "Failed to retrieve connections",
+		"error.network_forbidden":        "Not authorized to view this network",
+		"error.network_depth":            "depth must be between %d and %d",
+		"error.viewers_forbidden":        "Not authorized to view this profile's viewers",
+		"error.viewers_failed":           "Failed to retrieve profile viewers",
+		"error.searchable_forbidden":     "Not authorized to change this profile's discoverability",
+		"error.pause_forbidden":          "Not authorized to pause matchmaking for this profile",
+		"error.pause_in_past":            "until must be in the future",
+		"error.matrix_too_large":         "A compatibility matrix covers at most %d users",
+		"error.rebuild_running":          "A match rebuild is already running",
+		"error.rebuild_not_running":      "No match rebuild is running",
+		"error.rebuild_not_found":        "No match rebuild has run recently",
+		"error.rebuild_failed":           "Failed to manage the match rebuild",
+		"error.content_rejected":         "Content contains disallowed language",
+		"error.boost_out_of_range":       "boost must be greater than 0 and at most %v",
+		"error.profile_update_failed":    "Failed to update user profile",
+		"error.stats_failed":             "Failed to compute matchmaking stats",
+		"error.rescore_forbidden":        "Not authorized to re-score these matches",
+		"error.matches_busy":             "Matches are already being computed for this user; try again shortly",
+		"error.rescore_failed":           "Failed to re-score matches",
+		"error.preference_weights":       "Invalid preference_weights: %v",
+	},
+	"es": {
+		"reason.common_tags":        "Intereses en común: %s",
+		"reason.common_skills":      "Habilidades en común: %s",
+		"reason.shared_interests":   "Aficiones compartidas: %s",
+		"reason.similar_experience": "Nivel de experiencia similar",
+		"reason.same_location":      "Misma ubicación",
+		"reason.good_compatibility": "Buena compatibilidad general",
+
+		"error.unauthenticated":          "Usuario no autenticado",
+		"error.auth_header_required":     "Se requiere el encabezado Authorization",
+		"error.auth_header_invalid":      "Formato del encabezado Authorization no válido",
+		"error.invalid_token":            "Token no válido",
+		"error.csrf_invalid":             "Falta el token CSRF o no es válido",
+		"error.admin_required":           "Se requiere acceso de administrador",
+		"error.organizer_required":       "Se requiere acceso de organizador o administrador",
+		"error.service_unavailable":      "Servicio no disponible temporalmente",
+		"error.body_too_large":           "El cuerpo de la solicitud es demasiado grande",
+		"error.body_unreadable":          "No se pudo leer el cuerpo de la solicitud",
+		"error.invalid_body":             "Cuerpo de la solicitud no válido",
+		"error.user_id_required":         "Se requiere el ID de usuario",
+		"error.match_id_required":        "Se requiere el ID de la coincidencia",
+		"error.profile_not_found":        "Perfil de usuario no encontrado",
+		"error.match_not_found":          "Coincidencia no encontrada",
+		"error.unknown_weight_profile":   "Perfil de ponderación desconocido",
+		"error.invalid_cursor":           "Cursor no válido",
+		"error.export_format":            "format debe ser csv o json",
+		"error.profile_forbidden":        "No tienes permiso para enviar un perfil de este usuario",
+		"error.matches_forbidden":        "No tienes permiso para ver estas coincidencias",
+		"error.match_forbidden":          "No tienes permiso para actualizar esta coincidencia",
+		"error.match_duplicate":          "Coincidencia incluida más de una vez; solo se aplicó su primera actualización",
+		"error.bulk_too_large":           "Se pueden actualizar como máximo %d coincidencias a la vez",
+		"error.export_forbidden":         "No tienes permiso para exportar estas coincidencias",
+		"error.completeness_forbidden":   "No tienes permiso para ver la completitud de este perfil",
+		"error.undo_expired":             "El cambio de estado ya no se puede deshacer",
+		"error.nothing_to_undo":          "No hay ningún cambio de estado que deshacer",
+		"error.profile_create_failed":    "No se pudo crear el perfil de usuario",
+		"error.matches_find_failed":      "No se pudieron buscar coincidencias",
+		"error.matches_retrieve_failed":  "No se pudieron obtener las coincidencias",
+		"error.match_update_failed":      "No se pudo actualizar la coincidencia",
+		"error.profiles_retrieve_failed": "No se pudieron obtener los perfiles",
+		"error.presence_unavailable":     "El estado en línea no está disponible en este momento",
+		"error.connections_failed":       "No se pudieron obtener las conexiones",
+		"error.network_forbidden":        "No tienes permiso para ver esta red",
+		"error.network_depth":            "depth debe estar entre %d y %d",
+		"error.viewers_forbidden":        "No tienes permiso para ver quién visitó este perfil",
+		"error.viewers_failed":           "No se pudieron obtener las visitas del perfil",
+		"error.searchable_forbidden":     "No tienes permiso para cambiar la visibilidad en búsquedas de este perfil",
+		"error.pause_forbidden":          "No tienes permiso para pausar las coincidencias de este perfil",
+		"error.pause_in_past":            "until debe ser una fecha futura",
+		"error.matrix_too_large":         "Una matriz de compatibilidad admite como máximo %d usuarios",
+		"error.rebuild_running":          "Ya hay una reconstrucción de coincidencias en curso",
+		"error.rebuild_not_running":      "No hay ninguna reconstrucción de coincidencias en curso",
+		"error.rebuild_not_found":        "No se ha ejecutado ninguna reconstrucción de coincidencias recientemente",
+		"error.rebuild_failed":           "No se pudo gestionar la reconstrucción de coincidencias",
+		"error.content_rejected":         "El contenido incluye lenguaje no permitido",
+		"error.boost_out_of_range":       "boost debe ser mayor que 0 y como máximo %v",
+		"error.profile_update_failed":    "No se pudo actualizar el perfil de usuario",
+		"error.stats_failed":             "No se pudieron calcular las estadísticas de coincidencias",
+		"error.rescore_forbidden":        "No tienes permiso para recalcular estas coincidencias",
+		"error.matches_busy":             "Ya se están calculando las coincidencias de este usuario; inténtalo de nuevo en breve",
+		"error.rescore_failed":           "No se pudieron recalcular las coincidencias",
+		"error.preference_weights":       "preference_weights no válido: %v",
+	},
+}
@@ -0,0 +1,76 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when a request asks for no supported locale
+const DefaultLocale = "en"
+
+// Supported reports whether there is a catalog for locale
+func Supported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// ParseAcceptLanguage picks the supported locale a client prefers most from an
+// Accept-Language header (e.g. "es-MX,es;q=0.9,en;q=0.8"), matching on the
+// primary language subtag, or DefaultLocale if none is supported
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		locale, _, _ := strings.Cut(tag, "-")
+		if q > 0 && Supported(locale) {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+
+	// Stable so equally weighted languages keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
+
+// T renders the message for key in locale, falling back to DefaultLocale and
+// then to the key itself. args fill the message's fmt verbs.
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		if message, ok = catalogs[DefaultLocale][key]; !ok {
+			message = key
+		}
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
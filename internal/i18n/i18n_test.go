@@ -0,0 +1,35 @@
+package i18n
+
+import "testing"
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", DefaultLocale},
+		{"es", "es"},
+		{"es-MX,es;q=0.9,en;q=0.8", "es"},
+		{"en;q=0.5,es;q=0.9", "es"},
+		{"de-DE,fr;q=0.8", DefaultLocale},
+		{"es;q=0,en", "en"},
+		{"*", DefaultLocale},
+	}
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTFallsBack(t *testing.T) {
+	if got := T("es", "reason.common_skills", "go"); got != "Habilidades en común: go" {
+		t.Errorf("T(es) = %q", got)
+	}
+	if got := T("fr", "reason.common_skills", "go"); got != "Common skills: go" {
+		t.Errorf("T(unsupported locale) = %q, want the English message", got)
+	}
+	if got := T("es", "no.such.key"); got != "no.such.key" {
+		t.Errorf("T(unknown key) = %q, want the key itself", got)
+	}
+}
@@ -0,0 +1,38 @@
+package matchmaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// storeUpdatedProfile stores the profile a user-updated event carries. An
+// account-only event creates a minimal profile for a user who has none, so
+// new users are matchable straight after registering; for a user who
+// already has a profile it only updates the account fields.
+func (s *Service) storeUpdatedProfile(ctx context.Context, event models.UserUpdatedEvent) error {
+	if !event.AccountOnly {
+		return s.StoreUserProfile(ctx, event.Profile)
+	}
+
+	existing, err := s.GetUserProfile(ctx, event.UserID)
+	if err == redis.Nil {
+		profile := event.Profile
+		if profile.Visibility == "" {
+			profile.Visibility = models.ProfileVisibilityPublic
+		}
+		return s.StoreUserProfile(ctx, profile)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The stored profile is already normalized; normalizing it again would
+	// lose its display names
+	existing.Name = event.Profile.Name
+	existing.UpdatedAt = time.Now()
+	return s.storeNormalizedProfile(ctx, *existing)
+}
@@ -0,0 +1,79 @@
+package matchmaker
+
+import (
+	"context"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// MatchNotifier tells both users of a match about it, for example over their
+// WebSocket connections
+type MatchNotifier func(ctx context.Context, match models.Match)
+
+// SetAutoAcceptNotifier sets who is told when a match is accepted automatically
+func (s *Service) SetAutoAcceptNotifier(notifier MatchNotifier) {
+	s.autoAcceptNotifier.Store(&notifier)
+}
+
+// autoAccept accepts a new match on both users' behalf when its unboosted
+// score is above the auto-accept threshold. It is only used for pairs neither
+// user has responded to yet. Each acceptance is recorded in
+// the status history like a manual one, so either user can still undo it
+// within the undo window.
+func (s *Service) autoAccept(match *models.Match, score float64) {
+	if s.config.AutoAcceptThreshold <= 0 || score <= s.config.AutoAcceptThreshold {
+		return
+	}
+
+	match.SetUserStatus(match.UserID1, models.MatchStatusAccepted)
+	match.SetUserStatus(match.UserID2, models.MatchStatusAccepted)
+	match.AutoAccepted = true
+}
+
+// respondedMatches returns, for each user with a stored match with userID
+// that either of them has responded to, the most recently updated such match
+func (s *Service) respondedMatches(ctx context.Context, userID string) (map[string]models.Match, error) {
+	stored, err := s.GetMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responded := make(map[string]models.Match)
+	for _, match := range stored {
+		if untouched(match) {
+			continue
+		}
+		otherID := match.UserID2
+		if match.UserID2 == userID {
+			otherID = match.UserID1
+		}
+		if kept, exists := responded[otherID]; exists && !match.UpdatedAt.After(kept.UpdatedAt) {
+			continue
+		}
+		responded[otherID] = match
+	}
+	return responded, nil
+}
+
+// keepResponses gives a recomputed match both users' responses to the pair's
+// stored match, whichever way round that match was computed, so a pair one
+// user rejected isn't accepted again on their behalf
+func keepResponses(match *models.Match, stored models.Match) {
+	match.User1Status, match.User2Status = stored.User1Status, stored.User2Status
+	if stored.UserID1 != match.UserID1 {
+		match.User1Status, match.User2Status = stored.User2Status, stored.User1Status
+	}
+	match.StatusHistory = stored.StatusHistory
+	match.DeriveStatus()
+}
+
+// NotifyAutoAccepted tells both users about a match that was accepted
+// automatically; other matches are left to the usual match-created event
+func (s *Service) NotifyAutoAccepted(ctx context.Context, match models.Match) {
+	if !match.AutoAccepted {
+		return
+	}
+	if notifier := s.autoAcceptNotifier.Load(); notifier != nil && *notifier != nil {
+		(*notifier)(ctx, match)
+	}
+}
@@ -0,0 +1,81 @@
+package matchmaker
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestAutoAcceptAboveThreshold(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.events = &flakyWriter{}
+	ctx := context.Background()
+
+	for _, profile := range []models.UserProfile{
+		{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go", "sql"}, Location: "berlin", Experience: 5},
+		{UserID: "bob", Tags: []string{"fintech"}, Skills: []string{"go", "sql"}, Location: "berlin", Experience: 5},
+		{UserID: "carol", Tags: []string{"fintech"}, Skills: []string{"go", "design"}, Location: "lisbon", Experience: 5},
+	} {
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	// Off by default: every new match waits for both users
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want bob and carol", len(matches))
+	}
+	scores := make(map[string]float64)
+	for _, match := range matches {
+		if match.AutoAccepted || match.User1Status != models.MatchStatusPending || match.User2Status != models.MatchStatusPending {
+			t.Errorf("match with %s = %+v, want pending with auto-accept off", match.UserID2, match)
+		}
+		scores[match.UserID2] = match.Score
+	}
+	if scores["bob"] <= scores["carol"] {
+		t.Fatalf("bob scored %v, carol %v; want bob higher", scores["bob"], scores["carol"])
+	}
+
+	service.config.AutoAcceptThreshold = (scores["bob"] + scores["carol"]) / 2
+	var mu sync.Mutex
+	var notified []string
+	service.SetAutoAcceptNotifier(func(ctx context.Context, match models.Match) {
+		mu.Lock()
+		defer mu.Unlock()
+		notified = append(notified, match.UserID2)
+	})
+
+	matches, err = service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	for _, match := range matches {
+		want := models.MatchStatusPending
+		if match.UserID2 == "bob" {
+			want = models.MatchStatusAccepted
+		}
+		if match.User1Status != want || match.User2Status != want || match.AutoAccepted != (want == models.MatchStatusAccepted) {
+			t.Errorf("match with %s (score %v) statuses = %s/%s, want %s", match.UserID2, match.Score, match.User1Status, match.User2Status, want)
+		}
+		if err := service.StoreMatchWithEvent(ctx, match); err != nil {
+			t.Fatalf("StoreMatchWithEvent: %v", err)
+		}
+	}
+	// Both users hear about it once its match-created event is published
+	if err := service.RelayOutbox(ctx); err != nil {
+		t.Fatalf("RelayOutbox: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notified) != 1 || notified[0] != "bob" {
+		t.Errorf("notified about %v, want only the auto-accepted match with bob", notified)
+	}
+}
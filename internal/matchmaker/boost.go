@@ -0,0 +1,57 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// ErrBoostOutOfRange is returned for a boost that is not positive or exceeds MaxBoost
+var ErrBoostOutOfRange = errors.New("boost out of range")
+
+// Boost returns the multiplier applied to a profile's match scores, capped at MaxBoost
+func (s *Service) Boost(profile *models.UserProfile) float64 {
+	if profile.Boost <= 0 {
+		return 1
+	}
+	return min(profile.Boost, s.config.MaxBoost)
+}
+
+// BoostedScore applies the candidate's boost to a raw match score. Boosted
+// scores are ranked alongside unboosted ones, so the threshold is still
+// checked against the raw score. raw is 0 when the candidate isn't boosted.
+func (s *Service) BoostedScore(score float64, candidate *models.UserProfile) (boosted, raw float64) {
+	boost := s.Boost(candidate)
+	if boost == 1 {
+		return score, 0
+	}
+	return score * boost, score
+}
+
+// MaxBoost returns the largest boost a profile can be given
+func (s *Service) MaxBoost() float64 {
+	return s.config.MaxBoost
+}
+
+// SetBoost sets a user's boost multiplier; 1 removes the boost. It takes
+// effect the next time other users' matches are computed.
+func (s *Service) SetBoost(ctx context.Context, userID string, boost float64) (*models.UserProfile, error) {
+	if boost <= 0 || boost > s.config.MaxBoost {
+		return nil, ErrBoostOutOfRange
+	}
+
+	profile, err := s.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Boost = boost
+	if boost == 1 {
+		profile.Boost = 0
+	}
+	if err := s.storeNormalizedProfile(ctx, *profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
@@ -0,0 +1,48 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestBoostedProfileRanksAboveEqualUnboosted(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", userID, err)
+		}
+	}
+	if _, err := service.SetBoost(ctx, "carol", 1.5); err != nil {
+		t.Fatalf("SetBoost: %v", err)
+	}
+
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+
+	carol, bob := matches[0], matches[1]
+	if carol.UserID2 != "carol" || bob.UserID2 != "bob" {
+		t.Fatalf("ranking = %s, %s; want the boosted carol first", carol.UserID2, bob.UserID2)
+	}
+	if carol.RawScore != bob.Score {
+		t.Errorf("carol raw score %v differs from bob's score %v for identical profiles", carol.RawScore, bob.Score)
+	}
+	if carol.Score != carol.RawScore*1.5 || bob.RawScore != 0 {
+		t.Errorf("carol score %v (raw %v), bob raw %v; want carol boosted 1.5x and bob unboosted", carol.Score, carol.RawScore, bob.RawScore)
+	}
+
+	if _, err := service.SetBoost(ctx, "carol", service.MaxBoost()+1); !errors.Is(err, ErrBoostOutOfRange) {
+		t.Errorf("SetBoost above the cap = %v, want ErrBoostOutOfRange", err)
+	}
+}
@@ -0,0 +1,108 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"log"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// profileBatchSize is how many profiles are fetched from Redis per round trip
+const profileBatchSize = 500
+
+// candidateCeilingHits counts scans that loaded a sample of the stored
+// profiles because there were more than MaxCandidates
+var candidateCeilingHits = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "matchmaker_candidate_ceiling_hits_total",
+	Help: "Match computations that scanned a sample of the stored profiles because there were more than MATCH_MAX_CANDIDATES.",
+})
+
+// CandidateProfiles loads the stored profiles to scan when matching a user,
+// leaving out the user's own. Past MaxCandidates it loads a sample of that
+// many instead, chosen by hashing each profile with the user's id: a user
+// gets the same sample each time, while different users get different ones,
+// so every profile still gets considered for someone.
+func (s *Service) CandidateProfiles(ctx context.Context, userID string) ([]models.UserProfile, error) {
+	keys, err := utils.RedisClient.Keys(ctx, utils.UserProfileKeyPattern()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	own := utils.UserProfileKey(userID)
+	candidates := keys[:0]
+	for _, key := range keys {
+		if key != own {
+			candidates = append(candidates, key)
+		}
+	}
+
+	if limit := s.config.MaxCandidates; limit > 0 && len(candidates) > limit {
+		log.Printf("Matching user %s against %d of %d stored profiles (MATCH_MAX_CANDIDATES)", userID, limit, len(candidates))
+		candidateCeilingHits.Inc()
+		candidates = sampleKeys(candidates, userID, limit)
+	}
+
+	return loadProfiles(ctx, candidates)
+}
+
+// sampleKeys picks n keys, ranking each by a hash of it and seed
+func sampleKeys(keys []string, seed string, n int) []string {
+	type rankedKey struct {
+		key  string
+		rank uint64
+	}
+
+	ranked := make([]rankedKey, len(keys))
+	for i, key := range keys {
+		h := fnv.New64a()
+		h.Write([]byte(seed))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		ranked[i] = rankedKey{key: key, rank: h.Sum64()}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].rank != ranked[j].rank {
+			return ranked[i].rank < ranked[j].rank
+		}
+		return ranked[i].key < ranked[j].key
+	})
+
+	sample := make([]string, n)
+	for i := range sample {
+		sample[i] = ranked[i].key
+	}
+	return sample
+}
+
+// loadProfiles fetches the profiles stored under keys, skipping any that
+// expired meanwhile or don't parse
+func loadProfiles(ctx context.Context, keys []string) ([]models.UserProfile, error) {
+	var profiles []models.UserProfile
+	for start := 0; start < len(keys); start += profileBatchSize {
+		batch := keys[start:min(start+profileBatchSize, len(keys))]
+		values, err := utils.RedisClient.MGet(ctx, batch...).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				continue
+			}
+			var profile models.UserProfile
+			if err := json.Unmarshal([]byte(data), &profile); err != nil {
+				continue
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
@@ -0,0 +1,78 @@
+package matchmaker
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestCandidateProfilesNeverExceedCeiling(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.config.MaxCandidates = 4
+	ctx := context.Background()
+
+	for i := 0; i < 12; i++ {
+		profile := models.UserProfile{UserID: fmt.Sprintf("user-%02d", i), Tags: []string{"fintech"}, Location: "berlin", Experience: 5}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	hits := testutil.ToFloat64(candidateCeilingHits)
+	first, err := service.CandidateProfiles(ctx, "user-00")
+	if err != nil {
+		t.Fatalf("CandidateProfiles: %v", err)
+	}
+	if len(first) != 4 {
+		t.Fatalf("loaded %d profiles, want the ceiling of 4", len(first))
+	}
+	for _, profile := range first {
+		if profile.UserID == "user-00" {
+			t.Error("the user's own profile is a candidate")
+		}
+	}
+	if got := testutil.ToFloat64(candidateCeilingHits) - hits; got != 1 {
+		t.Errorf("ceiling hits grew by %v, want 1", got)
+	}
+
+	// The sample is the same each time for the same user
+	second, err := service.CandidateProfiles(ctx, "user-00")
+	if err != nil {
+		t.Fatalf("CandidateProfiles again: %v", err)
+	}
+	if !reflect.DeepEqual(userIDs(first), userIDs(second)) {
+		t.Errorf("samples differ: %v then %v", userIDs(first), userIDs(second))
+	}
+
+	matches, err := service.FindMatches(ctx, "user-00")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) > 4 {
+		t.Errorf("FindMatches scored %d candidates, want at most 4", len(matches))
+	}
+
+	// Without a ceiling everyone else is loaded
+	service.config.MaxCandidates = 0
+	all, err := service.CandidateProfiles(ctx, "user-00")
+	if err != nil {
+		t.Fatalf("CandidateProfiles uncapped: %v", err)
+	}
+	if len(all) != 11 {
+		t.Errorf("uncapped scan loaded %d profiles, want 11", len(all))
+	}
+}
+
+func userIDs(profiles []models.UserProfile) []string {
+	ids := make([]string, len(profiles))
+	for i, profile := range profiles {
+		ids[i] = profile.UserID
+	}
+	return ids
+}
@@ -0,0 +1,360 @@
+package matchmaker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// MatchWeights holds the relative weight of each profile attribute in the match score
+type MatchWeights struct {
+	Tags       float64 `json:"tags"`
+	Industries float64 `json:"industries"`
+	Experience float64 `json:"experience"`
+	Skills     float64 `json:"skills"`
+	Interests  float64 `json:"interests"`
+	Location   float64 `json:"location"`
+}
+
+// Validate reports a misconfigured set of weights: a negative or non-finite
+// weight, or weights that are all zero. Weights need not sum to 1; scores are
+// normalized by their total.
+func (w MatchWeights) Validate() error {
+	fields := []struct {
+		name   string
+		weight float64
+	}{
+		{"tags", w.Tags},
+		{"industries", w.Industries},
+		{"experience", w.Experience},
+		{"skills", w.Skills},
+		{"interests", w.Interests},
+		{"location", w.Location},
+	}
+
+	var total float64
+	for _, field := range fields {
+		if math.IsNaN(field.weight) || math.IsInf(field.weight, 0) || field.weight < 0 {
+			return fmt.Errorf("%s weight must be a non-negative number, got %v", field.name, field.weight)
+		}
+		total += field.weight
+	}
+	if total == 0 {
+		return errors.New("at least one weight must be greater than zero")
+	}
+	return nil
+}
+
+// DefaultMatchWeights returns the default attribute weights, which sum to 1
+func DefaultMatchWeights() MatchWeights {
+	return MatchWeights{
+		Tags:       0.25,
+		Industries: 0.2,
+		Experience: 0.15,
+		Skills:     0.15,
+		Interests:  0.15,
+		Location:   0.1,
+	}
+}
+
+// DefaultWeightProfile is the weight profile used when none is chosen
+const DefaultWeightProfile = "general"
+
+// DefaultWeightProfiles returns the built-in weight profiles for common personas
+func DefaultWeightProfiles() map[string]MatchWeights {
+	return map[string]MatchWeights{
+		DefaultWeightProfile: DefaultMatchWeights(),
+		"recruiting": {
+			Tags:       0.1,
+			Industries: 0.15,
+			Experience: 0.3,
+			Skills:     0.35,
+			Interests:  0.0,
+			Location:   0.1,
+		},
+		"cofounder": {
+			Tags:       0.15,
+			Industries: 0.15,
+			Experience: 0.1,
+			Skills:     0.2,
+			Interests:  0.25,
+			Location:   0.15,
+		},
+		"investor": {
+			Tags:       0.3,
+			Industries: 0.4,
+			Experience: 0.1,
+			Skills:     0.05,
+			Interests:  0.1,
+			Location:   0.05,
+		},
+	}
+}
+
+// Config holds the matchmaker tuning options
+type Config struct {
+	// Weights is the "general" weight profile
+	Weights MatchWeights
+
+	// WeightProfiles maps profile names to their attribute weights; it always
+	// contains DefaultWeightProfile
+	WeightProfiles map[string]MatchWeights
+
+	// Synonyms maps canonical (lowercase) terms to the term they should be stored as,
+	// e.g. "ml" -> "machine learning"
+	Synonyms map[string]string
+
+	// MinCommonAttributes is the number of shared tags or skills two profiles
+	// need before they can be matched; 0 disables the requirement
+	MinCommonAttributes int
+
+	// LockTTL bounds how long one instance holds a user's match computation lock
+	// before it must be extended
+	LockTTL time.Duration
+
+	// MaxStoredMatches is how many of a user's best matches are kept each time
+	// matches are computed; requests may override it up to MaxStoredMatchesLimit
+	MaxStoredMatches int
+
+	// MatchPageSize is how many stored matches a listing returns when the
+	// caller does not ask for a page size
+	MatchPageSize int
+
+	// ConsumerLagThreshold is the user-updated consumer lag, in messages, above
+	// which a warning is logged; 0 disables the warning
+	ConsumerLagThreshold int64
+
+	// ConsumerLagPollInterval is how often consumer lag is sampled
+	ConsumerLagPollInterval time.Duration
+
+	// UndoWindow is how long after changing their status on a match a user
+	// may undo the change
+	UndoWindow time.Duration
+
+	// MaxBoost caps the boost multiplier a profile can be given
+	MaxBoost float64
+
+	// MentorshipThreshold replaces MatchScoreThreshold for cross-level
+	// (mentor/mentee) pairs, which share less by design
+	MentorshipThreshold float64
+
+	// MentorshipExperienceGap is the experience gap, in years, at which a user
+	// declaring mentor or mentee seeking forms a cross-level pair
+	MentorshipExperienceGap int
+
+	// OutboxRelayInterval is how often the outbox relay looks for match-created
+	// events that are due for (re)publishing
+	OutboxRelayInterval time.Duration
+
+	// OutboxRetryBackoff is the delay before retrying a failed publish; it
+	// doubles with each failure up to OutboxMaxBackoff
+	OutboxRetryBackoff time.Duration
+	OutboxMaxBackoff   time.Duration
+
+	// MinProfileAge is how long a profile must exist before it appears in
+	// other users' matches; 0 disables the wait
+	MinProfileAge time.Duration
+
+	// RebuildRate caps how many profiles per second a global match rebuild
+	// recomputes, to keep it from overloading Redis; 0 removes the cap
+	RebuildRate float64
+
+	// ScoreBands are the thresholds used to describe match scores to clients
+	ScoreBands ScoreBands
+
+	// MaxMatrixUsers caps the group size of a compatibility matrix, whose
+	// cost grows with the square of it
+	MaxMatrixUsers int
+
+	// RedisRetryBackoff is how long the consumer waits before checking an
+	// unreachable Redis again; it doubles with each failure up to RedisMaxBackoff
+	RedisRetryBackoff time.Duration
+	RedisMaxBackoff   time.Duration
+
+	// UpdateDedupeWindow is how long a processed user-updated event is
+	// remembered so redeliveries of it are skipped; 0 disables deduplication
+	UpdateDedupeWindow time.Duration
+
+	// AutoAcceptThreshold is the unboosted score above which a new match is
+	// accepted on both users' behalf; 0 disables auto-accept
+	AutoAcceptThreshold float64
+
+	// StatsCacheTTL is how long aggregate stats are reused before they are
+	// recomputed from every stored match; 0 disables caching
+	StatsCacheTTL time.Duration
+
+	// MaxCandidates caps how many stored profiles one user's match scan
+	// loads, sampling that many when there are more; 0 removes the cap
+	MaxCandidates int
+}
+
+const (
+	// MaxStoredMatchesLimit bounds per-request overrides of MaxStoredMatches
+	MaxStoredMatchesLimit = 100
+
+	// MaxMatchPageSize bounds the default page size of match listings; the
+	// ?limit= a caller asks for is capped by PAGINATION_MAX_LIMIT instead
+	MaxMatchPageSize = 100
+)
+
+// LoadConfig reads the matchmaker configuration from the environment
+func LoadConfig() Config {
+	defaults := DefaultMatchWeights()
+	weights := MatchWeights{
+		Tags:       getEnvFloat("MATCH_WEIGHT_TAGS", defaults.Tags),
+		Industries: getEnvFloat("MATCH_WEIGHT_INDUSTRIES", defaults.Industries),
+		Experience: getEnvFloat("MATCH_WEIGHT_EXPERIENCE", defaults.Experience),
+		Skills:     getEnvFloat("MATCH_WEIGHT_SKILLS", defaults.Skills),
+		Interests:  getEnvFloat("MATCH_WEIGHT_INTERESTS", defaults.Interests),
+		Location:   getEnvFloat("MATCH_WEIGHT_LOCATION", defaults.Location),
+	}
+	if err := weights.Validate(); err != nil {
+		log.Printf("Invalid MATCH_WEIGHT_* configuration, using default weights: %v", err)
+		weights = defaults
+	}
+
+	profiles := loadWeightProfiles(os.Getenv("MATCH_WEIGHT_PROFILES_FILE"))
+	profiles[DefaultWeightProfile] = weights
+
+	return Config{
+		Weights:             weights,
+		WeightProfiles:      profiles,
+		Synonyms:            loadSynonyms(os.Getenv("MATCH_SYNONYMS_FILE")),
+		MinCommonAttributes: getEnvInt("MATCH_MIN_COMMON_ATTRIBUTES", 0),
+		LockTTL:             utils.GetEnvDuration("MATCH_LOCK_TTL", 30*time.Second),
+		MaxStoredMatches:    clamp(getEnvInt("MATCH_MAX_STORED", 10), 1, MaxStoredMatchesLimit),
+		MatchPageSize:       clamp(getEnvInt("MATCH_PAGE_SIZE", 10), 1, MaxMatchPageSize),
+
+		ConsumerLagThreshold:    int64(getEnvInt("MATCH_CONSUMER_LAG_WARN_THRESHOLD", 1000)),
+		ConsumerLagPollInterval: utils.GetEnvDuration("MATCH_CONSUMER_LAG_POLL_INTERVAL", 15*time.Second),
+		UndoWindow:              utils.GetEnvDuration("MATCH_UNDO_WINDOW", 5*time.Minute),
+		MaxBoost:                max(getEnvFloat("MATCH_MAX_BOOST", 2.0), 1.0),
+		MentorshipThreshold:     getEnvFloat("MATCH_MENTORSHIP_THRESHOLD", 0.2),
+		MentorshipExperienceGap: getEnvInt("MATCH_MENTORSHIP_EXPERIENCE_GAP", 5),
+		OutboxRelayInterval:     utils.GetEnvDuration("MATCH_OUTBOX_RELAY_INTERVAL", 5*time.Second),
+		OutboxRetryBackoff:      utils.GetEnvDuration("MATCH_OUTBOX_RETRY_BACKOFF", time.Second),
+		OutboxMaxBackoff:        utils.GetEnvDuration("MATCH_OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		MinProfileAge:           utils.GetEnvDuration("MATCH_MIN_PROFILE_AGE", 0),
+		RebuildRate:             max(getEnvFloat("MATCH_REBUILD_RATE", 20), 0),
+		ScoreBands:              loadScoreBands(),
+		MaxMatrixUsers:          clamp(getEnvInt("MATCH_MATRIX_MAX_USERS", 50), 2, MaxMatrixUsersLimit),
+		RedisRetryBackoff:       max(utils.GetEnvDuration("MATCH_REDIS_RETRY_BACKOFF", time.Second), time.Millisecond),
+		RedisMaxBackoff:         utils.GetEnvDuration("MATCH_REDIS_MAX_BACKOFF", 30*time.Second),
+		StatsCacheTTL:           utils.GetEnvDuration("MATCH_STATS_CACHE_TTL", time.Minute),
+		UpdateDedupeWindow:      utils.GetEnvDuration("MATCH_UPDATE_DEDUPE_WINDOW", time.Minute),
+		AutoAcceptThreshold:     max(getEnvFloat("MATCH_AUTO_ACCEPT_THRESHOLD", 0), 0),
+		MaxCandidates:           max(getEnvInt("MATCH_MAX_CANDIDATES", 10000), 0),
+	}
+}
+
+// loadScoreBands reads the score band thresholds, keeping the good band at or
+// below the strong one
+func loadScoreBands() ScoreBands {
+	defaults := DefaultScoreBands()
+	bands := ScoreBands{
+		Strong: getEnvFloat("MATCH_SCORE_BAND_STRONG", defaults.Strong),
+		Good:   getEnvFloat("MATCH_SCORE_BAND_GOOD", defaults.Good),
+	}
+	if bands.Good > bands.Strong {
+		log.Printf("MATCH_SCORE_BAND_GOOD (%v) is above MATCH_SCORE_BAND_STRONG (%v); using %v for both", bands.Good, bands.Strong, bands.Strong)
+		bands.Good = bands.Strong
+	}
+	return bands
+}
+
+// loadSynonyms reads a JSON object of term -> canonical term from a file
+func loadSynonyms(path string) map[string]string {
+	synonyms := make(map[string]string)
+	if path == "" {
+		return synonyms
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read synonyms file: %v", err)
+		return synonyms
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		log.Printf("Failed to parse synonyms file: %v", err)
+		return synonyms
+	}
+
+	for term, canonical := range raw {
+		key := strings.ToLower(strings.Join(strings.Fields(term), " "))
+		synonyms[key] = strings.ToLower(strings.Join(strings.Fields(canonical), " "))
+	}
+
+	return synonyms
+}
+
+// loadWeightProfiles returns the built-in weight profiles, overridden or
+// extended by a JSON object of profile name -> MatchWeights read from a file
+func loadWeightProfiles(path string) map[string]MatchWeights {
+	profiles := DefaultWeightProfiles()
+	if path == "" {
+		return profiles
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read weight profiles file: %v", err)
+		return profiles
+	}
+
+	var raw map[string]MatchWeights
+	if err := json.Unmarshal(data, &raw); err != nil {
+		log.Printf("Failed to parse weight profiles file: %v", err)
+		return profiles
+	}
+
+	for name, weights := range raw {
+		if err := weights.Validate(); err != nil {
+			log.Printf("Skipping invalid weight profile %q: %v", name, err)
+			continue
+		}
+		profiles[strings.ToLower(strings.TrimSpace(name))] = weights
+	}
+
+	return profiles
+}
+
+// getEnvFloat gets a float environment variable or returns a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// clamp limits n to the range [lo, hi]
+func clamp(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package matchmaker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// duplicateUpdates counts user-updated events skipped as redeliveries
+var duplicateUpdates = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "matchmaker_duplicate_updates_total",
+	Help: "User-updated events skipped because the same event was processed within the dedupe window.",
+})
+
+// updateFingerprint identifies a user-updated event by its timestamp and
+// profile content, so a redelivery of the same event has the same fingerprint
+func updateFingerprint(event models.UserUpdatedEvent) (string, error) {
+	hash, err := ProfileHash(event.Profile)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(event.Timestamp.UTC().Format(time.RFC3339Nano) + ":" + hash))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// isDuplicateUpdate reports whether event was already processed within the
+// dedupe window. It returns the event's dedupe key, which is empty when
+// deduplication is off or the event can't be fingerprinted.
+func (s *Service) isDuplicateUpdate(ctx context.Context, event models.UserUpdatedEvent) (string, bool) {
+	if s.config.UpdateDedupeWindow <= 0 {
+		return "", false
+	}
+
+	fingerprint, err := updateFingerprint(event)
+	if err != nil {
+		return "", false
+	}
+	key := utils.UserUpdateDedupeKey(event.UserID, fingerprint)
+
+	// A failed lookup processes the event; doing the work twice is safe
+	exists, err := utils.RedisClient.Exists(ctx, key).Result()
+	if err != nil || exists == 0 {
+		return key, false
+	}
+
+	duplicateUpdates.Inc()
+	return key, true
+}
+
+// markUpdateProcessed records that the event with dedupe key was processed,
+// so redeliveries within the dedupe window are skipped
+func (s *Service) markUpdateProcessed(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	utils.RedisClient.Set(ctx, key, 1, s.config.UpdateDedupeWindow)
+}
@@ -0,0 +1,59 @@
+package matchmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestRedeliveredUpdateIsProcessedOnce(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	service.events = &flakyWriter{}
+	service.config.UpdateDedupeWindow = time.Minute
+	ctx := context.Background()
+
+	event := models.UserUpdatedEvent{
+		UserID:    "alice",
+		Profile:   models.UserProfile{UserID: "alice", Tags: []string{"fintech"}},
+		Timestamp: time.Now(),
+	}
+	if err := service.ProcessUserUpdate(ctx, event); err != nil {
+		t.Fatalf("ProcessUserUpdate: %v", err)
+	}
+	profileKey := utils.UserProfileKey("alice")
+	if !server.Exists(profileKey) {
+		t.Fatal("first delivery did not store the profile")
+	}
+
+	// A recompute would store the profile again
+	server.Del(profileKey)
+	if err := service.ProcessUserUpdate(ctx, event); err != nil {
+		t.Fatalf("ProcessUserUpdate redelivery: %v", err)
+	}
+	if server.Exists(profileKey) {
+		t.Error("redelivered event was recomputed within the dedupe window")
+	}
+
+	// A later event for the same user is new work
+	event.Timestamp = event.Timestamp.Add(time.Second)
+	if err := service.ProcessUserUpdate(ctx, event); err != nil {
+		t.Fatalf("ProcessUserUpdate later event: %v", err)
+	}
+	if !server.Exists(profileKey) {
+		t.Error("a new event was skipped as a duplicate")
+	}
+
+	// Once the window passes the same event is processed again
+	server.Del(profileKey)
+	server.FastForward(time.Minute)
+	if err := service.ProcessUserUpdate(ctx, event); err != nil {
+		t.Fatalf("ProcessUserUpdate after the window: %v", err)
+	}
+	if !server.Exists(profileKey) {
+		t.Error("event still skipped after the dedupe window")
+	}
+}
@@ -0,0 +1,189 @@
+package matchmaker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// diagnosticsBuckets is the number of equal-width histogram buckets over [0, 1]
+const diagnosticsBuckets = 10
+
+// nearMissLimit caps how many rejected candidates just under the threshold are reported
+const nearMissLimit = 5
+
+// ScoreBreakdown is a match score split into each attribute's weighted contribution
+type ScoreBreakdown struct {
+	Tags       float64 `json:"tags"`
+	Industries float64 `json:"industries"`
+	Experience float64 `json:"experience"`
+	Skills     float64 `json:"skills"`
+	Interests  float64 `json:"interests"`
+	Location   float64 `json:"location"`
+	Total      float64 `json:"total"`
+}
+
+// HistogramBucket counts candidates whose score falls in [Min, Max)
+type HistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// CandidateDiagnostic describes how one candidate scored
+type CandidateDiagnostic struct {
+	UserID    string         `json:"user_id"`
+	Score     float64        `json:"score"`
+	Breakdown ScoreBreakdown `json:"breakdown"`
+}
+
+// StageCounts records how many candidates were dropped at each matching stage
+type StageCounts struct {
+	Candidates          int `json:"candidates"`
+	NotSearchable       int `json:"not_searchable"`
+	Paused              int `json:"paused"`
+	TooNew              int `json:"too_new"`
+	IncompatibleSeeking int `json:"incompatible_seeking"`
+	NotEnoughInCommon   int `json:"not_enough_in_common"`
+	BelowThreshold      int `json:"below_threshold"`
+	BeyondTopN          int `json:"beyond_top_n"`
+	Matched             int `json:"matched"`
+}
+
+// MatchDiagnostics explains how FindMatches treats a user's candidates
+type MatchDiagnostics struct {
+	UserID        string                `json:"user_id"`
+	Threshold     float64               `json:"threshold"` // peer threshold; mentorship pairs use MentorshipThreshold
+	WeightProfile string                `json:"weight_profile"`
+	Weights       MatchWeights          `json:"weights"`
+	Stages        StageCounts           `json:"stages"`
+	Histogram     []HistogramBucket     `json:"histogram"` // every scored candidate, before any filtering
+	NearMiss      []CandidateDiagnostic `json:"near_misses"`
+
+	// AverageContribution is the mean weighted contribution of each attribute
+	// across all scored candidates
+	AverageContribution ScoreBreakdown `json:"average_contribution"`
+}
+
+// Diagnose scores every candidate for a user the way FindMatches does and
+// reports the score distribution, stage drop-offs, and near misses
+func (s *Service) Diagnose(ctx context.Context, userID string) (*MatchDiagnostics, error) {
+	userProfile, err := s.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile: %v", err)
+	}
+
+	profiles, err := s.CandidateProfiles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate profiles: %v", err)
+	}
+
+	diagnostics := &MatchDiagnostics{
+		UserID:        userID,
+		Threshold:     MatchScoreThreshold,
+		WeightProfile: userProfile.WeightProfile,
+		Weights:       s.WeightsFor(userProfile),
+		Histogram:     make([]HistogramBucket, diagnosticsBuckets),
+		NearMiss:      []CandidateDiagnostic{},
+	}
+	for i := range diagnostics.Histogram {
+		diagnostics.Histogram[i].Min = float64(i) / diagnosticsBuckets
+		diagnostics.Histogram[i].Max = float64(i+1) / diagnosticsBuckets
+	}
+
+	var rejected []CandidateDiagnostic
+	var sum ScoreBreakdown
+	for _, profile := range profiles {
+		if profile.UserID == userID {
+			continue
+		}
+		diagnostics.Stages.Candidates++
+
+		breakdown := s.CalculateScoreBreakdown(userProfile, &profile, diagnostics.Weights)
+		diagnostics.Histogram[histogramBucket(breakdown.Total)].Count++
+		sum.add(breakdown)
+
+		if !profile.IsSearchable() {
+			diagnostics.Stages.NotSearchable++
+			continue
+		}
+
+		if profile.Paused(time.Now()) {
+			diagnostics.Stages.Paused++
+			continue
+		}
+
+		if !s.OldEnough(&profile, time.Now()) {
+			diagnostics.Stages.TooNew++
+			continue
+		}
+
+		if !s.SeekingCompatible(userProfile, &profile) {
+			diagnostics.Stages.IncompatibleSeeking++
+			continue
+		}
+
+		if !s.HasEnoughInCommon(userProfile, &profile) {
+			diagnostics.Stages.NotEnoughInCommon++
+			continue
+		}
+
+		if breakdown.Total <= s.MatchThreshold(userProfile, &profile) {
+			diagnostics.Stages.BelowThreshold++
+			rejected = append(rejected, CandidateDiagnostic{UserID: profile.UserID, Score: breakdown.Total, Breakdown: breakdown})
+			continue
+		}
+
+		diagnostics.Stages.Matched++
+	}
+
+	if limit := s.config.MaxStoredMatches; diagnostics.Stages.Matched > limit {
+		diagnostics.Stages.BeyondTopN = diagnostics.Stages.Matched - limit
+		diagnostics.Stages.Matched = limit
+	}
+
+	if n := float64(diagnostics.Stages.Candidates); n > 0 {
+		diagnostics.AverageContribution = ScoreBreakdown{
+			Tags:       sum.Tags / n,
+			Industries: sum.Industries / n,
+			Experience: sum.Experience / n,
+			Skills:     sum.Skills / n,
+			Interests:  sum.Interests / n,
+			Location:   sum.Location / n,
+			Total:      sum.Total / n,
+		}
+	}
+
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Score > rejected[j].Score
+	})
+	if len(rejected) > nearMissLimit {
+		rejected = rejected[:nearMissLimit]
+	}
+	diagnostics.NearMiss = append(diagnostics.NearMiss, rejected...)
+
+	return diagnostics, nil
+}
+
+// histogramBucket returns the bucket index for a score, placing 1.0 in the last bucket
+func histogramBucket(score float64) int {
+	bucket := int(score * diagnosticsBuckets)
+	if bucket < 0 {
+		return 0
+	}
+	if bucket >= diagnosticsBuckets {
+		return diagnosticsBuckets - 1
+	}
+	return bucket
+}
+
+func (b *ScoreBreakdown) add(other ScoreBreakdown) {
+	b.Tags += other.Tags
+	b.Industries += other.Industries
+	b.Experience += other.Experience
+	b.Skills += other.Skills
+	b.Interests += other.Interests
+	b.Location += other.Location
+	b.Total += other.Total
+}
@@ -0,0 +1,59 @@
+package matchmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestDiagnoseHistogramCoversEveryCandidate(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	paused := time.Now().Add(time.Hour)
+	hidden := false
+	profiles := []models.UserProfile{
+		{UserID: "alice", Tags: []string{"fintech", "ai"}, Skills: []string{"go"}, Location: "berlin", Experience: 5},
+		{UserID: "bob", Tags: []string{"fintech", "ai"}, Skills: []string{"go"}, Location: "berlin", Experience: 5},
+		{UserID: "carol", Tags: []string{"fintech"}, Skills: []string{"rust"}, Location: "paris", Experience: 2},
+		{UserID: "dave", Tags: []string{"biotech"}, Location: "tokyo", Experience: 20},
+		{UserID: "erin", Tags: []string{"fintech", "ai"}, Skills: []string{"go"}, Location: "berlin", Experience: 5, Searchable: &hidden},
+		{UserID: "frank", Tags: []string{"fintech", "ai"}, Skills: []string{"go"}, Location: "berlin", Experience: 5, PausedUntil: &paused},
+	}
+	for _, profile := range profiles {
+		profile.CreatedAt = time.Now().Add(-24 * time.Hour)
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	diagnostics, err := service.Diagnose(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Diagnose: %v", err)
+	}
+
+	stages := diagnostics.Stages
+	if stages.Candidates != len(profiles)-1 {
+		t.Fatalf("candidates = %d, want %d", stages.Candidates, len(profiles)-1)
+	}
+
+	total := 0
+	for _, bucket := range diagnostics.Histogram {
+		total += bucket.Count
+	}
+	if total != stages.Candidates {
+		t.Errorf("histogram counts sum to %d, want %d", total, stages.Candidates)
+	}
+
+	accounted := stages.NotSearchable + stages.Paused + stages.TooNew + stages.IncompatibleSeeking +
+		stages.NotEnoughInCommon + stages.BelowThreshold + stages.BeyondTopN + stages.Matched
+	if accounted != stages.Candidates {
+		t.Errorf("stages account for %d candidates, want %d: %+v", accounted, stages.Candidates, stages)
+	}
+	if stages.NotSearchable != 1 || stages.Paused != 1 {
+		t.Errorf("not searchable = %d, paused = %d, want 1 and 1", stages.NotSearchable, stages.Paused)
+	}
+}
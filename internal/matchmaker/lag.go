@@ -0,0 +1,86 @@
+package matchmaker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/segmentio/kafka-go"
+)
+
+// consumerLag reports how far the user-updated consumer is behind the
+// partition high watermark
+var consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "matchmaker_consumer_lag",
+	Help: "Messages between the matchmaker consumer's offset and the topic high watermark.",
+}, []string{"topic"})
+
+// ReaderStatsSource provides Kafka reader statistics; *kafka.Reader implements it
+type ReaderStatsSource interface {
+	Stats() kafka.ReaderStats
+}
+
+// LagMonitor polls a Kafka reader's lag, exports it as a gauge, and warns
+// when it crosses a threshold
+type LagMonitor struct {
+	source    ReaderStatsSource
+	threshold int64
+	interval  time.Duration
+	logf      func(format string, args ...interface{})
+
+	lagging bool
+}
+
+// NewLagMonitor creates a monitor that warns once lag exceeds threshold messages
+func NewLagMonitor(source ReaderStatsSource, threshold int64, interval time.Duration) *LagMonitor {
+	return &LagMonitor{
+		source:    source,
+		threshold: threshold,
+		interval:  interval,
+		logf:      log.Printf,
+	}
+}
+
+// Run polls until ctx is cancelled
+func (m *LagMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check records the current lag and logs when it crosses the threshold in
+// either direction, so a sustained backlog logs once rather than every poll
+func (m *LagMonitor) check() int64 {
+	stats := m.source.Stats()
+	consumerLag.WithLabelValues(stats.Topic).Set(float64(stats.Lag))
+
+	switch {
+	case m.threshold > 0 && stats.Lag > m.threshold && !m.lagging:
+		m.lagging = true
+		m.logf("WARNING: matchmaker consumer lag on %s is %d messages (threshold %d); match computation is falling behind",
+			stats.Topic, stats.Lag, m.threshold)
+	case m.lagging && stats.Lag <= m.threshold:
+		m.lagging = false
+		m.logf("Matchmaker consumer lag on %s recovered to %d messages", stats.Topic, stats.Lag)
+	}
+
+	return stats.Lag
+}
+
+// StartLagMonitor polls the user-updated consumer's lag until ctx is cancelled
+func (s *Service) StartLagMonitor(ctx context.Context) {
+	if s.config.ConsumerLagPollInterval <= 0 {
+		return
+	}
+	NewLagMonitor(s.reader, s.config.ConsumerLagThreshold, s.config.ConsumerLagPollInterval).Run(ctx)
+}
@@ -0,0 +1,56 @@
+package matchmaker
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/segmentio/kafka-go"
+)
+
+// stubStats reports a fixed lag for a topic
+type stubStats struct {
+	lag int64
+}
+
+func (s *stubStats) Stats() kafka.ReaderStats {
+	return kafka.ReaderStats{Topic: "user-updated-test", Lag: s.lag}
+}
+
+func TestLagMonitorWarnsOnceAboveThreshold(t *testing.T) {
+	source := &stubStats{}
+	monitor := NewLagMonitor(source, 100, 0)
+	var logged []string
+	monitor.logf = func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	for _, step := range []struct {
+		lag      int64
+		wantLogs int
+	}{
+		{lag: 50, wantLogs: 0},  // under the threshold
+		{lag: 100, wantLogs: 0}, // at the threshold
+		{lag: 250, wantLogs: 1}, // crossed: one warning
+		{lag: 400, wantLogs: 1}, // still lagging: no repeat
+		{lag: 20, wantLogs: 2},  // recovered
+		{lag: 10, wantLogs: 2},
+	} {
+		source.lag = step.lag
+		monitor.check()
+		if len(logged) != step.wantLogs {
+			t.Fatalf("after lag %d: %d log lines %q, want %d", step.lag, len(logged), logged, step.wantLogs)
+		}
+		if got := testutil.ToFloat64(consumerLag.WithLabelValues("user-updated-test")); got != float64(step.lag) {
+			t.Errorf("gauge = %v, want %d", got, step.lag)
+		}
+	}
+
+	if !strings.HasPrefix(logged[0], "WARNING") || !strings.Contains(logged[0], "250") {
+		t.Errorf("warning = %q, want a WARNING with the lag", logged[0])
+	}
+	if !strings.Contains(logged[1], "recovered") {
+		t.Errorf("recovery log = %q", logged[1])
+	}
+}
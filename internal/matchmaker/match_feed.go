@@ -0,0 +1,30 @@
+package matchmaker
+
+import (
+	"context"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// Match lifecycle events reported after a match is created. New matches are
+// announced on MatchesCreatedTopic instead.
+const (
+	MatchEventUpdated = "match_updated" // a user responded to it, or its score was recomputed
+	MatchEventRemoved = "match_removed" // it was dropped when its user's matches were rebuilt
+)
+
+// MatchFeedNotifier tells both users of a match that it changed
+type MatchFeedNotifier func(ctx context.Context, event string, match models.Match)
+
+// SetMatchFeedNotifier sets who is told when a stored match changes or is removed
+func (s *Service) SetMatchFeedNotifier(notifier MatchFeedNotifier) {
+	s.matchFeedNotifier.Store(&notifier)
+}
+
+// NotifyMatchChanged reports a MatchEventUpdated or MatchEventRemoved event
+// for a match to the match feed, if one is set
+func (s *Service) NotifyMatchChanged(ctx context.Context, event string, match models.Match) {
+	if notifier := s.matchFeedNotifier.Load(); notifier != nil && *notifier != nil {
+		(*notifier)(ctx, event, match)
+	}
+}
@@ -0,0 +1,96 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// MaxMatrixUsersLimit bounds the configurable compatibility matrix group size
+const MaxMatrixUsersLimit = 200
+
+var ErrMatrixTooLarge = errors.New("too many users for a compatibility matrix")
+
+// CompatibilityMatrix holds the pairwise scores of a group of users.
+// Scores[i][j] is the compatibility of UserIDs[i] and UserIDs[j]; the matrix
+// is symmetric and the diagonal is null.
+type CompatibilityMatrix struct {
+	UserIDs []string     `json:"user_ids"`
+	Scores  [][]*float64 `json:"scores"`
+	Missing []string     `json:"missing"` // requested users without a profile, or with a private one
+}
+
+// MaxMatrixUsers returns the largest group a compatibility matrix may cover
+func (s *Service) MaxMatrixUsers() int {
+	return s.config.MaxMatrixUsers
+}
+
+// CompatibilityMatrix scores every pair of the given users with weights.
+// Duplicate ids are ignored. Users without a public or limited profile are
+// left out and reported as missing.
+func (s *Service) CompatibilityMatrix(ctx context.Context, userIDs []string, weights MatchWeights) (*CompatibilityMatrix, error) {
+	seen := make(map[string]bool, len(userIDs))
+	var ids []string
+	for _, id := range userIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > s.config.MaxMatrixUsers {
+		return nil, ErrMatrixTooLarge
+	}
+
+	matrix := &CompatibilityMatrix{UserIDs: []string{}, Scores: [][]*float64{}, Missing: []string{}}
+	if len(ids) == 0 {
+		return matrix, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = utils.UserProfileKey(id)
+	}
+	values, err := utils.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*models.UserProfile
+	for i, value := range values {
+		var profile models.UserProfile
+		data, ok := value.(string)
+		if !ok || json.Unmarshal([]byte(data), &profile) != nil ||
+			profile.Visibility == models.ProfileVisibilityPrivate {
+			matrix.Missing = append(matrix.Missing, ids[i])
+			continue
+		}
+		profiles = append(profiles, &profile)
+		matrix.UserIDs = append(matrix.UserIDs, profile.UserID)
+	}
+
+	matrix.Scores = s.pairwiseScores(profiles, weights)
+	return matrix, nil
+}
+
+// pairwiseScores scores each pair once. A pair's score is the mean of scoring
+// it in each direction, so the matrix is symmetric even where scoring isn't
+// (such as mentorship experience gaps).
+func (s *Service) pairwiseScores(profiles []*models.UserProfile, weights MatchWeights) [][]*float64 {
+	scores := make([][]*float64, len(profiles))
+	for i := range scores {
+		scores[i] = make([]*float64, len(profiles))
+	}
+
+	for i := range profiles {
+		for j := i + 1; j < len(profiles); j++ {
+			score := (s.CalculateMatchScore(profiles[i], profiles[j], weights) +
+				s.CalculateMatchScore(profiles[j], profiles[i], weights)) / 2
+			scores[i][j] = &score
+			scores[j][i] = &score
+		}
+	}
+	return scores
+}
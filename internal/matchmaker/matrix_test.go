@@ -0,0 +1,89 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestCompatibilityMatrixIsSymmetric(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	profiles := []models.UserProfile{
+		{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 12},
+		{UserID: "bob", Tags: []string{"fintech"}, Skills: []string{"go", "rust"}, Location: "berlin", Experience: 2},
+		{UserID: "carol", Tags: []string{"health"}, Skills: []string{"design"}, Location: "lisbon", Experience: 5},
+		{UserID: "dave", Tags: []string{"fintech"}, Visibility: models.ProfileVisibilityPrivate},
+	}
+	for _, profile := range profiles {
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	weights, err := service.WeightProfile("")
+	if err != nil {
+		t.Fatalf("WeightProfile: %v", err)
+	}
+	matrix, err := service.CompatibilityMatrix(ctx, []string{"alice", "bob", "alice", "carol", "dave", "ghost"}, weights)
+	if err != nil {
+		t.Fatalf("CompatibilityMatrix: %v", err)
+	}
+
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(matrix.UserIDs, want) {
+		t.Fatalf("user ids = %v, want %v", matrix.UserIDs, want)
+	}
+	if want := []string{"dave", "ghost"}; !reflect.DeepEqual(matrix.Missing, want) {
+		t.Errorf("missing = %v, want the private dave and profileless ghost", matrix.Missing)
+	}
+
+	stored := make(map[string]*models.UserProfile)
+	for _, id := range matrix.UserIDs {
+		profile, err := service.GetUserProfile(ctx, id)
+		if err != nil {
+			t.Fatalf("GetUserProfile(%s): %v", id, err)
+		}
+		stored[id] = profile
+	}
+
+	for i, a := range matrix.UserIDs {
+		if matrix.Scores[i][i] != nil {
+			t.Errorf("self-score for %s = %v, want null", a, *matrix.Scores[i][i])
+		}
+		for j, b := range matrix.UserIDs {
+			if i == j {
+				continue
+			}
+			got := matrix.Scores[i][j]
+			if got == nil || matrix.Scores[j][i] == nil || *got != *matrix.Scores[j][i] {
+				t.Fatalf("scores[%s][%s] and scores[%s][%s] differ", a, b, b, a)
+			}
+			want := (service.CalculateMatchScore(stored[a], stored[b], weights) +
+				service.CalculateMatchScore(stored[b], stored[a], weights)) / 2
+			if *got != want {
+				t.Errorf("scores[%s][%s] = %v, want %v", a, b, *got, want)
+			}
+		}
+	}
+
+	// alice and bob share a tag, skill and city; carol shares nothing
+	if *matrix.Scores[0][1] <= *matrix.Scores[0][2] {
+		t.Errorf("alice-bob %v not above alice-carol %v", *matrix.Scores[0][1], *matrix.Scores[0][2])
+	}
+}
+
+func TestCompatibilityMatrixCapsGroupSize(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.config.MaxMatrixUsers = 2
+
+	_, err := service.CompatibilityMatrix(context.Background(), []string{"alice", "bob", "carol"}, MatchWeights{})
+	if !errors.Is(err, ErrMatrixTooLarge) {
+		t.Errorf("CompatibilityMatrix over the cap = %v, want ErrMatrixTooLarge", err)
+	}
+}
@@ -0,0 +1,182 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+const (
+	// DefaultNetworkDepth reaches matches of the user's mutual matches
+	DefaultNetworkDepth = 2
+
+	// MaxNetworkDepth bounds how far the match graph is traversed
+	MaxNetworkDepth = 3
+
+	// maxNetworkVia caps how many linking connections are listed per result
+	maxNetworkVia = 5
+)
+
+// NetworkConnection is a user reachable through the match graph but not yet
+// matched with the user the network was built for
+type NetworkConnection struct {
+	UserID string `json:"user_id"`
+
+	// Degree is the number of mutual-match hops to reach the user (2 or more)
+	Degree int `json:"degree"`
+
+	// Strength is the best product of match scores along a shortest path
+	Strength float64 `json:"strength"`
+
+	// Via lists the user's own mutual matches the paths run through, and
+	// MutualConnections counts them
+	Via               []string `json:"via"`
+	MutualConnections int      `json:"mutual_connections"`
+}
+
+// SecondDegreeConnections walks the mutual-match graph outward from userID up
+// to depth hops and returns the users found beyond the first hop that userID
+// has no match with, strongest first
+func (s *Service) SecondDegreeConnections(ctx context.Context, userID string, depth int) ([]NetworkConnection, error) {
+	depth = clamp(depth, DefaultNetworkDepth, MaxNetworkDepth)
+
+	graph, err := s.mutualMatchGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.MatchedUserIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Breadth-first by layer so every user is reached at their shortest
+	// distance; within that distance the strongest path wins
+	strength := map[string]float64{userID: 1}
+	via := map[string]map[string]bool{}
+	frontier := []string{userID}
+	var found []NetworkConnection
+
+	for hop := 1; hop <= depth && len(frontier) > 0; hop++ {
+		next := map[string]bool{}
+		for _, from := range frontier {
+			for to, score := range graph[from] {
+				if _, seen := strength[to]; seen && !next[to] {
+					continue
+				}
+
+				if candidate := strength[from] * score; candidate > strength[to] {
+					strength[to] = candidate
+				}
+				if via[to] == nil {
+					via[to] = map[string]bool{}
+				}
+				if hop == 1 {
+					via[to][to] = true
+				} else {
+					for link := range via[from] {
+						via[to][link] = true
+					}
+				}
+				next[to] = true
+			}
+		}
+
+		frontier = frontier[:0]
+		for id := range next {
+			frontier = append(frontier, id)
+			if hop < 2 || matched[id] {
+				continue
+			}
+			found = append(found, NetworkConnection{
+				UserID:            id,
+				Degree:            hop,
+				Strength:          strength[id],
+				Via:               sortedLinks(via[id]),
+				MutualConnections: len(via[id]),
+			})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Degree != found[j].Degree {
+			return found[i].Degree < found[j].Degree
+		}
+		if found[i].Strength != found[j].Strength {
+			return found[i].Strength > found[j].Strength
+		}
+		return found[i].UserID < found[j].UserID
+	})
+
+	return found, nil
+}
+
+// mutualMatchGraph loads every stored match once and returns the mutually
+// accepted ones as an adjacency map weighted by unboosted score
+func (s *Service) mutualMatchGraph(ctx context.Context) (map[string]map[string]float64, error) {
+	keys, err := utils.RedisClient.Keys(ctx, utils.MatchKeyPattern()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := map[string]map[string]float64{}
+	if len(keys) == 0 {
+		return graph, nil
+	}
+
+	values, err := utils.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	addEdge := func(a, b string, score float64) {
+		if graph[a] == nil {
+			graph[a] = map[string]float64{}
+		}
+		if score > graph[a][b] {
+			graph[a][b] = score
+		}
+	}
+
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var match models.Match
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			continue
+		}
+		match.DeriveStatus()
+		if !match.Mutual {
+			continue
+		}
+
+		// Boosts reorder match lists; they shouldn't inflate path strength
+		score := match.Score
+		if match.RawScore > 0 {
+			score = match.RawScore
+		}
+		addEdge(match.UserID1, match.UserID2, score)
+		addEdge(match.UserID2, match.UserID1, score)
+	}
+
+	return graph, nil
+}
+
+// sortedLinks returns up to maxNetworkVia linking users in a stable order
+func sortedLinks(links map[string]bool) []string {
+	ids := make([]string, 0, len(links))
+	for id := range links {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) > maxNetworkVia {
+		ids = ids[:maxNetworkVia]
+	}
+	return ids
+}
@@ -0,0 +1,67 @@
+package matchmaker
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestSecondDegreeConnections(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	mutual := func(id, user1, user2 string, score float64) models.Match {
+		return models.Match{ID: id, UserID1: user1, UserID2: user2, Score: score,
+			User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted}
+	}
+	// alice reaches dave through both bob and carol, and frank through dave
+	matches := []models.Match{
+		mutual("ab", "alice", "bob", 0.9),
+		mutual("ac", "alice", "carol", 0.5),
+		mutual("bd", "bob", "dave", 0.8),
+		mutual("cd", "carol", "dave", 0.9),
+		mutual("be", "bob", "erin", 0.6),
+		mutual("df", "dave", "frank", 0.5),
+		// alice already has a match with erin, so erin isn't suggested
+		{ID: "ae", UserID1: "alice", UserID2: "erin", Score: 0.4, User1Status: models.MatchStatusPending, User2Status: models.MatchStatusPending},
+		// A one-sided match isn't a connection to traverse
+		{ID: "cg", UserID1: "carol", UserID2: "gina", Score: 0.9, User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusPending},
+	}
+	for _, match := range matches {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+
+	found, err := service.SecondDegreeConnections(ctx, "alice", DefaultNetworkDepth)
+	if err != nil {
+		t.Fatalf("SecondDegreeConnections: %v", err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("found %+v, want only dave", found)
+	}
+	dave := found[0]
+	if dave.UserID != "dave" || dave.Degree != 2 || dave.MutualConnections != 2 || !reflect.DeepEqual(dave.Via, []string{"bob", "carol"}) {
+		t.Errorf("dave = %+v, want degree 2 via bob and carol", dave)
+	}
+	// The strongest path runs through bob: 0.9 * 0.8
+	if math.Abs(dave.Strength-0.72) > 1e-9 {
+		t.Errorf("dave strength = %v, want 0.72", dave.Strength)
+	}
+
+	// One more hop reaches frank, ranked after the closer dave
+	found, err = service.SecondDegreeConnections(ctx, "alice", 3)
+	if err != nil {
+		t.Fatalf("SecondDegreeConnections depth 3: %v", err)
+	}
+	if len(found) != 2 || found[0].UserID != "dave" || found[1].UserID != "frank" || found[1].Degree != 3 {
+		t.Fatalf("depth 3 found %+v, want dave then frank", found)
+	}
+	if math.Abs(found[1].Strength-0.36) > 1e-9 {
+		t.Errorf("frank strength = %v, want 0.36", found[1].Strength)
+	}
+}
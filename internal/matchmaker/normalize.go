@@ -0,0 +1,69 @@
+package matchmaker
+
+import (
+	"log"
+	"strings"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// NormalizeProfile canonicalizes a profile's tags, industries, skills, and
+// interests so the same term is always stored and compared in one form. The
+// user's original spelling is kept in DisplayNames for presentation.
+// Preference weights are scaled to sum to 1, or dropped if invalid.
+func (s *Service) NormalizeProfile(profile *models.UserProfile) {
+	if preferences, err := NormalizePreferenceWeights(profile.PreferenceWeights); err != nil {
+		log.Printf("Dropping invalid preference weights for user %s: %v", profile.UserID, err)
+		profile.PreferenceWeights = nil
+	} else {
+		profile.PreferenceWeights = preferences
+	}
+
+	display := make(map[string]string)
+
+	profile.Tags = s.normalizeTerms(profile.Tags, display)
+	profile.Industries = s.normalizeTerms(profile.Industries, display)
+	profile.Skills = s.normalizeTerms(profile.Skills, display)
+	profile.Interests = s.normalizeTerms(profile.Interests, display)
+
+	if len(display) == 0 {
+		display = nil
+	}
+	profile.DisplayNames = display
+}
+
+// normalizeTerms canonicalizes a list of terms, dropping blanks and duplicates,
+// and records the display form of any term whose canonical form differs
+func (s *Service) normalizeTerms(terms []string, display map[string]string) []string {
+	if terms == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(terms))
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		collapsed := strings.Join(strings.Fields(term), " ")
+		canonical := s.canonicalizeTerm(collapsed)
+		if canonical == "" || seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		normalized = append(normalized, canonical)
+
+		// Synonyms display as their canonical term; otherwise keep the user's casing
+		if _, isSynonym := s.config.Synonyms[strings.ToLower(collapsed)]; !isSynonym && collapsed != canonical {
+			display[canonical] = collapsed
+		}
+	}
+
+	return normalized
+}
+
+// canonicalizeTerm trims, collapses whitespace, lowercases, and applies the synonym table
+func (s *Service) canonicalizeTerm(term string) string {
+	canonical := strings.ToLower(strings.Join(strings.Fields(term), " "))
+	if synonym, ok := s.config.Synonyms[canonical]; ok {
+		return synonym
+	}
+	return canonical
+}
@@ -0,0 +1,38 @@
+package matchmaker
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestNormalizeProfileAppliesSynonymsAndCasing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	if err := os.WriteFile(path, []byte(`{"ML": "Machine  Learning", "js": "javascript"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MATCH_SYNONYMS_FILE", path)
+	service := &Service{config: LoadConfig()}
+
+	profile := &models.UserProfile{
+		Tags:   []string{"ML", " machine   learning ", "FinTech", ""},
+		Skills: []string{"JS", "Go"},
+	}
+	service.NormalizeProfile(profile)
+
+	if want := []string{"machine learning", "fintech"}; !reflect.DeepEqual(profile.Tags, want) {
+		t.Errorf("tags = %q, want %q", profile.Tags, want)
+	}
+	if want := []string{"javascript", "go"}; !reflect.DeepEqual(profile.Skills, want) {
+		t.Errorf("skills = %q, want %q", profile.Skills, want)
+	}
+
+	// Synonyms display as their canonical term; other terms keep the user's casing
+	want := map[string]string{"fintech": "FinTech", "go": "Go"}
+	if !reflect.DeepEqual(profile.DisplayNames, want) {
+		t.Errorf("display names = %v, want %v", profile.DisplayNames, want)
+	}
+}
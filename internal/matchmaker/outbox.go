@@ -0,0 +1,193 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+const (
+	// MatchesCreatedTopic receives one event per newly created match
+	MatchesCreatedTopic = "matches-created"
+
+	// outboxBatchSize is how many due events one relay pass claims at a time
+	outboxBatchSize = 100
+
+	// outboxClaimLease is how long a claimed event is hidden from other
+	// relays; an instance that dies mid-publish leaves it due again afterwards
+	outboxClaimLease = 30 * time.Second
+)
+
+// outboxPending reports how many match-created events are waiting to be published
+var outboxPending = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "matchmaker_outbox_pending",
+	Help: "Match-created events stored but not yet published to Kafka.",
+})
+
+// claimOutboxScript returns up to ARGV[2] events due at ARGV[1] and pushes
+// their next attempt to ARGV[3], so concurrent relays do not publish the
+// same event at once
+var claimOutboxScript = redis.NewScript(`
+local ids = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call("ZADD", KEYS[1], ARGV[3], id)
+end
+return ids
+`)
+
+// StoreMatchWithEvent stores a new match together with its match-created
+// event in one Redis transaction, so the event exists exactly when the match
+// does. The outbox relay publishes the event.
+func (s *Service) StoreMatchWithEvent(ctx context.Context, match models.Match) error {
+	data, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+
+	_, err = utils.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, utils.MatchKey(match.ID), data, utils.CacheTTLs().Match)
+		indexMatch(ctx, pipe, match)
+		pipe.HSet(ctx, utils.MatchOutboxEventsKey(), match.ID, data)
+		pipe.ZAdd(ctx, utils.MatchOutboxKey(), redis.Z{Score: float64(time.Now().UnixMilli()), Member: match.ID})
+		return nil
+	})
+	return err
+}
+
+// StartOutboxRelay publishes pending match-created events until ctx is cancelled
+func (s *Service) StartOutboxRelay(ctx context.Context) {
+	if s.config.OutboxRelayInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.OutboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RelayOutbox(ctx); err != nil {
+				log.Printf("Match outbox relay failed: %v", err)
+			}
+		}
+	}
+}
+
+// RelayOutbox publishes every match-created event that is due. An event is
+// removed once Kafka accepts it; a failed publish is rescheduled with
+// exponential backoff and retried on a later pass.
+func (s *Service) RelayOutbox(ctx context.Context) error {
+	for {
+		now := time.Now()
+		ids, err := claimOutboxScript.Run(ctx, utils.RedisClient, []string{utils.MatchOutboxKey()},
+			now.UnixMilli(), outboxBatchSize, now.Add(outboxClaimLease).UnixMilli()).StringSlice()
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		payloads, err := utils.RedisClient.HMGet(ctx, utils.MatchOutboxEventsKey(), ids...).Result()
+		if err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			payload, ok := payloads[i].(string)
+			if !ok {
+				// Nothing left to publish for this id
+				s.completeOutboxEvent(ctx, id)
+				continue
+			}
+			s.relayOutboxEvent(ctx, id, []byte(payload))
+		}
+
+		if len(ids) < outboxBatchSize {
+			break
+		}
+	}
+
+	if pending, err := utils.RedisClient.ZCard(ctx, utils.MatchOutboxKey()).Result(); err == nil {
+		outboxPending.Set(float64(pending))
+	}
+	return nil
+}
+
+// relayOutboxEvent publishes one event, then either completes it and notifies
+// webhooks, and both users if it was accepted automatically, or schedules a retry
+func (s *Service) relayOutboxEvent(ctx context.Context, id string, payload []byte) {
+	msg := kafka.Message{
+		Key:   []byte(id),
+		Value: payload,
+	}
+	spanCtx, span := tracing.StartProduce(ctx, MatchesCreatedTopic, &msg)
+	err := s.events.WriteMessages(spanCtx, msg)
+	tracing.End(span, err)
+	if err != nil {
+		s.retryOutboxEvent(ctx, id, err)
+		return
+	}
+
+	s.completeOutboxEvent(ctx, id)
+
+	var match models.Match
+	if err := json.Unmarshal(payload, &match); err != nil {
+		log.Printf("Failed to decode match-created event %s for webhooks: %v", id, err)
+		return
+	}
+	s.webhooks.Dispatch(models.WebhookEventMatchCreated, match)
+	s.NotifyAutoAccepted(ctx, match)
+}
+
+// retryOutboxEvent records a failed attempt and schedules the next one
+func (s *Service) retryOutboxEvent(ctx context.Context, id string, cause error) {
+	attempts, err := utils.RedisClient.HIncrBy(ctx, utils.MatchOutboxAttemptsKey(), id, 1).Result()
+	if err != nil {
+		log.Printf("Failed to record publish attempt for match %s: %v", id, err)
+		return
+	}
+
+	delay := s.outboxBackoff(attempts)
+	next := time.Now().Add(delay).UnixMilli()
+	if err := utils.RedisClient.ZAdd(ctx, utils.MatchOutboxKey(), redis.Z{Score: float64(next), Member: id}).Err(); err != nil {
+		log.Printf("Failed to reschedule match-created event %s: %v", id, err)
+		return
+	}
+	log.Printf("Failed to publish match created event %s (attempt %d), retrying in %s: %v", id, attempts, delay, cause)
+}
+
+// completeOutboxEvent removes a published event from the outbox
+func (s *Service) completeOutboxEvent(ctx context.Context, id string) {
+	_, err := utils.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, utils.MatchOutboxKey(), id)
+		pipe.HDel(ctx, utils.MatchOutboxEventsKey(), id)
+		pipe.HDel(ctx, utils.MatchOutboxAttemptsKey(), id)
+		return nil
+	})
+	if err != nil {
+		// The event stays claimed and is published again once the lease ends
+		log.Printf("Failed to clear published match-created event %s: %v", id, err)
+	}
+}
+
+// outboxBackoff is the delay after the given number of failed attempts
+func (s *Service) outboxBackoff(attempts int64) time.Duration {
+	delay := s.config.OutboxRetryBackoff
+	for i := int64(1); i < attempts && delay < s.config.OutboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.config.OutboxMaxBackoff)
+}
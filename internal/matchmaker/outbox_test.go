@@ -0,0 +1,72 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// flakyWriter fails the first failures writes and records the rest
+type flakyWriter struct {
+	mu       sync.Mutex
+	failures int
+	written  []kafka.Message
+}
+
+func (w *flakyWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("broker unavailable")
+	}
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func TestOutboxEventSurvivesPublishFailure(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	writer := &flakyWriter{failures: 1}
+	service.events = writer
+	service.config.OutboxRetryBackoff = 0 // retry on the very next pass
+	ctx := context.Background()
+
+	match := models.Match{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.8}
+	if err := service.StoreMatchWithEvent(ctx, match); err != nil {
+		t.Fatalf("StoreMatchWithEvent: %v", err)
+	}
+
+	// The failed publish leaves the event in the outbox with one attempt recorded
+	if err := service.RelayOutbox(ctx); err != nil {
+		t.Fatalf("RelayOutbox: %v", err)
+	}
+	if len(writer.written) != 0 {
+		t.Fatalf("published %d messages through a failing writer", len(writer.written))
+	}
+	if ok := server.Exists(utils.MatchOutboxEventsKey()); !ok {
+		t.Fatal("the event was dropped after a failed publish")
+	}
+	if attempts := server.HGet(utils.MatchOutboxAttemptsKey(), "m1"); attempts != "1" {
+		t.Errorf("attempts = %q, want 1", attempts)
+	}
+
+	// The next pass publishes it and clears the outbox
+	if err := service.RelayOutbox(ctx); err != nil {
+		t.Fatalf("RelayOutbox: %v", err)
+	}
+	if len(writer.written) != 1 || string(writer.written[0].Key) != "m1" {
+		t.Fatalf("published %v, want the event for m1 once", writer.written)
+	}
+	for _, key := range []string{utils.MatchOutboxKey(), utils.MatchOutboxEventsKey(), utils.MatchOutboxAttemptsKey()} {
+		if server.Exists(key) {
+			t.Errorf("%s still exists after a successful publish", key)
+		}
+	}
+}
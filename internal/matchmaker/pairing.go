@@ -0,0 +1,65 @@
+package matchmaker
+
+import (
+	"github.com/connect-up/auth-service/models"
+)
+
+// SeekingCompatible reports whether two profiles' declared seeking allow a
+// match: two users who both want a mentor, or both want a mentee, can't help
+// each other. Undeclared seeking is compatible with anything.
+func (s *Service) SeekingCompatible(profile1, profile2 *models.UserProfile) bool {
+	switch {
+	case profile1.Seeking == models.SeekingMentor && profile2.Seeking == models.SeekingMentor:
+		return false
+	case profile1.Seeking == models.SeekingMentee && profile2.Seeking == models.SeekingMentee:
+		return false
+	}
+	return true
+}
+
+// IsMentorshipPair reports whether two profiles form a cross-level pair: one
+// wants a mentor and the other a mentee, or one declares either and the
+// experience gap runs the right way by at least MentorshipExperienceGap years
+func (s *Service) IsMentorshipPair(profile1, profile2 *models.UserProfile) bool {
+	if (profile1.Seeking == models.SeekingMentor && profile2.Seeking == models.SeekingMentee) ||
+		(profile1.Seeking == models.SeekingMentee && profile2.Seeking == models.SeekingMentor) {
+		return true
+	}
+
+	return s.wantsMentorFrom(profile1, profile2) || s.wantsMentorFrom(profile2, profile1)
+}
+
+// wantsMentorFrom reports whether junior declares a mentorship relationship
+// with senior, who is sufficiently more experienced
+func (s *Service) wantsMentorFrom(junior, senior *models.UserProfile) bool {
+	if senior.Experience-junior.Experience < s.config.MentorshipExperienceGap {
+		return false
+	}
+	return junior.Seeking == models.SeekingMentor || senior.Seeking == models.SeekingMentee
+}
+
+// MatchThreshold returns the score a pair must exceed to be matched: the
+// mentorship threshold for cross-level pairs, MatchScoreThreshold otherwise
+func (s *Service) MatchThreshold(profile1, profile2 *models.UserProfile) float64 {
+	if s.IsMentorshipPair(profile1, profile2) {
+		return s.config.MentorshipThreshold
+	}
+	return MatchScoreThreshold
+}
+
+// experienceScore rates how well two profiles' experience fits. Peers score
+// best when close in experience; mentorship pairs when the gap is wide.
+func (s *Service) experienceScore(profile1, profile2 *models.UserProfile) float64 {
+	if !s.IsMentorshipPair(profile1, profile2) {
+		return s.calculateExperienceCompatibility(profile1.Experience, profile2.Experience)
+	}
+
+	gap := profile1.Experience - profile2.Experience
+	if gap < 0 {
+		gap = -gap
+	}
+	if s.config.MentorshipExperienceGap <= 0 || gap >= s.config.MentorshipExperienceGap {
+		return 1.0
+	}
+	return float64(gap) / float64(s.config.MentorshipExperienceGap)
+}
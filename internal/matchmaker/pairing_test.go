@@ -0,0 +1,64 @@
+package matchmaker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestMentorSeekerMatchesExperiencedMentee(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	// Only a skill in common, so the pair relies on the mentorship terms
+	junior := models.UserProfile{
+		UserID: "junior", Seeking: models.SeekingMentor, Experience: 1,
+		Tags: []string{"fintech"}, Industries: []string{"finance"}, Skills: []string{"go"}, Interests: []string{"chess"}, Location: "berlin",
+	}
+	senior := models.UserProfile{
+		UserID: "senior", Seeking: models.SeekingMentee, Experience: 12,
+		Tags: []string{"health"}, Industries: []string{"healthcare"}, Skills: []string{"go"}, Interests: []string{"sailing"}, Location: "lisbon",
+	}
+	for _, profile := range []models.UserProfile{junior, senior} {
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	if !service.IsMentorshipPair(&junior, &senior) {
+		t.Fatal("a mentor-seeker and a mentee-seeker are not a mentorship pair")
+	}
+	if got := service.MatchThreshold(&junior, &senior); got != service.config.MentorshipThreshold {
+		t.Errorf("MatchThreshold = %v, want the mentorship threshold %v", got, service.config.MentorshipThreshold)
+	}
+
+	// The same two profiles as peers: the experience gap counts against them
+	// and they have to clear the higher peer threshold
+	juniorPeer, seniorPeer := junior, senior
+	juniorPeer.Seeking, seniorPeer.Seeking = models.SeekingPeer, models.SeekingPeer
+	weights := service.WeightsFor(&junior)
+	mentorScore := service.CalculateMatchScore(&junior, &senior, weights)
+	peerScore := service.CalculateMatchScore(&juniorPeer, &seniorPeer, weights)
+	if mentorScore <= peerScore {
+		t.Errorf("mentorship score %v is not above peer score %v for a wide experience gap", mentorScore, peerScore)
+	}
+	if peerScore > MatchScoreThreshold {
+		t.Fatalf("peer score %v already clears the peer threshold; the test profiles share too much", peerScore)
+	}
+
+	matches, err := service.FindMatches(ctx, "junior")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].UserID2 != "senior" {
+		t.Fatalf("matches = %v, want the senior mentee-seeker", matchIDs(matches))
+	}
+
+	// Two users who both want a mentor can't help each other
+	other := models.UserProfile{UserID: "other", Seeking: models.SeekingMentor, Skills: []string{"go"}, Experience: 12}
+	if service.SeekingCompatible(&junior, &other) {
+		t.Error("two mentor-seekers are considered compatible")
+	}
+}
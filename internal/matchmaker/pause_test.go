@@ -0,0 +1,61 @@
+package matchmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestPausedUserIsNeitherSuggestedNorMatched(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", userID, err)
+		}
+	}
+	if _, err := service.PauseMatching(ctx, "carol", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PauseMatching: %v", err)
+	}
+
+	// Carol isn't suggested to others...
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches(alice): %v", err)
+	}
+	if len(matches) != 1 || matches[0].UserID2 != "bob" {
+		t.Errorf("alice's matches = %v, want only bob while carol is paused", matchIDs(matches))
+	}
+
+	// ...and gets no new matches of her own
+	matches, err = service.FindMatches(ctx, "carol")
+	if err != nil {
+		t.Fatalf("FindMatches(carol): %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("paused carol got %d new matches", len(matches))
+	}
+
+	// Resuming puts her straight back in the pool
+	if _, err := service.ResumeMatching(ctx, "carol"); err != nil {
+		t.Fatalf("ResumeMatching: %v", err)
+	}
+	matches, err = service.FindMatches(ctx, "carol")
+	if err != nil {
+		t.Fatalf("FindMatches(carol) after resuming: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("carol got %d matches after resuming, want 2", len(matches))
+	}
+
+	// A pause that has run out no longer counts
+	expired := time.Now().Add(-time.Minute)
+	if (&models.UserProfile{PausedUntil: &expired}).Paused(time.Now()) {
+		t.Error("a pause in the past still counts")
+	}
+}
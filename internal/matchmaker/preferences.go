@@ -0,0 +1,97 @@
+package matchmaker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Attributes a user can weight in their preference weights
+const (
+	AttributeTags       = "tags"
+	AttributeIndustries = "industries"
+	AttributeExperience = "experience"
+	AttributeSkills     = "skills"
+	AttributeInterests  = "interests"
+	AttributeLocation   = "location"
+)
+
+// Normalized scales the weights to sum to 1, keeping their proportions.
+// Weights that are all zero are returned unchanged.
+func (w MatchWeights) Normalized() MatchWeights {
+	total := w.Tags + w.Industries + w.Experience + w.Skills + w.Interests + w.Location
+	if total <= 0 {
+		return w
+	}
+	return MatchWeights{
+		Tags:       w.Tags / total,
+		Industries: w.Industries / total,
+		Experience: w.Experience / total,
+		Skills:     w.Skills / total,
+		Interests:  w.Interests / total,
+		Location:   w.Location / total,
+	}
+}
+
+// Map returns the weights keyed by attribute
+func (w MatchWeights) Map() map[string]float64 {
+	return map[string]float64{
+		AttributeTags:       w.Tags,
+		AttributeIndustries: w.Industries,
+		AttributeExperience: w.Experience,
+		AttributeSkills:     w.Skills,
+		AttributeInterests:  w.Interests,
+		AttributeLocation:   w.Location,
+	}
+}
+
+// PreferenceMatchWeights converts a user's preference weights, keyed by
+// attribute (case-insensitive), to match weights. Attributes left out weigh
+// nothing; the weights are relative, so {"industries": 2, "location": 1}
+// cares twice as much about industries as location and about nothing else.
+func PreferenceMatchWeights(preferences map[string]float64) (MatchWeights, error) {
+	var weights MatchWeights
+	fields := map[string]*float64{
+		AttributeTags:       &weights.Tags,
+		AttributeIndustries: &weights.Industries,
+		AttributeExperience: &weights.Experience,
+		AttributeSkills:     &weights.Skills,
+		AttributeInterests:  &weights.Interests,
+		AttributeLocation:   &weights.Location,
+	}
+
+	// Checked in a fixed order so the error names the same attribute every time
+	attributes := make([]string, 0, len(preferences))
+	for attribute := range preferences {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	for _, attribute := range attributes {
+		field, ok := fields[strings.ToLower(attribute)]
+		if !ok {
+			return MatchWeights{}, fmt.Errorf("unknown attribute %q", attribute)
+		}
+		*field += preferences[attribute]
+	}
+
+	if err := weights.Validate(); err != nil {
+		return MatchWeights{}, err
+	}
+	return weights, nil
+}
+
+// NormalizePreferenceWeights validates a user's preference weights and
+// returns them scaled to sum to 1, with every attribute listed. No
+// preferences normalize to nil, leaving the user on their weight profile.
+func NormalizePreferenceWeights(preferences map[string]float64) (map[string]float64, error) {
+	if len(preferences) == 0 {
+		return nil, nil
+	}
+
+	weights, err := PreferenceMatchWeights(preferences)
+	if err != nil {
+		return nil, err
+	}
+	return weights.Normalized().Map(), nil
+}
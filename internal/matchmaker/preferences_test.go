@@ -0,0 +1,61 @@
+package matchmaker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestPreferenceWeightsMakeScoresAsymmetric(t *testing.T) {
+	service := newTestService(t)
+
+	// Same industry, different cities: alice cares about the industry, bob
+	// about the city
+	alice := &models.UserProfile{UserID: "alice", Industries: []string{"finance"}, Location: "berlin",
+		PreferenceWeights: map[string]float64{"industries": 3, "location": 1}}
+	bob := &models.UserProfile{UserID: "bob", Industries: []string{"finance"}, Location: "paris",
+		PreferenceWeights: map[string]float64{"Industries": 1, "location": 3}}
+
+	forAlice := service.CalculateMatchScore(alice, bob, service.WeightsFor(alice))
+	forBob := service.CalculateMatchScore(bob, alice, service.WeightsFor(bob))
+	if forAlice <= forBob {
+		t.Errorf("alice scores the pair %v and bob %v; want alice, who weighs the shared industry, higher", forAlice, forBob)
+	}
+
+	// Without preferences both fall back to the same global weights
+	alice.PreferenceWeights, bob.PreferenceWeights = nil, nil
+	if a, b := service.CalculateMatchScore(alice, bob, service.WeightsFor(alice)), service.CalculateMatchScore(bob, alice, service.WeightsFor(bob)); a != b {
+		t.Errorf("without preferences alice scores %v and bob %v; want the same", a, b)
+	}
+}
+
+func TestNormalizePreferenceWeights(t *testing.T) {
+	weights, err := NormalizePreferenceWeights(map[string]float64{"Industries": 3, "location": 1})
+	if err != nil {
+		t.Fatalf("NormalizePreferenceWeights: %v", err)
+	}
+	if len(weights) != 6 || weights[AttributeIndustries] != 0.75 || weights[AttributeLocation] != 0.25 || weights[AttributeTags] != 0 {
+		t.Errorf("weights = %v, want industries 0.75, location 0.25 and the rest 0", weights)
+	}
+
+	var total float64
+	for _, weight := range weights {
+		total += weight
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("weights sum to %v, want 1", total)
+	}
+
+	if weights, err := NormalizePreferenceWeights(nil); weights != nil || err != nil {
+		t.Errorf("no preferences = %v, %v; want nil, nil", weights, err)
+	}
+	for _, invalid := range []map[string]float64{
+		{"height": 1},
+		{"location": -1},
+	} {
+		if _, err := NormalizePreferenceWeights(invalid); err == nil {
+			t.Errorf("NormalizePreferenceWeights(%v) succeeded, want an error", invalid)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package matchmaker
+
+import (
+	"math"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// Qualitative bands a match score is presented in
+const (
+	ScoreBandStrong = "strong"
+	ScoreBandGood   = "good"
+	ScoreBandWeak   = "weak"
+)
+
+// ScoreBands holds the lower bounds, as unboosted scores from 0 to 1, of the
+// strong and good bands; anything below Good is weak
+type ScoreBands struct {
+	Strong float64
+	Good   float64
+}
+
+// DefaultScoreBands returns the default band thresholds
+func DefaultScoreBands() ScoreBands {
+	return ScoreBands{Strong: 0.75, Good: 0.5}
+}
+
+// Band returns the band a score falls in; a score equal to a threshold
+// belongs to the higher band
+func (b ScoreBands) Band(score float64) string {
+	switch {
+	case score >= b.Strong:
+		return ScoreBandStrong
+	case score >= b.Good:
+		return ScoreBandGood
+	default:
+		return ScoreBandWeak
+	}
+}
+
+// ScorePercent rounds a 0-1 score to a whole percentage, clamped to 0-100
+func ScorePercent(score float64) int {
+	return clamp(int(math.Round(score*100)), 0, 100)
+}
+
+// PresentScore returns the percentage and band clients display for a match.
+// Both describe the unboosted score, so a boost changes a match's rank but
+// not how good it is said to be; score itself stays the ranking value.
+func (s *Service) PresentScore(score, rawScore float64) (int, string) {
+	if rawScore != 0 {
+		score = rawScore
+	}
+	return ScorePercent(score), s.config.ScoreBands.Band(score)
+}
+
+// presentMatch fills in a match's display score
+func (s *Service) presentMatch(match *models.Match) {
+	match.ScorePercent, match.ScoreBand = s.PresentScore(match.Score, match.RawScore)
+}
+
+// PresentMatchScore fills in a scored candidate's display score
+func (s *Service) PresentMatchScore(score *models.MatchScore) {
+	score.ScorePercent, score.ScoreBand = s.PresentScore(score.Score, score.RawScore)
+}
@@ -0,0 +1,39 @@
+package matchmaker
+
+import "testing"
+
+func TestScoreBandBoundaries(t *testing.T) {
+	t.Setenv("MATCH_SCORE_BAND_STRONG", "0.8")
+	t.Setenv("MATCH_SCORE_BAND_GOOD", "0.6")
+	service := &Service{config: LoadConfig()}
+
+	tests := []struct {
+		score       float64
+		wantPercent int
+		wantBand    string
+	}{
+		{0.8, 80, ScoreBandStrong}, // a score on a threshold takes the higher band
+		{0.7999, 80, ScoreBandGood},
+		{0.6, 60, ScoreBandGood},
+		{0.5999, 60, ScoreBandWeak},
+		{0, 0, ScoreBandWeak},
+		{1.2, 100, ScoreBandStrong},
+	}
+	for _, tt := range tests {
+		percent, band := service.PresentScore(tt.score, 0)
+		if percent != tt.wantPercent || band != tt.wantBand {
+			t.Errorf("PresentScore(%v) = %d, %s; want %d, %s", tt.score, percent, band, tt.wantPercent, tt.wantBand)
+		}
+	}
+
+	// A boosted match is described by its unboosted score
+	if percent, band := service.PresentScore(0.9, 0.6); percent != 60 || band != ScoreBandGood {
+		t.Errorf("boosted PresentScore = %d, %s; want 60, good", percent, band)
+	}
+
+	// A good threshold above the strong one is pulled down to it
+	t.Setenv("MATCH_SCORE_BAND_GOOD", "0.9")
+	if bands := loadScoreBands(); bands.Good != 0.8 {
+		t.Errorf("good threshold = %v, want it capped at 0.8", bands.Good)
+	}
+}
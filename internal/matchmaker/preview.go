@@ -0,0 +1,71 @@
+package matchmaker
+
+import (
+	"context"
+	"sort"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// Bounds on how many top matches a match preview includes
+const (
+	DefaultPreviewSampleSize = 5
+	MaxPreviewSampleSize     = 20
+)
+
+// MatchPreview estimates the matches a profile would get if it were stored
+type MatchPreview struct {
+	Count  int                 `json:"count"`  // profiles it would be matched with
+	Sample []models.MatchScore `json:"sample"` // the best of them, best first
+}
+
+// PreviewMatches scores a profile that isn't stored against the candidate
+// profiles as FindMatches would, writing nothing. Count covers every candidate
+// it would be matched with; private profiles are left out of the sample.
+func (s *Service) PreviewMatches(ctx context.Context, profile models.UserProfile, locale string, sampleSize int) (*MatchPreview, error) {
+	s.NormalizeProfile(&profile)
+
+	profiles, err := s.CandidateProfiles(ctx, profile.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	weights := s.WeightsFor(&profile)
+	preview := &MatchPreview{Sample: []models.MatchScore{}}
+	for _, candidate := range profiles {
+		if candidate.UserID == profile.UserID || !s.CanMatch(&profile, &candidate) {
+			continue
+		}
+
+		score := s.CalculateMatchScore(&profile, &candidate, weights)
+		if score <= s.MatchThreshold(&profile, &candidate) {
+			continue
+		}
+		preview.Count++
+
+		if candidate.Visibility == models.ProfileVisibilityPrivate {
+			continue
+		}
+		boosted, raw := s.BoostedScore(score, &candidate)
+		match := models.MatchScore{
+			UserID:   candidate.UserID,
+			Score:    boosted,
+			RawScore: raw,
+			Reason:   s.MatchReason(locale, &profile, &candidate),
+		}
+		s.PresentMatchScore(&match)
+		preview.Sample = append(preview.Sample, match)
+	}
+
+	sort.Slice(preview.Sample, func(i, j int) bool {
+		if preview.Sample[i].Score != preview.Sample[j].Score {
+			return preview.Sample[i].Score > preview.Sample[j].Score
+		}
+		return preview.Sample[i].UserID < preview.Sample[j].UserID
+	})
+	if len(preview.Sample) > sampleSize {
+		preview.Sample = preview.Sample[:sampleSize]
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,51 @@
+package matchmaker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestPreviewMatchesWritesNothing(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	for _, profile := range []models.UserProfile{
+		{UserID: "bob", Location: "berlin"},
+		{UserID: "carol", Location: "berlin"},
+		{UserID: "dave", Location: "berlin", Visibility: models.ProfileVisibilityPrivate},
+		{UserID: "erin", Tags: []string{"health"}, Skills: []string{"design"}, Industries: []string{"health"}, Interests: []string{"surf"}, Location: "lisbon", Experience: 30},
+	} {
+		if profile.Tags == nil {
+			profile.Tags, profile.Skills, profile.Experience = []string{"fintech"}, []string{"go"}, 5
+			profile.Industries, profile.Interests = []string{"finance"}, []string{"chess"}
+		}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+	before := server.Keys()
+
+	prospect := models.UserProfile{
+		UserID: "newcomer", Tags: []string{"fintech"}, Skills: []string{"go"}, Industries: []string{"finance"},
+		Interests: []string{"chess"}, Location: "berlin", Experience: 5,
+	}
+	preview, err := service.PreviewMatches(ctx, prospect, "en", 1)
+	if err != nil {
+		t.Fatalf("PreviewMatches: %v", err)
+	}
+
+	if after := server.Keys(); !reflect.DeepEqual(after, before) {
+		t.Errorf("Redis keys changed from %v to %v", before, after)
+	}
+	// The private dave counts but isn't shown; erin has nothing in common
+	if preview.Count != 3 {
+		t.Errorf("count = %d, want bob, carol and dave", preview.Count)
+	}
+	if len(preview.Sample) != 1 || preview.Sample[0].UserID != "bob" {
+		t.Errorf("sample = %+v, want only the top match bob", preview.Sample)
+	}
+}
@@ -0,0 +1,116 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/connect-up/auth-service/internal/i18n"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// similarExperienceYears is the largest experience gap, in years, described
+// as a similar experience level
+const similarExperienceYears = 2
+
+// MatchReason explains in the given locale why two profiles are a good match
+func (s *Service) MatchReason(locale string, profile1, profile2 *models.UserProfile) string {
+	sameLocation := profile1.Location != "" && profile2.Location != "" &&
+		strings.EqualFold(profile1.Location, profile2.Location)
+
+	return matchReason(locale,
+		s.FindCommonTags(profile1.Tags, profile2.Tags),
+		s.FindCommonSkills(profile1.Skills, profile2.Skills),
+		s.FindCommonInterests(profile1.Interests, profile2.Interests),
+		abs(profile1.Experience-profile2.Experience) <= similarExperienceYears,
+		sameLocation,
+	)
+}
+
+// ExplainMatches fills in the reason of each match in the given locale from
+// both users' current profiles. A match whose profiles are gone is explained
+// by the common attributes stored on it.
+func (s *Service) ExplainMatches(ctx context.Context, locale string, matches []models.Match) {
+	if len(matches) == 0 {
+		return
+	}
+
+	profiles := s.profilesByID(ctx, matches)
+	for i := range matches {
+		match := &matches[i]
+		profile1, profile2 := profiles[match.UserID1], profiles[match.UserID2]
+		if profile1 != nil && profile2 != nil {
+			match.Reason = s.MatchReason(locale, profile1, profile2)
+			continue
+		}
+		match.Reason = matchReason(locale, match.CommonTags, match.CommonSkills, match.CommonInterests, false, false)
+	}
+}
+
+// profilesByID loads the profiles of every user in matches in one round trip;
+// users whose profile can't be read are left out
+func (s *Service) profilesByID(ctx context.Context, matches []models.Match) map[string]*models.UserProfile {
+	var userIDs, keys []string
+	seen := make(map[string]bool)
+	for _, match := range matches {
+		for _, userID := range []string{match.UserID1, match.UserID2} {
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+				keys = append(keys, utils.UserProfileKey(userID))
+			}
+		}
+	}
+
+	profiles := make(map[string]*models.UserProfile, len(userIDs))
+	values, err := utils.RedisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return profiles
+	}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var profile models.UserProfile
+		if err := json.Unmarshal([]byte(data), &profile); err == nil {
+			profiles[userIDs[i]] = &profile
+		}
+	}
+	return profiles
+}
+
+// matchReason joins the localized reasons that apply, falling back to a
+// generic one when none do
+func matchReason(locale string, commonTags, commonSkills, commonInterests []string, similarExperience, sameLocation bool) string {
+	var reasons []string
+	if len(commonTags) > 0 {
+		reasons = append(reasons, i18n.T(locale, "reason.common_tags", strings.Join(commonTags, ", ")))
+	}
+	if len(commonSkills) > 0 {
+		reasons = append(reasons, i18n.T(locale, "reason.common_skills", strings.Join(commonSkills, ", ")))
+	}
+	if len(commonInterests) > 0 {
+		reasons = append(reasons, i18n.T(locale, "reason.shared_interests", strings.Join(commonInterests, ", ")))
+	}
+	if similarExperience {
+		reasons = append(reasons, i18n.T(locale, "reason.similar_experience"))
+	}
+	if sameLocation {
+		reasons = append(reasons, i18n.T(locale, "reason.same_location"))
+	}
+
+	if len(reasons) == 0 {
+		return i18n.T(locale, "reason.good_compatibility")
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,25 @@
+package matchmaker
+
+import (
+	"testing"
+
+	"github.com/connect-up/auth-service/internal/i18n"
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestMatchReasonInSpanish(t *testing.T) {
+	service := &Service{config: LoadConfig()}
+	alice := &models.UserProfile{UserID: "alice", Tags: []string{"fintech"}, Experience: 5, Location: "Madrid"}
+	bob := &models.UserProfile{UserID: "bob", Tags: []string{"fintech"}, Experience: 6, Location: "madrid"}
+
+	want := "Intereses en común: fintech; Nivel de experiencia similar; Misma ubicación"
+	if got := service.MatchReason(i18n.ParseAcceptLanguage("es-ES,es;q=0.9,en;q=0.8"), alice, bob); got != want {
+		t.Errorf("MatchReason(es) = %q, want %q", got, want)
+	}
+
+	// An unsupported language falls back to English
+	want = "Common interests: fintech; Similar experience level; Same location"
+	if got := service.MatchReason(i18n.ParseAcceptLanguage("de-DE"), alice, bob); got != want {
+		t.Errorf("MatchReason(de) = %q, want %q", got, want)
+	}
+}
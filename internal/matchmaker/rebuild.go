@@ -0,0 +1,347 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// Rebuild job states
+const (
+	RebuildStatusRunning     = "running"
+	RebuildStatusCompleted   = "completed"
+	RebuildStatusCancelled   = "cancelled"
+	RebuildStatusFailed      = "failed"
+	RebuildStatusInterrupted = "interrupted" // the running instance stopped without finishing
+)
+
+// rebuildStatusTTL is how long a rebuild's status is kept after it was last updated
+const rebuildStatusTTL = 24 * time.Hour
+
+var (
+	ErrRebuildRunning    = errors.New("a match rebuild is already running")
+	ErrRebuildNotRunning = errors.New("no match rebuild is running")
+)
+
+// RebuildJob reports the progress of a global match rebuild
+type RebuildJob struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	StartedBy     string     `json:"started_by"`
+	Total         int        `json:"total"`     // profiles to rebuild
+	Processed     int        `json:"processed"` // profiles handled so far, including failed and skipped ones
+	Failed        int        `json:"failed"`
+	Skipped       int        `json:"skipped"` // paused profiles and those whose matches were being computed by someone else
+	MatchesStored int        `json:"matches_stored"`
+	Error         string     `json:"error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// StartRebuild starts recomputing every user's matches in the background and
+// returns the new job. Only one rebuild runs at a time across instances.
+func (s *Service) StartRebuild(ctx context.Context, startedBy string) (*RebuildJob, error) {
+	lock, err := utils.AcquireLock(ctx, utils.MatchRebuildLockKey(), s.config.LockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, ErrRebuildRunning
+	}
+
+	job := &RebuildJob{
+		ID:        uuid.New().String(),
+		Status:    RebuildStatusRunning,
+		StartedBy: startedBy,
+		StartedAt: time.Now(),
+	}
+	if err := s.saveRebuildJob(ctx, job); err != nil {
+		lock.Release(context.Background())
+		return nil, err
+	}
+
+	// The job outlives the request that started it
+	snapshot := *job
+	go s.runRebuild(job, lock)
+	return &snapshot, nil
+}
+
+// RebuildStatus returns the latest rebuild job, or redis.Nil if none has run
+// recently. A job whose instance stopped mid-run is reported as interrupted.
+func (s *Service) RebuildStatus(ctx context.Context) (*RebuildJob, error) {
+	data, err := utils.RedisClient.Get(ctx, utils.MatchRebuildJobKey()).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var job RebuildJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	if job.Status == RebuildStatusRunning {
+		held, err := utils.RedisClient.Exists(ctx, utils.MatchRebuildLockKey()).Result()
+		if err != nil {
+			return nil, err
+		}
+		if held == 0 {
+			job.Status = RebuildStatusInterrupted
+		}
+	}
+	return &job, nil
+}
+
+// CancelRebuild asks the running rebuild to stop; it finishes the profile it
+// is on and reports itself cancelled
+func (s *Service) CancelRebuild(ctx context.Context) (*RebuildJob, error) {
+	job, err := s.RebuildStatus(ctx)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrRebuildNotRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != RebuildStatusRunning {
+		return nil, ErrRebuildNotRunning
+	}
+
+	if err := utils.RedisClient.Set(ctx, utils.MatchRebuildCancelKey(), job.ID, rebuildStatusTTL).Err(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// runRebuild works through every profile at no more than the configured
+// rate, recording progress after each one
+func (s *Service) runRebuild(job *RebuildJob, lock *utils.Lock) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go lock.KeepAlive(ctx)
+	defer func() {
+		cancel()
+		if err := lock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release match rebuild lock: %v", err)
+		}
+	}()
+
+	err := s.rebuildAll(ctx, job)
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = RebuildStatusCancelled
+	case err != nil:
+		job.Status = RebuildStatusFailed
+		job.Error = err.Error()
+	default:
+		job.Status = RebuildStatusCompleted
+	}
+	finished := time.Now()
+	job.FinishedAt = &finished
+
+	if err := s.saveRebuildJob(ctx, job); err != nil {
+		log.Printf("Failed to save match rebuild %s status: %v", job.ID, err)
+	}
+	log.Printf("Match rebuild %s %s: %d/%d profiles, %d failed, %d skipped, %d matches stored",
+		job.ID, job.Status, job.Processed, job.Total, job.Failed, job.Skipped, job.MatchesStored)
+}
+
+// rebuildAll recomputes each profile's matches, returning context.Canceled
+// if the job was cancelled part way
+func (s *Service) rebuildAll(ctx context.Context, job *RebuildJob) error {
+	profiles, err := s.GetAllUserProfiles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get all profiles: %v", err)
+	}
+
+	previous, err := s.matchesByUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get stored matches: %v", err)
+	}
+
+	job.Total = len(profiles)
+	if err := s.saveRebuildJob(ctx, job); err != nil {
+		return err
+	}
+
+	var throttle <-chan time.Time
+	if s.config.RebuildRate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / s.config.RebuildRate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for _, profile := range profiles {
+		if s.rebuildCancelled(ctx, job.ID) {
+			return context.Canceled
+		}
+		if throttle != nil {
+			<-throttle
+		}
+
+		var (
+			stored  int
+			skipped bool
+			err     error
+		)
+		if profile.Paused(time.Now()) {
+			// A paused user's matches are left as they are until they come back
+			skipped = true
+		} else {
+			stored, skipped, err = s.rebuildUserMatches(ctx, profile.UserID, previous[profile.UserID])
+		}
+		switch {
+		case err != nil:
+			log.Printf("Match rebuild %s failed for user %s: %v", job.ID, profile.UserID, err)
+			job.Failed++
+		case skipped:
+			job.Skipped++
+		}
+		job.MatchesStored += stored
+		job.Processed++
+
+		if err := s.saveRebuildJob(ctx, job); err != nil {
+			log.Printf("Failed to save match rebuild %s progress: %v", job.ID, err)
+		}
+	}
+
+	if job.MatchesStored > 0 {
+		if err := s.RelayOutbox(ctx); err != nil {
+			log.Printf("Failed to relay match created events: %v", err)
+		}
+	}
+	return nil
+}
+
+// rebuildUserMatches replaces the matches computed for a user (those where
+// they are UserID1) with freshly scored ones. A pair that is still matched
+// keeps its id and both users' responses; matches that are no longer found
+// are removed unless either user has responded to them. skipped is true if
+// the user's matches were already being computed.
+func (s *Service) rebuildUserMatches(ctx context.Context, userID string, previous []models.Match) (stored int, skipped bool, err error) {
+	lock, err := utils.AcquireLock(ctx, utils.MatchmakerLockKey(userID), s.config.LockTTL)
+	if err != nil {
+		return 0, false, err
+	}
+	if lock == nil {
+		return 0, true, nil
+	}
+	defer lock.Release(context.Background())
+
+	matches, err := s.FindMatches(ctx, userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Index the previous matches by candidate, preferring one a user has
+	// responded to when a pair was matched more than once
+	byCandidate := make(map[string]models.Match)
+	var stale []models.Match
+	for _, match := range previous {
+		kept, exists := byCandidate[match.UserID2]
+		if !exists {
+			byCandidate[match.UserID2] = match
+			continue
+		}
+		if untouched(kept) && !untouched(match) {
+			byCandidate[match.UserID2], match = match, kept
+		}
+		stale = append(stale, match)
+	}
+
+	now := time.Now()
+	for _, match := range matches {
+		old, exists := byCandidate[match.UserID2]
+		if !exists {
+			if err := s.StoreMatchWithEvent(ctx, match); err != nil {
+				return stored, false, err
+			}
+			stored++
+			continue
+		}
+		delete(byCandidate, match.UserID2)
+
+		old.Score = match.Score
+		old.RawScore = match.RawScore
+		old.CommonTags = match.CommonTags
+		old.CommonSkills = match.CommonSkills
+		old.CommonInterests = match.CommonInterests
+		old.UpdatedAt = now
+		if err := s.StoreMatch(ctx, old); err != nil {
+			return stored, false, err
+		}
+		stored++
+		s.NotifyMatchChanged(ctx, MatchEventUpdated, old)
+	}
+
+	for _, match := range byCandidate {
+		stale = append(stale, match)
+	}
+	for _, match := range stale {
+		if !untouched(match) {
+			continue
+		}
+		if _, err := utils.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, utils.MatchKey(match.ID))
+			unindexMatch(ctx, pipe, match)
+			return nil
+		}); err != nil {
+			return stored, false, err
+		}
+		s.NotifyMatchChanged(ctx, MatchEventRemoved, match)
+	}
+
+	return stored, false, nil
+}
+
+// untouched reports whether neither user has responded to a match
+func untouched(match models.Match) bool {
+	return match.User1Status == models.MatchStatusPending && match.User2Status == models.MatchStatusPending
+}
+
+// matchesByUser loads every stored match, grouped by the user they were
+// computed for (UserID1)
+func (s *Service) matchesByUser(ctx context.Context) (map[string][]models.Match, error) {
+	keys, err := utils.RedisClient.Keys(ctx, utils.MatchKeyPattern()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[string][]models.Match)
+	for _, key := range keys {
+		data, err := utils.RedisClient.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+
+		var match models.Match
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			continue
+		}
+		match.DeriveStatus()
+		byUser[match.UserID1] = append(byUser[match.UserID1], match)
+	}
+
+	return byUser, nil
+}
+
+// rebuildCancelled reports whether the job has been asked to stop
+func (s *Service) rebuildCancelled(ctx context.Context, jobID string) bool {
+	cancelled, err := utils.RedisClient.Get(ctx, utils.MatchRebuildCancelKey()).Result()
+	return err == nil && cancelled == jobID
+}
+
+// saveRebuildJob records a rebuild's progress
+func (s *Service) saveRebuildJob(ctx context.Context, job *RebuildJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return utils.RedisClient.Set(ctx, utils.MatchRebuildJobKey(), data, rebuildStatusTTL).Err()
+}
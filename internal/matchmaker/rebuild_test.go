@@ -0,0 +1,99 @@
+package matchmaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// waitForRebuild polls the rebuild status until the job stops running
+func waitForRebuild(t *testing.T, service *Service) *RebuildJob {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := service.RebuildStatus(context.Background())
+		if err != nil {
+			t.Fatalf("RebuildStatus: %v", err)
+		}
+		if job.Status != RebuildStatusRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("rebuild did not finish")
+	return nil
+}
+
+func TestRebuildRecomputesEveryProfile(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	writer := &flakyWriter{}
+	service.events = writer
+	service.config.RebuildRate = 0
+	ctx := context.Background()
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", userID, err)
+		}
+	}
+	// A match alice responded to survives the rebuild with its status
+	accepted := models.Match{ID: "kept", UserID1: "alice", UserID2: "bob", Score: 0.1,
+		User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusPending}
+	// A match nobody responded to that the rebuild no longer finds is removed
+	stale := models.Match{ID: "stale", UserID1: "alice", UserID2: "zed", Score: 0.5,
+		User1Status: models.MatchStatusPending, User2Status: models.MatchStatusPending}
+	for _, match := range []models.Match{accepted, stale} {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch(%s): %v", match.ID, err)
+		}
+	}
+
+	started, err := service.StartRebuild(ctx, "admin-1")
+	if err != nil {
+		t.Fatalf("StartRebuild: %v", err)
+	}
+	if started.Status != RebuildStatusRunning || started.StartedBy != "admin-1" {
+		t.Errorf("started job = %+v", started)
+	}
+
+	job := waitForRebuild(t, service)
+	if job.ID != started.ID || job.Status != RebuildStatusCompleted || job.FinishedAt == nil {
+		t.Fatalf("job = %+v, want %s completed", job, started.ID)
+	}
+	if job.Total != 3 || job.Processed != 3 || job.Failed != 0 || job.MatchesStored != 6 {
+		t.Errorf("job progress = %+v, want 3 profiles processed and 6 matches stored", job)
+	}
+
+	kept, err := service.GetMatch(ctx, "kept")
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	if kept.User1Status != models.MatchStatusAccepted || kept.Score <= accepted.Score {
+		t.Errorf("kept match = %+v, want alice's acceptance kept and the score refreshed", kept)
+	}
+	if _, err := service.GetMatch(ctx, "stale"); err == nil {
+		t.Error("stale match survived the rebuild")
+	}
+	for _, userID := range []string{"alice", "zed"} {
+		if indexed, _ := utils.RedisClient.SIsMember(ctx, utils.UserMatchesKey(userID), "stale").Result(); indexed {
+			t.Errorf("stale match is still in %s's match index", userID)
+		}
+	}
+	// Only the five new matches announce themselves
+	writer.mu.Lock()
+	published := len(writer.written)
+	writer.mu.Unlock()
+	if published != 5 {
+		t.Errorf("published %d match-created events, want 5", published)
+	}
+
+	// With nothing running there is nothing to cancel
+	if _, err := service.CancelRebuild(ctx); err != ErrRebuildNotRunning {
+		t.Errorf("CancelRebuild after completion = %v, want ErrRebuildNotRunning", err)
+	}
+}
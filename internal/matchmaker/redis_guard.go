@@ -0,0 +1,72 @@
+package matchmaker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// errRedisNotConfigured is reported when no Redis client has been set up
+var errRedisNotConfigured = errors.New("redis client not initialized")
+
+var (
+	// redisAvailable is 1 while the consumer can reach Redis and 0 while it waits for it
+	redisAvailable = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "matchmaker_redis_available",
+		Help: "Whether the matchmaker consumer can reach Redis (1) or is waiting for it (0).",
+	})
+
+	// redisUnavailableChecks counts failed Redis health checks by the consumer
+	redisUnavailableChecks = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "matchmaker_redis_unavailable_total",
+		Help: "Redis health checks the matchmaker consumer failed while holding back user updates.",
+	})
+)
+
+// pingRedis checks that the shared Redis client can serve requests
+func pingRedis(ctx context.Context) error {
+	if utils.RedisClient == nil {
+		return errRedisNotConfigured
+	}
+	return utils.RedisClient.Ping(ctx).Err()
+}
+
+// redisHealthy reports whether Redis is reachable right now
+func (s *Service) redisHealthy(ctx context.Context) bool {
+	return s.redisCheck(ctx) == nil
+}
+
+// waitForRedis blocks until Redis is reachable, checking again with
+// exponential backoff. Every profile and match lives in Redis, so processing
+// an update without it would fail and drop the update. It returns ctx's
+// error if ctx is cancelled first.
+func (s *Service) waitForRedis(ctx context.Context) error {
+	backoff := s.config.RedisRetryBackoff
+	for {
+		err := s.redisCheck(ctx)
+		if err == nil {
+			redisAvailable.Set(1)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		redisAvailable.Set(0)
+		redisUnavailableChecks.Inc()
+		log.Printf("Warning: Redis unavailable, holding back user updates for %v: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.config.RedisMaxBackoff)
+	}
+}
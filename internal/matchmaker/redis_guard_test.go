@@ -0,0 +1,69 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestConsumerHoldsUpdateWhileRedisIsDown(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	service.config.RedisRetryBackoff = time.Millisecond
+	service.config.RedisMaxBackoff = 5 * time.Millisecond
+
+	event := models.UserUpdatedEvent{
+		UserID:    "alice",
+		Profile:   models.UserProfile{UserID: "alice", Tags: []string{"fintech"}},
+		Timestamp: time.Now(),
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+
+	server.SetError("LOADING Redis is unavailable")
+	done := make(chan error, 1)
+	go func() { done <- service.consumeUserUpdated(context.Background(), kafka.Message{Value: value}) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("consumer gave up on the update while Redis was down: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	server.SetError("")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("consumeUserUpdated after recovery: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("consumer did not retry the update once Redis recovered")
+	}
+
+	if !server.Exists(utils.UserProfileKey("alice")) {
+		t.Error("held update was not applied after Redis recovered")
+	}
+}
+
+func TestConsumerStopsWaitingForRedisOnShutdown(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.config.RedisRetryBackoff = time.Millisecond
+	service.config.RedisMaxBackoff = 5 * time.Millisecond
+	service.redisCheck = func(context.Context) error { return errors.New("connection refused") }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := service.consumeUserUpdated(ctx, kafka.Message{Value: []byte(`{"user_id":"alice"}`)}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("consumeUserUpdated = %v, want the context's error", err)
+	}
+}
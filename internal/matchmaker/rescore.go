@@ -0,0 +1,140 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// ErrMatchesBusy is returned when a user's matches are already being computed
+var ErrMatchesBusy = errors.New("matches are already being computed for this user")
+
+// rescoreWriteAttempts is how many times a re-scored match is written before
+// giving up when its users keep changing it
+const rescoreWriteAttempts = 3
+
+// RescoreMatches re-scores a user's stored matches, on either side of them,
+// against the current profiles, best first. Unlike recomputing, it keeps
+// every match with its id and both users' responses, even one that no longer
+// clears the threshold; only scores and what the two have in common change.
+// A match whose other profile is gone is returned unchanged, and one removed
+// while it was being re-scored is left out.
+func (s *Service) RescoreMatches(ctx context.Context, userID string) (_ []models.Match, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "matchmaker.rescore_matches")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := s.GetUserProfile(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	lock, err := utils.AcquireLock(ctx, utils.MatchmakerLockKey(userID), s.config.LockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		return nil, ErrMatchesBusy
+	}
+	defer lock.Release(context.Background())
+
+	matches, err := s.GetMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := s.profilesByID(ctx, matches)
+	now := time.Now()
+	kept := matches[:0]
+	for _, match := range matches {
+		profile1, profile2 := profiles[match.UserID1], profiles[match.UserID2]
+		if profile1 == nil || profile2 == nil {
+			kept = append(kept, match)
+			continue
+		}
+
+		// Scored the way the match was found: by its first user's weights,
+		// boosted by its second user's boost
+		score := s.CalculateMatchScore(profile1, profile2, s.WeightsFor(profile1))
+		match.Score, match.RawScore = s.BoostedScore(score, profile2)
+		match.CommonTags = s.FindCommonTags(profile1.Tags, profile2.Tags)
+		match.CommonSkills = s.FindCommonSkills(profile1.Skills, profile2.Skills)
+		match.CommonInterests = s.FindCommonInterests(profile1.Interests, profile2.Interests)
+		match.UpdatedAt = now
+
+		stored, err := s.storeRescoredMatch(ctx, match)
+		if err == redis.Nil {
+			continue // Removed since it was read
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.NotifyMatchChanged(ctx, MatchEventUpdated, *stored)
+		kept = append(kept, *stored)
+	}
+	matches = kept
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches, nil
+}
+
+// storeRescoredMatch writes a re-scored match's score and common attributes
+// onto the match as it is stored now, leaving the rest as it is, so a
+// response given while the match was being re-scored isn't overwritten. The
+// write is retried if the match changes under it, and redis.Nil is returned
+// if the match no longer exists.
+func (s *Service) storeRescoredMatch(ctx context.Context, rescored models.Match) (*models.Match, error) {
+	key := utils.MatchKey(rescored.ID)
+	var match models.Match
+	update := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		match = models.Match{}
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			return err
+		}
+
+		match.Score, match.RawScore = rescored.Score, rescored.RawScore
+		match.CommonTags = rescored.CommonTags
+		match.CommonSkills = rescored.CommonSkills
+		match.CommonInterests = rescored.CommonInterests
+		match.UpdatedAt = rescored.UpdatedAt
+		match.DeriveStatus()
+		s.presentMatch(&match)
+
+		updated, err := json.Marshal(match)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, updated, utils.CacheTTLs().Match)
+			indexMatch(ctx, pipe, match)
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for i := 0; i < rescoreWriteAttempts; i++ {
+		if err = utils.RedisClient.Watch(ctx, update, key); err != redis.TxFailedErr {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &match, nil
+}
@@ -0,0 +1,108 @@
+package matchmaker
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestRescoreMatchesUpdatesScoresAndKeepsStatuses(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	profiles := []models.UserProfile{
+		{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5},
+		{UserID: "bob", Tags: []string{"fintech"}, Skills: []string{"rust"}, Location: "berlin", Experience: 5},
+	}
+	for _, profile := range profiles {
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+	alice, bob := &profiles[0], &profiles[1]
+	oldScore := service.CalculateMatchScore(alice, bob, service.WeightsFor(alice))
+	match := models.Match{
+		ID: "m1", UserID1: "alice", UserID2: "bob", Score: oldScore, CommonTags: []string{"fintech"},
+		User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusRejected,
+	}
+	if err := service.StoreMatch(ctx, match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	// Bob picks up alice's skill
+	bob.Skills = []string{"rust", "go"}
+	if err := service.storeNormalizedProfile(ctx, *bob); err != nil {
+		t.Fatalf("store edited bob: %v", err)
+	}
+
+	rescored, err := service.RescoreMatches(ctx, "bob")
+	if err != nil {
+		t.Fatalf("RescoreMatches: %v", err)
+	}
+	if len(rescored) != 1 || rescored[0].ID != "m1" {
+		t.Fatalf("rescored = %+v, want match m1 kept", rescored)
+	}
+
+	stored, err := service.GetMatch(ctx, "m1")
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	if stored.Score <= oldScore {
+		t.Errorf("score = %v, want above %v after the shared skill", stored.Score, oldScore)
+	}
+	if !reflect.DeepEqual(stored.CommonSkills, []string{"go"}) {
+		t.Errorf("common skills = %v, want [go]", stored.CommonSkills)
+	}
+	if stored.User1Status != models.MatchStatusAccepted || stored.User2Status != models.MatchStatusRejected {
+		t.Errorf("statuses = %s/%s, want accepted/rejected kept", stored.User1Status, stored.User2Status)
+	}
+}
+
+func TestRescoredMatchKeepsResponseGivenMeanwhile(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	match := models.Match{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.4,
+		User1Status: models.MatchStatusPending, User2Status: models.MatchStatusPending}
+	if err := service.StoreMatch(ctx, match); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+
+	// The re-score read the match before bob accepted it
+	rescored := match
+	rescored.Score, rescored.CommonSkills = 0.7, []string{"go"}
+	accepted := match
+	accepted.User2Status = models.MatchStatusAccepted
+	if err := service.StoreMatch(ctx, accepted); err != nil {
+		t.Fatalf("StoreMatch accepted: %v", err)
+	}
+
+	if _, err := service.storeRescoredMatch(ctx, rescored); err != nil {
+		t.Fatalf("storeRescoredMatch: %v", err)
+	}
+	stored, err := service.GetMatch(ctx, "m1")
+	if err != nil {
+		t.Fatalf("GetMatch: %v", err)
+	}
+	if stored.User2Status != models.MatchStatusAccepted {
+		t.Errorf("bob's status = %s, want his acceptance kept", stored.User2Status)
+	}
+	if stored.Score != 0.7 || !reflect.DeepEqual(stored.CommonSkills, []string{"go"}) {
+		t.Errorf("stored = %+v, want the new score and common skills", stored)
+	}
+
+	// A match removed meanwhile isn't brought back
+	rescored.ID = "gone"
+	if _, err := service.storeRescoredMatch(ctx, rescored); err != redis.Nil {
+		t.Errorf("storeRescoredMatch(gone) = %v, want redis.Nil", err)
+	}
+	if _, err := service.GetMatch(ctx, "gone"); err == nil {
+		t.Error("a removed match was stored again")
+	}
+}
@@ -0,0 +1,58 @@
+package matchmaker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestMisconfiguredWeightsScoreStaysInRange(t *testing.T) {
+	service := newTestService(t)
+	alice := &models.UserProfile{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Industries: []string{"finance"}, Location: "berlin"}
+	bob := &models.UserProfile{UserID: "bob", Tags: []string{"fintech"}, Skills: []string{"design"}, Industries: []string{"health"}, Location: "lisbon"}
+
+	tests := []struct {
+		name    string
+		weights MatchWeights
+		want    float64
+	}{
+		// Tags alone contribute 2 of a total weight of 1
+		{"negative weight inflates the score", MatchWeights{Tags: 2, Skills: -1}, 1},
+		// Tags alone contribute -1 of a total weight of 1
+		{"negative weight sinks the score", MatchWeights{Tags: -1, Skills: 2}, 0},
+		{"NaN weight", MatchWeights{Tags: math.NaN(), Skills: 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.weights.Validate(); err == nil {
+				t.Error("Validate accepted misconfigured weights")
+			}
+			if got := service.CalculateMatchScore(alice, bob, tt.weights); got != tt.want {
+				t.Errorf("score = %v, want it clamped to %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultWeightsScoreInRange(t *testing.T) {
+	service := newTestService(t)
+	weights := DefaultMatchWeights()
+	if err := weights.Validate(); err != nil {
+		t.Fatalf("default weights invalid: %v", err)
+	}
+
+	profiles := []*models.UserProfile{
+		{UserID: "empty"},
+		{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Interests: []string{"chess"}, Location: "berlin", Experience: 30},
+		{UserID: "bob", Tags: []string{"fintech", "ai"}, Skills: []string{"go", "ml"}, Industries: []string{"finance"}, Location: "berlin"},
+		{UserID: "carol", Tags: []string{"health"}, Location: "lisbon", Experience: 2},
+	}
+	for _, a := range profiles {
+		for _, b := range profiles {
+			if score := service.CalculateMatchScore(a, b, weights); score < 0 || score > 1 || math.IsNaN(score) {
+				t.Errorf("score(%s, %s) = %v, outside [0, 1]", a.UserID, b.UserID, score)
+			}
+		}
+	}
+}
@@ -2,24 +2,47 @@ package matchmaker
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/connect-up/auth-service/internal/webhook"
 	"github.com/connect-up/auth-service/models"
 	"github.com/connect-up/auth-service/utils"
 )
 
+// MatchScoreThreshold is the score a candidate must exceed to be matched
+const MatchScoreThreshold = 0.3
+
 type Service struct {
-	reader *kafka.Reader
-	writer *kafka.Writer
+	reader   *kafka.Reader
+	writer   *kafka.Writer
+	events   utils.MessageWriter // publishes match-created events; the writer outside tests
+	config   Config
+	webhooks *webhook.Dispatcher
+
+	// redisCheck reports whether Redis is reachable; pingRedis outside tests
+	redisCheck func(ctx context.Context) error
+
+	// autoAcceptNotifier is told about matches accepted automatically. It is
+	// set after the consumer and relay have started, hence atomic.
+	autoAcceptNotifier atomic.Pointer[MatchNotifier]
+
+	// matchFeedNotifier is told when a stored match changes or is removed
+	matchFeedNotifier atomic.Pointer[MatchFeedNotifier]
 }
 
 // NewService creates a new matchmaker service
@@ -34,16 +57,24 @@ func NewService(kafkaBrokers []string, topic string) *Service {
 
 	writer := &kafka.Writer{
 		Addr:     kafka.TCP(kafkaBrokers...),
-		Topic:    "matches-created",
+		Topic:    MatchesCreatedTopic,
 		Balancer: &kafka.LeastBytes{},
 	}
 
 	return &Service{
-		reader: reader,
-		writer: writer,
+		reader:     reader,
+		writer:     writer,
+		events:     writer,
+		config:     LoadConfig(),
+		redisCheck: pingRedis,
 	}
 }
 
+// SetWebhookDispatcher sets the dispatcher used to notify webhooks of new matches
+func (s *Service) SetWebhookDispatcher(dispatcher *webhook.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
 // StartConsumer starts the Kafka consumer for user-updated events
 func (s *Service) StartConsumer(ctx context.Context) {
 	log.Println("Starting matchmaker Kafka consumer...")
@@ -51,27 +82,88 @@ func (s *Service) StartConsumer(ctx context.Context) {
 	for {
 		m, err := s.reader.ReadMessage(ctx)
 		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
 			log.Printf("Error reading message: %v", err)
 			continue
 		}
 
-		var event models.UserUpdatedEvent
-		if err := json.Unmarshal(m.Value, &event); err != nil {
-			log.Printf("Error unmarshaling event: %v", err)
-			continue
+		if err := s.consumeUserUpdated(ctx, m); err != nil {
+			return
 		}
+	}
+}
 
-		log.Printf("Processing user update for user: %s", event.UserID)
-		if err := s.ProcessUserUpdate(ctx, event); err != nil {
-			log.Printf("Error processing user update: %v", err)
+// consumeUserUpdated processes one user-updated message, holding it while
+// Redis is unreachable and retrying it once Redis recovers, rather than
+// failing it and moving on. It only returns an error if ctx is cancelled.
+func (s *Service) consumeUserUpdated(ctx context.Context, m kafka.Message) error {
+	for {
+		if err := s.waitForRedis(ctx); err != nil {
+			return err
+		}
+		// A failure with Redis still up is the update's own fault, so it
+		// isn't retried
+		if err := s.handleUserUpdated(ctx, m); err == nil || s.redisHealthy(ctx) {
+			return nil
 		}
 	}
 }
 
-// ProcessUserUpdate processes a user update event and finds matches
+// handleUserUpdated processes one user-updated message under a consumer span
+// that continues the producer's trace
+func (s *Service) handleUserUpdated(ctx context.Context, m kafka.Message) error {
+	ctx, span := tracing.StartConsume(ctx, &m)
+	var err error
+	defer func() { tracing.End(span, err) }()
+
+	var event models.UserUpdatedEvent
+	if err = json.Unmarshal(m.Value, &event); err != nil {
+		// Retrying can't fix a malformed event
+		log.Printf("Error unmarshaling event: %v", err)
+		return nil
+	}
+
+	log.Printf("Processing user update for user: %s", event.UserID)
+	if err = s.ProcessUserUpdate(ctx, event); err != nil {
+		log.Printf("Error processing user update: %v", err)
+	}
+	return err
+}
+
+// ProcessUserUpdate processes a user update event and finds matches.
+// Only one instance computes matches for a user at a time; if another
+// instance holds the user's lock the update is skipped. So is an event
+// already processed within the dedupe window, such as a Kafka redelivery.
 func (s *Service) ProcessUserUpdate(ctx context.Context, event models.UserUpdatedEvent) error {
+	lock, err := utils.AcquireLock(ctx, utils.MatchmakerLockKey(event.UserID), s.config.LockTTL)
+	if err != nil {
+		return fmt.Errorf("failed to acquire match lock: %v", err)
+	}
+	if lock == nil {
+		log.Printf("Match computation already running for user %s, skipping", event.UserID)
+		return nil
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+	go lock.KeepAlive(lockCtx)
+	defer func() {
+		cancel()
+		if err := lock.Release(context.Background()); err != nil {
+			log.Printf("Failed to release match lock for user %s: %v", event.UserID, err)
+		}
+	}()
+
+	// Checked under the lock so a concurrent duplicate sees this one's mark
+	dedupeKey, duplicate := s.isDuplicateUpdate(ctx, event)
+	if duplicate {
+		log.Printf("Skipping duplicate user update for user %s", event.UserID)
+		return nil
+	}
+
 	// Store the updated profile
-	if err := s.StoreUserProfile(ctx, event.Profile); err != nil {
+	if err := s.storeUpdatedProfile(ctx, event); err != nil {
 		return fmt.Errorf("failed to store user profile: %v", err)
 	}
 
@@ -81,38 +173,174 @@ func (s *Service) ProcessUserUpdate(ctx context.Context, event models.UserUpdate
 		return fmt.Errorf("failed to find matches: %v", err)
 	}
 
-	// Store matches
+	// Store matches along with their match-created events
 	for _, match := range matches {
-		if err := s.StoreMatch(ctx, match); err != nil {
+		if err := s.StoreMatchWithEvent(ctx, match); err != nil {
 			log.Printf("Failed to store match: %v", err)
 			continue
 		}
 	}
 
-	// Publish match creation events
+	// Publish the new events now rather than waiting for the relay's next
+	// pass; anything that fails stays in the outbox and is retried
 	if len(matches) > 0 {
-		if err := s.PublishMatchesCreated(ctx, matches); err != nil {
-			log.Printf("Failed to publish matches created: %v", err)
+		if err := s.RelayOutbox(ctx); err != nil {
+			log.Printf("Failed to relay match created events: %v", err)
 		}
 	}
 
+	// Only marked once processed, so a failed attempt can still be retried
+	s.markUpdateProcessed(ctx, dedupeKey)
 	return nil
 }
 
-// StoreUserProfile stores a user profile in Redis
+// StoreUserProfile normalizes and stores a user profile in Redis
 func (s *Service) StoreUserProfile(ctx context.Context, profile models.UserProfile) error {
-	key := fmt.Sprintf("user_profile:%s", profile.UserID)
+	s.NormalizeProfile(&profile)
+	s.keepStoredFields(ctx, &profile)
+	return s.storeNormalizedProfile(ctx, profile)
+}
+
+// keepStoredFields carries the fields users can't set with their profile over
+// from the stored profile: the boost, the searchable flag, the pause, the
+// creation time that profile age is measured from, and the name synced from
+// the account unless a new one is given. A profile stored for the first
+// time is created now and searchable.
+func (s *Service) keepStoredFields(ctx context.Context, profile *models.UserProfile) {
+	existing, err := s.GetUserProfile(ctx, profile.UserID)
+	if err != nil || existing.CreatedAt.IsZero() {
+		profile.CreatedAt = time.Now()
+	} else {
+		profile.CreatedAt = existing.CreatedAt
+	}
+	if err == nil {
+		profile.Boost = existing.Boost
+		profile.Searchable = existing.Searchable
+		profile.PausedUntil = existing.PausedUntil
+		if profile.Name == "" {
+			profile.Name = existing.Name
+		}
+	}
+}
+
+// SetSearchable opts a user in to or out of appearing in other users'
+// matches and discovery. Matches already created are kept.
+func (s *Service) SetSearchable(ctx context.Context, userID string, searchable bool) (*models.UserProfile, error) {
+	profile, err := s.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Searchable = &searchable
+	if err := s.storeNormalizedProfile(ctx, *profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// PauseMatching keeps a user out of new matches, both their own and other
+// users', until the given time. Matches already created are kept.
+func (s *Service) PauseMatching(ctx context.Context, userID string, until time.Time) (*models.UserProfile, error) {
+	return s.setPausedUntil(ctx, userID, &until)
+}
+
+// ResumeMatching lifts a user's pause so they are matched again right away
+func (s *Service) ResumeMatching(ctx context.Context, userID string) (*models.UserProfile, error) {
+	return s.setPausedUntil(ctx, userID, nil)
+}
+
+func (s *Service) setPausedUntil(ctx context.Context, userID string, until *time.Time) (*models.UserProfile, error) {
+	profile, err := s.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.PausedUntil = until
+	if err := s.storeNormalizedProfile(ctx, *profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// storeNormalizedProfile stores an already normalized profile and its content hash
+func (s *Service) storeNormalizedProfile(ctx context.Context, profile models.UserProfile) error {
+	key := utils.UserProfileKey(profile.UserID)
 	data, err := json.Marshal(profile)
 	if err != nil {
 		return err
 	}
 
-	return utils.RedisClient.Set(ctx, key, data, 24*time.Hour).Err()
+	hash, err := ProfileHash(profile)
+	if err != nil {
+		return err
+	}
+
+	pipe := utils.RedisClient.TxPipeline()
+	profileTTL := utils.CacheTTLs().Profile
+	pipe.Set(ctx, key, data, profileTTL)
+	pipe.Set(ctx, utils.UserProfileHashKey(profile.UserID), hash, profileTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// StoreUserProfileIfChanged stores a profile unless its normalized content
+// matches what is already stored, in which case only the stored profile's
+// expiry is refreshed. It reports whether the profile changed.
+func (s *Service) StoreUserProfileIfChanged(ctx context.Context, profile models.UserProfile) (bool, error) {
+	s.NormalizeProfile(&profile)
+	// The stored hash covers the carried-over fields, such as the account
+	// name, so they're filled in before hashing
+	s.keepStoredFields(ctx, &profile)
+
+	hash, err := ProfileHash(profile)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := utils.RedisClient.Get(ctx, utils.UserProfileHashKey(profile.UserID)).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if stored == hash {
+		pipe := utils.RedisClient.TxPipeline()
+		profileTTL := utils.CacheTTLs().Profile
+		exists := pipe.Expire(ctx, utils.UserProfileKey(profile.UserID), profileTTL)
+		pipe.Expire(ctx, utils.UserProfileHashKey(profile.UserID), profileTTL)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return false, err
+		}
+		// The profile may have expired while its hash lingered
+		if exists.Val() {
+			return false, nil
+		}
+	}
+
+	return true, s.storeNormalizedProfile(ctx, profile)
+}
+
+// ProfileHash returns a hash of a normalized profile's content, ignoring
+// timestamps and the fields managed outside the profile itself: the boost,
+// the searchable flag and the pause
+func ProfileHash(profile models.UserProfile) (string, error) {
+	profile.CreatedAt = time.Time{}
+	profile.UpdatedAt = time.Time{}
+	profile.Boost = 0
+	profile.Searchable = nil
+	profile.PausedUntil = nil
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
 }
 
 // GetUserProfile retrieves a user profile from Redis
 func (s *Service) GetUserProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
-	key := fmt.Sprintf("user_profile:%s", userID)
+	key := utils.UserProfileKey(userID)
 	data, err := utils.RedisClient.Get(ctx, key).Result()
 	if err != nil {
 		return nil, err
@@ -126,38 +354,78 @@ func (s *Service) GetUserProfile(ctx context.Context, userID string) (*models.Us
 	return &profile, nil
 }
 
-// FindMatches finds potential matches for a user
+// FindMatches finds a user's best matches, keeping the configured number of them
 func (s *Service) FindMatches(ctx context.Context, userID string) ([]models.Match, error) {
+	return s.FindMatchesLimit(ctx, userID, 0)
+}
+
+// FindMatchesLimit finds a user's best matches, keeping at most limit of them.
+// A limit of 0 uses the configured MaxStoredMatches.
+func (s *Service) FindMatchesLimit(ctx context.Context, userID string, limit int) (_ []models.Match, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "matchmaker.find_matches")
+	defer func() { tracing.End(span, err) }()
+
+	limit = s.StoredMatchLimit(limit)
+
 	userProfile, err := s.GetUserProfile(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user profile: %v", err)
 	}
 
-	// Get all user profiles
-	profiles, err := s.GetAllUserProfiles(ctx)
+	// Get the candidate profiles
+	profiles, err := s.CandidateProfiles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate profiles: %v", err)
+	}
+
+	// Stored matches show up for both users, so a user who isn't searchable
+	// or has paused matchmaking gets no new ones
+	if !userProfile.IsSearchable() || userProfile.Paused(time.Now()) {
+		return nil, nil
+	}
+
+	// Recomputing a pair keeps the users' responses to it
+	responded, err := s.respondedMatches(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all profiles: %v", err)
+		return nil, fmt.Errorf("failed to get stored matches: %v", err)
 	}
 
+	weights := s.WeightsFor(userProfile)
+
 	var matches []models.Match
 	for _, profile := range profiles {
 		if profile.UserID == userID {
 			continue // Skip self
 		}
 
-		score := s.CalculateMatchScore(userProfile, &profile)
-		if score > 0.3 { // Minimum match threshold
+		if !s.CanMatch(userProfile, &profile) {
+			continue
+		}
+
+		score := s.CalculateMatchScore(userProfile, &profile, weights)
+		if score > s.MatchThreshold(userProfile, &profile) {
+			boosted, raw := s.BoostedScore(score, &profile)
 			match := models.Match{
-				ID:           uuid.New().String(),
-				UserID1:      userID,
-				UserID2:      profile.UserID,
-				Score:        score,
-				CommonTags:   s.FindCommonTags(userProfile.Tags, profile.Tags),
-				CommonSkills: s.FindCommonSkills(userProfile.Skills, profile.Skills),
-				Status:       "pending",
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
+				ID:              uuid.New().String(),
+				UserID1:         userID,
+				UserID2:         profile.UserID,
+				Score:           boosted,
+				RawScore:        raw,
+				CommonTags:      s.FindCommonTags(userProfile.Tags, profile.Tags),
+				CommonSkills:    s.FindCommonSkills(userProfile.Skills, profile.Skills),
+				CommonInterests: s.FindCommonInterests(userProfile.Interests, profile.Interests),
+				Status:          models.MatchStatusPending,
+				User1Status:     models.MatchStatusPending,
+				User2Status:     models.MatchStatusPending,
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+			}
+			if stored, ok := responded[profile.UserID]; ok {
+				keepResponses(&match, stored)
+			} else {
+				s.autoAccept(&match, score)
 			}
+			s.presentMatch(&match)
 			matches = append(matches, match)
 		}
 	}
@@ -167,45 +435,139 @@ func (s *Service) FindMatches(ctx context.Context, userID string) ([]models.Matc
 		return matches[i].Score > matches[j].Score
 	})
 
-	// Limit to top 10 matches
-	if len(matches) > 10 {
-		matches = matches[:10]
+	// Limit to the top matches
+	if len(matches) > limit {
+		matches = matches[:limit]
 	}
 
 	return matches, nil
 }
 
-// CalculateMatchScore calculates a match score between two users
-func (s *Service) CalculateMatchScore(profile1, profile2 *models.UserProfile) float64 {
-	var score float64
-	var totalWeight float64
+// StoredMatchLimit resolves how many matches to keep for a request, falling
+// back to the configured default and capping overrides at MaxStoredMatchesLimit
+func (s *Service) StoredMatchLimit(requested int) int {
+	if requested <= 0 {
+		return s.config.MaxStoredMatches
+	}
+	return clamp(requested, 1, MaxStoredMatchesLimit)
+}
 
-	// Tag similarity (weight: 0.3)
-	tagScore := s.calculateSimilarity(profile1.Tags, profile2.Tags)
-	score += tagScore * 0.3
-	totalWeight += 0.3
+// MatchPageSize resolves how many matches a listing returns, falling back to
+// the configured default and capping requests at MaxMatchPageSize
+func (s *Service) MatchPageSize(requested int) int {
+	if requested <= 0 {
+		return s.config.MatchPageSize
+	}
+	return clamp(requested, 1, MaxMatchPageSize)
+}
+
+// ErrUnknownWeightProfile is returned when a weight profile name isn't configured
+var ErrUnknownWeightProfile = errors.New("unknown weight profile")
+
+// WeightProfile returns the weights for a named profile; an empty name selects
+// DefaultWeightProfile
+func (s *Service) WeightProfile(name string) (MatchWeights, error) {
+	if name == "" {
+		name = DefaultWeightProfile
+	}
+	weights, ok := s.config.WeightProfiles[strings.ToLower(name)]
+	if !ok {
+		return MatchWeights{}, ErrUnknownWeightProfile
+	}
+	return weights, nil
+}
+
+// WeightsFor returns the weights a user's profile scores candidates with: its
+// preference weights if it has any, otherwise its weight profile, falling back
+// to DefaultWeightProfile if that is no longer configured
+func (s *Service) WeightsFor(profile *models.UserProfile) MatchWeights {
+	if len(profile.PreferenceWeights) > 0 {
+		if weights, err := PreferenceMatchWeights(profile.PreferenceWeights); err == nil {
+			return weights.Normalized()
+		}
+	}
+
+	weights, err := s.WeightProfile(profile.WeightProfile)
+	if err != nil {
+		return s.config.Weights
+	}
+	return weights
+}
+
+// UndoWindow returns how long a match status change can be undone for
+func (s *Service) UndoWindow() time.Duration {
+	return s.config.UndoWindow
+}
 
-	// Industry similarity (weight: 0.25)
-	industryScore := s.calculateSimilarity(profile1.Industries, profile2.Industries)
-	score += industryScore * 0.25
-	totalWeight += 0.25
+// CalculateMatchScore calculates a match score between two users using the given weights
+func (s *Service) CalculateMatchScore(profile1, profile2 *models.UserProfile, weights MatchWeights) float64 {
+	return s.CalculateScoreBreakdown(profile1, profile2, weights).Total
+}
+
+// CalculateScoreBreakdown calculates a match score along with each attribute's
+// weighted contribution to it. The contributions sum to Total, unless
+// misconfigured weights pushed it outside [0, 1] and it had to be clamped.
+func (s *Service) CalculateScoreBreakdown(profile1, profile2 *models.UserProfile, weights MatchWeights) ScoreBreakdown {
+	totalWeight := weights.Tags + weights.Industries + weights.Experience +
+		weights.Skills + weights.Interests + weights.Location
+	if totalWeight <= 0 {
+		return ScoreBreakdown{}
+	}
 
-	// Experience compatibility (weight: 0.2)
-	expScore := s.calculateExperienceCompatibility(profile1.Experience, profile2.Experience)
-	score += expScore * 0.2
-	totalWeight += 0.2
+	breakdown := ScoreBreakdown{
+		Tags:       s.calculateSimilarity(profile1.Tags, profile2.Tags) * weights.Tags / totalWeight,
+		Industries: s.calculateSimilarity(profile1.Industries, profile2.Industries) * weights.Industries / totalWeight,
+		Experience: s.experienceScore(profile1, profile2) * weights.Experience / totalWeight,
+		Skills:     s.calculateSimilarity(profile1.Skills, profile2.Skills) * weights.Skills / totalWeight,
+		Interests:  s.calculateSimilarity(profile1.Interests, profile2.Interests) * weights.Interests / totalWeight,
+		Location:   s.calculateLocationCompatibility(profile1.Location, profile2.Location) * weights.Location / totalWeight,
+	}
+	breakdown.Total = clampScore(breakdown.Tags + breakdown.Industries + breakdown.Experience +
+		breakdown.Skills + breakdown.Interests + breakdown.Location)
 
-	// Skills similarity (weight: 0.15)
-	skillsScore := s.calculateSimilarity(profile1.Skills, profile2.Skills)
-	score += skillsScore * 0.15
-	totalWeight += 0.15
+	return breakdown
+}
 
-	// Location similarity (weight: 0.1)
-	locationScore := s.calculateLocationCompatibility(profile1.Location, profile2.Location)
-	score += locationScore * 0.1
-	totalWeight += 0.1
+// clampScore limits a match score to [0, 1]; NaN counts as 0
+func clampScore(score float64) float64 {
+	if math.IsNaN(score) || score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
 
-	return score / totalWeight
+// CanMatch reports whether candidate profile2 passes the filters applied
+// before scoring against profile1: searchable, not paused, old enough,
+// compatible seeking, and enough in common
+func (s *Service) CanMatch(profile1, profile2 *models.UserProfile) bool {
+	now := time.Now()
+	return profile2.IsSearchable() && !profile2.Paused(now) && s.OldEnough(profile2, now) &&
+		s.SeekingCompatible(profile1, profile2) && s.HasEnoughInCommon(profile1, profile2)
+}
+
+// OldEnough reports whether a profile has existed for the configured minimum
+// age and may therefore appear in other users' matches
+func (s *Service) OldEnough(profile *models.UserProfile, now time.Time) bool {
+	if s.config.MinProfileAge <= 0 {
+		return true
+	}
+	return !profile.CreatedAt.IsZero() && now.Sub(profile.CreatedAt) >= s.config.MinProfileAge
+}
+
+// HasEnoughInCommon reports whether two profiles share at least the configured
+// minimum number of tags and skills combined
+func (s *Service) HasEnoughInCommon(profile1, profile2 *models.UserProfile) bool {
+	if s.config.MinCommonAttributes <= 0 {
+		return true
+	}
+
+	common := len(s.FindCommonTags(profile1.Tags, profile2.Tags)) +
+		len(s.FindCommonSkills(profile1.Skills, profile2.Skills))
+
+	return common >= s.config.MinCommonAttributes
 }
 
 // calculateSimilarity calculates Jaccard similarity between two string slices
@@ -317,55 +679,127 @@ func (s *Service) FindCommonSkills(skills1, skills2 []string) []string {
 	return common
 }
 
-// GetAllUserProfiles retrieves all user profiles from Redis
+// FindCommonInterests finds common interests between two users
+func (s *Service) FindCommonInterests(interests1, interests2 []string) []string {
+	set1 := make(map[string]bool)
+	for _, interest := range interests1 {
+		set1[strings.ToLower(interest)] = true
+	}
+
+	var common []string
+	for _, interest := range interests2 {
+		if set1[strings.ToLower(interest)] {
+			common = append(common, interest)
+		}
+	}
+
+	return common
+}
+
+// GetAllUserProfiles retrieves all user profiles from Redis, however many
+// there are. Scans for one user's matches use CandidateProfiles instead.
 func (s *Service) GetAllUserProfiles(ctx context.Context) ([]models.UserProfile, error) {
-	pattern := "user_profile:*"
+	pattern := utils.UserProfileKeyPattern()
 	keys, err := utils.RedisClient.Keys(ctx, pattern).Result()
 	if err != nil {
 		return nil, err
 	}
 
-	var profiles []models.UserProfile
-	for _, key := range keys {
-		data, err := utils.RedisClient.Get(ctx, key).Result()
-		if err != nil {
-			continue
-		}
+	return loadProfiles(ctx, keys)
+}
 
-		var profile models.UserProfile
-		if err := json.Unmarshal([]byte(data), &profile); err != nil {
-			continue
+// StoreMatch stores a match in Redis, along with its users' match indexes
+func (s *Service) StoreMatch(ctx context.Context, match models.Match) error {
+	return s.StoreMatches(ctx, []models.Match{match})
+}
+
+// StoreMatches stores several matches in Redis in one round trip, along with
+// their users' match indexes
+func (s *Service) StoreMatches(ctx context.Context, matches []models.Match) error {
+	_, err := utils.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, match := range matches {
+			data, err := json.Marshal(match)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, utils.MatchKey(match.ID), data, utils.CacheTTLs().Match)
+			indexMatch(ctx, pipe, match)
 		}
+		return nil
+	})
+	return err
+}
 
-		profiles = append(profiles, profile)
+// indexMatch adds a match to both of its users' match indexes. An index
+// lives as long as the newest match added to it; ids of matches that have
+// expired since are dropped when the index is read.
+func indexMatch(ctx context.Context, pipe redis.Pipeliner, match models.Match) {
+	for _, userID := range []string{match.UserID1, match.UserID2} {
+		key := utils.UserMatchesKey(userID)
+		pipe.SAdd(ctx, key, match.ID)
+		pipe.Expire(ctx, key, utils.CacheTTLs().Match)
 	}
+}
 
-	return profiles, nil
+// unindexMatch removes a match from both of its users' match indexes
+func unindexMatch(ctx context.Context, pipe redis.Pipeliner, match models.Match) {
+	pipe.SRem(ctx, utils.UserMatchesKey(match.UserID1), match.ID)
+	pipe.SRem(ctx, utils.UserMatchesKey(match.UserID2), match.ID)
 }
 
-// StoreMatch stores a match in Redis
-func (s *Service) StoreMatch(ctx context.Context, match models.Match) error {
-	key := fmt.Sprintf("match:%s", match.ID)
-	data, err := json.Marshal(match)
+// IndexStoredMatches adds every stored match to its users' match indexes,
+// for matches stored before the indexes existed
+func (s *Service) IndexStoredMatches(ctx context.Context) error {
+	keys, err := utils.RedisClient.Keys(ctx, utils.MatchKeyPattern()).Result()
 	if err != nil {
 		return err
 	}
 
-	return utils.RedisClient.Set(ctx, key, data, 7*24*time.Hour).Err()
+	for _, key := range keys {
+		data, err := utils.RedisClient.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		var match models.Match
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			continue
+		}
+		if _, err := utils.RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			indexMatch(ctx, pipe, match)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// GetMatchesForUser retrieves matches for a specific user
+// GetMatchesForUser retrieves matches for a specific user through their match index
 func (s *Service) GetMatchesForUser(ctx context.Context, userID string) ([]models.Match, error) {
-	pattern := "match:*"
-	keys, err := utils.RedisClient.Keys(ctx, pattern).Result()
+	indexKey := utils.UserMatchesKey(userID)
+	ids, err := utils.RedisClient.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = utils.MatchKey(id)
+	}
+	values, err := utils.RedisClient.MGet(ctx, keys...).Result()
 	if err != nil {
 		return nil, err
 	}
 
 	var matches []models.Match
-	for _, key := range keys {
-		data, err := utils.RedisClient.Get(ctx, key).Result()
-		if err != nil {
+	var expired []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			expired = append(expired, ids[i])
 			continue
 		}
 
@@ -375,38 +809,165 @@ func (s *Service) GetMatchesForUser(ctx context.Context, userID string) ([]model
 		}
 
 		if match.UserID1 == userID || match.UserID2 == userID {
+			match.DeriveStatus()
+			s.presentMatch(&match)
 			matches = append(matches, match)
 		}
 	}
 
-	// Sort by score descending
+	if len(expired) > 0 {
+		if err := utils.RedisClient.SRem(ctx, indexKey, expired...).Err(); err != nil {
+			log.Printf("Failed to drop expired matches from %s: %v", indexKey, err)
+		}
+	}
+
+	// Sort by score descending, breaking ties by ID so the order is stable
 	sort.Slice(matches, func(i, j int) bool {
-		return matches[i].Score > matches[j].Score
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
 	})
 
 	return matches, nil
 }
 
-// PublishMatchesCreated publishes match creation events to Kafka
-func (s *Service) PublishMatchesCreated(ctx context.Context, matches []models.Match) error {
+// GetMatch retrieves a single match from Redis
+func (s *Service) GetMatch(ctx context.Context, matchID string) (*models.Match, error) {
+	data, err := utils.RedisClient.Get(ctx, utils.MatchKey(matchID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var match models.Match
+	if err := json.Unmarshal([]byte(data), &match); err != nil {
+		return nil, err
+	}
+	match.DeriveStatus()
+	s.presentMatch(&match)
+
+	return &match, nil
+}
+
+// GetMutualMatches returns the user's matches that both users have accepted
+func (s *Service) GetMutualMatches(ctx context.Context, userID string) ([]models.Match, error) {
+	matches, err := s.GetMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	mutual := []models.Match{}
 	for _, match := range matches {
-		data, err := json.Marshal(match)
+		if match.Mutual {
+			mutual = append(mutual, match)
+		}
+	}
+
+	return mutual, nil
+}
+
+// MigrateMatchStatuses rewrites matches stored with only a single status so
+// they carry per-user statuses, keeping each match's remaining TTL
+func (s *Service) MigrateMatchStatuses(ctx context.Context) error {
+	keys, err := utils.RedisClient.Keys(ctx, utils.MatchKeyPattern()).Result()
+	if err != nil {
+		return err
+	}
+
+	migrated := 0
+	for _, key := range keys {
+		data, err := utils.RedisClient.Get(ctx, key).Result()
 		if err != nil {
 			continue
 		}
 
-		err = s.writer.WriteMessages(ctx, kafka.Message{
-			Key:   []byte(match.ID),
-			Value: data,
-		})
+		var match models.Match
+		if err := json.Unmarshal([]byte(data), &match); err != nil {
+			continue
+		}
+		if match.User1Status != "" || match.User2Status != "" {
+			continue
+		}
+
+		match.DeriveStatus()
+		updated, err := json.Marshal(match)
 		if err != nil {
-			log.Printf("Failed to publish match created event: %v", err)
+			continue
 		}
+
+		if err := utils.RedisClient.Set(ctx, key, updated, redis.KeepTTL).Err(); err != nil {
+			return err
+		}
+		migrated++
 	}
 
+	if migrated > 0 {
+		log.Printf("Migrated %d matches to per-user statuses", migrated)
+	}
 	return nil
 }
 
+// GetAcceptedMatchUserIDs returns the ids of users who have a mutually accepted match with the given user
+func (s *Service) GetAcceptedMatchUserIDs(ctx context.Context, userID string) ([]string, error) {
+	matches, err := s.GetMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var userIDs []string
+	for _, match := range matches {
+		if !match.Mutual {
+			continue
+		}
+
+		otherID := match.UserID2
+		if match.UserID2 == userID {
+			otherID = match.UserID1
+		}
+
+		if !seen[otherID] {
+			seen[otherID] = true
+			userIDs = append(userIDs, otherID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// MatchedUserIDs returns the ids of everyone who has a match with the user, whatever its status
+func (s *Service) MatchedUserIDs(ctx context.Context, userID string) (map[string]bool, error) {
+	matches, err := s.GetMatchesForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		if match.UserID1 == userID {
+			matched[match.UserID2] = true
+		} else {
+			matched[match.UserID1] = true
+		}
+	}
+	return matched, nil
+}
+
+// HasMutualMatch reports whether two users have a mutually accepted match
+func (s *Service) HasMutualMatch(ctx context.Context, userID, otherUserID string) (bool, error) {
+	matchUserIDs, err := s.GetAcceptedMatchUserIDs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range matchUserIDs {
+		if id == otherUserID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Close closes the Kafka connections
 func (s *Service) Close() error {
 	if s.reader != nil {
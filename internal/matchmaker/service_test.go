@@ -0,0 +1,321 @@
+package matchmaker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// newTestRedis points utils.RedisClient at an in-memory Redis for the
+// duration of the test
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	previous := utils.RedisClient
+	utils.RedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		utils.RedisClient.Close()
+		utils.RedisClient = previous
+	})
+	return server
+}
+
+// newTestService creates a service whose Kafka broker is never reached by
+// the code under test
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	service := NewService([]string{"localhost:9092"}, "test-topic")
+	t.Cleanup(func() { service.Close() })
+	return service
+}
+
+func TestGetMatchesForUserReadsIndex(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	matches := []models.Match{
+		{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.9},
+		{ID: "m2", UserID1: "carol", UserID2: "alice", Score: 0.7},
+		{ID: "m3", UserID1: "bob", UserID2: "carol", Score: 0.8},
+	}
+	for _, match := range matches {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+
+	got, err := service.GetMatchesForUser(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetMatchesForUser: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "m1" || got[1].ID != "m2" {
+		t.Fatalf("got %v, want m1 then m2", matchIDs(got))
+	}
+
+	// An expired match is dropped from the index when it is next read
+	server.Del(utils.MatchKey("m1"))
+	got, err = service.GetMatchesForUser(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetMatchesForUser: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "m2" {
+		t.Fatalf("got %v after expiry, want m2", matchIDs(got))
+	}
+	if ok, _ := server.SIsMember(utils.UserMatchesKey("alice"), "m1"); ok {
+		t.Error("expired match m1 is still in alice's index")
+	}
+}
+
+func matchIDs(matches []models.Match) []string {
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	return ids
+}
+
+func TestSharedInterestsRaiseScore(t *testing.T) {
+	service := &Service{config: LoadConfig()}
+	weights := DefaultMatchWeights()
+
+	alice := &models.UserProfile{UserID: "alice", Tags: []string{"fintech"}, Interests: []string{"Climbing", "chess"}}
+	climber := &models.UserProfile{UserID: "bob", Tags: []string{"fintech"}, Interests: []string{"climbing", "Chess"}}
+	sailor := &models.UserProfile{UserID: "carol", Tags: []string{"fintech"}, Interests: []string{"sailing"}}
+
+	shared := service.CalculateScoreBreakdown(alice, climber, weights)
+	unshared := service.CalculateScoreBreakdown(alice, sailor, weights)
+
+	if shared.Interests <= 0 {
+		t.Errorf("interests contribution = %v with shared interests, want > 0", shared.Interests)
+	}
+	if unshared.Interests != 0 {
+		t.Errorf("interests contribution = %v with nothing shared, want 0", unshared.Interests)
+	}
+	if shared.Total <= unshared.Total {
+		t.Errorf("score with shared interests %v is not above score without %v", shared.Total, unshared.Total)
+	}
+
+	weights.Interests = 0
+	if got := service.CalculateMatchScore(alice, climber, weights); got != service.CalculateMatchScore(alice, sailor, weights) {
+		t.Errorf("interests still change the score with a zero interests weight")
+	}
+}
+
+func TestMinCommonAttributesExcludesPairsSharingNothing(t *testing.T) {
+	t.Setenv("MATCH_MIN_COMMON_ATTRIBUTES", "1")
+	service := &Service{config: LoadConfig()}
+
+	alice := &models.UserProfile{UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Industries: []string{"finance"}}
+	sharesSkill := &models.UserProfile{UserID: "bob", Tags: []string{"health"}, Skills: []string{"Go"}}
+	sharesIndustry := &models.UserProfile{UserID: "carol", Tags: []string{"health"}, Skills: []string{"rust"}, Industries: []string{"finance"}}
+
+	if !service.HasEnoughInCommon(alice, sharesSkill) {
+		t.Error("a pair sharing one skill was excluded with K=1")
+	}
+	// Only tags and skills count towards K
+	if service.HasEnoughInCommon(alice, sharesIndustry) {
+		t.Error("a pair sharing no tags or skills was kept with K=1")
+	}
+
+	service.config.MinCommonAttributes = 0
+	if !service.HasEnoughInCommon(alice, sharesIndustry) {
+		t.Error("K=0 still excludes pairs")
+	}
+}
+
+func TestStoreMatchesStoresEveryMatch(t *testing.T) {
+	server := newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	if err := service.StoreMatches(ctx, nil); err != nil {
+		t.Fatalf("StoreMatches with no matches: %v", err)
+	}
+
+	matches := []models.Match{
+		{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.9, User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusPending},
+		{ID: "m2", UserID1: "carol", UserID2: "alice", Score: 0.7, User1Status: models.MatchStatusRejected, User2Status: models.MatchStatusPending},
+	}
+	if err := service.StoreMatches(ctx, matches); err != nil {
+		t.Fatalf("StoreMatches: %v", err)
+	}
+
+	for _, want := range matches {
+		got, err := service.GetMatch(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("GetMatch(%s): %v", want.ID, err)
+		}
+		if got.UserID1 != want.UserID1 || got.UserID2 != want.UserID2 || got.Score != want.Score ||
+			got.User1Status != want.User1Status || got.User2Status != want.User2Status {
+			t.Errorf("stored %s = %+v, want %+v", want.ID, got, want)
+		}
+		if ttl := server.TTL(utils.MatchKey(want.ID)); ttl != utils.CacheTTLs().Match {
+			t.Errorf("%s TTL = %v, want %v", want.ID, ttl, utils.CacheTTLs().Match)
+		}
+		for _, userID := range []string{want.UserID1, want.UserID2} {
+			if ok, _ := server.SIsMember(utils.UserMatchesKey(userID), want.ID); !ok {
+				t.Errorf("%s is missing from %s's index", want.ID, userID)
+			}
+		}
+	}
+}
+
+func TestGetMutualMatchesNeedsBothUsers(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	matches := []models.Match{
+		{ID: "one-sided", UserID1: "alice", UserID2: "bob", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusPending},
+		{ID: "mutual", UserID1: "carol", UserID2: "alice", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusAccepted},
+		{ID: "rejected", UserID1: "alice", UserID2: "dave", User1Status: models.MatchStatusAccepted, User2Status: models.MatchStatusRejected},
+	}
+	for _, match := range matches {
+		if err := service.StoreMatch(ctx, match); err != nil {
+			t.Fatalf("StoreMatch: %v", err)
+		}
+	}
+
+	mutual, err := service.GetMutualMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetMutualMatches: %v", err)
+	}
+	if len(mutual) != 1 || mutual[0].ID != "mutual" || mutual[0].Status != models.MatchStatusAccepted {
+		t.Errorf("mutual matches = %v, want only the match both users accepted", matchIDs(mutual))
+	}
+
+	// Bob accepting too makes the one-sided match mutual
+	if ok, err := service.HasMutualMatch(ctx, "alice", "bob"); err != nil || ok {
+		t.Errorf("HasMutualMatch before bob accepts = %v, %v", ok, err)
+	}
+	matches[0].User2Status = models.MatchStatusAccepted
+	if err := service.StoreMatch(ctx, matches[0]); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+	if mutual, err = service.GetMutualMatches(ctx, "alice"); err != nil || len(mutual) != 2 {
+		t.Errorf("mutual matches after bob accepts = %v, %v; want 2", matchIDs(mutual), err)
+	}
+	if ok, err := service.HasMutualMatch(ctx, "alice", "bob"); err != nil || !ok {
+		t.Errorf("HasMutualMatch after bob accepts = %v, %v", ok, err)
+	}
+}
+
+func TestFindMatchesRespectsStoredMatchCap(t *testing.T) {
+	t.Setenv("MATCH_MAX_STORED", "7")
+	newTestRedis(t)
+	service := newTestService(t)
+	ctx := context.Background()
+
+	profile := models.UserProfile{
+		Tags:       []string{"fintech", "ai"},
+		Skills:     []string{"go"},
+		Location:   "berlin",
+		Experience: 5,
+	}
+	for i := 0; i <= 25; i++ {
+		profile.UserID = fmt.Sprintf("user-%02d", i)
+		if err := service.StoreUserProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", profile.UserID, err)
+		}
+	}
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: 0, want: 7},   // the configured cap
+		{limit: 20, want: 20}, // a per-request override
+		{limit: 50, want: 25}, // every qualifying profile
+	}
+	for _, tt := range tests {
+		matches, err := service.FindMatchesLimit(ctx, "user-00", tt.limit)
+		if err != nil {
+			t.Fatalf("FindMatchesLimit(%d): %v", tt.limit, err)
+		}
+		if len(matches) != tt.want {
+			t.Errorf("FindMatchesLimit(%d) returned %d matches, want %d", tt.limit, len(matches), tt.want)
+		}
+	}
+}
+
+func TestWeightProfilesScoreTheSamePairDifferently(t *testing.T) {
+	service := &Service{config: LoadConfig()}
+
+	alice := &models.UserProfile{UserID: "alice", Skills: []string{"go"}, Interests: []string{"climbing", "chess"}, Location: "berlin"}
+	bob := &models.UserProfile{UserID: "bob", Skills: []string{"design"}, Interests: []string{"climbing", "chess"}, Location: "berlin"}
+
+	recruiting, err := service.WeightProfile("recruiting")
+	if err != nil {
+		t.Fatalf("WeightProfile(recruiting): %v", err)
+	}
+	cofounder, err := service.WeightProfile("cofounder")
+	if err != nil {
+		t.Fatalf("WeightProfile(cofounder): %v", err)
+	}
+
+	// Shared interests count for a cofounder search but not for recruiting
+	recruitingScore := service.CalculateMatchScore(alice, bob, recruiting)
+	cofounderScore := service.CalculateMatchScore(alice, bob, cofounder)
+	if cofounderScore <= recruitingScore {
+		t.Errorf("cofounder score %v is not above recruiting score %v", cofounderScore, recruitingScore)
+	}
+
+	// A user's saved profile selects its weights; an unknown one falls back to general
+	alice.WeightProfile = "cofounder"
+	if got := service.WeightsFor(alice); got != cofounder {
+		t.Errorf("WeightsFor(cofounder user) = %+v, want %+v", got, cofounder)
+	}
+	alice.WeightProfile = "astrology"
+	if got, want := service.WeightsFor(alice), service.config.WeightProfiles[DefaultWeightProfile]; got != want {
+		t.Errorf("WeightsFor(unknown profile) = %+v, want %+v", got, want)
+	}
+}
+
+func TestMinProfileAgeHoldsBackNewProfiles(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.config.MinProfileAge = time.Hour
+	ctx := context.Background()
+
+	store := func(userID string, createdAt time.Time) {
+		t.Helper()
+		profile := models.UserProfile{UserID: userID, Tags: []string{"fintech"}, Skills: []string{"go"}, Location: "berlin", Experience: 5, CreatedAt: createdAt}
+		if err := service.storeNormalizedProfile(ctx, profile); err != nil {
+			t.Fatalf("store %s: %v", userID, err)
+		}
+	}
+	store("alice", time.Now().Add(-2*time.Hour))
+	store("bob", time.Now())
+
+	matches, err := service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("matches = %v, want the fresh profile held back", matchIDs(matches))
+	}
+
+	// Once bob's profile is old enough it is matched
+	store("bob", time.Now().Add(-time.Hour-time.Minute))
+	matches, err = service.FindMatches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("FindMatches: %v", err)
+	}
+	if len(matches) != 1 || matches[0].UserID2 != "bob" {
+		t.Errorf("got %d matches, want bob once his profile is an hour old", len(matches))
+	}
+
+	// The default of 0 matches new profiles right away
+	service.config.MinProfileAge = 0
+	if !service.OldEnough(&models.UserProfile{CreatedAt: time.Now()}, time.Now()) {
+		t.Error("a new profile is held back with no minimum age")
+	}
+}
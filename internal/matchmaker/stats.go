@@ -0,0 +1,140 @@
+package matchmaker
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// statsBuckets is how many equal-width score ranges the distribution has
+const statsBuckets = 10
+
+// statsReadBatch is how many stored matches are fetched per MGET
+const statsReadBatch = 500
+
+// ScoreBucket counts the matches whose unboosted score falls in [Min, Max);
+// the top bucket also holds perfect scores
+type ScoreBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// Stats summarizes stored profiles and matches for product reporting
+type Stats struct {
+	TotalProfiles     int           `json:"total_profiles"`
+	TotalMatches      int           `json:"total_matches"`
+	AcceptedMatches   int           `json:"accepted_matches"` // accepted by both users
+	RejectedMatches   int           `json:"rejected_matches"` // rejected by either user
+	PendingMatches    int           `json:"pending_matches"`
+	AcceptanceRate    float64       `json:"acceptance_rate"` // accepted matches over all matches
+	RejectionRate     float64       `json:"rejection_rate"`  // rejected matches over all matches
+	AverageScore      float64       `json:"average_score"`   // unboosted
+	ScoreDistribution []ScoreBucket `json:"score_distribution"`
+	GeneratedAt       time.Time     `json:"generated_at"`
+}
+
+// Stats returns aggregate matchmaking statistics. Computing them reads every
+// stored match, so the result is cached for StatsCacheTTL.
+func (s *Service) Stats(ctx context.Context) (*Stats, error) {
+	if data, err := utils.RedisClient.Get(ctx, utils.MatchStatsKey()).Bytes(); err == nil {
+		var stats Stats
+		if err := json.Unmarshal(data, &stats); err == nil {
+			return &stats, nil
+		}
+	}
+
+	stats, err := s.computeStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.config.StatsCacheTTL > 0 {
+		if data, err := json.Marshal(stats); err == nil {
+			if err := utils.RedisClient.Set(ctx, utils.MatchStatsKey(), data, s.config.StatsCacheTTL).Err(); err != nil {
+				log.Printf("Failed to cache matchmaking stats: %v", err)
+			}
+		}
+	}
+	return stats, nil
+}
+
+// computeStats scans the stored profiles and matches
+func (s *Service) computeStats(ctx context.Context) (*Stats, error) {
+	profileKeys, err := utils.RedisClient.Keys(ctx, utils.UserProfileKeyPattern()).Result()
+	if err != nil {
+		return nil, err
+	}
+	matchKeys, err := utils.RedisClient.Keys(ctx, utils.MatchKeyPattern()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{
+		TotalProfiles:     len(profileKeys),
+		ScoreDistribution: make([]ScoreBucket, statsBuckets),
+		GeneratedAt:       time.Now().UTC(),
+	}
+	for i := range stats.ScoreDistribution {
+		stats.ScoreDistribution[i] = ScoreBucket{
+			Min: float64(i) / statsBuckets,
+			Max: float64(i+1) / statsBuckets,
+		}
+	}
+
+	var scoreSum float64
+	for start := 0; start < len(matchKeys); start += statsReadBatch {
+		batch := matchKeys[start:min(start+statsReadBatch, len(matchKeys))]
+		values, err := utils.RedisClient.MGet(ctx, batch...).Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				// Expired between KEYS and MGET
+				continue
+			}
+
+			var match models.Match
+			if err := json.Unmarshal([]byte(data), &match); err != nil {
+				continue
+			}
+			match.DeriveStatus()
+
+			stats.TotalMatches++
+			switch match.Status {
+			case models.MatchStatusAccepted:
+				stats.AcceptedMatches++
+			case models.MatchStatusRejected:
+				stats.RejectedMatches++
+			default:
+				stats.PendingMatches++
+			}
+
+			score := match.Score
+			if match.RawScore > 0 {
+				score = match.RawScore
+			}
+			scoreSum += score
+			bucket := int(math.Floor(score * statsBuckets))
+			stats.ScoreDistribution[clamp(bucket, 0, statsBuckets-1)].Count++
+		}
+	}
+
+	if stats.TotalMatches > 0 {
+		total := float64(stats.TotalMatches)
+		stats.AcceptanceRate = float64(stats.AcceptedMatches) / total
+		stats.RejectionRate = float64(stats.RejectedMatches) / total
+		stats.AverageScore = scoreSum / total
+	}
+	return stats, nil
+}
@@ -0,0 +1,70 @@
+package matchmaker
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestStatsAcceptanceRate(t *testing.T) {
+	newTestRedis(t)
+	service := newTestService(t)
+	service.config.StatsCacheTTL = time.Minute
+	ctx := context.Background()
+
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		if err := service.storeNormalizedProfile(ctx, models.UserProfile{UserID: userID}); err != nil {
+			t.Fatalf("store %s: %v", userID, err)
+		}
+	}
+
+	accepted, rejected, pending := models.MatchStatusAccepted, models.MatchStatusRejected, models.MatchStatusPending
+	matches := []models.Match{
+		{ID: "m1", UserID1: "alice", UserID2: "bob", Score: 0.95, User1Status: accepted, User2Status: accepted},
+		{ID: "m2", UserID1: "alice", UserID2: "carol", Score: 0.5, User1Status: accepted, User2Status: rejected},
+		{ID: "m3", UserID1: "bob", UserID2: "carol", Score: 0.5, User1Status: accepted, User2Status: pending},
+		{ID: "m4", UserID1: "carol", UserID2: "dave", Score: 0.9, RawScore: 0.2, User1Status: pending, User2Status: pending},
+	}
+	if err := service.StoreMatches(ctx, matches); err != nil {
+		t.Fatalf("StoreMatches: %v", err)
+	}
+
+	stats, err := service.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.TotalProfiles != 3 || stats.TotalMatches != 4 {
+		t.Errorf("totals = %d profiles, %d matches; want 3 and 4", stats.TotalProfiles, stats.TotalMatches)
+	}
+	if stats.AcceptedMatches != 1 || stats.RejectedMatches != 1 || stats.PendingMatches != 2 {
+		t.Errorf("accepted/rejected/pending = %d/%d/%d, want 1/1/2", stats.AcceptedMatches, stats.RejectedMatches, stats.PendingMatches)
+	}
+	if stats.AcceptanceRate != 0.25 || stats.RejectionRate != 0.25 {
+		t.Errorf("acceptance rate %v, rejection rate %v; want 0.25 each", stats.AcceptanceRate, stats.RejectionRate)
+	}
+	// m4 counts at its unboosted 0.2
+	if want := (0.95 + 0.5 + 0.5 + 0.2) / 4; math.Abs(stats.AverageScore-want) > 1e-9 {
+		t.Errorf("average score = %v, want %v", stats.AverageScore, want)
+	}
+	for bucket, want := range map[int]int{2: 1, 5: 2, 9: 1} {
+		if got := stats.ScoreDistribution[bucket].Count; got != want {
+			t.Errorf("bucket [%v, %v) count = %d, want %d", stats.ScoreDistribution[bucket].Min, stats.ScoreDistribution[bucket].Max, got, want)
+		}
+	}
+
+	// Cached until the TTL passes, even as matches change
+	extra := models.Match{ID: "m5", UserID1: "alice", UserID2: "dave", Score: 0.7, User1Status: accepted, User2Status: accepted}
+	if err := service.StoreMatch(ctx, extra); err != nil {
+		t.Fatalf("StoreMatch: %v", err)
+	}
+	cached, err := service.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if cached.TotalMatches != 4 {
+		t.Errorf("cached stats count %d matches, want the 4 from before", cached.TotalMatches)
+	}
+}
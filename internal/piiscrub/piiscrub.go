@@ -0,0 +1,161 @@
+// Package piiscrub removes personal data from analytics event payloads
+// before they leave the service.
+package piiscrub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// Mode decides what replaces personal data
+type Mode string
+
+// Scrubbing modes
+const (
+	ModeOff    Mode = "off"    // payloads are left as they are
+	ModeHash   Mode = "hash"   // values are replaced with a salted SHA-256, so equal values still correlate
+	ModeRedact Mode = "redact" // values are replaced with Redacted
+)
+
+// Redacted replaces personal data under ModeRedact
+const Redacted = "[redacted]"
+
+// DefaultKeys are the payload keys whose values are always personal data
+var DefaultKeys = []string{"email", "phone", "phone_number", "ip", "ip_address"}
+
+// emailPattern finds email addresses in free text
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Scrubber replaces personal data in event payloads: whole values under
+// known keys, at any depth, and matches of its patterns in any other string.
+// A nil Scrubber leaves payloads as they are.
+type Scrubber struct {
+	mode     Mode
+	salt     string
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// NewScrubber creates a scrubber for the given keys, matched ignoring case,
+// and patterns. salt is mixed into hashes so they can't be reversed by
+// hashing guessed values.
+func NewScrubber(mode Mode, salt string, keys []string, patterns []*regexp.Regexp) *Scrubber {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			set[key] = struct{}{}
+		}
+	}
+	return &Scrubber{mode: mode, salt: salt, keys: set, patterns: patterns}
+}
+
+// NewScrubberFromEnv builds a scrubber from ANALYTICS_PII_MODE (off, hash, or
+// redact), the keys in ANALYTICS_PII_KEYS (comma separated, DefaultKeys when
+// unset), and the built-in email pattern plus the regular expressions in
+// ANALYTICS_PII_PATTERNS_FILE (one per line). Hashes are salted with
+// ANALYTICS_PII_SALT.
+func NewScrubberFromEnv() *Scrubber {
+	mode := Mode(strings.ToLower(utils.GetEnv("ANALYTICS_PII_MODE", string(ModeHash))))
+	switch mode {
+	case ModeOff, ModeHash, ModeRedact:
+	default:
+		log.Printf("Unknown ANALYTICS_PII_MODE %q, hashing personal data", mode)
+		mode = ModeHash
+	}
+
+	keys := DefaultKeys
+	if value := os.Getenv("ANALYTICS_PII_KEYS"); value != "" {
+		keys = strings.Split(value, ",")
+	}
+
+	patterns := []*regexp.Regexp{emailPattern}
+	if path := os.Getenv("ANALYTICS_PII_PATTERNS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Failed to read analytics PII patterns file: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			pattern, err := regexp.Compile(line)
+			if err != nil {
+				log.Printf("Skipping invalid analytics PII pattern %q: %v", line, err)
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return NewScrubber(mode, os.Getenv("ANALYTICS_PII_SALT"), keys, patterns)
+}
+
+// Scrub returns a copy of data with personal data replaced; data itself is
+// not changed
+func (s *Scrubber) Scrub(data map[string]interface{}) map[string]interface{} {
+	if s == nil || s.mode == ModeOff || data == nil {
+		return data
+	}
+	return s.scrubMap(data)
+}
+
+func (s *Scrubber) scrubMap(data map[string]interface{}) map[string]interface{} {
+	scrubbed := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		_, personal := s.keys[strings.ToLower(key)]
+		scrubbed[key] = s.scrubValue(value, personal)
+	}
+	return scrubbed
+}
+
+// scrubValue scrubs one value. A personal value is replaced whole; otherwise
+// only pattern matches in strings are.
+func (s *Scrubber) scrubValue(value interface{}, personal bool) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return s.scrubMap(v)
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.scrubValue(item, personal)
+		}
+		return scrubbed
+	case []string:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.scrubValue(item, personal)
+		}
+		return scrubbed
+	case string:
+		if personal {
+			return s.replace(v)
+		}
+		for _, pattern := range s.patterns {
+			v = pattern.ReplaceAllStringFunc(v, s.replace)
+		}
+		return v
+	default:
+		if personal {
+			return s.replace(fmt.Sprint(v))
+		}
+		return v
+	}
+}
+
+// replace returns what stands in for a piece of personal data
+func (s *Scrubber) replace(value string) string {
+	if s.mode == ModeRedact {
+		return Redacted
+	}
+	sum := sha256.Sum256([]byte(s.salt + strings.ToLower(strings.TrimSpace(value))))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
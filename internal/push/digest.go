@@ -0,0 +1,149 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+// PendingMessage is a chat message waiting to be included in a notification
+type PendingMessage struct {
+	MessageID string    `json:"message_id"`
+	SenderID  string    `json:"sender_id"`
+	Preview   string    `json:"preview,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Digester folds the messages an offline user receives within a window into
+// one notification. The window is shared through Redis, so messages handled
+// by different instances still produce a single notification.
+type Digester struct {
+	notifier      Notifier
+	window        time.Duration
+	previewLength int
+}
+
+// NewDigester creates a digester sending through notifier. A window of 0
+// sends one notification per message.
+func NewDigester(notifier Notifier, window time.Duration, previewLength int) *Digester {
+	return &Digester{notifier: notifier, window: window, previewLength: previewLength}
+}
+
+// NewDigesterFromEnv creates a digester for the notifier configured by
+// PUSH_NOTIFIER, batching over PUSH_DIGEST_WINDOW with previews cut to
+// PUSH_PREVIEW_LENGTH characters. It returns nil when push is off.
+func NewDigesterFromEnv() *Digester {
+	notifier := NewNotifierFromEnv()
+	if notifier == nil {
+		return nil
+	}
+	return NewDigester(notifier,
+		utils.GetEnvDuration("PUSH_DIGEST_WINDOW", 30*time.Second),
+		utils.GetEnvInt("PUSH_PREVIEW_LENGTH", 100))
+}
+
+// Preview shortens message content for a notification
+func (d *Digester) Preview(content string) string {
+	if d.previewLength <= 0 || utf8.RuneCountInString(content) <= d.previewLength {
+		return content
+	}
+	runes := []rune(content)
+	return string(runes[:d.previewLength]) + "…"
+}
+
+// Enqueue records a message for an offline user. The first message of a
+// window schedules the notification; later ones are folded into it.
+// Notifications are always sent in the background, so a slow provider can't
+// hold up the caller, typically the sender's connection.
+func (d *Digester) Enqueue(ctx context.Context, userID string, message PendingMessage) error {
+	if d.window <= 0 || utils.RedisClient == nil {
+		go d.send(userID, []PendingMessage{message})
+		return nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	// The pending list outlives the window in case the instance that
+	// scheduled the notification dies; the next window picks it up
+	pendingKey := utils.PushPendingKey(userID)
+	pipe := utils.RedisClient.TxPipeline()
+	pipe.RPush(ctx, pendingKey, data)
+	pipe.Expire(ctx, pendingKey, d.window+time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	opened, err := utils.RedisClient.SetNX(ctx, utils.PushWindowKey(userID), 1, d.window+time.Minute).Result()
+	if err != nil {
+		return err
+	}
+	if opened {
+		time.AfterFunc(d.window, func() { d.flush(userID) })
+	}
+	return nil
+}
+
+// flush sends the messages collected for a user and closes their window
+func (d *Digester) flush(userID string) {
+	ctx := context.Background()
+
+	pendingKey := utils.PushPendingKey(userID)
+	pipe := utils.RedisClient.TxPipeline()
+	entries := pipe.LRange(ctx, pendingKey, 0, -1)
+	pipe.Del(ctx, pendingKey, utils.PushWindowKey(userID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to collect push notifications for user %s: %v", userID, err)
+		return
+	}
+
+	messages := make([]PendingMessage, 0, len(entries.Val()))
+	for _, entry := range entries.Val() {
+		var message PendingMessage
+		if err := json.Unmarshal([]byte(entry), &message); err != nil {
+			log.Printf("Failed to parse pending push notification: %v", err)
+			continue
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return
+	}
+	d.send(userID, messages)
+}
+
+// send notifies a user of messages, logging a failure since nobody is
+// waiting on the result
+func (d *Digester) send(userID string, messages []PendingMessage) {
+	if err := d.notifier.Notify(context.Background(), digest(userID, messages)); err != nil {
+		log.Printf("Failed to send push notification to user %s: %v", userID, err)
+	}
+}
+
+// digest builds the notification covering messages, oldest first
+func digest(userID string, messages []PendingMessage) Notification {
+	latest := messages[len(messages)-1]
+	notification := Notification{
+		UserID:    userID,
+		SenderID:  latest.SenderID,
+		MessageID: latest.MessageID,
+		Preview:   latest.Preview,
+		Count:     len(messages),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	seen := make(map[string]bool)
+	for _, message := range messages {
+		if !seen[message.SenderID] {
+			seen[message.SenderID] = true
+			notification.SenderIDs = append(notification.SenderIDs, message.SenderID)
+		}
+	}
+	return notification
+}
@@ -0,0 +1,91 @@
+package push
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/connect-up/auth-service/utils"
+)
+
+func TestDigestFoldsRapidMessages(t *testing.T) {
+	server := miniredis.RunT(t)
+	previous := utils.RedisClient
+	utils.RedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		utils.RedisClient.Close()
+		utils.RedisClient = previous
+	})
+
+	sent := make(chan Notification, 4)
+	digester := NewDigester(NotifierFunc(func(ctx context.Context, n Notification) error {
+		sent <- n
+		return nil
+	}), 50*time.Millisecond, 100)
+
+	ctx := context.Background()
+	for _, message := range []PendingMessage{
+		{MessageID: "m1", SenderID: "alice", Preview: "hi"},
+		{MessageID: "m2", SenderID: "bob", Preview: "hello"},
+		{MessageID: "m3", SenderID: "alice", Preview: "are you there?"},
+	} {
+		if err := digester.Enqueue(ctx, "carol", message); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	select {
+	case n := <-sent:
+		if n.UserID != "carol" || n.Count != 3 || n.MessageID != "m3" || n.Preview != "are you there?" {
+			t.Errorf("notification = %+v, want one digest of 3 ending with m3", n)
+		}
+		if !reflect.DeepEqual(n.SenderIDs, []string{"alice", "bob"}) {
+			t.Errorf("senders = %v, want alice then bob", n.SenderIDs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification was sent after the window")
+	}
+
+	select {
+	case n := <-sent:
+		t.Errorf("got a second notification %+v for the same window", n)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestUnbatchedNotificationDoesNotBlockSender(t *testing.T) {
+	release := make(chan struct{})
+	sent := make(chan Notification, 1)
+	digester := NewDigester(NotifierFunc(func(ctx context.Context, n Notification) error {
+		<-release // a provider that is slow to answer
+		sent <- n
+		return nil
+	}), 0, 100)
+
+	enqueued := make(chan error, 1)
+	go func() {
+		enqueued <- digester.Enqueue(context.Background(), "carol", PendingMessage{MessageID: "m1", SenderID: "alice"})
+	}()
+	select {
+	case err := <-enqueued:
+		if err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Enqueue waited on the push provider")
+	}
+
+	close(release)
+	select {
+	case n := <-sent:
+		if n.UserID != "carol" || n.Count != 1 || n.MessageID != "m1" {
+			t.Errorf("notification = %+v, want one for m1", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no notification was sent")
+	}
+}
@@ -0,0 +1,117 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/connect-up/auth-service/internal/webhook"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// Notification tells an offline user about chat messages they missed. Messages
+// arriving close together are folded into one notification.
+type Notification struct {
+	UserID    string    `json:"user_id"`           // recipient
+	SenderID  string    `json:"sender_id"`         // sender of the latest message
+	SenderIDs []string  `json:"sender_ids"`        // every sender in the digest, first message first
+	MessageID string    `json:"message_id"`        // latest message
+	Preview   string    `json:"preview,omitempty"` // start of the latest message; empty if the recipient hides previews
+	Count     int       `json:"count"`             // messages covered
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Notifier delivers push notifications to a provider such as FCM, APNs, or a
+// push gateway reached by webhook
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// NotifierFunc adapts a function to a Notifier
+type NotifierFunc func(ctx context.Context, notification Notification) error
+
+// Notify calls f
+func (f NotifierFunc) Notify(ctx context.Context, notification Notification) error {
+	return f(ctx, notification)
+}
+
+// LogNotifier logs notifications instead of sending them, for development
+type LogNotifier struct{}
+
+// Notify logs the notification
+func (LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	log.Printf("Push notification for %s: %d message(s), latest %s from %s",
+		notification.UserID, notification.Count, notification.MessageID, notification.SenderID)
+	return nil
+}
+
+// WebhookNotifier POSTs notifications as JSON to a push gateway, which fans
+// them out to the recipient's devices. Requests are signed like outgoing
+// webhooks when a secret is set.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier that POSTs to url
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// Notify POSTs the notification to the gateway
+func (n *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewNotifierFromEnv builds the notifier named by PUSH_NOTIFIER: off (the
+// default, returning nil), log, or webhook (POSTing to PUSH_WEBHOOK_URL,
+// signed with PUSH_WEBHOOK_SECRET)
+func NewNotifierFromEnv() Notifier {
+	switch kind := strings.ToLower(utils.GetEnv("PUSH_NOTIFIER", "off")); kind {
+	case "off":
+		return nil
+	case "log":
+		return LogNotifier{}
+	case "webhook":
+		url := utils.GetEnv("PUSH_WEBHOOK_URL", "")
+		if url == "" {
+			log.Printf("PUSH_NOTIFIER is webhook but PUSH_WEBHOOK_URL is not set, push notifications are off")
+			return nil
+		}
+		return NewWebhookNotifier(url, utils.GetEnv("PUSH_WEBHOOK_SECRET", ""), utils.GetEnvDuration("PUSH_TIMEOUT", 10*time.Second))
+	default:
+		log.Printf("Unknown PUSH_NOTIFIER %q, push notifications are off", kind)
+		return nil
+	}
+}
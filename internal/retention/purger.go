@@ -0,0 +1,102 @@
+package retention
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// messagesPurged counts messages removed for being past their retention
+var messagesPurged = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "messages_retention_purged_total",
+	Help: "Chat messages removed for being past their retention, by mode (purge or archive).",
+}, []string{"mode"})
+
+// Config holds the message retention policy
+type Config struct {
+	Days      int           // default retention; 0 keeps messages forever unless a conversation overrides it
+	Mode      string        // models.RetentionModePurge or models.RetentionModeArchive
+	Interval  time.Duration // how often expired messages are looked for; 0 disables the worker
+	BatchSize int           // messages removed per statement, to keep locks short
+}
+
+// LoadConfig reads the retention policy from the environment
+func LoadConfig() Config {
+	mode := strings.ToLower(utils.GetEnv("MESSAGE_RETENTION_MODE", models.RetentionModePurge))
+	if mode != models.RetentionModePurge && mode != models.RetentionModeArchive {
+		log.Printf("Unknown MESSAGE_RETENTION_MODE %q, purging expired messages", mode)
+		mode = models.RetentionModePurge
+	}
+
+	return Config{
+		Days:      max(utils.GetEnvInt("MESSAGE_RETENTION_DAYS", 0), 0),
+		Mode:      mode,
+		Interval:  utils.GetEnvDuration("MESSAGE_RETENTION_INTERVAL", time.Hour),
+		BatchSize: max(utils.GetEnvInt("MESSAGE_RETENTION_BATCH_SIZE", 1000), 1),
+	}
+}
+
+// Purger periodically removes chat messages past their retention
+type Purger struct {
+	config Config
+	now    func() time.Time
+	purge  func(now time.Time, defaultDays int, mode string, limit int) (int64, error)
+}
+
+// NewPurger creates a purger for config
+func NewPurger(config Config) *Purger {
+	return &Purger{config: config, now: time.Now, purge: models.PurgeExpiredMessages}
+}
+
+// Start purges expired messages every interval until ctx is cancelled
+func (p *Purger) Start(ctx context.Context) {
+	if p.config.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := p.PurgeOnce(ctx); err != nil {
+				log.Printf("Message retention purge failed: %v", err)
+			}
+		}
+	}
+}
+
+// PurgeOnce removes every message past its retention, batch by batch, and
+// returns how many were removed. Retention is measured from a single
+// instant, so messages expiring mid-run wait for the next one.
+func (p *Purger) PurgeOnce(ctx context.Context) (int64, error) {
+	now := p.now()
+
+	var total int64
+	for ctx.Err() == nil {
+		n, err := p.purge(now, p.config.Days, p.config.Mode, p.config.BatchSize)
+		total += n
+		messagesPurged.WithLabelValues(p.config.Mode).Add(float64(n))
+		if err != nil {
+			return total, err
+		}
+		if n < int64(p.config.BatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Message retention removed %d messages (%s)", total, p.config.Mode)
+	}
+	return total, ctx.Err()
+}
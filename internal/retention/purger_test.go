@@ -0,0 +1,69 @@
+package retention
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+// memoryMessages is an in-memory message table purged by creation time
+type memoryMessages struct {
+	createdAt map[string]time.Time
+	calls     int
+}
+
+func (m *memoryMessages) purge(now time.Time, defaultDays int, mode string, limit int) (int64, error) {
+	m.calls++
+	cutoff := now.AddDate(0, 0, -defaultDays)
+
+	var expired []string
+	for id, created := range m.createdAt {
+		if created.Before(cutoff) {
+			expired = append(expired, id)
+		}
+	}
+	sort.Strings(expired)
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	for _, id := range expired {
+		delete(m.createdAt, id)
+	}
+	return int64(len(expired)), nil
+}
+
+func TestPurgeOnceRemovesOnlyExpiredMessages(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	messages := &memoryMessages{createdAt: map[string]time.Time{
+		"old-1":  now.AddDate(0, 0, -90),
+		"old-2":  now.AddDate(0, 0, -31),
+		"old-3":  now.AddDate(0, 0, -30).Add(-time.Minute),
+		"edge":   now.AddDate(0, 0, -30),
+		"recent": now.AddDate(0, 0, -1),
+	}}
+
+	purger := NewPurger(Config{Days: 30, Mode: models.RetentionModePurge, BatchSize: 2})
+	purger.now = func() time.Time { return now }
+	purger.purge = messages.purge
+
+	removed, err := purger.PurgeOnce(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeOnce: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("removed %d messages, want the 3 older than 30 days", removed)
+	}
+	if _, ok := messages.createdAt["edge"]; !ok {
+		t.Error("purged a message exactly at the retention age")
+	}
+	if _, ok := messages.createdAt["recent"]; !ok {
+		t.Error("purged a recent message")
+	}
+	// A full batch of two, then a short one that ends the run
+	if messages.calls != 2 {
+		t.Errorf("purged in %d batches, want 2", messages.calls)
+	}
+}
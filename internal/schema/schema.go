@@ -0,0 +1,131 @@
+// Package schema validates request bodies against the JSON Schema
+// definitions in schemas/, which double as documentation of each body.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Names of the request body schemas, each defined in schemas/<name>.json
+const (
+	Company    = "company"
+	Profile    = "profile"
+	Investment = "investment"
+)
+
+//go:embed schemas/*.json
+var files embed.FS
+
+// schemas holds the compiled schemas by name. It is filled once by Load,
+// before requests are served.
+var schemas map[string]*jsonschema.Schema
+
+// FieldError is one schema violation. Path is a JSON Pointer (RFC 6901) to
+// the offending value, "" for the body itself.
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// Load compiles every schema, failing on the first that doesn't compile
+func Load() error {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+
+	compiled := make(map[string]*jsonschema.Schema)
+	for _, name := range []string{Company, Profile, Investment} {
+		file := "schemas/" + name + ".json"
+		data, err := files.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := compiler.AddResource(file, bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("load %s schema: %w", name, err)
+		}
+		if compiled[name], err = compiler.Compile(file); err != nil {
+			return fmt.Errorf("compile %s schema: %w", name, err)
+		}
+	}
+
+	schemas = compiled
+	return nil
+}
+
+// Validate checks a JSON body against the named schema, returning every
+// violation ordered by path. The error is non-nil only when the body isn't
+// JSON or the schema isn't loaded.
+func Validate(name string, body []byte) ([]FieldError, error) {
+	s, ok := schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("schema %q is not loaded", name)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after JSON body")
+	}
+
+	err := s.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	var violations []FieldError
+	collect(validationErr, &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Path < violations[j].Path
+	})
+	return violations, nil
+}
+
+// collect appends the leaves of a validation error tree, which are the
+// individual violations; inner nodes only group them
+func collect(err *jsonschema.ValidationError, violations *[]FieldError) {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			collect(cause, violations)
+		}
+		return
+	}
+
+	// A missing required property is reported against its parent; point
+	// at each missing property instead
+	if strings.HasSuffix(err.KeywordLocation, "/required") {
+		if names, ok := strings.CutPrefix(err.Message, "missing properties: "); ok {
+			for _, name := range strings.Split(names, ", ") {
+				name = strings.Trim(name, "'")
+				*violations = append(*violations, FieldError{
+					Path:    err.InstanceLocation + "/" + escapePointer(name),
+					Message: "is required",
+				})
+			}
+			return
+		}
+	}
+
+	*violations = append(*violations, FieldError{Path: err.InstanceLocation, Message: err.Message})
+}
+
+// escapePointer escapes a property name for use as a JSON Pointer token
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	body := []byte(`{
+		"founded_year": 1700,
+		"revenue": -5,
+		"is_public": "yes",
+		"tags": ["ok", 7]
+	}`)
+	violations, err := Validate(Company, body)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.Path)
+		if violation.Message == "" {
+			t.Errorf("violation at %q has no message", violation.Path)
+		}
+	}
+	want := []string{"/founded_year", "/is_public", "/name", "/revenue", "/tags/1"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("violation paths = %v, want %v", paths, want)
+	}
+	for _, violation := range violations {
+		if violation.Path == "/name" && violation.Message != "is required" {
+			t.Errorf("missing name message = %q, want \"is required\"", violation.Message)
+		}
+	}
+}
+
+func TestValidateAcceptsValidBody(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	violations, err := Validate(Company, []byte(`{"name": "Acme", "founded_year": 2015, "tags": ["b2b"]}`))
+	if err != nil || len(violations) != 0 {
+		t.Errorf("Validate = %v, %v; want no violations", violations, err)
+	}
+
+	if _, err := Validate(Company, []byte(`{"name": "Acme"} {}`)); err == nil {
+		t.Error("Validate accepted trailing data after the body")
+	}
+}
+
+func TestValidateAcceptsUnsetOptionalFields(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// Zero and null values the models treat as not given, as the API
+	// returns them
+	bodies := map[string]string{
+		Company:    `{"name": "Acme", "founded_year": 0, "description": null, "tags": null, "revenue": null}`,
+		Investment: `{"company_id": "8a5f2e8e-4f8f-4b53-9d0f-0b7d8b1d6f11", "amount": 100, "status": "", "currency": "", "notes": null}`,
+		Profile:    `{"user_id": "u1", "tags": null, "visibility": "", "seeking": null, "max_matches": 0, "preference_weights": null}`,
+	}
+	for name, body := range bodies {
+		violations, err := Validate(name, []byte(body))
+		if err != nil || len(violations) != 0 {
+			t.Errorf("Validate(%s) = %v, %v; want no violations", name, violations, err)
+		}
+	}
+}
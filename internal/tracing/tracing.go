@@ -0,0 +1,131 @@
+// Package tracing sets up OpenTelemetry tracing. Spans are exported over
+// OTLP/HTTP when an endpoint is configured; otherwise the global no-op
+// provider stays in place and instrumentation costs next to nothing.
+package tracing
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this service's spans
+const instrumentationName = "github.com/connect-up/auth-service"
+
+// Tracer returns the service's tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Init installs a tracer provider exporting over OTLP/HTTP when
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set.
+// The exporter reads the rest of its settings from the standard OTEL_*
+// variables. The returned function flushes and stops the provider.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "auth-service"
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("OpenTelemetry tracing enabled for %s", serviceName)
+	return provider.Shutdown, nil
+}
+
+// End records err on the span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// kafkaHeaderCarrier adapts Kafka message headers for trace context propagation
+type kafkaHeaderCarrier struct {
+	msg *kafka.Message
+}
+
+func (c kafkaHeaderCarrier) Get(key string) string {
+	for _, header := range c.msg.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (c kafkaHeaderCarrier) Set(key, value string) {
+	for i, header := range c.msg.Headers {
+		if header.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c kafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, header := range c.msg.Headers {
+		keys[i] = header.Key
+	}
+	return keys
+}
+
+// StartProduce starts a producer span for a message bound for topic and
+// injects its context into msg's headers so the consumer's span joins the
+// same trace. topic is passed separately since writers with a fixed topic
+// leave msg.Topic empty.
+func StartProduce(ctx context.Context, topic string, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, "kafka.produce "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+		))
+	otel.GetTextMapPropagator().Inject(ctx, kafkaHeaderCarrier{msg: msg})
+	return ctx, span
+}
+
+// StartConsume starts a consumer span continuing the trace carried in msg's headers
+func StartConsume(ctx context.Context, msg *kafka.Message) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, kafkaHeaderCarrier{msg: msg})
+	return Tracer().Start(ctx, "kafka.consume "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.kafka.destination.partition", msg.Partition),
+			attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+		))
+}
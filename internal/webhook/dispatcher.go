@@ -0,0 +1,183 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/connect-up/auth-service/models"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// Event is the JSON body POSTed to webhook subscribers
+type Event struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// delivery is one event queued for one subscriber
+type delivery struct {
+	webhook *models.Webhook
+	event   string
+	eventID string
+	body    []byte
+}
+
+// Dispatcher delivers events to registered webhooks from a pool of background workers
+type Dispatcher struct {
+	client      *http.Client
+	queue       chan delivery
+	workers     int
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// NewDispatcher creates a webhook dispatcher configured from the environment
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		client:      &http.Client{Timeout: utils.GetEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second)},
+		queue:       make(chan delivery, utils.GetEnvInt("WEBHOOK_QUEUE_SIZE", 1000)),
+		workers:     utils.GetEnvInt("WEBHOOK_WORKERS", 4),
+		maxAttempts: utils.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 5),
+		backoff:     utils.GetEnvDuration("WEBHOOK_RETRY_BACKOFF", time.Second),
+	}
+}
+
+// Start runs the delivery workers until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < d.workers; i++ {
+		go d.worker(ctx)
+	}
+}
+
+// Dispatch queues an event for every active webhook subscribed to it.
+// It never blocks the caller; if the queue is full the delivery is dead-lettered.
+func (d *Dispatcher) Dispatch(eventType string, data interface{}) {
+	if d == nil {
+		return
+	}
+
+	webhooks, err := models.GetWebhooksForEvent(eventType)
+	if err != nil {
+		log.Printf("Failed to load webhooks for %s: %v", eventType, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	event := Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to marshal webhook event: %v", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		job := delivery{webhook: webhook, event: eventType, eventID: event.ID, body: body}
+		select {
+		case d.queue <- job:
+		default:
+			d.deadLetter(job, 0, 0, "delivery queue full")
+		}
+	}
+}
+
+// worker delivers queued events until ctx is cancelled
+func (d *Dispatcher) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-d.queue:
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+// deliver POSTs an event, retrying transient failures with exponential backoff
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	var lastErr string
+	var statusCode int
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		var retryable bool
+		statusCode, retryable, lastErr = d.post(ctx, job)
+		if lastErr == "" {
+			return
+		}
+		if !retryable || attempt == d.maxAttempts {
+			d.deadLetter(job, attempt, statusCode, lastErr)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			d.deadLetter(job, attempt, statusCode, "dispatcher stopped: "+lastErr)
+			return
+		case <-time.After(d.backoff << (attempt - 1)):
+		}
+	}
+}
+
+// post sends one delivery attempt. It returns the response status, whether a
+// failure is worth retrying, and an error description ("" on success).
+func (d *Dispatcher) post(ctx context.Context, job delivery) (int, bool, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.webhook.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, false, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", job.event)
+	req.Header.Set("X-Webhook-ID", job.eventID)
+	req.Header.Set(SignatureHeader, Sign(job.webhook.Secret, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, true, err.Error()
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, ""
+	}
+
+	// Client errors won't succeed on retry, except timeouts and rate limiting
+	retryable := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+
+	return resp.StatusCode, retryable, fmt.Sprintf("unexpected status %d", resp.StatusCode)
+}
+
+// deadLetter records a delivery that permanently failed
+func (d *Dispatcher) deadLetter(job delivery, attempts, statusCode int, lastErr string) {
+	log.Printf("Webhook %s delivery of %s failed after %d attempts: %s", job.webhook.ID, job.event, attempts, lastErr)
+
+	deadLetter := models.WebhookDeadLetter{
+		WebhookID:  job.webhook.ID,
+		EventType:  job.event,
+		Payload:    job.body,
+		Attempts:   attempts,
+		LastError:  lastErr,
+		StatusCode: statusCode,
+	}
+	if err := models.RecordWebhookDeadLetter(&deadLetter); err != nil {
+		log.Printf("Failed to record webhook dead letter: %v", err)
+	}
+}
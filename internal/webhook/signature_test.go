@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/connect-up/auth-service/models"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"type":"match.created"}`)
+	signature := Sign("secret", body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", "secret", body, signature, true},
+		{"wrong secret", "other", body, signature, false},
+		{"tampered body", "secret", []byte(`{"type":"match.deleted"}`), signature, false},
+		{"missing prefix", "secret", body, signature[len(signaturePrefix):], false},
+		{"empty", "secret", body, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("VerifySignature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeliverySignatureVerifiesAtReceiver(t *testing.T) {
+	const secret = "whsec"
+	verified := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified <- VerifySignature(secret, body, r.Header.Get(SignatureHeader))
+	}))
+	defer srv.Close()
+
+	d := &Dispatcher{client: srv.Client(), maxAttempts: 1, backoff: time.Millisecond}
+	d.deliver(context.Background(), delivery{
+		webhook: &models.Webhook{ID: "wh-1", URL: srv.URL, Secret: secret},
+		event:   models.WebhookEventCompanyCreated,
+		eventID: "evt-1",
+		body:    []byte(`{"id":"evt-1"}`),
+	})
+
+	select {
+	case ok := <-verified:
+		if !ok {
+			t.Error("receiver could not verify the delivery's signature")
+		}
+	default:
+		t.Fatal("webhook was not delivered")
+	}
+}
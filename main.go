@@ -3,16 +3,27 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/internal/contentfilter"
 	"github.com/connect-up/auth-service/internal/matchmaker"
+	"github.com/connect-up/auth-service/internal/push"
+	"github.com/connect-up/auth-service/internal/retention"
+	"github.com/connect-up/auth-service/internal/schema"
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/connect-up/auth-service/internal/webhook"
 	"github.com/connect-up/auth-service/models"
 	"github.com/connect-up/auth-service/routes"
 	"github.com/connect-up/auth-service/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -22,8 +33,27 @@ func main() {
 		log.Println("No .env file found, using system environment variables")
 	}
 
+	// Initialize tracing before anything that opens spans
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	// Initialize JWT
-	utils.InitJWT()
+	if err := utils.InitJWT(); err != nil {
+		log.Fatalf("Failed to initialize JWT: %v", err)
+	}
+
+	// Compile the request body schemas
+	if err := schema.Load(); err != nil {
+		log.Fatalf("Failed to load request schemas: %v", err)
+	}
+
+	// Initialize base currency and exchange rates
+	utils.InitCurrency()
+
+	// Cap the page size of every listing
+	utils.SetMaxPageSize(utils.LoadMaxPageSize())
 
 	// Initialize database
 	if err := models.InitDatabase(); err != nil {
@@ -43,6 +73,9 @@ func main() {
 	// Create Gin router
 	router := gin.Default()
 
+	// Trace every request
+	router.Use(utils.TracingMiddleware())
+
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
@@ -57,18 +90,25 @@ func main() {
 		c.Next()
 	})
 
+	// Limit request body sizes, with a larger cap for file uploads
+	maxBodyBytes := int64(utils.GetEnvInt("MAX_REQUEST_BODY_BYTES", 1<<20))
+	maxUploadBytes := int64(utils.GetEnvInt("MAX_UPLOAD_BODY_BYTES", 32<<20))
+	router.Use(utils.BodySizeLimitMiddleware(maxBodyBytes, map[string]int64{
+		"/api/v1/showcase/companies/import": maxUploadBytes,
+	}))
+
 	// Initialize Kafka
 	kafkaBrokers := strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ",")
 	kafkaUserTopic := getEnv("KAFKA_USER_UPDATED_TOPIC", "user-updated")
 	kafkaChatTopic := getEnv("KAFKA_CHAT_TOPIC", "chat-messages")
-	kafkaAnalyticsTopic := getEnv("KAFKA_ANALYTICS_TOPIC", "analytics_events")
 
-	// Create Kafka writer for analytics
+	// Create the Kafka writer for analytics and chat events. Each message names
+	// its own topic, so the writer must not set one.
 	kafkaWriter := &kafka.Writer{
 		Addr:     kafka.TCP(kafkaBrokers...),
-		Topic:    kafkaAnalyticsTopic,
 		Balancer: &kafka.LeastBytes{},
 	}
+	kafkaPublisher := utils.NewAsyncPublisher(kafkaWriter, utils.LoadAsyncPublisherConfig())
 
 	// Create Kafka reader for chat messages
 	kafkaReader := kafka.NewReader(kafka.ReaderConfig{
@@ -79,35 +119,84 @@ func main() {
 		MaxBytes: 10e6, // 10MB
 	})
 
+	// Start webhook delivery workers
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	defer stopWebhooks()
+	webhookDispatcher := webhook.NewDispatcher()
+	webhookDispatcher.Start(webhookCtx)
+
 	// Initialize matchmaker service
 	matchmakerService := matchmaker.NewService(kafkaBrokers, kafkaUserTopic)
+	matchmakerService.SetWebhookDispatcher(webhookDispatcher)
 	defer matchmakerService.Close()
 
+	// Index matches stored before per-user match indexes existed
+	if err := matchmakerService.IndexStoredMatches(context.Background()); err != nil {
+		log.Printf("Failed to index stored matches: %v", err)
+	}
+
+	// Give matches stored with a single status per-user statuses
+	if err := matchmakerService.MigrateMatchStatuses(context.Background()); err != nil {
+		log.Printf("Failed to migrate match statuses: %v", err)
+	}
+
 	// Start Kafka consumer in background
 	go func() {
 		ctx := context.Background()
 		matchmakerService.StartConsumer(ctx)
 	}()
+	go matchmakerService.StartLagMonitor(context.Background())
+	go matchmakerService.StartOutboxRelay(context.Background())
+
+	// Purge chat messages past their retention
+	go retention.NewPurger(retention.LoadConfig()).Start(context.Background())
 
 	// Initialize handlers
-	matchmakerHandler := handlers.NewMatchmakerHandler(matchmakerService)
-	showcaseHandler := handlers.NewShowcaseHandler(models.DB, kafkaWriter, utils.RedisClient)
-	websocketHandler := handlers.NewWebSocketHandler(kafkaWriter, kafkaReader, models.DB)
+	moderator := contentfilter.NewModeratorFromEnv()
+	matchmakerHandler := handlers.NewMatchmakerHandler(matchmakerService, moderator)
+	showcaseHandler := handlers.NewShowcaseHandler(models.DB, kafkaPublisher, utils.RedisClient, webhookDispatcher, moderator)
+	moderationHandler := handlers.NewModerationHandler(utils.RedisClient)
+	webhookHandler := handlers.NewWebhookHandler()
+	auditHandler := handlers.NewAuditHandler()
+	messageHandler := handlers.NewMessageHandler()
+	websocketHandler := handlers.NewWebSocketHandler(kafkaPublisher, kafkaReader, models.DB, matchmakerService, utils.RedisClient, moderator)
+	websocketHandler.SetPushDigester(push.NewDigesterFromEnv())
+	matchmakerService.SetAutoAcceptNotifier(websocketHandler.NotifyMatchAccepted)
+	showcaseHandler.SetUserNotifier(websocketHandler.NotifyUser)
+	matchmakerService.SetMatchFeedNotifier(websocketHandler.NotifyMatchChanged)
+	websocketHandler.SetMatchFeedReader(kafka.NewReader(kafka.ReaderConfig{
+		Brokers:  kafkaBrokers,
+		Topic:    matchmaker.MatchesCreatedTopic,
+		GroupID:  "auth-service-match-feed",
+		MinBytes: 10e3, // 10KB
+		MaxBytes: 10e6, // 10MB
+	}))
 
 	// Setup routes
-	routes.SetupAuthRoutes(router, models.DB)
+	routes.SetupAuthRoutes(router, models.DB, kafkaPublisher)
 	routes.SetupMatchmakerRoutes(router, matchmakerHandler)
 	routes.SetupShowcaseRoutes(router, showcaseHandler)
+	routes.SetupModerationRoutes(router, moderationHandler)
+	routes.SetupWebhookRoutes(router, webhookHandler)
+	routes.SetupAuditRoutes(router, auditHandler)
+	routes.SetupMessageRoutes(router, messageHandler)
 
 	// WebSocket routes
-	router.GET("/ws", utils.AuthMiddleware(), websocketHandler.HandleWebSocket)
+	// Reconnecting clients may present a reconnection token instead of a JWT
+	router.GET("/ws", utils.OptionalAuthMiddleware(), websocketHandler.HandleWebSocket)
 	router.GET("/api/v1/websocket/online-users", utils.AuthMiddleware(), websocketHandler.GetOnlineUsers)
 
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "auth-service",
+			"status":                  "ok",
+			"service":                 "auth-service",
+			"database_available":      models.DatabaseAvailable(),
+			"kafka_publisher":         kafkaPublisher.Metrics(),
+			"company_directory_cache": showcaseHandler.DirectoryCacheMetrics(),
 			"features": []string{
 				"authentication",
 				"matchmaking",
@@ -125,9 +214,46 @@ func main() {
 	log.Printf("Auth service starting on port %s", port)
 	log.Printf("Features enabled: Authentication, Matchmaking, Showcase, WebSocket Messaging, Kafka Integration, Redis Caching")
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
 	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for an interrupt signal, then drain WebSocket clients and shut down
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	websocketHandler.Shutdown(ctx)
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shut down: %v", err)
+	}
+
+	// Write any queued Kafka events before exiting
+	if err := kafkaPublisher.Close(ctx); err != nil {
+		log.Printf("Failed to flush Kafka events: %v", err)
+	}
+	if err := kafkaWriter.Close(); err != nil {
+		log.Printf("Failed to close Kafka writer: %v", err)
+	}
+
+	// Export any spans still buffered
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
+	}
+
+	log.Println("Server stopped")
 }
 
 // getEnv gets an environment variable or returns a default value
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Audited entity types
+const (
+	AuditEntityCompany    = "company"
+	AuditEntityInvestment = "investment"
+)
+
+// AuditEntry records a change to a company or investment
+type AuditEntry struct {
+	ID         string                 `json:"id"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Action     string                 `json:"action"` // company_updated, company_ownership_transferred, company_merged, company_merged_into, investment_created, investment_status_changed
+	ActorID    string                 `json:"actor_id"`
+	Changes    map[string]FieldChange `json:"changes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// FieldChange holds a field's value before and after a change
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// DiffFields returns the fields whose values differ between two snapshots.
+// A nil before snapshot records every field of after as newly set.
+func DiffFields(before, after map[string]interface{}) map[string]FieldChange {
+	changes := make(map[string]FieldChange)
+	for field, value := range after {
+		var old interface{}
+		if before != nil {
+			old = before[field]
+			if reflect.DeepEqual(old, value) {
+				continue
+			}
+		}
+		changes[field] = FieldChange{Before: old, After: value}
+	}
+	return changes
+}
+
+// RecordAudit writes an audit entry within the transaction making the change
+func RecordAudit(tx *sql.Tx, entry *AuditEntry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO audit_log (entity_type, entity_id, action, actor_id, changes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return tx.QueryRow(query, entry.EntityType, entry.EntityID, entry.Action, entry.ActorID, changes).
+		Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// GetAuditLog returns the audit entries for an entity, newest first
+func GetAuditLog(entityType, entityID string, limit, offset int) ([]*AuditEntry, error) {
+	query := `
+		SELECT id, entity_type, entity_id, action, actor_id, changes, created_at
+		FROM audit_log
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := queryRead(query, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		var actorID sql.NullString
+		var changes []byte
+		if err := rows.Scan(&entry.ID, &entry.EntityType, &entry.EntityID, &entry.Action,
+			&actorID, &changes, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.ActorID = actorID.String
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// companyAuditFields snapshots the audited fields of a company
+func companyAuditFields(company *Company) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           company.Name,
+		"description":    company.Description,
+		"industry":       company.Industry,
+		"founded_year":   company.FoundedYear,
+		"headquarters":   company.Headquarters,
+		"website":        company.Website,
+		"logo_url":       company.LogoURL,
+		"employee_count": company.EmployeeCount,
+		"revenue":        company.Revenue,
+		"funding_stage":  company.FundingStage,
+		"total_funding":  company.TotalFunding,
+		"valuation":      company.Valuation,
+		"is_public":      company.IsPublic,
+		"tags":           company.Tags,
+		"slug":           company.Slug,
+	}
+}
+
+// investmentAuditFields snapshots the audited fields of an investment
+func investmentAuditFields(investment *Investment) map[string]interface{} {
+	return map[string]interface{}{
+		"company_id":      investment.CompanyID,
+		"investor_id":     investment.InvestorID,
+		"amount":          investment.Amount,
+		"currency":        investment.Currency,
+		"investment_type": investment.InvestmentType,
+		"round":           investment.Round,
+		"date":            investment.Date.Format("2006-01-02"),
+		"status":          investment.Status,
+		"notes":           investment.Notes,
+		"anonymous":       investment.Anonymous,
+	}
+}
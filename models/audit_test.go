@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// companyRow returns a companies row for a company, as scanned by scanCompany
+func companyRow(company *Company) *sqlmock.Rows {
+	tags := "{}"
+	if len(company.Tags) > 0 {
+		value, _ := pqArrayValue(company.Tags)
+		tags = value
+	}
+	return sqlmock.NewRows(companyRowColumns).AddRow(
+		company.ID, company.Name, company.Description, company.Industry, company.FoundedYear,
+		company.Headquarters, company.Website, company.LogoURL, company.EmployeeCount, company.Revenue,
+		company.FundingStage, company.TotalFunding, company.Valuation, company.CreatedAt, company.UpdatedAt,
+		company.CreatedBy, company.IsPublic, tags, company.Slug,
+	)
+}
+
+// auditChanges matches an audit_log changes argument holding exactly want
+type auditChanges map[string]FieldChange
+
+func (want auditChanges) Match(value driver.Value) bool {
+	data, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+	var got map[string]FieldChange
+	if err := json.Unmarshal(data, &got); err != nil {
+		return false
+	}
+	// Round-trip want so numbers compare as JSON decodes them
+	encoded, _ := json.Marshal(map[string]FieldChange(want))
+	var expected map[string]FieldChange
+	json.Unmarshal(encoded, &expected)
+	return reflect.DeepEqual(got, expected)
+}
+
+func TestUpdateCompanyRecordsDiff(t *testing.T) {
+	mock := newTestDB(t)
+	before := &Company{
+		ID: "c1", Name: "Acme", Description: "Rockets", Industry: "aerospace",
+		Revenue: 100, CreatedBy: "owner-1", IsPublic: true, Slug: "acme",
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	after := *before
+	after.Description = "Reusable rockets"
+	after.Industry = "space"
+	if err := ValidateCompany(&after); err != nil {
+		t.Fatalf("ValidateCompany: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs("c1").
+		WillReturnRows(companyRow(before))
+	mock.ExpectExec(`UPDATE companies SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO audit_log`).
+		WithArgs(AuditEntityCompany, "c1", "company_updated", "editor-1", auditChanges{
+			"description": {Before: "Rockets", After: "Reusable rockets"},
+			"industry":    {Before: "aerospace", After: "space"},
+		}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("audit-1", time.Now()))
+	mock.ExpectCommit()
+
+	if err := UpdateCompany(&after, "editor-1"); err != nil {
+		t.Fatalf("UpdateCompany: %v", err)
+	}
+}
+
+func TestDiffFields(t *testing.T) {
+	before := map[string]interface{}{"name": "Acme", "tags": []string{"b2b"}, "revenue": 1.0}
+	after := map[string]interface{}{"name": "Acme", "tags": []string{"b2b", "ai"}, "revenue": 1.0}
+
+	want := map[string]FieldChange{"tags": {Before: []string{"b2b"}, After: []string{"b2b", "ai"}}}
+	if got := DiffFields(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFields = %v, want %v", got, want)
+	}
+
+	// A new entity records every field as newly set
+	if got := DiffFields(nil, after); len(got) != len(after) || got["name"].Before != nil {
+		t.Errorf("DiffFields(nil, after) = %v, want every field set from nil", got)
+	}
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// FinancialSnapshot is a company's revenue, funding and valuation as of a
+// point in time
+type FinancialSnapshot struct {
+	Revenue      float64   `json:"revenue"`
+	TotalFunding float64   `json:"total_funding"`
+	Valuation    float64   `json:"valuation"`
+	RecordedBy   string    `json:"recorded_by,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// financialsChanged reports whether an update changes any tracked financial field
+func financialsChanged(before, after *Company) bool {
+	return before.Revenue != after.Revenue ||
+		before.TotalFunding != after.TotalFunding ||
+		before.Valuation != after.Valuation
+}
+
+// recordFinancialSnapshot appends a company's current financials to its
+// history within tx
+func recordFinancialSnapshot(tx *sql.Tx, company *Company, actorID string) error {
+	query := `
+		INSERT INTO company_financials_history (company_id, revenue, total_funding, valuation, recorded_by)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.Exec(query, company.ID, company.Revenue, company.TotalFunding, company.Valuation,
+		sql.NullString{String: actorID, Valid: actorID != ""})
+	return err
+}
+
+// GetCompanyFinancialHistory retrieves a company's financial snapshots, oldest
+// first, optionally limited to those recorded in [from, to). Zero times
+// leave that end open.
+func GetCompanyFinancialHistory(companyID string, from, to time.Time) ([]*FinancialSnapshot, error) {
+	query := `
+		SELECT COALESCE(revenue, 0), COALESCE(total_funding, 0), COALESCE(valuation, 0),
+			COALESCE(recorded_by::text, ''), recorded_at
+		FROM company_financials_history
+		WHERE company_id = $1
+			AND ($2::timestamp IS NULL OR recorded_at >= $2)
+			AND ($3::timestamp IS NULL OR recorded_at < $3)
+		ORDER BY recorded_at, id
+	`
+
+	rows, err := queryRead(query, companyID, nullTime(from), nullTime(to))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []*FinancialSnapshot{}
+	for rows.Next() {
+		var snapshot FinancialSnapshot
+		if err := rows.Scan(&snapshot.Revenue, &snapshot.TotalFunding, &snapshot.Valuation,
+			&snapshot.RecordedBy, &snapshot.RecordedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, &snapshot)
+	}
+	return history, rows.Err()
+}
+
+// nullTime maps the zero time to NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
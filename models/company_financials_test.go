@@ -0,0 +1,56 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUpdateCompanyRecordsFinancialHistory(t *testing.T) {
+	mock := newTestDB(t)
+	before := &Company{
+		ID: "c1", Name: "Acme", Industry: "aerospace", Revenue: 100, TotalFunding: 50, Valuation: 1000,
+		CreatedBy: "owner-1", IsPublic: true, Slug: "acme", CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	after := *before
+	after.Valuation = 2500
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs("c1").
+		WillReturnRows(companyRow(before))
+	mock.ExpectExec(`UPDATE companies SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO company_financials_history`).
+		WithArgs("c1", 100.0, 50.0, 2500.0, "editor-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO audit_log`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("audit-1", time.Now()))
+	mock.ExpectCommit()
+
+	if err := UpdateCompany(&after, "editor-1"); err != nil {
+		t.Fatalf("UpdateCompany: %v", err)
+	}
+}
+
+func TestUpdateCompanySkipsHistoryWhenFinancialsUnchanged(t *testing.T) {
+	mock := newTestDB(t)
+	before := &Company{
+		ID: "c1", Name: "Acme", Description: "Rockets", Revenue: 100,
+		CreatedBy: "owner-1", IsPublic: true, Slug: "acme", CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	after := *before
+	after.Description = "Reusable rockets"
+
+	// sqlmock fails the update on any unexpected history insert
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs("c1").
+		WillReturnRows(companyRow(before))
+	mock.ExpectExec(`UPDATE companies SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`INSERT INTO audit_log`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("audit-1", time.Now()))
+	mock.ExpectCommit()
+
+	if err := UpdateCompany(&after, "editor-1"); err != nil {
+		t.Fatalf("UpdateCompany: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// CompanyFollower is one entry in a company's follower list. Anonymous
+// followers are counted but their identity is left out.
+type CompanyFollower struct {
+	UserID     string    `json:"user_id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Anonymous  bool      `json:"anonymous"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// FollowCompany records that a user follows a company. Following again only
+// updates the anonymous flag.
+func FollowCompany(companyID, userID string, anonymous bool) error {
+	query := `
+		INSERT INTO company_followers (company_id, user_id, anonymous)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (company_id, user_id) DO UPDATE SET anonymous = EXCLUDED.anonymous
+	`
+
+	_, err := DB.Exec(query, companyID, userID, anonymous)
+	return err
+}
+
+// UnfollowCompany removes a follow, reporting sql.ErrNoRows if the user was
+// not following the company
+func UnfollowCompany(companyID, userID string) error {
+	result, err := DB.Exec(`DELETE FROM company_followers WHERE company_id = $1 AND user_id = $2`, companyID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetCompanyFollowers retrieves a company's followers, newest first
+func GetCompanyFollowers(companyID string, limit, offset int) ([]*CompanyFollower, error) {
+	query := `
+		SELECT f.user_id, u.first_name, u.last_name, f.anonymous, f.created_at
+		FROM company_followers f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.company_id = $1
+		ORDER BY f.created_at DESC, f.user_id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := queryRead(query, companyID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	followers := []*CompanyFollower{}
+	for rows.Next() {
+		var follower CompanyFollower
+		var firstName, lastName string
+		if err := rows.Scan(&follower.UserID, &firstName, &lastName, &follower.Anonymous, &follower.FollowedAt); err != nil {
+			return nil, err
+		}
+		if follower.Anonymous {
+			follower.UserID = ""
+		} else {
+			follower.Name = strings.TrimSpace(firstName + " " + lastName)
+		}
+		followers = append(followers, &follower)
+	}
+
+	return followers, rows.Err()
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrMergeSameCompany is returned when a company is merged into itself
+var ErrMergeSameCompany = errors.New("cannot merge a company into itself")
+
+// MergeCompanies folds a duplicate company into the target within one
+// transaction: the source's investments, followers, data room grants,
+// financial history, investment interest, intro requests and activity move
+// to the target, its tags are added to the target's, and it
+// is soft-deleted. The target's other fields are kept. It returns the merged
+// target and the source as it was, reporting sql.ErrNoRows if either is missing.
+func MergeCompanies(targetID, sourceID, actorID string) (target, source *Company, err error) {
+	if targetID == sourceID {
+		return nil, nil, ErrMergeSameCompany
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock both rows in id order so concurrent merges of the same pair can't deadlock
+	locked := make(map[string]*Company, 2)
+	first, second := targetID, sourceID
+	if second < first {
+		first, second = second, first
+	}
+	for _, id := range []string{first, second} {
+		company, err := scanCompany(tx.QueryRow(`SELECT `+companyColumns+`
+			FROM companies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id))
+		if err != nil {
+			return nil, nil, err
+		}
+		locked[id] = company
+	}
+	target, source = locked[targetID], locked[sourceID]
+
+	if _, err := tx.Exec(`UPDATE investments SET company_id = $1, updated_at = CURRENT_TIMESTAMP WHERE company_id = $2`,
+		targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	// Users following both keep their existing follow of the target
+	if _, err := tx.Exec(`
+		INSERT INTO company_followers (company_id, user_id, anonymous, created_at)
+		SELECT $1, user_id, anonymous, created_at FROM company_followers WHERE company_id = $2
+		ON CONFLICT (company_id, user_id) DO NOTHING
+	`, targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM company_followers WHERE company_id = $1`, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	// Investors with data room access to the duplicate keep it on the target;
+	// those granted both keep the target's grant
+	if _, err := tx.Exec(`
+		INSERT INTO access_grants (company_id, investor_id, granted_by, created_at)
+		SELECT $1, investor_id, granted_by, created_at FROM access_grants WHERE company_id = $2
+		ON CONFLICT (company_id, investor_id) DO NOTHING
+	`, targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM access_grants WHERE company_id = $1`, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	// The duplicate's financial snapshots join the target's history, which
+	// is ordered by when each was recorded
+	if _, err := tx.Exec(`UPDATE company_financials_history SET company_id = $1 WHERE company_id = $2`,
+		targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	// Investors interested in both keep their interest in the target
+	if _, err := tx.Exec(`
+		UPDATE investment_interests SET company_id = $1
+		WHERE company_id = $2
+			AND investor_id NOT IN (SELECT investor_id FROM investment_interests WHERE company_id = $1)
+	`, targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	// Intro requests follow the company, so accepted ones still let the pair
+	// message; an investor with a request to both keeps the target's
+	if _, err := tx.Exec(`
+		UPDATE intro_requests SET company_id = $1
+		WHERE company_id = $2
+			AND investor_id NOT IN (SELECT investor_id FROM intro_requests WHERE company_id = $1)
+	`, targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE company_activities SET company_id = $1 WHERE company_id = $2`,
+		targetID, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	tags := unionTags(target.Tags, source.Tags)
+	if _, err := tx.Exec(`UPDATE companies SET tags = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		pq.Array(tags), targetID); err != nil {
+		return nil, nil, err
+	}
+	if _, err := tx.Exec(`UPDATE companies SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1`, sourceID); err != nil {
+		return nil, nil, err
+	}
+
+	changes := map[string]FieldChange{
+		"merged_from": {Before: nil, After: sourceID},
+	}
+	if len(tags) != len(target.Tags) {
+		changes["tags"] = FieldChange{Before: target.Tags, After: tags}
+	}
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityCompany,
+		EntityID:   targetID,
+		Action:     "company_merged",
+		ActorID:    actorID,
+		Changes:    changes,
+	}); err != nil {
+		return nil, nil, err
+	}
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityCompany,
+		EntityID:   sourceID,
+		Action:     "company_merged_into",
+		ActorID:    actorID,
+		Changes: map[string]FieldChange{
+			"merged_into": {Before: nil, After: targetID},
+		},
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	target.Tags = tags
+	return target, source, nil
+}
+
+// unionTags appends the tags of b missing from a, keeping a's order
+func unionTags(a, b []string) []string {
+	tags := append([]string{}, a...)
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, tag := range a {
+		seen[tag] = true
+	}
+	for _, tag := range b {
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
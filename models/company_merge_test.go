@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMergeCompaniesMovesInvestmentsAndRetiresSource(t *testing.T) {
+	mock := newTestDB(t)
+	target := &Company{ID: "c1", Name: "Acme", Tags: []string{"b2b", "ai"}, IsPublic: true, Slug: "acme"}
+	source := &Company{ID: "c2", Name: "ACME Inc", Tags: []string{"ai", "robotics"}, IsPublic: true, Slug: "acme-inc"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs("c1").
+		WillReturnRows(companyRow(target))
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs("c2").
+		WillReturnRows(companyRow(source))
+	mock.ExpectExec(`UPDATE investments SET company_id = \$1`).WithArgs("c1", "c2").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO company_followers`).WithArgs("c1", "c2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM company_followers WHERE company_id = \$1`).WithArgs("c2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO access_grants \(company_id, investor_id, granted_by, created_at\)\s+SELECT \$1, investor_id, granted_by, created_at FROM access_grants WHERE company_id = \$2\s+ON CONFLICT \(company_id, investor_id\) DO NOTHING`).
+		WithArgs("c1", "c2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM access_grants WHERE company_id = \$1`).WithArgs("c2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE company_financials_history SET company_id = \$1 WHERE company_id = \$2`).WithArgs("c1", "c2").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`UPDATE investment_interests SET company_id = \$1`).WithArgs("c1", "c2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE intro_requests SET company_id = \$1\s+WHERE company_id = \$2\s+AND investor_id NOT IN \(SELECT investor_id FROM intro_requests WHERE company_id = \$1\)`).
+		WithArgs("c1", "c2").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE company_activities SET company_id = \$1`).WithArgs("c1", "c2").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`UPDATE companies SET tags = \$1`).WithArgs(`{"b2b","ai","robotics"}`, "c1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE companies SET deleted_at = CURRENT_TIMESTAMP WHERE id = \$1`).WithArgs("c2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`INSERT INTO audit_log`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("audit", time.Now()))
+	}
+	mock.ExpectCommit()
+
+	merged, _, err := MergeCompanies("c1", "c2", "admin-1")
+	if err != nil {
+		t.Fatalf("MergeCompanies: %v", err)
+	}
+	if merged.Name != "Acme" || len(merged.Tags) != 3 {
+		t.Errorf("merged = %+v, want Acme's fields with the union of both tag sets", merged)
+	}
+
+	// The soft-deleted source no longer matches lookups
+	mock.ExpectQuery(`FROM companies WHERE id = \$1 AND deleted_at IS NULL`).WithArgs("c2").
+		WillReturnRows(sqlmock.NewRows(companyRowColumns))
+	if _, err := GetCompanyByID("c2"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetCompanyByID(source) = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestMergeCompanyIntoItself(t *testing.T) {
+	newTestDB(t)
+	if _, _, err := MergeCompanies("c1", "c1", "admin-1"); !errors.Is(err, ErrMergeSameCompany) {
+		t.Errorf("MergeCompanies into itself = %v, want ErrMergeSameCompany", err)
+	}
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxCompanySlugLength bounds the name-derived part of a slug, leaving room
+// for a collision suffix
+const maxCompanySlugLength = 80
+
+// What happens to a company's slug when it is renamed (COMPANY_SLUG_ON_RENAME)
+const (
+	CompanySlugPreserve   = "preserve"   // keep the slug so existing links keep working
+	CompanySlugRegenerate = "regenerate" // derive a new slug from the new name
+)
+
+// companySlugOnRename returns the configured rename policy
+func companySlugOnRename() string {
+	if strings.ToLower(getEnv("COMPANY_SLUG_ON_RENAME", CompanySlugPreserve)) == CompanySlugRegenerate {
+		return CompanySlugRegenerate
+	}
+	return CompanySlugPreserve
+}
+
+// Slugify turns a company name into a URL-friendly slug: lowercase ASCII
+// letters and digits separated by single hyphens, with accents stripped.
+// A name with nothing usable becomes "company".
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			hyphen = true
+		}
+		if b.Len() >= maxCompanySlugLength {
+			break
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > maxCompanySlugLength {
+		slug = strings.TrimRight(slug[:maxCompanySlugLength], "-")
+	}
+	if slug == "" {
+		return "company"
+	}
+	return slug
+}
+
+// pickCompanySlug returns base if it is free, otherwise base-2, base-3, and
+// so on, the first not in taken
+func pickCompanySlug(base string, taken map[string]bool) string {
+	if !taken[base] {
+		return base
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+// uniqueCompanySlug picks a free slug for a company name inside tx. An
+// advisory lock on the base slug serializes concurrent picks so they don't
+// race for the same suffix. excludeID lets a company keep its own slug.
+func uniqueCompanySlug(tx *sql.Tx, name, excludeID string) (string, error) {
+	base := Slugify(name)
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, "company_slug:"+base); err != nil {
+		return "", err
+	}
+
+	rows, err := tx.Query(`SELECT slug FROM companies WHERE (slug = $1 OR slug LIKE $2) AND id::text <> $3`,
+		base, escapeLikePattern(base)+"-%", excludeID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	return pickCompanySlug(base, taken), nil
+}
+
+// GetCompanyIDBySlug resolves a slug to the id of the company using it
+func GetCompanyIDBySlug(slug string) (string, error) {
+	var id string
+	err := queryRowRead(`SELECT id FROM companies WHERE slug = $1 AND deleted_at IS NULL`, []interface{}{slug}, &id)
+	return id, err
+}
+
+// backfillCompanySlugs gives every company created before slugs existed one
+func backfillCompanySlugs() error {
+	rows, err := DB.Query(`SELECT id, name FROM companies WHERE slug IS NULL ORDER BY created_at`)
+	if err != nil {
+		return err
+	}
+
+	type pending struct{ id, name string }
+	var companies []pending
+	for rows.Next() {
+		var company pending
+		if err := rows.Scan(&company.id, &company.name); err != nil {
+			rows.Close()
+			return err
+		}
+		companies = append(companies, company)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, company := range companies {
+		tx, err := DB.Begin()
+		if err != nil {
+			return err
+		}
+		slug, err := uniqueCompanySlug(tx, company.name, company.id)
+		if err == nil {
+			_, err = tx.Exec(`UPDATE companies SET slug = $1 WHERE id = $2`, slug, company.id)
+		}
+		if err == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Acme Rockets":        "acme-rockets",
+		"  Café   Olé, Inc. ": "cafe-ole-inc",
+		"AT&T":                "at-t",
+		"100% Juice!":         "100-juice",
+		"東京":                  "company",
+		"---":                 "company",
+		"Ünïcödé — Labs (EU)": "unicode-labs-eu",
+		"already-a-slug-2024": "already-a-slug-2024",
+	}
+	for name, want := range tests {
+		if got := Slugify(name); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestUniqueCompanySlugAddsCollisionSuffix(t *testing.T) {
+	mock := newTestDB(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).WithArgs("company_slug:acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT slug FROM companies WHERE \(slug = \$1 OR slug LIKE \$2\) AND id::text <> \$3`).
+		WithArgs("acme", "acme-%", "").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("acme").AddRow("acme-2").AddRow("acme-rockets"))
+	mock.ExpectRollback()
+
+	tx, err := DB.Begin()
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	slug, err := uniqueCompanySlug(tx, "ACME", "")
+	if err != nil {
+		t.Fatalf("uniqueCompanySlug: %v", err)
+	}
+	if slug != "acme-3" {
+		t.Errorf("slug = %q, want acme-3 with acme and acme-2 taken", slug)
+	}
+
+	if got := pickCompanySlug("acme", map[string]bool{"acme-2": true}); got != "acme" {
+		t.Errorf("pickCompanySlug with the base free = %q, want acme", got)
+	}
+}
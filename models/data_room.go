@@ -0,0 +1,157 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AccessGrant gives an investor access to a company's data room: the
+// financial fields other viewers see redacted
+type AccessGrant struct {
+	CompanyID  string    `json:"company_id"`
+	InvestorID string    `json:"investor_id"`
+	GrantedBy  string    `json:"granted_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RedactFinancials clears the fields only data room members may see
+func (c *Company) RedactFinancials() {
+	c.Revenue = 0
+	c.TotalFunding = 0
+	c.Valuation = 0
+	c.FinancialsRedacted = true
+}
+
+// GrantCompanyAccess gives an investor data room access and records the grant
+// in the audit log. Granting existing access changes nothing.
+func GrantCompanyAccess(companyID, investorID, actorID string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		INSERT INTO access_grants (company_id, investor_id, granted_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (company_id, investor_id) DO NOTHING
+	`, companyID, investorID, actorID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return nil // already granted
+	}
+
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityCompany,
+		EntityID:   companyID,
+		Action:     "data_room_access_granted",
+		ActorID:    actorID,
+		Changes: map[string]FieldChange{
+			"investor_id": {Before: nil, After: investorID},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeCompanyAccess removes an investor's data room access, reporting
+// sql.ErrNoRows if they had none
+func RevokeCompanyAccess(companyID, investorID, actorID string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM access_grants WHERE company_id = $1 AND investor_id = $2`, companyID, investorID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityCompany,
+		EntityID:   companyID,
+		Action:     "data_room_access_revoked",
+		ActorID:    actorID,
+		Changes: map[string]FieldChange{
+			"investor_id": {Before: investorID, After: nil},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCompanyAccessGrants lists who has access to a company's data room, oldest first
+func GetCompanyAccessGrants(companyID string) ([]*AccessGrant, error) {
+	rows, err := queryRead(`
+		SELECT company_id, investor_id, granted_by, created_at
+		FROM access_grants
+		WHERE company_id = $1
+		ORDER BY created_at, investor_id
+	`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []*AccessGrant{}
+	for rows.Next() {
+		var grant AccessGrant
+		var grantedBy sql.NullString
+		if err := rows.Scan(&grant.CompanyID, &grant.InvestorID, &grantedBy, &grant.CreatedAt); err != nil {
+			return nil, err
+		}
+		grant.GrantedBy = grantedBy.String
+		grants = append(grants, &grant)
+	}
+
+	return grants, rows.Err()
+}
+
+// GrantedCompanyIDs returns which of the given companies a user has data room access to
+func GrantedCompanyIDs(userID string, companyIDs []string) (map[string]bool, error) {
+	granted := make(map[string]bool)
+	if userID == "" || len(companyIDs) == 0 {
+		return granted, nil
+	}
+
+	rows, err := queryRead(`
+		SELECT company_id FROM access_grants
+		WHERE investor_id = $1 AND company_id = ANY($2)
+	`, userID, pq.Array(companyIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var companyID string
+		if err := rows.Scan(&companyID); err != nil {
+			return nil, err
+		}
+		granted[companyID] = true
+	}
+
+	return granted, rows.Err()
+}
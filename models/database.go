@@ -2,13 +2,17 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// pgUniqueViolation is the Postgres error code for a unique constraint violation
+const pgUniqueViolation = "23505"
+
 var DB *sql.DB
 
 // InitDatabase initializes the database connection
@@ -26,11 +30,17 @@ func InitDatabase() error {
 
 	// Open database connection
 	var err error
-	DB, err = sql.Open("postgres", connStr)
+	DB, err = sql.Open(tracedDriverName, connStr)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %v", err)
 	}
 
+	LoadPoolConfig().Apply(DB)
+	if err = InitMessageEncryption(getEnv("MESSAGE_ENCRYPTION_KEY", "")); err != nil {
+		return err
+	}
+	resilience = LoadResilienceConfig()
+
 	// Test the connection
 	if err = DB.Ping(); err != nil {
 		return fmt.Errorf("failed to ping database: %v", err)
@@ -54,10 +64,13 @@ func createUsersTable() error {
 		password VARCHAR(255) NOT NULL,
 		first_name VARCHAR(100) NOT NULL,
 		last_name VARCHAR(100) NOT NULL,
+		role VARCHAR(20) NOT NULL DEFAULT 'user',
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);
 	
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+
 	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 	`
 
@@ -71,4 +84,10 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+} 
+
+// IsUniqueViolation reports whether err is a Postgres unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Intro request statuses
+const (
+	IntroStatusPending  = "pending"
+	IntroStatusAccepted = "accepted"
+	IntroStatusDeclined = "declined"
+)
+
+// ErrIntroAlreadyAnswered is returned when responding to an intro request
+// that was already accepted or declined
+var ErrIntroAlreadyAnswered = errors.New("intro request was already answered")
+
+// IntroRequest is an investor's request for an introduction to a company's
+// owner. Once the owner accepts, the two may message each other even where
+// messaging is limited to accepted matches.
+type IntroRequest struct {
+	ID           string     `json:"id"`
+	CompanyID    string     `json:"company_id"`
+	CompanyName  string     `json:"company_name,omitempty"`
+	OwnerID      string     `json:"owner_id"` // the company's current owner
+	InvestorID   string     `json:"investor_id"`
+	InvestorName string     `json:"investor_name,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	Status       string     `json:"status"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// introRequestColumns selects an intro request with its company's name and
+// owner and the investor's first and last name, for scanIntroRequest
+const introRequestColumns = `
+	i.id, i.company_id, c.name, c.created_by, i.investor_id, u.first_name, u.last_name,
+	i.message, i.status, i.responded_at, i.created_at
+`
+
+// introRequestJoins joins an intro request to its company and investor
+const introRequestJoins = `
+	FROM intro_requests i
+	JOIN companies c ON c.id = i.company_id
+	JOIN users u ON u.id = i.investor_id
+`
+
+// CreateIntroRequest records an investor's request for an introduction to a
+// company's owner. An investor requests an introduction to a company once:
+// repeating it returns the original request, whatever its status, with
+// created false.
+func CreateIntroRequest(intro *IntroRequest) (created bool, err error) {
+	err = DB.QueryRow(`
+		INSERT INTO intro_requests (company_id, investor_id, message)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (company_id, investor_id) DO NOTHING
+		RETURNING id
+	`, intro.CompanyID, intro.InvestorID, intro.Message).Scan(&intro.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	created = err == nil
+
+	existing, err := getIntroRequest(`i.company_id = $1 AND i.investor_id = $2`, intro.CompanyID, intro.InvestorID)
+	if err != nil {
+		return false, err
+	}
+	*intro = *existing
+	return created, nil
+}
+
+// GetIntroRequest retrieves an intro request, reporting sql.ErrNoRows if it
+// doesn't exist or its company was deleted
+func GetIntroRequest(id string) (*IntroRequest, error) {
+	return getIntroRequest(`i.id = $1`, id)
+}
+
+// getIntroRequest retrieves the intro request matching condition
+func getIntroRequest(condition string, args ...interface{}) (*IntroRequest, error) {
+	query := `SELECT ` + introRequestColumns + introRequestJoins + `
+		WHERE ` + condition + ` AND c.deleted_at IS NULL`
+
+	return scanIntroRequest(DB.QueryRow(query, args...))
+}
+
+// RespondToIntroRequest accepts or declines a pending intro request,
+// reporting ErrIntroAlreadyAnswered if it was already answered
+func RespondToIntroRequest(id, status, actorID string) (*IntroRequest, error) {
+	result, err := DB.Exec(`
+		UPDATE intro_requests SET status = $1, responded_by = $2, responded_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = $4
+	`, status, actorID, id, IntroStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	intro, err := GetIntroRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return intro, ErrIntroAlreadyAnswered
+	}
+	return intro, nil
+}
+
+// GetSentIntroRequests lists the intro requests an investor has made, newest first
+func GetSentIntroRequests(investorID string, limit, offset int) ([]*IntroRequest, error) {
+	query := `SELECT ` + introRequestColumns + introRequestJoins + `
+		WHERE i.investor_id = $1 AND c.deleted_at IS NULL
+		ORDER BY i.created_at DESC, i.id
+		LIMIT $2 OFFSET $3`
+
+	return queryIntroRequests(query, investorID, limit, offset)
+}
+
+// GetReceivedIntroRequests lists the intro requests made to the companies a
+// user owns, newest first, optionally only those with status
+func GetReceivedIntroRequests(ownerID, status string, limit, offset int) ([]*IntroRequest, error) {
+	query := `SELECT ` + introRequestColumns + introRequestJoins + `
+		WHERE c.created_by = $1 AND c.deleted_at IS NULL AND ($2::text = '' OR i.status = $2)
+		ORDER BY i.created_at DESC, i.id
+		LIMIT $3 OFFSET $4`
+
+	return queryIntroRequests(query, ownerID, status, limit, offset)
+}
+
+// HasAcceptedIntro reports whether either user accepted an intro request
+// from the other, for a company the accepting user still owns
+func HasAcceptedIntro(userID, otherUserID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM intro_requests i
+			JOIN companies c ON c.id = i.company_id
+			WHERE i.status = $3 AND c.deleted_at IS NULL
+				AND ((i.investor_id = $1 AND c.created_by = $2) OR (i.investor_id = $2 AND c.created_by = $1))
+		)
+	`
+
+	var accepted bool
+	err := queryRowRead(query, []interface{}{userID, otherUserID, IntroStatusAccepted}, &accepted)
+	return accepted, err
+}
+
+// queryIntroRequests runs an intro request listing query selecting introRequestColumns
+func queryIntroRequests(query string, args ...interface{}) ([]*IntroRequest, error) {
+	rows, err := queryRead(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	intros := []*IntroRequest{}
+	for rows.Next() {
+		intro, err := scanIntroRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		intros = append(intros, intro)
+	}
+
+	return intros, rows.Err()
+}
+
+// scanIntroRequest scans a row selecting introRequestColumns
+func scanIntroRequest(row interface{ Scan(...interface{}) error }) (*IntroRequest, error) {
+	var intro IntroRequest
+	var firstName, lastName string
+	var message sql.NullString
+	var respondedAt sql.NullTime
+	if err := row.Scan(&intro.ID, &intro.CompanyID, &intro.CompanyName, &intro.OwnerID, &intro.InvestorID,
+		&firstName, &lastName, &message, &intro.Status, &respondedAt, &intro.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	intro.InvestorName = strings.TrimSpace(firstName + " " + lastName)
+	intro.Message = message.String
+	if respondedAt.Valid {
+		intro.RespondedAt = &respondedAt.Time
+	}
+	return &intro, nil
+}
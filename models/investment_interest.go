@@ -0,0 +1,124 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// InvestmentInterest is an investor's non-binding interest in a company,
+// a lighter step before recording an Investment
+type InvestmentInterest struct {
+	ID           string    `json:"id"`
+	CompanyID    string    `json:"company_id"`
+	CompanyName  string    `json:"company_name,omitempty"`
+	InvestorID   string    `json:"investor_id"`
+	InvestorName string    `json:"investor_name,omitempty"`
+	Amount       *float64  `json:"amount,omitempty"` // indicative amount, if the investor gave one
+	Currency     string    `json:"currency"`
+	Message      string    `json:"message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ExpressInvestmentInterest records an investor's interest in a company.
+// Interest is recorded once per investor and company: repeating it leaves
+// the original untouched and returns it with created false.
+func ExpressInvestmentInterest(interest *InvestmentInterest) (created bool, err error) {
+	if interest.Currency == "" {
+		interest.Currency = "USD"
+	}
+
+	query := `
+		INSERT INTO investment_interests (company_id, investor_id, amount, currency, message)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (company_id, investor_id) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err = DB.QueryRow(query, interest.CompanyID, interest.InvestorID, interest.Amount, interest.Currency, interest.Message).
+		Scan(&interest.ID, &interest.CreatedAt)
+	if err == nil {
+		return true, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	var amount sql.NullFloat64
+	var message sql.NullString
+	err = DB.QueryRow(`
+		SELECT id, amount, currency, message, created_at
+		FROM investment_interests
+		WHERE company_id = $1 AND investor_id = $2
+	`, interest.CompanyID, interest.InvestorID).
+		Scan(&interest.ID, &amount, &interest.Currency, &message, &interest.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	interest.Amount = nil
+	if amount.Valid {
+		interest.Amount = &amount.Float64
+	}
+	interest.Message = message.String
+	return false, nil
+}
+
+// GetSentInvestmentInterests lists the interest an investor has expressed, newest first
+func GetSentInvestmentInterests(investorID string, limit, offset int) ([]*InvestmentInterest, error) {
+	query := `
+		SELECT i.id, i.company_id, c.name, i.investor_id, '', '', i.amount, i.currency, i.message, i.created_at
+		FROM investment_interests i
+		JOIN companies c ON c.id = i.company_id
+		WHERE i.investor_id = $1 AND c.deleted_at IS NULL
+		ORDER BY i.created_at DESC, i.id
+		LIMIT $2 OFFSET $3
+	`
+
+	return queryInvestmentInterests(query, investorID, limit, offset)
+}
+
+// GetReceivedInvestmentInterests lists the interest expressed in the
+// companies a user owns, newest first
+func GetReceivedInvestmentInterests(ownerID string, limit, offset int) ([]*InvestmentInterest, error) {
+	query := `
+		SELECT i.id, i.company_id, c.name, i.investor_id, u.first_name, u.last_name, i.amount, i.currency, i.message, i.created_at
+		FROM investment_interests i
+		JOIN companies c ON c.id = i.company_id
+		JOIN users u ON u.id = i.investor_id
+		WHERE c.created_by = $1 AND c.deleted_at IS NULL
+		ORDER BY i.created_at DESC, i.id
+		LIMIT $2 OFFSET $3
+	`
+
+	return queryInvestmentInterests(query, ownerID, limit, offset)
+}
+
+// queryInvestmentInterests runs an interest listing query selecting the
+// interest, company name and investor first and last name
+func queryInvestmentInterests(query string, args ...interface{}) ([]*InvestmentInterest, error) {
+	rows, err := queryRead(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interests := []*InvestmentInterest{}
+	for rows.Next() {
+		var interest InvestmentInterest
+		var firstName, lastName string
+		var amount sql.NullFloat64
+		var message sql.NullString
+		if err := rows.Scan(&interest.ID, &interest.CompanyID, &interest.CompanyName, &interest.InvestorID,
+			&firstName, &lastName, &amount, &interest.Currency, &message, &interest.CreatedAt); err != nil {
+			return nil, err
+		}
+		if amount.Valid {
+			interest.Amount = &amount.Float64
+		}
+		interest.Message = message.String
+		interest.InvestorName = strings.TrimSpace(firstName + " " + lastName)
+		interests = append(interests, &interest)
+	}
+
+	return interests, rows.Err()
+}
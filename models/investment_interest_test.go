@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestExpressInvestmentInterestIsIdempotent(t *testing.T) {
+	mock := newTestDB(t)
+	createdAt := time.Now().Add(-time.Hour)
+	amount := 25000.0
+
+	mock.ExpectQuery(`INSERT INTO investment_interests`).
+		WithArgs("c1", "investor-1", &amount, "USD", "Keen to join the seed round").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("ii-1", createdAt))
+
+	first := &InvestmentInterest{CompanyID: "c1", InvestorID: "investor-1", Amount: &amount, Message: "Keen to join the seed round"}
+	created, err := ExpressInvestmentInterest(first)
+	if err != nil {
+		t.Fatalf("ExpressInvestmentInterest: %v", err)
+	}
+	if !created || first.ID != "ii-1" {
+		t.Fatalf("first interest created = %v, id = %q; want a new ii-1", created, first.ID)
+	}
+
+	// The unique (company_id, investor_id) conflict inserts nothing, and the
+	// original interest is returned as it was
+	mock.ExpectQuery(`INSERT INTO investment_interests`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+	mock.ExpectQuery(`FROM investment_interests\s+WHERE company_id = \$1 AND investor_id = \$2`).WithArgs("c1", "investor-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount", "currency", "message", "created_at"}).
+			AddRow("ii-1", amount, "USD", "Keen to join the seed round", createdAt))
+
+	repeat := &InvestmentInterest{CompanyID: "c1", InvestorID: "investor-1", Message: "Still keen"}
+	created, err = ExpressInvestmentInterest(repeat)
+	if err != nil {
+		t.Fatalf("repeated ExpressInvestmentInterest: %v", err)
+	}
+	if created || repeat.ID != "ii-1" || repeat.Message != "Keen to join the seed round" || repeat.Amount == nil || *repeat.Amount != amount {
+		t.Errorf("repeated interest = %+v (created %v), want the original ii-1 unchanged", repeat, created)
+	}
+}
+
+func TestOwnerSeesReceivedInvestmentInterest(t *testing.T) {
+	mock := newTestDB(t)
+	columns := []string{"id", "company_id", "name", "investor_id", "first_name", "last_name", "amount", "currency", "message", "created_at"}
+
+	mock.ExpectQuery(`WHERE c.created_by = \$1 AND c.deleted_at IS NULL`).WithArgs("owner-1", 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("ii-2", "c1", "Acme", "investor-2", "Grace", "Hopper", nil, "EUR", nil, time.Now()).
+			AddRow("ii-1", "c1", "Acme", "investor-1", "Ada", "Lovelace", 25000.0, "USD", "Keen", time.Now().Add(-time.Hour)))
+
+	interests, err := GetReceivedInvestmentInterests("owner-1", 20, 0)
+	if err != nil {
+		t.Fatalf("GetReceivedInvestmentInterests: %v", err)
+	}
+	if len(interests) != 2 {
+		t.Fatalf("got %d interests, want 2", len(interests))
+	}
+	if got := interests[0]; got.InvestorName != "Grace Hopper" || got.Amount != nil || got.CompanyName != "Acme" {
+		t.Errorf("first interest = %+v, want Grace's without an amount", got)
+	}
+	if got := interests[1]; got.InvestorName != "Ada Lovelace" || got.Amount == nil || *got.Amount != 25000 || got.Message != "Keen" {
+		t.Errorf("second interest = %+v, want Ada's 25000 with her message", got)
+	}
+}
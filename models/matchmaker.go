@@ -1,31 +1,269 @@
 package models
 
 import (
+	"errors"
 	"time"
+)
+
+// UserProfile represents a user's matchmaking profile
 type UserProfile struct {
-	UserID     string    `json:"user_id" db:"user_id"`
-	Tags       []string  `json:"tags" db:"tags"`
-	Industries []string  `json:"industries" db:"industries"`
-	Experience int       `json:"experience" db:"experience"` // years of experience
-	Interests  []string  `json:"interests" db:"interests"`
-	Location   string    `json:"location" db:"location"`
-	Bio        string    `json:"bio" db:"bio"`
-	Skills     []string  `json:"skills" db:"skills"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	UserID     string   `json:"user_id" db:"user_id"`
+	Tags       []string `json:"tags" db:"tags"`
+	Industries []string `json:"industries" db:"industries"`
+	Experience int      `json:"experience" db:"experience"` // years of experience
+	Interests  []string `json:"interests" db:"interests"`
+	Location   string   `json:"location" db:"location"`
+	Bio        string   `json:"bio" db:"bio"`
+	Skills     []string `json:"skills" db:"skills"`
+	Visibility string   `json:"visibility" db:"visibility"` // public, limited, private
+
+	// Name is the user's display name, synced from their account
+	Name string `json:"name,omitempty" db:"name"`
+
+	// WeightProfile names the matchmaker weight profile this user's matches are scored with
+	WeightProfile string `json:"weight_profile,omitempty" db:"weight_profile"`
+
+	// PreferenceWeights, when set, replaces the weight profile's weights for
+	// the matches computed for this user, keyed by attribute (tags,
+	// industries, experience, skills, interests, location). Stored
+	// normalized to sum to 1.
+	PreferenceWeights map[string]float64 `json:"preference_weights,omitempty" db:"preference_weights"`
+
+	// Seeking declares the relationship the user is looking for: peer, mentor, or mentee
+	Seeking string `json:"seeking,omitempty" db:"seeking"`
+
+	// Searchable is false when the user has opted out of appearing in other
+	// users' matches, search results and recommendations. Profiles stored
+	// without it are searchable; use IsSearchable to read it.
+	Searchable *bool `json:"searchable,omitempty" db:"searchable"`
+
+	// Incognito keeps this user out of the viewer lists of profiles they look at
+	Incognito bool `json:"incognito,omitempty" db:"incognito"`
+
+	// PausedUntil keeps the user out of new matches, in both directions, until
+	// it passes. It is only set through the pause endpoints.
+	PausedUntil *time.Time `json:"paused_until,omitempty" db:"paused_until"`
+
+	// Boost multiplies this user's score in other users' match lists; 0 means
+	// unboosted (1.0). It is only set through the admin boost endpoint.
+	Boost float64 `json:"boost,omitempty" db:"boost"`
+
+	// DisplayNames maps canonical tag/skill/industry/interest terms to the user's original spelling
+	DisplayNames map[string]string `json:"display_names,omitempty" db:"display_names"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// IsSearchable reports whether the user appears in other users' matches and
+// discovery, which they do unless they opted out
+func (p *UserProfile) IsSearchable() bool {
+	return p.Searchable == nil || *p.Searchable
+}
+
+// Paused reports whether the user has paused matchmaking at now
+func (p *UserProfile) Paused(now time.Time) bool {
+	return p.PausedUntil != nil && now.Before(*p.PausedUntil)
+}
+
+// Relationships a user can be seeking
+const (
+	SeekingPeer   = "peer"   // someone at a similar level
+	SeekingMentor = "mentor" // someone more experienced to learn from
+	SeekingMentee = "mentee" // someone less experienced to guide
+)
+
+// Profile visibility settings
+const (
+	ProfileVisibilityPublic  = "public"  // all fields visible to everyone
+	ProfileVisibilityLimited = "limited" // only tags visible to other users
+	ProfileVisibilityPrivate = "private" // profile withheld from other users
+)
+
+// profileField is a profile attribute counted towards completeness
+type profileField struct {
+	name   string
+	weight int
+	filled func(*UserProfile) bool
+}
+
+// profileCompletenessFields lists the scored profile fields, most impactful
+// first; the weights sum to 100 and mirror how much each field drives matching
+var profileCompletenessFields = []profileField{
+	{"tags", 20, func(p *UserProfile) bool { return len(p.Tags) > 0 }},
+	{"skills", 20, func(p *UserProfile) bool { return len(p.Skills) > 0 }},
+	{"industries", 15, func(p *UserProfile) bool { return len(p.Industries) > 0 }},
+	{"interests", 15, func(p *UserProfile) bool { return len(p.Interests) > 0 }},
+	{"experience", 10, func(p *UserProfile) bool { return p.Experience > 0 }},
+	{"location", 10, func(p *UserProfile) bool { return p.Location != "" }},
+	{"bio", 10, func(p *UserProfile) bool { return p.Bio != "" }},
+}
+
+// highImpactProfileFieldWeight is the weight at which a missing field is worth prompting for
+const highImpactProfileFieldWeight = 15
+
+// ProfileCompleteness scores how complete a profile is from 0 to 100,
+// weighting each populated field by its impact on matching
+func ProfileCompleteness(profile *UserProfile) int {
+	score := 0
+	for _, field := range profileCompletenessFields {
+		if field.filled(profile) {
+			score += field.weight
+		}
+	}
+	return score
+}
+
+// MissingProfileFields lists the unpopulated high-impact fields of a profile,
+// most impactful first
+func MissingProfileFields(profile *UserProfile) []string {
+	missing := []string{}
+	for _, field := range profileCompletenessFields {
+		if field.weight >= highImpactProfileFieldWeight && !field.filled(profile) {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
 }
 
 // Match represents a match between two users
 type Match struct {
-	ID           string    `json:"id" db:"id"`
-	UserID1      string    `json:"user_id_1" db:"user_id_1"`
-	UserID2      string    `json:"user_id_2" db:"user_id_2"`
-	Score        float64   `json:"score" db:"score"`
-	CommonTags   []string  `json:"common_tags" db:"common_tags"`
-	CommonSkills []string  `json:"common_skills" db:"common_skills"`
-	Status       string    `json:"status" db:"status"` // pending, accepted, rejected
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID              string    `json:"id" db:"id"`
+	UserID1         string    `json:"user_id_1" db:"user_id_1"`
+	UserID2         string    `json:"user_id_2" db:"user_id_2"`
+	Score           float64   `json:"score" db:"score"`                   // ranking score, RawScore times UserID2's boost
+	RawScore        float64   `json:"raw_score,omitempty" db:"raw_score"` // set when UserID2 is boosted
+	ScorePercent    int       `json:"score_percent" db:"-"`               // unboosted score as a whole percentage, for display
+	ScoreBand       string    `json:"score_band,omitempty" db:"-"`        // strong, good or weak
+	CommonTags      []string  `json:"common_tags" db:"common_tags"`
+	CommonSkills    []string  `json:"common_skills" db:"common_skills"`
+	CommonInterests []string  `json:"common_interests" db:"common_interests"`
+	Status          string    `json:"status" db:"status"` // derived: accepted when mutual, rejected if either side rejected, otherwise pending
+	User1Status     string    `json:"user1_status" db:"user1_status"`
+	User2Status     string    `json:"user2_status" db:"user2_status"`
+	Mutual          bool      `json:"mutual" db:"mutual"`
+	AutoAccepted    bool      `json:"auto_accepted,omitempty" db:"auto_accepted"` // accepted on both users' behalf for a very high score
+	Reason          string    `json:"reason,omitempty" db:"-"`                    // why the users match, in the caller's language; not stored
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	// StatusHistory holds the most recent per-user status changes, oldest first
+	StatusHistory []MatchStatusChange `json:"status_history,omitempty" db:"status_history"`
+}
+
+// MatchStatusChange records one participant changing their status on a match
+type MatchStatusChange struct {
+	UserID    string    `json:"user_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// maxMatchStatusHistory caps how many status changes a match keeps
+const maxMatchStatusHistory = 10
+
+// Match statuses
+const (
+	MatchStatusPending  = "pending"
+	MatchStatusAccepted = "accepted"
+	MatchStatusRejected = "rejected"
+)
+
+// Match status errors
+var (
+	ErrNotMatchParticipant = errors.New("user is not part of this match")
+	ErrNothingToUndo       = errors.New("no status change to undo")
+	ErrUndoWindowExpired   = errors.New("status change can no longer be undone")
+)
+
+// SetUserStatus records one participant's response to the match and
+// re-derives the overall status
+func (m *Match) SetUserStatus(userID, status string) error {
+	m.DeriveStatus()
+
+	var previous *string
+	switch userID {
+	case m.UserID1:
+		previous = &m.User1Status
+	case m.UserID2:
+		previous = &m.User2Status
+	default:
+		return ErrNotMatchParticipant
+	}
+
+	if *previous != status {
+		m.StatusHistory = append(m.StatusHistory, MatchStatusChange{
+			UserID:    userID,
+			From:      *previous,
+			To:        status,
+			ChangedAt: time.Now(),
+		})
+		if len(m.StatusHistory) > maxMatchStatusHistory {
+			m.StatusHistory = m.StatusHistory[len(m.StatusHistory)-maxMatchStatusHistory:]
+		}
+	}
+	*previous = status
+
+	m.DeriveStatus()
+	return nil
+}
+
+// UndoUserStatus reverts a participant's most recent status change, provided
+// it was made within window of now
+func (m *Match) UndoUserStatus(userID string, window time.Duration, now time.Time) error {
+	if userID != m.UserID1 && userID != m.UserID2 {
+		return ErrNotMatchParticipant
+	}
+
+	for i := len(m.StatusHistory) - 1; i >= 0; i-- {
+		change := m.StatusHistory[i]
+		if change.UserID != userID {
+			continue
+		}
+		if now.Sub(change.ChangedAt) > window {
+			return ErrUndoWindowExpired
+		}
+
+		if userID == m.UserID1 {
+			m.User1Status = change.From
+		} else {
+			m.User2Status = change.From
+		}
+		m.StatusHistory = append(m.StatusHistory[:i], m.StatusHistory[i+1:]...)
+		m.DeriveStatus()
+		return nil
+	}
+
+	return ErrNothingToUndo
+}
+
+// DeriveStatus computes Status and Mutual from the per-user statuses.
+// Matches stored before per-user statuses existed carry only Status; both
+// users inherit it so existing accepted matches stay connected.
+func (m *Match) DeriveStatus() {
+	if m.User1Status == "" && m.User2Status == "" {
+		legacy := m.Status
+		if legacy == "" {
+			legacy = MatchStatusPending
+		}
+		m.User1Status, m.User2Status = legacy, legacy
+	}
+	if m.User1Status == "" {
+		m.User1Status = MatchStatusPending
+	}
+	if m.User2Status == "" {
+		m.User2Status = MatchStatusPending
+	}
+
+	m.Mutual = m.User1Status == MatchStatusAccepted && m.User2Status == MatchStatusAccepted
+
+	switch {
+	case m.Mutual:
+		m.Status = MatchStatusAccepted
+	case m.User1Status == MatchStatusRejected || m.User2Status == MatchStatusRejected:
+		m.Status = MatchStatusRejected
+	default:
+		m.Status = MatchStatusPending
+	}
 }
 
 // MatchRequest represents the request to create a user profile
@@ -38,12 +276,23 @@ type MatchRequest struct {
 	Location   string   `json:"location"`
 	Bio        string   `json:"bio"`
 	Skills     []string `json:"skills"`
+	Visibility string   `json:"visibility" binding:"omitempty,oneof=public limited private"`
+	MaxMatches int      `json:"max_matches" binding:"omitempty,min=1,max=100"`
+	Seeking    string   `json:"seeking" binding:"omitempty,oneof=peer mentor mentee"`
+	Incognito  bool     `json:"incognito"`
+
+	WeightProfile string `json:"weight_profile"` // general (default), recruiting, cofounder, investor
+
+	// PreferenceWeights overrides the weight profile with the user's own
+	// relative weights, e.g. {"industries": 2, "location": 1}
+	PreferenceWeights map[string]float64 `json:"preference_weights"`
 }
 
 // MatchResponse represents the response for match endpoints
 type MatchResponse struct {
-	Matches []Match `json:"matches"`
-	Total   int     `json:"total"`
+	Matches    []Match `json:"matches"`
+	Total      int     `json:"total"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
 // UserUpdatedEvent represents the Kafka event for user updates
@@ -51,13 +300,22 @@ type UserUpdatedEvent struct {
 	UserID    string      `json:"user_id"`
 	Profile   UserProfile `json:"profile"`
 	Timestamp time.Time   `json:"timestamp"`
+
+	// AccountOnly marks an event published from the user's account, on
+	// registration or account edits. Its profile carries only the account
+	// fields, which update a stored profile rather than replace it.
+	AccountOnly bool `json:"account_only,omitempty"`
 }
 
 // MatchScore represents a match score calculation
 type MatchScore struct {
-	UserID string  `json:"user_id"`
-	Score  float64 `json:"score"`
-	Reason string  `json:"reason"`
+	UserID   string  `json:"user_id"`
+	Score    float64 `json:"score"`               // ranking score, RawScore times the candidate's boost
+	RawScore float64 `json:"raw_score,omitempty"` // set when the candidate is boosted
+	Reason   string  `json:"reason"`
+
+	ScorePercent int    `json:"score_percent"`        // unboosted score as a whole percentage, for display
+	ScoreBand    string `json:"score_band,omitempty"` // strong, good or weak
 }
 
 // MatchmakingCriteria represents the criteria for finding matches
@@ -71,4 +329,10 @@ type MatchmakingCriteria struct {
 	Location   string   `json:"location"`
 	Limit      int      `json:"limit"`
 	Offset     int      `json:"offset"`
+
+	// WeightProfile overrides the user's weight profile for this search
+	WeightProfile string `json:"weight_profile"`
+
+	// OnlyOnline limits results to users connected on any instance
+	OnlyOnline bool `json:"only_online"`
 }
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProfileCompleteness(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     UserProfile
+		wantScore   int
+		wantMissing []string
+	}{
+		{
+			name:        "empty",
+			profile:     UserProfile{UserID: "alice"},
+			wantScore:   0,
+			wantMissing: []string{"tags", "skills", "industries", "interests"},
+		},
+		{
+			name:        "partial",
+			profile:     UserProfile{UserID: "alice", Tags: []string{"fintech"}, Industries: []string{"finance"}, Location: "Berlin"},
+			wantScore:   45,
+			wantMissing: []string{"skills", "interests"},
+		},
+		{
+			name: "full",
+			profile: UserProfile{
+				UserID: "alice", Tags: []string{"fintech"}, Skills: []string{"go"}, Industries: []string{"finance"},
+				Interests: []string{"chess"}, Experience: 8, Location: "Berlin", Bio: "Payments engineer",
+			},
+			wantScore:   100,
+			wantMissing: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProfileCompleteness(&tt.profile); got != tt.wantScore {
+				t.Errorf("ProfileCompleteness = %d, want %d", got, tt.wantScore)
+			}
+			if got := MissingProfileFields(&tt.profile); !reflect.DeepEqual(got, tt.wantMissing) {
+				t.Errorf("MissingProfileFields = %v, want %v", got, tt.wantMissing)
+			}
+		})
+	}
+}
+
+func TestProfilesWithoutSearchableFlagAreSearchable(t *testing.T) {
+	tests := map[string]bool{
+		`{"user_id":"alice"}`:                    true,
+		`{"user_id":"alice","searchable":true}`:  true,
+		`{"user_id":"alice","searchable":false}`: false,
+	}
+	for stored, want := range tests {
+		var profile UserProfile
+		if err := json.Unmarshal([]byte(stored), &profile); err != nil {
+			t.Fatalf("decode %s: %v", stored, err)
+		}
+		if got := profile.IsSearchable(); got != want {
+			t.Errorf("%s: IsSearchable() = %v, want %v", stored, got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetMessageByID retrieves a message by ID; soft-deleted messages are not returned
+func GetMessageByID(id string) (*Message, error) {
+	query := `
+		SELECT id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, COALESCE(seq, 0)
+		FROM messages
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	var message Message
+	var nonce []byte
+	err := queryRowRead(query, []interface{}{id}, &message.ID, &message.SenderID, &message.ReceiverID, &message.Content,
+		&nonce, &message.MessageType, &message.IsRead, &message.CreatedAt, &message.UpdatedAt, &message.Seq)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.Content, err = openMessageContent(message.Content, nonce); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// MessageSearchFilter narrows a message search
+type MessageSearchFilter struct {
+	PeerID string // only messages exchanged with this user
+	Limit  int
+	Offset int
+}
+
+// MessageSearchResult is a message matching a search, with its relevance
+type MessageSearchResult struct {
+	Message
+	Rank float64 `json:"rank"`
+}
+
+// SearchMessages full-text searches the messages a user sent or received,
+// most relevant first. Soft-deleted messages are excluded, as are encrypted
+// ones since their content can't be indexed.
+func SearchMessages(userID, query string, filter MessageSearchFilter) ([]*MessageSearchResult, error) {
+	// The to_tsvector expression must match idx_messages_content_search for the index to be used
+	conditions := []string{
+		"(sender_id = $1 OR receiver_id = $1)",
+		"deleted_at IS NULL",
+		"content_nonce IS NULL",
+		"to_tsvector('english', content) @@ websearch_to_tsquery('english', $2)",
+	}
+	args := []interface{}{userID, query}
+
+	if filter.PeerID != "" {
+		args = append(args, filter.PeerID)
+		conditions = append(conditions, fmt.Sprintf("(sender_id = $%d OR receiver_id = $%d)", len(args), len(args)))
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	sqlQuery := fmt.Sprintf(`
+		SELECT id, sender_id, receiver_id, content, message_type, is_read, created_at, updated_at, COALESCE(seq, 0),
+		       ts_rank(to_tsvector('english', content), websearch_to_tsquery('english', $2)) AS rank
+		FROM messages
+		WHERE %s
+		ORDER BY rank DESC, created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := queryRead(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*MessageSearchResult{}
+	for rows.Next() {
+		var result MessageSearchResult
+		if err := rows.Scan(&result.ID, &result.SenderID, &result.ReceiverID, &result.Content,
+			&result.MessageType, &result.IsRead, &result.CreatedAt, &result.UpdatedAt, &result.Seq, &result.Rank); err != nil {
+			return nil, err
+		}
+		results = append(results, &result)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrMessageKeyMissing is returned when reading an encrypted message without a key configured
+var ErrMessageKeyMissing = errors.New("message is encrypted but MESSAGE_ENCRYPTION_KEY is not set")
+
+// messageAEAD encrypts message content at rest; nil leaves new messages in plaintext
+var messageAEAD cipher.AEAD
+
+// InitMessageEncryption enables AES-GCM encryption of message content with a
+// base64-encoded 16, 24, or 32 byte key. An empty key disables encryption of
+// new messages; existing plaintext rows read the same either way.
+func InitMessageEncryption(encodedKey string) error {
+	if encodedKey == "" {
+		messageAEAD = nil
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return fmt.Errorf("invalid message encryption key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("invalid message encryption key: %v", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	messageAEAD = aead
+	return nil
+}
+
+// SealMessageContent prepares message content for storage. With encryption
+// enabled it returns the base64 ciphertext and the random nonce to store in
+// content_nonce; otherwise the content unchanged and a nil nonce.
+func SealMessageContent(content string) (string, []byte, error) {
+	if messageAEAD == nil {
+		return content, nil, nil
+	}
+
+	nonce := make([]byte, messageAEAD.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+
+	sealed := messageAEAD.Seal(nil, nonce, []byte(content), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nonce, nil
+}
+
+// openMessageContent reverses SealMessageContent; rows without a nonce are plaintext
+func openMessageContent(stored string, nonce []byte) (string, error) {
+	if nonce == nil {
+		return stored, nil
+	}
+	if messageAEAD == nil {
+		return "", ErrMessageKeyMissing
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode message content: %v", err)
+	}
+
+	content, err := messageAEAD.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt message content: %v", err)
+	}
+	return string(content), nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useMessageKey enables message encryption with key for the duration of the test
+func useMessageKey(t *testing.T, key string) {
+	t.Helper()
+	previous := messageAEAD
+	t.Cleanup(func() { messageAEAD = previous })
+	if err := InitMessageEncryption(key); err != nil {
+		t.Fatalf("InitMessageEncryption: %v", err)
+	}
+}
+
+func TestMessageContentRoundTrip(t *testing.T) {
+	useMessageKey(t, base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+
+	const content = "Let's talk term sheets on Friday"
+	sealed, nonce, err := SealMessageContent(content)
+	if err != nil {
+		t.Fatalf("SealMessageContent: %v", err)
+	}
+	if nonce == nil || sealed == content || strings.Contains(sealed, "term sheets") {
+		t.Fatalf("content was stored readable: %q (nonce %v)", sealed, nonce)
+	}
+
+	// Every message gets its own nonce
+	again, otherNonce, _ := SealMessageContent(content)
+	if again == sealed || string(otherNonce) == string(nonce) {
+		t.Error("sealing the same content twice gave the same ciphertext")
+	}
+
+	opened, err := openMessageContent(sealed, nonce)
+	if err != nil {
+		t.Fatalf("openMessageContent: %v", err)
+	}
+	if opened != content {
+		t.Errorf("opened = %q, want %q", opened, content)
+	}
+
+	// Rows written before encryption was enabled still read
+	if opened, err := openMessageContent("plain old message", nil); err != nil || opened != "plain old message" {
+		t.Errorf("plaintext row = %q, %v", opened, err)
+	}
+}
+
+func TestMessageEncryptionDisabled(t *testing.T) {
+	useMessageKey(t, "")
+
+	sealed, nonce, err := SealMessageContent("hello")
+	if err != nil || sealed != "hello" || nonce != nil {
+		t.Fatalf("SealMessageContent = %q, %v, %v; want the content unchanged with no nonce", sealed, nonce, err)
+	}
+
+	// An encrypted row can't be read back without the key
+	if _, err := openMessageContent("c2VhbGVk", []byte("nonce")); !errors.Is(err, ErrMessageKeyMissing) {
+		t.Errorf("openMessageContent without a key = %v, want ErrMessageKeyMissing", err)
+	}
+
+	if err := InitMessageEncryption("not base64!"); err == nil {
+		t.Error("InitMessageEncryption accepted a malformed key")
+	}
+}
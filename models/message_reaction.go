@@ -0,0 +1,152 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+// maxReactionRunes bounds a reaction; emoji ZWJ sequences (families, flags
+// with modifiers) run to about ten code points
+const maxReactionRunes = 16
+
+var (
+	ErrNotConversationParticipant = errors.New("user is not a participant in this conversation")
+	ErrInvalidReaction            = errors.New("reaction must be a single emoji")
+)
+
+// ReactionSummary aggregates one emoji's reactions on a message
+type ReactionSummary struct {
+	Emoji   string   `json:"emoji"`
+	Count   int      `json:"count"`
+	UserIDs []string `json:"user_ids"`
+}
+
+// ValidReaction reports whether emoji is acceptable as a reaction: non-empty,
+// short, and free of letters, digits, whitespace and control characters
+func ValidReaction(emoji string) bool {
+	if emoji == "" || !utf8.ValidString(emoji) || utf8.RuneCountInString(emoji) > maxReactionRunes {
+		return false
+	}
+	for _, r := range emoji {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddMessageReaction records a user's reaction to a message and returns the
+// other participant. Reacting twice with the same emoji is a no-op.
+func AddMessageReaction(messageID, userID, emoji string) (string, error) {
+	if !ValidReaction(emoji) {
+		return "", ErrInvalidReaction
+	}
+
+	peerID, err := conversationPeer(messageID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	query := `
+		INSERT INTO message_reactions (message_id, user_id, emoji)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (message_id, user_id, emoji) DO NOTHING
+	`
+	if _, err := DB.Exec(query, messageID, userID, emoji); err != nil {
+		return "", err
+	}
+	return peerID, nil
+}
+
+// RemoveMessageReaction removes a user's reaction and returns the other
+// participant, reporting sql.ErrNoRows if the user hadn't reacted that way
+func RemoveMessageReaction(messageID, userID, emoji string) (string, error) {
+	peerID, err := conversationPeer(messageID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := DB.Exec(`DELETE FROM message_reactions WHERE message_id = $1 AND user_id = $2 AND emoji = $3`,
+		messageID, userID, emoji)
+	if err != nil {
+		return "", err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if affected == 0 {
+		return "", sql.ErrNoRows
+	}
+	return peerID, nil
+}
+
+// conversationPeer returns the other participant of a message's
+// conversation, or ErrNotConversationParticipant if userID isn't in it. It
+// reads the primary so a message that was just sent can be reacted to.
+func conversationPeer(messageID, userID string) (string, error) {
+	var senderID, receiverID string
+	err := DB.QueryRow(`SELECT sender_id, receiver_id FROM messages WHERE id = $1 AND deleted_at IS NULL`, messageID).
+		Scan(&senderID, &receiverID)
+	if err != nil {
+		return "", err
+	}
+
+	switch userID {
+	case senderID:
+		return receiverID, nil
+	case receiverID:
+		return senderID, nil
+	}
+	return "", ErrNotConversationParticipant
+}
+
+// AttachReactionSummaries fills in the reactions on each message, with
+// emojis ordered by when they were first used
+func AttachReactionSummaries(messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	query := `
+		SELECT message_id, emoji, COUNT(*), array_agg(user_id::text ORDER BY created_at, user_id)
+		FROM message_reactions
+		WHERE message_id = ANY($1)
+		GROUP BY message_id, emoji
+		ORDER BY message_id, MIN(created_at), emoji
+	`
+
+	rows, err := queryRead(query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	summaries := make(map[string][]ReactionSummary)
+	for rows.Next() {
+		var messageID string
+		var summary ReactionSummary
+		if err := rows.Scan(&messageID, &summary.Emoji, &summary.Count, pq.Array(&summary.UserIDs)); err != nil {
+			return err
+		}
+		summaries[messageID] = append(summaries[messageID], summary)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		message.Reactions = summaries[message.ID]
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectConversation expects a message's participants to be looked up
+func expectConversation(mock sqlmock.Sqlmock, messageID, senderID, receiverID string) {
+	mock.ExpectQuery(`SELECT sender_id, receiver_id FROM messages WHERE id = \$1 AND deleted_at IS NULL`).
+		WithArgs(messageID).
+		WillReturnRows(sqlmock.NewRows([]string{"sender_id", "receiver_id"}).AddRow(senderID, receiverID))
+}
+
+func TestAddAndRemoveMessageReaction(t *testing.T) {
+	mock := newTestDB(t)
+
+	// The receiver reacts and the sender is the one to notify
+	expectConversation(mock, "msg-1", "alice", "bob")
+	mock.ExpectExec(`INSERT INTO message_reactions \(message_id, user_id, emoji\)`).
+		WithArgs("msg-1", "bob", "🎉").WillReturnResult(sqlmock.NewResult(0, 1))
+	peer, err := AddMessageReaction("msg-1", "bob", "🎉")
+	if err != nil || peer != "alice" {
+		t.Fatalf("AddMessageReaction = %q, %v; want alice", peer, err)
+	}
+
+	expectConversation(mock, "msg-1", "alice", "bob")
+	mock.ExpectExec(`DELETE FROM message_reactions WHERE message_id = \$1 AND user_id = \$2 AND emoji = \$3`).
+		WithArgs("msg-1", "bob", "🎉").WillReturnResult(sqlmock.NewResult(0, 1))
+	peer, err = RemoveMessageReaction("msg-1", "bob", "🎉")
+	if err != nil || peer != "alice" {
+		t.Fatalf("RemoveMessageReaction = %q, %v; want alice", peer, err)
+	}
+
+	// Removing it again finds nothing
+	expectConversation(mock, "msg-1", "alice", "bob")
+	mock.ExpectExec(`DELETE FROM message_reactions`).
+		WithArgs("msg-1", "bob", "🎉").WillReturnResult(sqlmock.NewResult(0, 0))
+	if _, err := RemoveMessageReaction("msg-1", "bob", "🎉"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("removing a missing reaction = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestMessageReactionRequiresParticipant(t *testing.T) {
+	mock := newTestDB(t)
+
+	expectConversation(mock, "msg-1", "alice", "bob")
+	if _, err := AddMessageReaction("msg-1", "mallory", "👍"); !errors.Is(err, ErrNotConversationParticipant) {
+		t.Errorf("outsider reaction = %v, want ErrNotConversationParticipant", err)
+	}
+
+	// Text isn't a reaction, and is rejected before the database is touched
+	for _, emoji := range []string{"", "lol", "👍 ", "1"} {
+		if _, err := AddMessageReaction("msg-1", "bob", emoji); !errors.Is(err, ErrInvalidReaction) {
+			t.Errorf("AddMessageReaction(%q) = %v, want ErrInvalidReaction", emoji, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Message retention modes
+const (
+	RetentionModePurge   = "purge"   // expired messages are deleted
+	RetentionModeArchive = "archive" // expired messages are moved to messages_archive
+)
+
+// ConversationRetention overrides the retention policy for the messages
+// between two users. RetentionDays of 0 keeps them forever.
+type ConversationRetention struct {
+	UserID        string    `json:"user_id"`
+	PeerID        string    `json:"peer_id"`
+	RetentionDays int       `json:"retention_days"`
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SetConversationRetention sets the retention override for the conversation
+// between two users, whichever of them asks
+func SetConversationRetention(retention *ConversationRetention) error {
+	query := `
+		INSERT INTO conversation_retention (user_low, user_high, retention_days, updated_by)
+		VALUES (LEAST($1::uuid, $2::uuid), GREATEST($1::uuid, $2::uuid), $3, $4)
+		ON CONFLICT (user_low, user_high) DO UPDATE
+		SET retention_days = EXCLUDED.retention_days, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return DB.QueryRow(query, retention.UserID, retention.PeerID, retention.RetentionDays, retention.UpdatedBy).
+		Scan(&retention.UpdatedAt)
+}
+
+// ClearConversationRetention removes a conversation's retention override so
+// the default policy applies again. It returns sql.ErrNoRows if there was none.
+func ClearConversationRetention(userID, peerID string) error {
+	result, err := DB.Exec(`
+		DELETE FROM conversation_retention
+		WHERE user_low = LEAST($1::uuid, $2::uuid) AND user_high = GREATEST($1::uuid, $2::uuid)
+	`, userID, peerID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// expiredMessagesQuery selects up to $3 messages past their retention at $1:
+// the conversation's override if it has one, otherwise $2 days (0 keeping
+// them forever). Messages with a report still awaiting action are kept as
+// evidence. Rows locked by a concurrent purge are skipped.
+const expiredMessagesQuery = `
+	SELECT m.id
+	FROM messages m
+	LEFT JOIN conversation_retention r
+		ON r.user_low = LEAST(m.sender_id, m.receiver_id) AND r.user_high = GREATEST(m.sender_id, m.receiver_id)
+	WHERE CASE
+			WHEN r.retention_days IS NOT NULL
+				THEN r.retention_days > 0 AND m.created_at < $1::timestamp - r.retention_days * INTERVAL '1 day'
+			ELSE $2::int > 0 AND m.created_at < $1::timestamp - $2::int * INTERVAL '1 day'
+		END
+		AND NOT EXISTS (
+			SELECT 1 FROM reports rp
+			WHERE rp.target_type = 'message' AND rp.target_id = m.id AND rp.status <> 'actioned'
+		)
+	ORDER BY m.created_at
+	LIMIT $3
+	FOR UPDATE OF m SKIP LOCKED
+`
+
+// PurgeExpiredMessages removes one batch of at most limit messages past
+// their retention as of now, deleting them or moving them to the archive
+// depending on mode, and returns how many were removed. Their reactions are
+// deleted with them.
+func PurgeExpiredMessages(now time.Time, defaultDays int, mode string, limit int) (int64, error) {
+	query := `
+		WITH expired AS (` + expiredMessagesQuery + `)
+		DELETE FROM messages WHERE id IN (SELECT id FROM expired)
+	`
+	if mode == RetentionModeArchive {
+		query = `
+			WITH expired AS (` + expiredMessagesQuery + `),
+			removed AS (
+				DELETE FROM messages WHERE id IN (SELECT id FROM expired)
+				RETURNING id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq
+			)
+			INSERT INTO messages_archive (id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq)
+			SELECT id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq
+			FROM removed
+		`
+	}
+
+	result, err := DB.Exec(query, now.UTC(), defaultDays, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPurgeExpiredMessagesQuery(t *testing.T) {
+	// Shared by both modes: the conversation's override decides the cutoff
+	// when it has one, messages with an open report are kept, and rows
+	// another purge holds are skipped
+	const expired = `(?s)WITH expired AS \(\s*SELECT m\.id\s+FROM messages m\s+` +
+		`LEFT JOIN conversation_retention r\s+ON r\.user_low = LEAST\(m\.sender_id, m\.receiver_id\) AND r\.user_high = GREATEST\(m\.sender_id, m\.receiver_id\)\s+` +
+		`WHERE CASE\s+WHEN r\.retention_days IS NOT NULL\s+` +
+		`THEN r\.retention_days > 0 AND m\.created_at < \$1::timestamp - r\.retention_days \* INTERVAL '1 day'\s+` +
+		`ELSE \$2::int > 0 AND m\.created_at < \$1::timestamp - \$2::int \* INTERVAL '1 day'\s+END\s+` +
+		`AND NOT EXISTS \(\s*SELECT 1 FROM reports rp\s+WHERE rp\.target_type = 'message' AND rp\.target_id = m\.id AND rp\.status <> 'actioned'\s*\)\s+` +
+		`ORDER BY m\.created_at\s+LIMIT \$3\s+FOR UPDATE OF m SKIP LOCKED\s*\)`
+
+	tests := []struct {
+		mode  string
+		query string
+	}{
+		{RetentionModePurge, `^\s*` + expired + `\s+DELETE FROM messages WHERE id IN \(SELECT id FROM expired\)\s*$`},
+		{RetentionModeArchive, `^\s*` + expired + `,\s+removed AS \(\s*DELETE FROM messages WHERE id IN \(SELECT id FROM expired\)\s+` +
+			`RETURNING id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq\s*\)\s+` +
+			`INSERT INTO messages_archive \(id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq\)\s+` +
+			`SELECT id, sender_id, receiver_id, content, content_nonce, message_type, is_read, created_at, updated_at, deleted_at, seq\s+FROM removed\s*$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			mock := newTestDB(t)
+
+			// The cutoff is computed in UTC whatever the caller's zone
+			now := time.Date(2024, 6, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+			mock.ExpectExec(tt.query).WithArgs(now.UTC(), 30, 500).WillReturnResult(sqlmock.NewResult(0, 7))
+
+			removed, err := PurgeExpiredMessages(now, 30, tt.mode, 500)
+			if err != nil {
+				t.Fatalf("PurgeExpiredMessages: %v", err)
+			}
+			if removed != 7 {
+				t.Errorf("removed %d messages, want 7", removed)
+			}
+		})
+	}
+}
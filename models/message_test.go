@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSearchMessagesMultiWordQuery(t *testing.T) {
+	mock := newTestDB(t)
+	now := time.Now()
+
+	// The whole query goes to websearch_to_tsquery as one argument, so every
+	// word must match; the peer filter and paging follow it
+	mock.ExpectQuery(`to_tsvector\('english', content\) @@ websearch_to_tsquery\('english', \$2\) AND \(sender_id = \$3 OR receiver_id = \$3\)`).
+		WithArgs("alice", "quarterly revenue report", "bob", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id", "content", "message_type", "is_read", "created_at", "updated_at", "seq", "rank"}).
+			AddRow("msg-1", "bob", "alice", "The quarterly revenue report is attached", "text", false, now, now, 4, 0.42).
+			AddRow("msg-2", "alice", "bob", "Revenue looked fine in the quarterly report", "text", true, now, now, 2, 0.17))
+
+	results, err := SearchMessages("alice", "quarterly revenue report", MessageSearchFilter{PeerID: "bob", Limit: 20})
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].ID != "msg-1" || results[0].Rank != 0.42 || results[0].Seq != 4 {
+		t.Errorf("first result = %+v, want msg-1 ranked 0.42", results[0])
+	}
+}
+
+func TestSearchMessagesExcludesDeletedAndEncrypted(t *testing.T) {
+	mock := newTestDB(t)
+
+	mock.ExpectQuery(`deleted_at IS NULL AND content_nonce IS NULL`).
+		WithArgs("alice", "launch plan", 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id", "content", "message_type", "is_read", "created_at", "updated_at", "seq", "rank"}))
+
+	results, err := SearchMessages("alice", "launch plan", MessageSearchFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchMessages: %v", err)
+	}
+	if results == nil || len(results) != 0 {
+		t.Errorf("results = %v, want an empty list", results)
+	}
+}
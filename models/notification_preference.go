@@ -0,0 +1,46 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// NotificationPreferences controls the push notifications a user receives
+// for chat messages that arrive while they are offline
+type NotificationPreferences struct {
+	UserID       string    `json:"user_id"`
+	PushEnabled  bool      `json:"push_enabled"`
+	ShowPreviews bool      `json:"show_previews"` // include the start of the message in the notification
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+// GetNotificationPreferences retrieves a user's notification preferences.
+// Users who never changed them get push with previews.
+func GetNotificationPreferences(userID string) (*NotificationPreferences, error) {
+	prefs := NotificationPreferences{UserID: userID, PushEnabled: true, ShowPreviews: true}
+
+	query := `
+		SELECT push_enabled, show_previews, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	err := queryRowRead(query, []interface{}{userID}, &prefs.PushEnabled, &prefs.ShowPreviews, &prefs.UpdatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// SetNotificationPreferences stores a user's notification preferences
+func SetNotificationPreferences(prefs *NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, push_enabled, show_previews)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET push_enabled = EXCLUDED.push_enabled, show_previews = EXCLUDED.show_previews, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	return DB.QueryRow(query, prefs.UserID, prefs.PushEnabled, prefs.ShowPreviews).Scan(&prefs.UpdatedAt)
+}
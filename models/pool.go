@@ -0,0 +1,32 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PoolConfig sizes the database connection pool
+type PoolConfig struct {
+	MaxOpenConns    int           // connections open at once, 0 for unlimited
+	MaxIdleConns    int           // idle connections kept for reuse
+	ConnMaxLifetime time.Duration // age after which a connection is closed and replaced
+	ConnMaxIdleTime time.Duration // idle time after which a connection is closed
+}
+
+// LoadPoolConfig reads the connection pool settings from the environment
+func LoadPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+	}
+}
+
+// Apply configures db's connection pool
+func (c PoolConfig) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(c.MaxOpenConns)
+	db.SetMaxIdleConns(c.MaxIdleConns)
+	db.SetConnMaxLifetime(c.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+}
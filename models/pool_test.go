@@ -0,0 +1,79 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// poolTestDriver opens connections that do nothing, so pool behaviour can
+// be observed without a database
+type poolTestDriver struct{}
+
+func (poolTestDriver) Open(name string) (driver.Conn, error) { return poolTestConn{}, nil }
+
+type poolTestConn struct{}
+
+func (poolTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (poolTestConn) Close() error              { return nil }
+func (poolTestConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func init() {
+	sql.Register("pooltest", poolTestDriver{})
+}
+
+func TestPoolConfigApplied(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "4")
+	t.Setenv("DB_MAX_IDLE_CONNS", "1")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "10m")
+	t.Setenv("DB_CONN_MAX_IDLE_TIME", "90s")
+
+	config := LoadPoolConfig()
+	want := PoolConfig{MaxOpenConns: 4, MaxIdleConns: 1, ConnMaxLifetime: 10 * time.Minute, ConnMaxIdleTime: 90 * time.Second}
+	if config != want {
+		t.Fatalf("LoadPoolConfig = %+v, want %+v", config, want)
+	}
+
+	db, err := sql.Open("pooltest", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	config.Apply(db)
+
+	if got := db.Stats().MaxOpenConnections; got != 4 {
+		t.Errorf("MaxOpenConnections = %d, want 4", got)
+	}
+
+	// Returning three connections keeps one idle and closes the rest
+	ctx := context.Background()
+	var conns []*sql.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Conn: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		conn.Close()
+	}
+	if stats := db.Stats(); stats.Idle != 1 || stats.MaxIdleClosed != 2 {
+		t.Errorf("idle = %d, closed for max idle = %d; want 1 and 2", stats.Idle, stats.MaxIdleClosed)
+	}
+
+	// database/sql doesn't report the connection time limits, so read them off the pool
+	fields := reflect.ValueOf(db).Elem()
+	if got := time.Duration(fields.FieldByName("maxLifetime").Int()); got != 10*time.Minute {
+		t.Errorf("max lifetime = %v, want 10m", got)
+	}
+	if got := time.Duration(fields.FieldByName("maxIdleTime").Int()); got != 90*time.Second {
+		t.Errorf("max idle time = %v, want 90s", got)
+	}
+}
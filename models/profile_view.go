@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ProfileViewer is one distinct user who viewed a profile
+type ProfileViewer struct {
+	ViewerID     string    `json:"viewer_id"`
+	Name         string    `json:"name,omitempty"`
+	ViewCount    int       `json:"view_count"`
+	LastViewedAt time.Time `json:"last_viewed_at"`
+}
+
+// RecordProfileView records that viewerID looked at profileID's profile.
+// Repeat views bump the count and timestamp; self-views aren't recorded.
+func RecordProfileView(profileID, viewerID string) error {
+	if viewerID == "" || profileID == viewerID {
+		return nil
+	}
+
+	query := `
+		INSERT INTO profile_views (profile_id, viewer_id)
+		VALUES ($1, $2)
+		ON CONFLICT (profile_id, viewer_id) DO UPDATE
+		SET view_count = profile_views.view_count + 1, last_viewed_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := DB.Exec(query, profileID, viewerID)
+	return err
+}
+
+// GetProfileViewers retrieves the distinct users who viewed a profile, most
+// recent first
+func GetProfileViewers(profileID string, limit, offset int) ([]*ProfileViewer, error) {
+	query := `
+		SELECT v.viewer_id, COALESCE(u.first_name, ''), COALESCE(u.last_name, ''), v.view_count, v.last_viewed_at
+		FROM profile_views v
+		LEFT JOIN users u ON u.id = v.viewer_id
+		WHERE v.profile_id = $1
+		ORDER BY v.last_viewed_at DESC, v.viewer_id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := queryRead(query, profileID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewers := []*ProfileViewer{}
+	for rows.Next() {
+		var viewer ProfileViewer
+		var firstName, lastName string
+		if err := rows.Scan(&viewer.ViewerID, &firstName, &lastName, &viewer.ViewCount, &viewer.LastViewedAt); err != nil {
+			return nil, err
+		}
+		viewer.Name = strings.TrimSpace(firstName + " " + lastName)
+		viewers = append(viewers, &viewer)
+	}
+
+	return viewers, rows.Err()
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRecordProfileViewSkipsSelfViews(t *testing.T) {
+	mock := newTestDB(t)
+
+	// Only the view by someone else reaches the database
+	mock.ExpectExec(`INSERT INTO profile_views \(profile_id, viewer_id\)`).
+		WithArgs("alice", "bob").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for _, viewer := range []string{"alice", "", "bob"} {
+		if err := RecordProfileView("alice", viewer); err != nil {
+			t.Errorf("RecordProfileView(alice, %q): %v", viewer, err)
+		}
+	}
+}
+
+func TestGetProfileViewers(t *testing.T) {
+	mock := newTestDB(t)
+	recent, earlier := time.Now(), time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(`FROM profile_views v\s+LEFT JOIN users u ON u.id = v.viewer_id\s+WHERE v.profile_id = \$1\s+ORDER BY v.last_viewed_at DESC`).
+		WithArgs("alice", 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"viewer_id", "first_name", "last_name", "view_count", "last_viewed_at"}).
+			AddRow("bob", "Bob", "Builder", 3, recent).
+			AddRow("carol", "", "", 1, earlier))
+
+	viewers, err := GetProfileViewers("alice", 20, 0)
+	if err != nil {
+		t.Fatalf("GetProfileViewers: %v", err)
+	}
+	if len(viewers) != 2 {
+		t.Fatalf("got %d viewers, want 2", len(viewers))
+	}
+	if bob := viewers[0]; bob.ViewerID != "bob" || bob.Name != "Bob Builder" || bob.ViewCount != 3 || !bob.LastViewedAt.Equal(recent) {
+		t.Errorf("first viewer = %+v, want bob with 3 views", bob)
+	}
+	if carol := viewers[1]; carol.ViewerID != "carol" || carol.Name != "" {
+		t.Errorf("second viewer = %+v, want carol without a name", carol)
+	}
+}
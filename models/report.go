@@ -0,0 +1,198 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Report target types
+const (
+	ReportTargetMessage = "message"
+	ReportTargetCompany = "company"
+	ReportTargetUser    = "user"
+)
+
+// Report statuses
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusReviewed = "reviewed"
+	ReportStatusActioned = "actioned"
+)
+
+// Report represents a user report of a message, company, or user
+type Report struct {
+	ID         string     `json:"id"`
+	ReporterID string     `json:"reporter_id"`
+	TargetType string     `json:"target_type"` // message, company, user
+	TargetID   string     `json:"target_id"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"` // open, reviewed, actioned
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreateReportRequest represents the request body for reporting content
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=message company user"`
+	TargetID   string `json:"target_id" binding:"required,uuid"`
+	Reason     string `json:"reason" binding:"required,max=1000"`
+}
+
+// UpdateReportStatusRequest represents the request body for reviewing a report
+type UpdateReportStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=open reviewed actioned"`
+}
+
+// CanTransitionReport reports whether a report may move between two statuses.
+// Actioned reports are final.
+func CanTransitionReport(from, to string) bool {
+	switch from {
+	case ReportStatusOpen:
+		return to == ReportStatusReviewed || to == ReportStatusActioned
+	case ReportStatusReviewed:
+		return to == ReportStatusOpen || to == ReportStatusActioned
+	}
+	return false
+}
+
+// ReportTargetExists checks that the reported content exists and isn't already removed
+func ReportTargetExists(targetType, targetID string) (bool, error) {
+	var query string
+	switch targetType {
+	case ReportTargetMessage:
+		query = `SELECT EXISTS(SELECT 1 FROM messages WHERE id = $1 AND deleted_at IS NULL)`
+	case ReportTargetCompany:
+		query = `SELECT EXISTS(SELECT 1 FROM companies WHERE id = $1 AND deleted_at IS NULL)`
+	case ReportTargetUser:
+		query = `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`
+	default:
+		return false, nil
+	}
+
+	var exists bool
+	err := queryRowRead(query, []interface{}{targetID}, &exists)
+	return exists, err
+}
+
+// CreateReport creates a new open report
+func CreateReport(report *Report) error {
+	query := `
+		INSERT INTO reports (reporter_id, target_type, target_id, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	return DB.QueryRow(query, report.ReporterID, report.TargetType, report.TargetID, report.Reason).
+		Scan(&report.ID, &report.Status, &report.CreatedAt, &report.UpdatedAt)
+}
+
+// GetReportByID retrieves a report by ID
+func GetReportByID(id string) (*Report, error) {
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, status,
+		       reviewed_by, reviewed_at, created_at, updated_at
+		FROM reports WHERE id = $1
+	`
+
+	var report *Report
+	err := readWithRetry(func() error {
+		var err error
+		report, err = scanReport(DB.QueryRow(query, id))
+		return err
+	})
+	return report, err
+}
+
+// ListReports lists reports, optionally filtered by status, oldest first
+func ListReports(status string, limit, offset int) ([]*Report, error) {
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, status,
+		       reviewed_by, reviewed_at, created_at, updated_at
+		FROM reports
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := queryRead(query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*Report{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// UpdateReportStatus moves a report to a new status. When the report is
+// actioned, the reported message or company is soft-deleted in the same transaction.
+func UpdateReportStatus(report *Report, status, reviewerID string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE reports SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP,
+		       updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = $4
+		RETURNING reviewed_at, updated_at
+	`, status, reviewerID, report.ID, report.Status).Scan(&report.ReviewedAt, &report.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if status == ReportStatusActioned {
+		switch report.TargetType {
+		case ReportTargetMessage:
+			_, err = tx.Exec(`UPDATE messages SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, report.TargetID)
+		case ReportTargetCompany:
+			_, err = tx.Exec(`UPDATE companies SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, report.TargetID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	report.Status = status
+	report.ReviewedBy = reviewerID
+	return nil
+}
+
+// scanReport scans a report row
+func scanReport(row interface{ Scan(...interface{}) error }) (*Report, error) {
+	var report Report
+	var reporterID, reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+
+	err := row.Scan(
+		&report.ID, &reporterID, &report.TargetType, &report.TargetID, &report.Reason,
+		&report.Status, &reviewedBy, &reviewedAt, &report.CreatedAt, &report.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	report.ReporterID = reporterID.String
+	report.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		report.ReviewedAt = &reviewedAt.Time
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrDatabaseUnavailable is returned without querying while the circuit
+// breaker is open because the database keeps failing
+var ErrDatabaseUnavailable = errors.New("database temporarily unavailable")
+
+// ResilienceConfig tunes retries and the circuit breaker around database reads
+type ResilienceConfig struct {
+	MaxAttempts      int           // attempts per read, including the first
+	RetryBackoff     time.Duration // delay before the first retry, doubled for each later one
+	FailureThreshold int           // consecutive transient failures that open the breaker
+	Cooldown         time.Duration // how long the breaker stays open before a trial query
+}
+
+// LoadResilienceConfig reads the database retry and breaker settings from the environment
+func LoadResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		MaxAttempts:      getEnvInt("DB_RETRY_MAX_ATTEMPTS", 3),
+		RetryBackoff:     getEnvDuration("DB_RETRY_BACKOFF", 50*time.Millisecond),
+		FailureThreshold: getEnvInt("DB_BREAKER_FAILURE_THRESHOLD", 5),
+		Cooldown:         getEnvDuration("DB_BREAKER_COOLDOWN", 30*time.Second),
+	}
+}
+
+var (
+	// resilience is reloaded by InitDatabase once the environment is set up
+	resilience = LoadResilienceConfig()
+	breaker    = &circuitBreaker{}
+)
+
+// circuitBreaker stops sending queries to a database that keeps failing.
+// After Cooldown a single trial query is let through; success closes the
+// breaker, another transient failure keeps it open for a further Cooldown.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a query may be sent now
+func (b *circuitBreaker) allow(cfg ResilienceConfig) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cfg.FailureThreshold <= 0 || b.failures < cfg.FailureThreshold {
+		return true
+	}
+	if time.Since(b.openedAt) < cfg.Cooldown || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the breaker with the outcome of a query
+func (b *circuitBreaker) record(cfg ResilienceConfig, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !IsTransientDBError(err) {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if cfg.FailureThreshold > 0 && b.failures >= cfg.FailureThreshold {
+		if b.failures == cfg.FailureThreshold {
+			log.Printf("Database circuit breaker opened after %d consecutive failures: %v", b.failures, err)
+		}
+		b.openedAt = time.Now()
+	}
+}
+
+// isOpen reports whether queries are currently being refused
+func (b *circuitBreaker) isOpen(cfg ResilienceConfig) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return cfg.FailureThreshold > 0 && b.failures >= cfg.FailureThreshold &&
+		time.Since(b.openedAt) < cfg.Cooldown
+}
+
+// DatabaseAvailable reports whether the circuit breaker is letting queries through
+func DatabaseAvailable() bool {
+	return !breaker.isOpen(resilience)
+}
+
+// IsTransientDBError reports whether err is a connection-level or
+// concurrency failure that may succeed if the query is retried
+func IsTransientDBError(err error) bool {
+	if err == nil || errors.Is(err, sql.ErrNoRows) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case strings.HasPrefix(string(pqErr.Code), "08"): // connection exception
+			return true
+		case pqErr.Code == "40001", pqErr.Code == "40P01": // serialization failure, deadlock
+			return true
+		case pqErr.Code == "53300", pqErr.Code == "57P01", pqErr.Code == "57P03": // too many connections, shutdown, starting up
+			return true
+		}
+		return false
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// readWithRetry runs an idempotent read, retrying transient failures with
+// exponential backoff. Only use it for queries that are safe to repeat.
+func readWithRetry(read func() error) error {
+	cfg := resilience
+
+	var err error
+	for attempt := 0; attempt < max(cfg.MaxAttempts, 1); attempt++ {
+		if attempt > 0 {
+			time.Sleep(cfg.RetryBackoff << (attempt - 1))
+		}
+		if !breaker.allow(cfg) {
+			return ErrDatabaseUnavailable
+		}
+
+		err = read()
+		breaker.record(cfg, err)
+		if !IsTransientDBError(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// queryRead runs an idempotent multi-row query, retrying transient failures
+func queryRead(query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := readWithRetry(func() error {
+		var err error
+		rows, err = DB.Query(query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// queryRowRead runs an idempotent single-row query and scans it into dest,
+// retrying transient failures
+func queryRowRead(query string, args []interface{}, dest ...interface{}) error {
+	return readWithRetry(func() error {
+		return DB.QueryRow(query, args...).Scan(dest...)
+	})
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	if n, err := strconv.Atoi(getEnv(key, "")); err == nil {
+		return n
+	}
+	return defaultValue
+}
+
+// getEnvDuration gets a duration environment variable or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(getEnv(key, "")); err == nil {
+		return d
+	}
+	return defaultValue
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// useResilience swaps in cfg and a closed breaker for the duration of the test
+func useResilience(t *testing.T, cfg ResilienceConfig) {
+	t.Helper()
+	previousConfig, previousBreaker := resilience, breaker
+	resilience, breaker = cfg, &circuitBreaker{}
+	t.Cleanup(func() {
+		resilience, breaker = previousConfig, previousBreaker
+	})
+}
+
+// connectionFailure is the error Postgres reports when a connection drops
+var connectionFailure = &pq.Error{Code: "08006", Message: "connection failure"}
+
+func TestReadRetriesTransientFailure(t *testing.T) {
+	useResilience(t, ResilienceConfig{MaxAttempts: 3, RetryBackoff: time.Millisecond, FailureThreshold: 5, Cooldown: time.Minute})
+	mock := newTestDB(t)
+
+	mock.ExpectQuery(`SELECT name FROM users`).WillReturnError(connectionFailure)
+	mock.ExpectQuery(`SELECT name FROM users`).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("Ada"))
+
+	var name string
+	if err := queryRowRead(`SELECT name FROM users WHERE id = $1`, []interface{}{"u1"}, &name); err != nil {
+		t.Fatalf("queryRowRead: %v", err)
+	}
+	if name != "Ada" {
+		t.Errorf("name = %q, want Ada", name)
+	}
+	if !DatabaseAvailable() {
+		t.Error("breaker opened after a recovered failure")
+	}
+}
+
+func TestReadDoesNotRetryPermanentFailure(t *testing.T) {
+	useResilience(t, ResilienceConfig{MaxAttempts: 3, RetryBackoff: time.Millisecond, FailureThreshold: 5, Cooldown: time.Minute})
+	mock := newTestDB(t)
+
+	syntaxError := &pq.Error{Code: "42601", Message: "syntax error"}
+	mock.ExpectQuery(`SELECT`).WillReturnError(syntaxError)
+
+	if _, err := queryRead(`SELECT broken`); !errors.Is(err, syntaxError) {
+		t.Errorf("queryRead error = %v, want the syntax error after one attempt", err)
+	}
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	useResilience(t, ResilienceConfig{MaxAttempts: 1, FailureThreshold: 2, Cooldown: 20 * time.Millisecond})
+	mock := newTestDB(t)
+	read := func() error {
+		var n int
+		return queryRowRead(`SELECT 1`, nil, &n)
+	}
+
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(connectionFailure)
+	mock.ExpectQuery(`SELECT 1`).WillReturnError(connectionFailure)
+	for i := 0; i < 2; i++ {
+		if err := read(); !errors.Is(err, connectionFailure) {
+			t.Fatalf("read %d error = %v, want the connection failure", i, err)
+		}
+	}
+
+	// Open: reads fail fast without reaching the database
+	if err := read(); !errors.Is(err, ErrDatabaseUnavailable) {
+		t.Fatalf("read while open = %v, want ErrDatabaseUnavailable", err)
+	}
+	if DatabaseAvailable() {
+		t.Error("DatabaseAvailable() = true while the breaker is open")
+	}
+
+	// After the cooldown a trial read that succeeds closes the breaker
+	time.Sleep(30 * time.Millisecond)
+	mock.ExpectQuery(`SELECT 1`).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	if err := read(); err != nil {
+		t.Fatalf("trial read: %v", err)
+	}
+	if !DatabaseAvailable() {
+		t.Error("DatabaseAvailable() = false after a successful trial read")
+	}
+}
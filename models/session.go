@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Session represents a logged-in device
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+// CreateSession records a new login session. tokenHash identifies the
+// session's current refresh token.
+func CreateSession(session *Session, tokenHash string) error {
+	query := `
+		INSERT INTO sessions (id, user_id, session_token, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, last_used_at
+	`
+
+	return DB.QueryRow(query,
+		session.ID, session.UserID, tokenHash, session.UserAgent, session.IPAddress, session.ExpiresAt,
+	).Scan(&session.CreatedAt, &session.LastUsedAt)
+}
+
+// GetActiveSessions lists a user's unexpired, unrevoked sessions, most recently used first
+func GetActiveSessions(userID string) ([]*Session, error) {
+	query := `
+		SELECT id, user_id, COALESCE(user_agent, ''), COALESCE(ip_address, ''), created_at,
+		       COALESCE(last_used_at, created_at), expires_at
+		FROM sessions
+		WHERE user_id = $1 AND is_active = true AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := queryRead(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*Session{}
+	for rows.Next() {
+		var session Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// IsSessionActive reports whether a session exists, belongs to the user, and is still valid
+func IsSessionActive(userID, sessionID string) (bool, error) {
+	var active bool
+	err := queryRowRead(`
+		SELECT is_active AND expires_at > CURRENT_TIMESTAMP
+		FROM sessions WHERE id = $1 AND user_id = $2
+	`, []interface{}{sessionID, userID}, &active)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return active, err
+}
+
+// RotateSession records a newly issued refresh token for a session
+func RotateSession(sessionID, tokenHash string, expiresAt time.Time) error {
+	_, err := DB.Exec(`
+		UPDATE sessions SET session_token = $1, expires_at = $2, last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`, tokenHash, expiresAt, sessionID)
+	return err
+}
+
+// RevokeSession deactivates one of a user's sessions
+func RevokeSession(userID, sessionID string) error {
+	result, err := DB.Exec(`
+		UPDATE sessions SET is_active = false
+		WHERE id = $1 AND user_id = $2 AND is_active = true
+	`, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
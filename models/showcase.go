@@ -2,7 +2,19 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
+)
+
+// Company tag limits
+const (
+	MaxCompanyTags      = 20
+	MaxCompanyTagLength = 50
 )
 
 // Company represents a company profile
@@ -24,6 +36,12 @@ type Company struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 	CreatedBy     string    `json:"created_by"`
 	IsPublic      bool      `json:"is_public"`
+	Tags          []string  `json:"tags"` // thematic tags, e.g. climate, b2b, ai
+	Slug          string    `json:"slug"` // URL-friendly unique name, set on create
+
+	// FinancialsRedacted is set when revenue, total funding, and valuation
+	// were withheld because the viewer has no data room access
+	FinancialsRedacted bool `json:"financials_redacted,omitempty"`
 }
 
 // Investment represents an investment record
@@ -38,8 +56,43 @@ type Investment struct {
 	Date           time.Time `json:"date"`
 	Status         string    `json:"status"` // pending, completed, cancelled
 	Notes          string    `json:"notes"`
+	Anonymous      bool      `json:"anonymous"` // hide the investor from the company's investor list
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+
+	// InvestorName replaces InvestorID when an anonymous investor is
+	// redacted for the viewer
+	InvestorName string `json:"investor_name,omitempty"`
+}
+
+// PortfolioSummary aggregates an investor's investments
+type PortfolioSummary struct {
+	InvestorID   string               `json:"investor_id"`
+	Totals       []CurrencyTotal      `json:"totals"` // excludes cancelled investments
+	CompanyCount int                  `json:"company_count"`
+	ByRound      []PortfolioBreakdown `json:"by_round"`
+	ByStatus     []PortfolioBreakdown `json:"by_status"`
+
+	// Totals normalized to the base currency; currencies without a known
+	// exchange rate are reported separately in Unconverted
+	BaseCurrency string          `json:"base_currency,omitempty"`
+	TotalInBase  float64         `json:"total_in_base"`
+	Unconverted  []CurrencyTotal `json:"unconverted,omitempty"`
+}
+
+// CurrencyTotal is a sum of investment amounts in a single currency
+type CurrencyTotal struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	Count    int     `json:"count"`
+}
+
+// PortfolioBreakdown groups investment totals by a dimension such as round or status
+type PortfolioBreakdown struct {
+	Key      string  `json:"key"`
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	Count    int     `json:"count"`
 }
 
 // AnalyticsEvent represents analytics tracking events
@@ -54,6 +107,29 @@ type AnalyticsEvent struct {
 	SessionID string                 `json:"session_id"`
 }
 
+// CompanyFacet counts the public companies sharing one value of a field
+type CompanyFacet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// CompanyFacets lists the industries and funding stages of public companies
+// with how many companies have each, for building search filters
+type CompanyFacets struct {
+	Industries    []CompanyFacet `json:"industries"`
+	FundingStages []CompanyFacet `json:"funding_stages"`
+}
+
+// CompanyDirectory is one page of the public company directory
+type CompanyDirectory struct {
+	Companies     []*Company     `json:"companies"`
+	Total         int            `json:"total"`
+	Limit         int            `json:"limit"`
+	Offset        int            `json:"offset"`
+	Industries    []CompanyFacet `json:"industries"`
+	FundingStages []CompanyFacet `json:"funding_stages"`
+}
+
 // Message represents a chat message
 type Message struct {
 	ID          string    `json:"id"`
@@ -64,6 +140,24 @@ type Message struct {
 	IsRead      bool      `json:"is_read"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Seq numbers the messages of a conversation from 1 in the order the
+	// server stored them; clients sort by it. A number is never reused, even
+	// after its message is purged. Messages stored before numbering was
+	// introduced were numbered when it was.
+	Seq int64 `json:"seq"`
+
+	Reactions []ReactionSummary `json:"reactions,omitempty"`
+}
+
+// CompanyActivity represents an entry in a company's activity feed
+type CompanyActivity struct {
+	ID        string                 `json:"id"`
+	CompanyID string                 `json:"company_id"`
+	ActorID   string                 `json:"actor_id"`
+	Action    string                 `json:"action"` // company_created, company_updated, investment_created, etc.
+	Details   map[string]interface{} `json:"details"`
+	CreatedAt time.Time              `json:"created_at"`
 }
 
 // CreateShowcaseTables creates the showcase-related tables
@@ -139,25 +233,274 @@ func CreateShowcaseTables() error {
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			is_active BOOLEAN DEFAULT true
 		);`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS user_agent TEXT;`,
+		`ALTER TABLE companies ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;`,
+		`ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;`,
+		`ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_nonce BYTEA;`,
+		`ALTER TABLE companies ADD COLUMN IF NOT EXISTS slug VARCHAR(100);`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);`,
+		`ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;`,
+		`ALTER TABLE companies ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';`,
+		`ALTER TABLE investments ADD COLUMN IF NOT EXISTS anonymous BOOLEAN NOT NULL DEFAULT FALSE;`,
+		`ALTER TABLE messages ADD COLUMN IF NOT EXISTS seq BIGINT;`,
+		// Number messages stored before sequence numbers, oldest first, after any already numbered
+		`UPDATE messages m SET seq = n.seq
+		FROM (
+			SELECT u.id, COALESCE(MAX(numbered.seq), 0) + ROW_NUMBER() OVER (
+				PARTITION BY LEAST(u.sender_id, u.receiver_id), GREATEST(u.sender_id, u.receiver_id)
+				ORDER BY u.created_at, u.id
+			) AS seq
+			FROM messages u
+			LEFT JOIN messages numbered
+				ON LEAST(numbered.sender_id, numbered.receiver_id) = LEAST(u.sender_id, u.receiver_id)
+				AND GREATEST(numbered.sender_id, numbered.receiver_id) = GREATEST(u.sender_id, u.receiver_id)
+				AND numbered.seq IS NOT NULL
+			WHERE u.seq IS NULL
+			GROUP BY u.id
+		) n
+		WHERE m.id = n.id;`,
+		// Name matches weigh more than description matches in full-text search
+		`ALTER TABLE companies ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+			setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+			setweight(to_tsvector('english', coalesce(description, '')), 'B')
+		) STORED;`,
+
+		// Company activity feed table
+		`CREATE TABLE IF NOT EXISTS company_activities (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			action VARCHAR(50) NOT NULL,
+			details JSONB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		// Company followers; anonymous follows are hidden from the owner's list
+		`CREATE TABLE IF NOT EXISTS company_followers (
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (company_id, user_id)
+		);`,
+
+		// Profile views, one row per distinct viewer of a matchmaking profile
+		`CREATE TABLE IF NOT EXISTS profile_views (
+			profile_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			viewer_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			view_count INTEGER NOT NULL DEFAULT 1,
+			last_viewed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (profile_id, viewer_id)
+		);`,
+
+		// Per-conversation message retention overrides; a conversation is the
+		// unordered pair of its users
+		`CREATE TABLE IF NOT EXISTS conversation_retention (
+			user_low UUID REFERENCES users(id) ON DELETE CASCADE,
+			user_high UUID REFERENCES users(id) ON DELETE CASCADE,
+			retention_days INTEGER NOT NULL,
+			updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (user_low, user_high)
+		);`,
+
+		// Messages past their retention under MESSAGE_RETENTION_MODE=archive
+		`CREATE TABLE IF NOT EXISTS messages_archive (
+			id UUID PRIMARY KEY,
+			sender_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			receiver_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			content TEXT NOT NULL,
+			content_nonce BYTEA,
+			message_type VARCHAR(20),
+			is_read BOOLEAN,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			deleted_at TIMESTAMP,
+			archived_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`ALTER TABLE messages_archive ADD COLUMN IF NOT EXISTS seq BIGINT;`,
+
+		// The last sequence number given out in each conversation; numbers are
+		// taken from here rather than from the messages still stored, so one
+		// freed by a retention purge is never reused
+		`CREATE TABLE IF NOT EXISTS conversation_sequences (
+			user_low UUID REFERENCES users(id) ON DELETE CASCADE,
+			user_high UUID REFERENCES users(id) ON DELETE CASCADE,
+			last_seq BIGINT NOT NULL,
+			PRIMARY KEY (user_low, user_high)
+		);`,
+		// Continue after the highest number in use, archived messages included
+		`INSERT INTO conversation_sequences (user_low, user_high, last_seq)
+		SELECT LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id), MAX(seq)
+		FROM (
+			SELECT sender_id, receiver_id, seq FROM messages
+			UNION ALL
+			SELECT sender_id, receiver_id, seq FROM messages_archive
+		) numbered
+		WHERE seq IS NOT NULL
+		GROUP BY LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id)
+		ON CONFLICT (user_low, user_high) DO UPDATE
+			SET last_seq = GREATEST(conversation_sequences.last_seq, EXCLUDED.last_seq);`,
+
+		// Push notification settings for offline chat messages; users without a row get the defaults
+		`CREATE TABLE IF NOT EXISTS notification_preferences (
+			user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			show_previews BOOLEAN NOT NULL DEFAULT TRUE,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		// Emoji reactions on chat messages; a user may add several different emojis
+		`CREATE TABLE IF NOT EXISTS message_reactions (
+			message_id UUID REFERENCES messages(id) ON DELETE CASCADE,
+			user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			emoji VARCHAR(64) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (message_id, user_id, emoji)
+		);`,
+
+		// Data room access: investors who may see a company's financials
+		`CREATE TABLE IF NOT EXISTS access_grants (
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			investor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (company_id, investor_id)
+		);`,
+
+		// Non-binding investment interest; one per investor and company
+		`CREATE TABLE IF NOT EXISTS investment_interests (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			investor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			amount DECIMAL(15,2),
+			currency VARCHAR(3) DEFAULT 'USD',
+			message TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (company_id, investor_id)
+		);`,
+
+		// Investor requests for an introduction to a company's owner; one per investor and company
+		`CREATE TABLE IF NOT EXISTS intro_requests (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			investor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+			message TEXT,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			responded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			responded_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (company_id, investor_id)
+		);`,
+
+		// Content reports for moderation
+		`CREATE TABLE IF NOT EXISTS reports (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			reporter_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			target_type VARCHAR(20) NOT NULL,
+			target_id UUID NOT NULL,
+			reason TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'open',
+			reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			reviewed_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		// Webhook subscribers and failed deliveries
+		`CREATE TABLE IF NOT EXISTS webhooks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			url VARCHAR(2048) NOT NULL,
+			events TEXT[] NOT NULL,
+			secret VARCHAR(128) NOT NULL,
+			created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			webhook_id UUID REFERENCES webhooks(id) ON DELETE CASCADE,
+			event_type VARCHAR(50) NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INTEGER NOT NULL,
+			last_error TEXT,
+			status_code INTEGER,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		// Company revenue, funding and valuation over time; a row per change
+		`CREATE TABLE IF NOT EXISTS company_financials_history (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID REFERENCES companies(id) ON DELETE CASCADE,
+			revenue DECIMAL(15,2),
+			total_funding DECIMAL(15,2),
+			valuation DECIMAL(15,2),
+			recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			recorded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
+
+		// Audit trail for company and investment changes
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			entity_type VARCHAR(20) NOT NULL,
+			entity_id UUID NOT NULL,
+			action VARCHAR(50) NOT NULL,
+			actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			changes JSONB NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);`,
 
 		// Create indexes
 		`CREATE INDEX IF NOT EXISTS idx_companies_industry ON companies(industry);`,
 		`CREATE INDEX IF NOT EXISTS idx_companies_funding_stage ON companies(funding_stage);`,
 		`CREATE INDEX IF NOT EXISTS idx_companies_is_public ON companies(is_public);`,
+		`CREATE INDEX IF NOT EXISTS idx_companies_tags ON companies USING GIN(tags);`,
 		`CREATE INDEX IF NOT EXISTS idx_investments_company_id ON investments(company_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_investments_investor_id ON investments(investor_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_investments_date ON investments(date);`,
+		`CREATE INDEX IF NOT EXISTS idx_investments_investor_date ON investments(investor_id, date DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_investments_status ON investments(status);`,
+		`CREATE INDEX IF NOT EXISTS idx_investments_round ON investments(round);`,
+		`CREATE INDEX IF NOT EXISTS idx_investments_amount ON investments(amount);`,
 		`CREATE INDEX IF NOT EXISTS idx_analytics_events_user_id ON analytics_events(user_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_analytics_events_timestamp ON analytics_events(timestamp);`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_sender_id ON messages(sender_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_receiver_id ON messages(receiver_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_created_at ON messages(created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_profile_views_recent ON profile_views(profile_id, last_viewed_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_content_search ON messages USING GIN (to_tsvector('english', content));`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_conversation_seq ON messages (LEAST(sender_id, receiver_id), GREATEST(sender_id, receiver_id), seq);`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_sessions_token ON sessions(session_token);`,
+		`CREATE INDEX IF NOT EXISTS idx_reports_status ON reports(status, created_at);`,
+		`CREATE INDEX IF NOT EXISTS idx_reports_target ON reports(target_type, target_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_webhook_id ON webhook_dead_letters(webhook_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_company_activities_company_id ON company_activities(company_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_company_followers_company_id ON company_followers(company_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_company_followers_user_id ON company_followers(user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_access_grants_investor_id ON access_grants(investor_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_investment_interests_investor_id ON investment_interests(investor_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_intro_requests_investor_id ON intro_requests(investor_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_company_financials_history_company ON company_financials_history(company_id, recorded_at);`,
+
+		// Start the history of companies created before it was tracked from their current figures
+		`INSERT INTO company_financials_history (company_id, revenue, total_funding, valuation, recorded_at)
+			SELECT c.id, c.revenue, c.total_funding, c.valuation, c.updated_at
+			FROM companies c
+			WHERE NOT EXISTS (SELECT 1 FROM company_financials_history h WHERE h.company_id = c.id);`,
 
 		// Full-text search indexes
 		`CREATE INDEX IF NOT EXISTS idx_companies_name_fts ON companies USING GIN(to_tsvector('english', name));`,
 		`CREATE INDEX IF NOT EXISTS idx_companies_description_fts ON companies USING GIN(to_tsvector('english', description));`,
+		`CREATE INDEX IF NOT EXISTS idx_companies_search_vector ON companies USING GIN(search_vector);`,
+
+		// Trigram index for prefix and typo-tolerant name search
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+		`CREATE INDEX IF NOT EXISTS idx_companies_name_trgm ON companies USING GIN(name gin_trgm_ops);`,
+
+		// Slugs are never reused, so old links can't resolve to another company
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_companies_slug ON companies(slug);`,
 	}
 
 	for _, query := range queries {
@@ -166,101 +509,535 @@ func CreateShowcaseTables() error {
 		}
 	}
 
-	return nil
+	return backfillCompanySlugs()
 }
 
 // GetCompanyByID retrieves a company by ID
 func GetCompanyByID(id string) (*Company, error) {
-	query := `
-		SELECT id, name, description, industry, founded_year, headquarters, 
-		       website, logo_url, employee_count, revenue, funding_stage, 
-		       total_funding, valuation, created_at, updated_at, created_by, is_public
-		FROM companies WHERE id = $1
-	`
-
-	var company Company
-	err := DB.QueryRow(query, id).Scan(
-		&company.ID, &company.Name, &company.Description, &company.Industry,
-		&company.FoundedYear, &company.Headquarters, &company.Website, &company.LogoURL,
-		&company.EmployeeCount, &company.Revenue, &company.FundingStage,
-		&company.TotalFunding, &company.Valuation, &company.CreatedAt,
-		&company.UpdatedAt, &company.CreatedBy, &company.IsPublic,
-	)
+	var company *Company
+	err := readWithRetry(func() error {
+		var err error
+		company, err = scanCompany(DB.QueryRow(`SELECT `+companyColumns+`
+			FROM companies WHERE id = $1 AND deleted_at IS NULL`, id))
+		return err
+	})
+	return company, err
+}
 
+// GetCompaniesByIDs loads the companies with the given ids in one query.
+// Deleted and unknown ids are left out; the result is in no particular order.
+func GetCompaniesByIDs(ids []string) ([]*Company, error) {
+	rows, err := queryRead(`SELECT `+companyColumns+`
+		FROM companies WHERE id = ANY($1) AND deleted_at IS NULL`, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &company, nil
+	var companies []*Company
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, company)
+	}
+
+	return companies, rows.Err()
 }
 
-// CreateCompany creates a new company
-func CreateCompany(company *Company) error {
-	query := `
+// insertCompanyQuery inserts a company, slug included, returning its generated fields
+const insertCompanyQuery = `
 		INSERT INTO companies (name, description, industry, founded_year, headquarters,
 		                     website, logo_url, employee_count, revenue, funding_stage,
-		                     total_funding, valuation, created_by, is_public)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		                     total_funding, valuation, created_by, is_public, tags, slug)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id, created_at, updated_at
 	`
 
-	return DB.QueryRow(query,
+// CreateCompany creates a new company with a unique slug derived from its name
+func CreateCompany(company *Company) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertCompany(tx, company); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertCompany picks the company's slug and inserts it within tx
+func insertCompany(tx *sql.Tx, company *Company) error {
+	slug, err := uniqueCompanySlug(tx, company.Name, "")
+	if err != nil {
+		return err
+	}
+	company.Slug = slug
+
+	err = tx.QueryRow(insertCompanyQuery,
 		company.Name, company.Description, company.Industry, company.FoundedYear,
 		company.Headquarters, company.Website, company.LogoURL, company.EmployeeCount,
 		company.Revenue, company.FundingStage, company.TotalFunding, company.Valuation,
-		company.CreatedBy, company.IsPublic,
+		company.CreatedBy, company.IsPublic, pq.Array(company.Tags), company.Slug,
 	).Scan(&company.ID, &company.CreatedAt, &company.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return recordFinancialSnapshot(tx, company, company.CreatedBy)
+}
+
+// ValidateCompany checks a company's fields before it is written and
+// normalizes its tags to trimmed, lowercase, de-duplicated form
+func ValidateCompany(company *Company) error {
+	if company.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(company.Name) > 255 {
+		return errors.New("name must be at most 255 characters")
+	}
+	if company.FoundedYear != 0 && (company.FoundedYear < 1800 || company.FoundedYear > time.Now().Year()) {
+		return errors.New("founded_year is out of range")
+	}
+	if company.EmployeeCount < 0 {
+		return errors.New("employee_count must not be negative")
+	}
+	if company.Revenue < 0 || company.TotalFunding < 0 || company.Valuation < 0 {
+		return errors.New("financial figures must not be negative")
+	}
+
+	company.Tags = NormalizeCompanyTags(company.Tags)
+	if len(company.Tags) > MaxCompanyTags {
+		return errors.New("too many tags")
+	}
+	for _, tag := range company.Tags {
+		if len(tag) > MaxCompanyTagLength {
+			return errors.New("tags must be at most 50 characters")
+		}
+	}
+	return nil
+}
+
+// NormalizeCompanyTags trims and lowercases tags, dropping blanks and duplicates
+func NormalizeCompanyTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// CreateCompanies inserts a batch of companies in a single transaction. Each
+// insert runs under its own savepoint so one bad row doesn't abort the batch;
+// the returned slice holds the per-company insert error, or nil on success.
+func CreateCompanies(companies []*Company) ([]error, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rowErrors := make([]error, len(companies))
+	for i, company := range companies {
+		if _, err := tx.Exec("SAVEPOINT company_row"); err != nil {
+			return nil, err
+		}
+
+		if err := insertCompany(tx, company); err != nil {
+			rowErrors[i] = err
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT company_row"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT company_row"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return rowErrors, nil
+}
+
+// Investment statuses
+const (
+	InvestmentStatusPending   = "pending"
+	InvestmentStatusCompleted = "completed"
+	InvestmentStatusCancelled = "cancelled"
+)
+
+// companyColumns lists the columns scanned by scanCompany
+const companyColumns = `id, name, description, industry, founded_year, headquarters,
+		       website, logo_url, employee_count, revenue, funding_stage,
+		       total_funding, valuation, created_at, updated_at, created_by, is_public, tags,
+		       COALESCE(slug, '')`
+
+// scanCompany scans a row selected with companyColumns
+func scanCompany(row interface{ Scan(...interface{}) error }) (*Company, error) {
+	var company Company
+	err := row.Scan(
+		&company.ID, &company.Name, &company.Description, &company.Industry,
+		&company.FoundedYear, &company.Headquarters, &company.Website, &company.LogoURL,
+		&company.EmployeeCount, &company.Revenue, &company.FundingStage,
+		&company.TotalFunding, &company.Valuation, &company.CreatedAt,
+		&company.UpdatedAt, &company.CreatedBy, &company.IsPublic, pq.Array(&company.Tags),
+		&company.Slug,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &company, nil
 }
 
-// UpdateCompany updates an existing company
-func UpdateCompany(company *Company) error {
+// UpdateCompany updates an existing company and records the changed fields
+// in the audit log within the same transaction
+func UpdateCompany(company *Company, actorID string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := scanCompany(tx.QueryRow(`SELECT `+companyColumns+`
+		FROM companies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, company.ID))
+	if err != nil {
+		return err
+	}
+
+	// Renames keep the slug unless COMPANY_SLUG_ON_RENAME=regenerate
+	company.Slug = before.Slug
+	if company.Slug == "" || (company.Name != before.Name && companySlugOnRename() == CompanySlugRegenerate) {
+		if company.Slug, err = uniqueCompanySlug(tx, company.Name, company.ID); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE companies SET 
 			name = $1, description = $2, industry = $3, founded_year = $4,
 			headquarters = $5, website = $6, logo_url = $7, employee_count = $8,
 			revenue = $9, funding_stage = $10, total_funding = $11, valuation = $12,
-			is_public = $13, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $14
+			is_public = $13, tags = $14, slug = $15, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $16
 	`
 
-	result, err := DB.Exec(query,
+	_, err = tx.Exec(query,
 		company.Name, company.Description, company.Industry, company.FoundedYear,
 		company.Headquarters, company.Website, company.LogoURL, company.EmployeeCount,
 		company.Revenue, company.FundingStage, company.TotalFunding, company.Valuation,
-		company.IsPublic, company.ID,
+		company.IsPublic, pq.Array(company.Tags), company.Slug, company.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if financialsChanged(before, company) {
+		if err := recordFinancialSnapshot(tx, company, actorID); err != nil {
+			return err
+		}
+	}
+
+	changes := DiffFields(companyAuditFields(before), companyAuditFields(company))
+	if len(changes) > 0 {
+		if err := RecordAudit(tx, &AuditEntry{
+			EntityType: AuditEntityCompany,
+			EntityID:   company.ID,
+			Action:     "company_updated",
+			ActorID:    actorID,
+			Changes:    changes,
+		}); err != nil {
+			return err
+		}
+	}
+
+	company.CreatedBy = before.CreatedBy
+	company.CreatedAt = before.CreatedAt
+	return tx.Commit()
+}
+
+// TransferCompanyOwnership makes another user the owner of a company and
+// records the transfer in the audit log within the same transaction
+func TransferCompanyOwnership(companyID, newOwnerID, actorID string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var previousOwner sql.NullString
+	err = tx.QueryRow(`SELECT created_by FROM companies WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, companyID).
+		Scan(&previousOwner)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE companies SET created_by = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		newOwnerID, companyID); err != nil {
+		return err
+	}
+
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityCompany,
+		EntityID:   companyID,
+		Action:     "company_ownership_transferred",
+		ActorID:    actorID,
+		Changes: map[string]FieldChange{
+			"created_by": {Before: previousOwner.String, After: newOwnerID},
+		},
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateInvestment creates an investment and records it in the audit log
+// within the same transaction
+func CreateInvestment(investment *Investment, actorID string) error {
+	if investment.Status == "" {
+		investment.Status = InvestmentStatusPending
+	}
+	if investment.Currency == "" {
+		investment.Currency = "USD"
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO investments (company_id, investor_id, amount, currency, investment_type, round, date, status, notes, anonymous)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(query,
+		investment.CompanyID, investment.InvestorID, investment.Amount, investment.Currency,
+		investment.InvestmentType, investment.Round, investment.Date, investment.Status, investment.Notes,
+		investment.Anonymous,
+	).Scan(&investment.ID, &investment.CreatedAt, &investment.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityInvestment,
+		EntityID:   investment.ID,
+		Action:     "investment_created",
+		ActorID:    actorID,
+		Changes:    DiffFields(nil, investmentAuditFields(investment)),
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetInvestmentByID retrieves an investment by ID
+func GetInvestmentByID(id string) (*Investment, error) {
+	query := `
+		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, anonymous, created_at, updated_at
+		FROM investments WHERE id = $1
+	`
+
+	var investment Investment
+	err := queryRowRead(query, []interface{}{id},
+		&investment.ID, &investment.CompanyID, &investment.InvestorID, &investment.Amount,
+		&investment.Currency, &investment.InvestmentType, &investment.Round, &investment.Date,
+		&investment.Status, &investment.Notes, &investment.Anonymous, &investment.CreatedAt, &investment.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &investment, nil
+}
+
+// InvestmentFilter narrows an investment listing. Zero values leave a filter unset;
+// an empty InvestorID lists every investor's investments.
+type InvestmentFilter struct {
+	InvestorID     string
+	Round          string
+	Status         string
+	InvestmentType string
+	DateFrom       *time.Time
+	DateTo         *time.Time
+	MinAmount      *float64
+	MaxAmount      *float64
+	Limit          int
+	Offset         int
+}
+
+// FilterInvestments lists investments matching a filter, newest first
+func FilterInvestments(filter InvestmentFilter) ([]Investment, error) {
+	query := `
+		SELECT id, company_id, investor_id, amount, currency, investment_type, round, date, status, notes, anonymous, created_at, updated_at
+		FROM investments
+		WHERE 1 = 1
+	`
+
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		query += fmt.Sprintf(" AND "+condition, len(args))
+	}
+
+	if filter.InvestorID != "" {
+		addCondition("investor_id = $%d", filter.InvestorID)
+	}
+	if filter.Round != "" {
+		addCondition("round = $%d", filter.Round)
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.InvestmentType != "" {
+		addCondition("investment_type = $%d", filter.InvestmentType)
+	}
+	if filter.DateFrom != nil {
+		addCondition("date >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		addCondition("date <= $%d", *filter.DateTo)
+	}
+	if filter.MinAmount != nil {
+		addCondition("amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("amount <= $%d", *filter.MaxAmount)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY date DESC, id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := queryRead(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	investments := []Investment{}
+	for rows.Next() {
+		var investment Investment
+		err := rows.Scan(
+			&investment.ID, &investment.CompanyID, &investment.InvestorID, &investment.Amount,
+			&investment.Currency, &investment.InvestmentType, &investment.Round, &investment.Date,
+			&investment.Status, &investment.Notes, &investment.Anonymous, &investment.CreatedAt, &investment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		investments = append(investments, investment)
+	}
+
+	return investments, rows.Err()
+}
 
+// CanTransitionInvestment reports whether an investment may move between two
+// statuses. Cancelled investments are final.
+func CanTransitionInvestment(from, to string) bool {
+	switch from {
+	case InvestmentStatusPending:
+		return to == InvestmentStatusCompleted || to == InvestmentStatusCancelled
+	case InvestmentStatusCompleted:
+		return to == InvestmentStatusCancelled
+	}
+	return false
+}
+
+// UpdateInvestmentStatus moves an investment to a new status and records the
+// change in the audit log within the same transaction. It returns
+// sql.ErrNoRows if the investment's status changed concurrently.
+func UpdateInvestmentStatus(investment *Investment, status, actorID string) error {
+	tx, err := DB.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	err = tx.QueryRow(`
+		UPDATE investments SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = $3
+		RETURNING updated_at
+	`, status, investment.ID, investment.Status).Scan(&investment.UpdatedAt)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if err := RecordAudit(tx, &AuditEntry{
+		EntityType: AuditEntityInvestment,
+		EntityID:   investment.ID,
+		Action:     "investment_status_changed",
+		ActorID:    actorID,
+		Changes: map[string]FieldChange{
+			"status": {Before: investment.Status, After: status},
+		},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
 	}
 
+	investment.Status = status
 	return nil
 }
 
-// SearchCompanies searches companies with filters
-func SearchCompanies(query string, industry string, fundingStage string, limit, offset int) ([]*Company, error) {
+// Company search modes
+const (
+	CompanySearchSubstring = "substring" // query appears anywhere in the name or description
+	CompanySearchPrefix    = "prefix"    // name starts with the query or is a near miss, ranked by similarity
+	CompanySearchFullText  = "fulltext"  // query words match the name or description, name matches ranked first
+)
+
+// SearchCompanies searches companies with filters. When tags are given, a
+// company must carry all of them if matchAllTags is set, otherwise any of them.
+// In CompanySearchPrefix mode the query is matched against names with pg_trgm
+// so typos still match; prefix matches rank first, then by similarity. In
+// CompanySearchFullText mode it is parsed as a web search query and ranked
+// with ts_rank over search_vector, where name terms carry weight A and
+// description terms weight B, so a name match outranks a description match.
+func SearchCompanies(query, mode string, industry string, fundingStage string, tags []string, matchAllTags bool, limit, offset int) ([]*Company, error) {
 	baseQuery := `
-		SELECT id, name, description, industry, founded_year, headquarters,
-		       website, logo_url, employee_count, revenue, funding_stage,
-		       total_funding, valuation, created_at, updated_at, created_by, is_public
+		SELECT ` + companyColumns + `
 		FROM companies
-		WHERE is_public = true
+		WHERE is_public = true AND deleted_at IS NULL
 	`
 
 	var conditions []string
 	var args []interface{}
 	argIndex := 1
 
-	if query != "" {
+	orderBy := `created_at DESC`
+	if query != "" && mode == CompanySearchPrefix {
+		prefixArg, queryArg := `$`+string(rune(argIndex+48)), `$`+string(rune(argIndex+49))
+		conditions = append(conditions, `(name ILIKE `+prefixArg+` OR name % `+queryArg+`)`)
+		orderBy = `name ILIKE ` + prefixArg + ` DESC, similarity(name, ` + queryArg + `) DESC, created_at DESC`
+		args = append(args, escapeLikePattern(query)+"%", query)
+		argIndex += 2
+	} else if query != "" && mode == CompanySearchFullText {
+		tsQuery := `websearch_to_tsquery('english', $` + string(rune(argIndex+48)) + `)`
+		conditions = append(conditions, `search_vector @@ `+tsQuery)
+		orderBy = `ts_rank(search_vector, ` + tsQuery + `) DESC, created_at DESC`
+		args = append(args, query)
+		argIndex++
+	} else if query != "" {
 		conditions = append(conditions, `(name ILIKE $`+string(rune(argIndex+48))+` OR description ILIKE $`+string(rune(argIndex+48))+`)`)
 		args = append(args, "%"+query+"%")
 		argIndex++
@@ -278,6 +1055,16 @@ func SearchCompanies(query string, industry string, fundingStage string, limit,
 		argIndex++
 	}
 
+	if len(tags) > 0 {
+		operator := "&&"
+		if matchAllTags {
+			operator = "@>"
+		}
+		conditions = append(conditions, `tags `+operator+` $`+string(rune(argIndex+48)))
+		args = append(args, pq.Array(tags))
+		argIndex++
+	}
+
 	if len(conditions) > 0 {
 		baseQuery += " AND " + conditions[0]
 		for i := 1; i < len(conditions); i++ {
@@ -285,10 +1072,10 @@ func SearchCompanies(query string, industry string, fundingStage string, limit,
 		}
 	}
 
-	baseQuery += ` ORDER BY created_at DESC LIMIT $` + string(rune(argIndex+48)) + ` OFFSET $` + string(rune(argIndex+49))
+	baseQuery += ` ORDER BY ` + orderBy + ` LIMIT $` + string(rune(argIndex+48)) + ` OFFSET $` + string(rune(argIndex+49))
 	args = append(args, limit, offset)
 
-	rows, err := DB.Query(baseQuery, args...)
+	rows, err := queryRead(baseQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -296,19 +1083,231 @@ func SearchCompanies(query string, industry string, fundingStage string, limit,
 
 	var companies []*Company
 	for rows.Next() {
-		var company Company
-		err := rows.Scan(
-			&company.ID, &company.Name, &company.Description, &company.Industry,
-			&company.FoundedYear, &company.Headquarters, &company.Website, &company.LogoURL,
-			&company.EmployeeCount, &company.Revenue, &company.FundingStage,
-			&company.TotalFunding, &company.Valuation, &company.CreatedAt,
-			&company.UpdatedAt, &company.CreatedBy, &company.IsPublic,
-		)
+		company, err := scanCompany(rows)
 		if err != nil {
 			return nil, err
 		}
-		companies = append(companies, &company)
+		companies = append(companies, company)
 	}
 
 	return companies, nil
 }
+
+// GetCompanyDirectory returns a page of public companies, newest first, with
+// facet counts by industry and funding stage across all public companies
+func GetCompanyDirectory(limit, offset int) (*CompanyDirectory, error) {
+	directory := &CompanyDirectory{Limit: limit, Offset: offset}
+
+	if err := queryRowRead(`SELECT COUNT(*) FROM companies WHERE is_public = true AND deleted_at IS NULL`,
+		nil, &directory.Total); err != nil {
+		return nil, err
+	}
+
+	rows, err := queryRead(`SELECT `+companyColumns+`
+		FROM companies
+		WHERE is_public = true AND deleted_at IS NULL
+		ORDER BY created_at DESC, id
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directory.Companies = []*Company{}
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		directory.Companies = append(directory.Companies, company)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	facets, err := GetCompanyFacets()
+	if err != nil {
+		return nil, err
+	}
+	directory.Industries = facets.Industries
+	directory.FundingStages = facets.FundingStages
+
+	return directory, nil
+}
+
+// GetCompanyFacets counts public companies by industry and by funding stage
+func GetCompanyFacets() (*CompanyFacets, error) {
+	var facets CompanyFacets
+	var err error
+	if facets.Industries, err = getCompanyFacet("industry"); err != nil {
+		return nil, err
+	}
+	if facets.FundingStages, err = getCompanyFacet("funding_stage"); err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+// getCompanyFacet counts public companies by the values of a column
+func getCompanyFacet(column string) ([]CompanyFacet, error) {
+	query := `
+		SELECT COALESCE(` + column + `, ''), COUNT(*)
+		FROM companies
+		WHERE is_public = true AND deleted_at IS NULL
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+	`
+
+	rows, err := queryRead(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := []CompanyFacet{}
+	for rows.Next() {
+		var facet CompanyFacet
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, err
+		}
+		facets = append(facets, facet)
+	}
+
+	return facets, rows.Err()
+}
+
+// RecordCompanyActivity appends an entry to a company's activity feed
+func RecordCompanyActivity(activity *CompanyActivity) error {
+	details, err := json.Marshal(activity.Details)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO company_activities (company_id, actor_id, action, details)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return DB.QueryRow(query, activity.CompanyID, activity.ActorID, activity.Action, details).
+		Scan(&activity.ID, &activity.CreatedAt)
+}
+
+// GetCompanyActivity retrieves a company's activity feed, newest first
+func GetCompanyActivity(companyID string, limit, offset int) ([]*CompanyActivity, error) {
+	query := `
+		SELECT id, company_id, actor_id, action, details, created_at
+		FROM company_activities
+		WHERE company_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := queryRead(query, companyID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []*CompanyActivity{}
+	for rows.Next() {
+		var activity CompanyActivity
+		var actorID sql.NullString
+		var details []byte
+		if err := rows.Scan(&activity.ID, &activity.CompanyID, &actorID, &activity.Action, &details, &activity.CreatedAt); err != nil {
+			return nil, err
+		}
+		activity.ActorID = actorID.String
+		if len(details) > 0 {
+			if err := json.Unmarshal(details, &activity.Details); err != nil {
+				return nil, err
+			}
+		}
+		activities = append(activities, &activity)
+	}
+
+	return activities, rows.Err()
+}
+
+// GetPortfolioSummary computes an investor's portfolio totals and breakdowns
+func GetPortfolioSummary(investorID string) (*PortfolioSummary, error) {
+	summary := &PortfolioSummary{
+		InvestorID: investorID,
+		Totals:     []CurrencyTotal{},
+	}
+
+	rows, err := queryRead(`
+		SELECT currency, SUM(amount), COUNT(*)
+		FROM investments
+		WHERE investor_id = $1 AND status <> 'cancelled'
+		GROUP BY currency
+		ORDER BY currency
+	`, investorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total CurrencyTotal
+		if err := rows.Scan(&total.Currency, &total.Amount, &total.Count); err != nil {
+			return nil, err
+		}
+		summary.Totals = append(summary.Totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = queryRowRead(`
+		SELECT COUNT(DISTINCT company_id)
+		FROM investments
+		WHERE investor_id = $1 AND status <> 'cancelled'
+	`, []interface{}{investorID}, &summary.CompanyCount)
+	if err != nil {
+		return nil, err
+	}
+
+	if summary.ByRound, err = getPortfolioBreakdown(investorID, "round"); err != nil {
+		return nil, err
+	}
+	if summary.ByStatus, err = getPortfolioBreakdown(investorID, "status"); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// getPortfolioBreakdown groups an investor's investments by the given column and currency.
+// column must be a trusted column name, never user input.
+func getPortfolioBreakdown(investorID, column string) ([]PortfolioBreakdown, error) {
+	query := `
+		SELECT COALESCE(` + column + `, ''), currency, SUM(amount), COUNT(*)
+		FROM investments
+		WHERE investor_id = $1
+		GROUP BY 1, currency
+		ORDER BY 1, currency
+	`
+
+	rows, err := queryRead(query, investorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := []PortfolioBreakdown{}
+	for rows.Next() {
+		var item PortfolioBreakdown
+		if err := rows.Scan(&item.Key, &item.Currency, &item.Amount, &item.Count); err != nil {
+			return nil, err
+		}
+		breakdown = append(breakdown, item)
+	}
+
+	return breakdown, rows.Err()
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input matches literally
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// newTestDB points DB at a mock database for the duration of the test,
+// failing it if any expectation set on the mock goes unmet
+func newTestDB(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	previous := DB
+	DB = db
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+		DB = previous
+	})
+	return mock
+}
+
+func TestGetPortfolioSummaryKeepsCurrenciesApart(t *testing.T) {
+	mock := newTestDB(t)
+
+	mock.ExpectQuery(`SELECT currency, SUM\(amount\), COUNT\(\*\)`).WithArgs("inv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "sum", "count"}).
+			AddRow("EUR", 2500.0, 2).
+			AddRow("USD", 10000.0, 3))
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT company_id\)`).WithArgs("inv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	mock.ExpectQuery(`COALESCE\(round, ''\)`).WithArgs("inv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "currency", "sum", "count"}).
+			AddRow("seed", "EUR", 2500.0, 2).
+			AddRow("seed", "USD", 4000.0, 1).
+			AddRow("series_a", "USD", 6000.0, 2))
+	mock.ExpectQuery(`COALESCE\(status, ''\)`).WithArgs("inv-1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "currency", "sum", "count"}).
+			AddRow("completed", "EUR", 2500.0, 2).
+			AddRow("completed", "USD", 10000.0, 3))
+
+	summary, err := GetPortfolioSummary("inv-1")
+	if err != nil {
+		t.Fatalf("GetPortfolioSummary: %v", err)
+	}
+
+	want := []CurrencyTotal{{"EUR", 2500, 2}, {"USD", 10000, 3}}
+	if len(summary.Totals) != len(want) {
+		t.Fatalf("totals = %+v, want %+v", summary.Totals, want)
+	}
+	for i := range want {
+		if summary.Totals[i] != want[i] {
+			t.Errorf("totals[%d] = %+v, want %+v", i, summary.Totals[i], want[i])
+		}
+	}
+	if summary.CompanyCount != 4 {
+		t.Errorf("company count = %d, want 4", summary.CompanyCount)
+	}
+	if len(summary.ByRound) != 3 || summary.ByRound[0].Currency != "EUR" || summary.ByRound[1].Currency != "USD" {
+		t.Errorf("by round = %+v, want seed split by EUR and USD", summary.ByRound)
+	}
+}
+
+// companyRowColumns are the columns scanned by scanCompany
+var companyRowColumns = []string{"id", "name", "description", "industry", "founded_year", "headquarters",
+	"website", "logo_url", "employee_count", "revenue", "funding_stage",
+	"total_funding", "valuation", "created_at", "updated_at", "created_by", "is_public", "tags", "slug"}
+
+func TestSearchCompaniesTagModes(t *testing.T) {
+	mock := newTestDB(t)
+	tags := []string{"ai", "climate"}
+
+	// Every tag must be carried when matchAllTags is set, any of them otherwise
+	mock.ExpectQuery(`AND tags @> \$1 ORDER BY`).WithArgs(`{"ai","climate"}`, 20, 0).
+		WillReturnRows(sqlmock.NewRows(companyRowColumns))
+	mock.ExpectQuery(`AND tags && \$1 ORDER BY`).WithArgs(`{"ai","climate"}`, 20, 0).
+		WillReturnRows(sqlmock.NewRows(companyRowColumns))
+
+	if _, err := SearchCompanies("", "", "", "", tags, true, 20, 0); err != nil {
+		t.Fatalf("SearchCompanies all tags: %v", err)
+	}
+	if _, err := SearchCompanies("", "", "", "", tags, false, 20, 0); err != nil {
+		t.Fatalf("SearchCompanies any tag: %v", err)
+	}
+}
+
+func TestSearchCompaniesPrefixMode(t *testing.T) {
+	mock := newTestDB(t)
+	techCorp := &Company{ID: "c1", Name: "TechCorp", IsPublic: true}
+
+	// A prefix is matched with ILIKE, a typo through pg_trgm similarity, and
+	// prefix matches are ranked before near misses
+	for _, query := range []string{"Tech", "Tehc"} {
+		mock.ExpectQuery(`AND \(name ILIKE \$1 OR name % \$2\) ORDER BY name ILIKE \$1 DESC, similarity\(name, \$2\) DESC`).
+			WithArgs(query+"%", query, 10, 0).
+			WillReturnRows(companyRow(techCorp))
+
+		companies, err := SearchCompanies(query, CompanySearchPrefix, "", "", nil, false, 10, 0)
+		if err != nil {
+			t.Fatalf("SearchCompanies(%q): %v", query, err)
+		}
+		if len(companies) != 1 || companies[0].Name != "TechCorp" {
+			t.Errorf("SearchCompanies(%q) = %v, want TechCorp", query, companies)
+		}
+	}
+
+	// LIKE wildcards in the query are matched literally
+	mock.ExpectQuery(`name ILIKE \$1 OR name % \$2`).WithArgs(`100\%%`, "100%", 10, 0).
+		WillReturnRows(sqlmock.NewRows(companyRowColumns))
+	if _, err := SearchCompanies("100%", CompanySearchPrefix, "", "", nil, false, 10, 0); err != nil {
+		t.Fatalf("SearchCompanies with a wildcard: %v", err)
+	}
+}
+
+func TestSearchCompaniesFullTextRanksNameMatchesFirst(t *testing.T) {
+	mock := newTestDB(t)
+	nameMatch := &Company{ID: "c1", Name: "Rocket Labs", Description: "Launch services", IsPublic: true}
+	descriptionMatch := &Company{ID: "c2", Name: "Orbital", Description: "We build rocket engines", IsPublic: true}
+
+	// Postgres ranks by ts_rank over the weighted search_vector; the rows
+	// come back in that order and must keep it
+	rows := companyRow(nameMatch)
+	rows.AddRow(descriptionMatch.ID, descriptionMatch.Name, descriptionMatch.Description, "", 0, "", "", "", 0, 0.0,
+		"", 0.0, 0.0, time.Time{}, time.Time{}, "", true, "{}", "")
+	mock.ExpectQuery(`AND search_vector @@ websearch_to_tsquery\('english', \$1\) ORDER BY ts_rank\(search_vector, websearch_to_tsquery\('english', \$1\)\) DESC`).
+		WithArgs("rocket", 10, 0).
+		WillReturnRows(rows)
+
+	companies, err := SearchCompanies("rocket", CompanySearchFullText, "", "", nil, false, 10, 0)
+	if err != nil {
+		t.Fatalf("SearchCompanies: %v", err)
+	}
+	if len(companies) != 2 || companies[0].ID != "c1" || companies[1].ID != "c2" {
+		t.Fatalf("results = %v, want the name match before the description match", companies)
+	}
+
+}
+
+// pqArrayValue encodes a string slice the way the database returns a TEXT[]
+func pqArrayValue(values []string) (string, error) {
+	value, err := pq.Array(values).Value()
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDriverName is the driver InitDatabase opens: lib/pq with a client
+// span around every query and statement
+const tracedDriverName = "postgres-traced"
+
+func init() {
+	sql.Register(tracedDriverName, tracedDriver{})
+}
+
+// pqConn is the set of interfaces lib/pq connections implement
+type pqConn interface {
+	driver.Conn
+	driver.QueryerContext
+	driver.ExecerContext
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// tracedDriver opens lib/pq connections wrapped in tracedConn
+type tracedDriver struct{}
+
+func (tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := pq.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if pc, ok := conn.(pqConn); ok {
+		return tracedConn{pc}, nil
+	}
+	return conn, nil
+}
+
+// tracedConn starts a span for each query and statement it runs
+type tracedConn struct {
+	pqConn
+}
+
+func (c tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := startQuerySpan(ctx, "db.query", query)
+	rows, err := c.pqConn.QueryContext(ctx, query, args)
+	tracing.End(span, err)
+	return rows, err
+}
+
+func (c tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := startQuerySpan(ctx, "db.exec", query)
+	result, err := c.pqConn.ExecContext(ctx, query, args)
+	tracing.End(span, err)
+	return result, err
+}
+
+// startQuerySpan starts a client span for a SQL statement. Model functions
+// don't take a context yet, so these spans usually start their own trace.
+func startQuerySpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.query.text", query),
+		))
+}
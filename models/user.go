@@ -11,39 +11,65 @@ type User struct {
 	Password  string    `json:"-" db:"password"` // "-" means this field won't be included in JSON
 	FirstName string    `json:"first_name" db:"first_name"`
 	LastName  string    `json:"last_name" db:"last_name"`
+	Role      string    `json:"role" db:"role"` // user, admin
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// User roles
+const (
+	RoleUser      = "user"
+	RoleAdmin     = "admin"
+	RoleOrganizer = "organizer" // runs events; may view group compatibility
+)
+
 // CreateUserRequest represents the request body for user registration
 type CreateUserRequest struct {
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required,min=6"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
+
+	// UseCookies asks for the tokens as HttpOnly cookies (browser clients; requires AUTH_COOKIES_ENABLED)
+	UseCookies bool `json:"use_cookies"`
 }
 
 // LoginRequest represents the request body for user login
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+
+	// UseCookies asks for the tokens as HttpOnly cookies (browser clients; requires AUTH_COOKIES_ENABLED)
+	UseCookies bool `json:"use_cookies"`
 }
 
 // AuthResponse represents the response for authentication endpoints
 type AuthResponse struct {
 	User         User   `json:"user"`
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`  // omitted when set as a cookie
+	RefreshToken string `json:"refresh_token,omitempty"` // omitted when set as a cookie
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
+
+	// CSRFToken must be sent in the X-CSRF-Token header on state-changing
+	// requests authenticated by cookie
+	CSRFToken string `json:"csrf_token,omitempty"`
 }
 
-// RefreshTokenRequest represents the request body for token refresh
+// RefreshTokenRequest represents the request body for token refresh. Clients
+// using cookie auth leave it out and send the refresh token cookie instead.
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 // ProfileResponse represents the response for get profile endpoint
 type ProfileResponse struct {
 	User User `json:"user"`
 }
+
+// UserExists checks whether a user account exists
+func UserExists(id string) (bool, error) {
+	var exists bool
+	err := queryRowRead(`SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, []interface{}{id}, &exists)
+	return exists, err
+}
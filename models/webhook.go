@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook event types
+const (
+	WebhookEventCompanyCreated    = "company_created"
+	WebhookEventInvestmentCreated = "investment_created"
+	WebhookEventMatchCreated      = "match_created"
+)
+
+// Webhook represents an external subscriber to platform events
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"` // only returned when the webhook is created
+	CreatedBy string    `json:"created_by"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhookRequest represents the request body for registering a webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=company_created investment_created match_created"`
+}
+
+// WebhookDeadLetter records a delivery that permanently failed
+type WebhookDeadLetter struct {
+	ID         string          `json:"id"`
+	WebhookID  string          `json:"webhook_id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	StatusCode int             `json:"status_code,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// CreateWebhook registers a new webhook
+func CreateWebhook(webhook *Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, events, secret, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, is_active, created_at
+	`
+
+	return DB.QueryRow(query, webhook.URL, pq.Array(webhook.Events), webhook.Secret, webhook.CreatedBy).
+		Scan(&webhook.ID, &webhook.IsActive, &webhook.CreatedAt)
+}
+
+// ListWebhooks lists all registered webhooks without their secrets
+func ListWebhooks() ([]*Webhook, error) {
+	query := `
+		SELECT id, url, events, created_by, is_active, created_at
+		FROM webhooks ORDER BY created_at DESC
+	`
+
+	rows, err := queryRead(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+	for rows.Next() {
+		var webhook Webhook
+		var createdBy sql.NullString // cleared when the admin who created it is deleted
+		if err := rows.Scan(&webhook.ID, &webhook.URL, pq.Array(&webhook.Events),
+			&createdBy, &webhook.IsActive, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhook.CreatedBy = createdBy.String
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// GetWebhooksForEvent returns the active webhooks subscribed to an event, including their secrets
+func GetWebhooksForEvent(eventType string) ([]*Webhook, error) {
+	query := `
+		SELECT id, url, events, secret, created_by, is_active, created_at
+		FROM webhooks WHERE is_active = true AND $1 = ANY(events)
+	`
+
+	rows, err := queryRead(query, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*Webhook
+	for rows.Next() {
+		var webhook Webhook
+		var createdBy sql.NullString
+		if err := rows.Scan(&webhook.ID, &webhook.URL, pq.Array(&webhook.Events), &webhook.Secret,
+			&createdBy, &webhook.IsActive, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhook.CreatedBy = createdBy.String
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook removes a webhook, returning sql.ErrNoRows if it doesn't exist
+func DeleteWebhook(id string) error {
+	var deletedID string
+	return DB.QueryRow(`DELETE FROM webhooks WHERE id = $1 RETURNING id`, id).Scan(&deletedID)
+}
+
+// RecordWebhookDeadLetter stores a delivery that could not be completed
+func RecordWebhookDeadLetter(deadLetter *WebhookDeadLetter) error {
+	query := `
+		INSERT INTO webhook_dead_letters (webhook_id, event_type, payload, attempts, last_error, status_code)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	return DB.QueryRow(query, deadLetter.WebhookID, deadLetter.EventType, []byte(deadLetter.Payload),
+		deadLetter.Attempts, deadLetter.LastError, deadLetter.StatusCode).
+		Scan(&deadLetter.ID, &deadLetter.CreatedAt)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWebhooksOutliveTheirCreator(t *testing.T) {
+	mock := newTestDB(t)
+	now := time.Now()
+
+	// created_by is set to NULL when the admin who created the webhook is deleted
+	mock.ExpectQuery(`SELECT id, url, events, created_by, is_active, created_at\s+FROM webhooks`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "created_by", "is_active", "created_at"}).
+			AddRow("w1", "https://example.com/hook", `{"company_created"}`, nil, true, now))
+	webhooks, err := ListWebhooks()
+	if err != nil {
+		t.Fatalf("ListWebhooks: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].ID != "w1" || webhooks[0].CreatedBy != "" {
+		t.Errorf("webhooks = %+v, want w1 without a creator", webhooks)
+	}
+
+	mock.ExpectQuery(`SELECT id, url, events, secret, created_by, is_active, created_at\s+FROM webhooks WHERE is_active = true`).
+		WithArgs(WebhookEventCompanyCreated).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "secret", "created_by", "is_active", "created_at"}).
+			AddRow("w1", "https://example.com/hook", `{"company_created"}`, "s3cret", nil, true, now))
+	subscribed, err := GetWebhooksForEvent(WebhookEventCompanyCreated)
+	if err != nil {
+		t.Fatalf("GetWebhooksForEvent: %v", err)
+	}
+	if len(subscribed) != 1 || subscribed[0].Secret != "s3cret" {
+		t.Errorf("subscribed = %+v, want w1 with its secret", subscribed)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// SetupAuditRoutes sets up the audit log routes (admin only)
+func SetupAuditRoutes(router *gin.Engine, auditHandler *handlers.AuditHandler) {
+	audit := router.Group("/api/v1/admin/audit")
+	audit.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware(), utils.AdminMiddleware())
+	{
+		audit.GET("", auditHandler.GetAuditLog)
+	}
+}
@@ -9,22 +9,29 @@ import (
 )
 
 // SetupAuthRoutes sets up authentication routes
-func SetupAuthRoutes(router *gin.Engine, db *sql.DB) {
-	authHandler := handlers.NewAuthHandler(db)
+func SetupAuthRoutes(router *gin.Engine, db *sql.DB, publisher *utils.AsyncPublisher) {
+	authHandler := handlers.NewAuthHandler(db, publisher)
 
 	// Public routes (no authentication required)
 	auth := router.Group("/auth")
+	auth.Use(utils.DatabaseAvailableMiddleware())
 	{
 		auth.POST("/register", authHandler.Register)
 		auth.POST("/login", authHandler.Login)
 		auth.POST("/refresh", authHandler.RefreshToken)
+		auth.GET("/validate", authHandler.ValidateToken)
 	}
 
+	// Public keys for verifying access tokens signed with RS256
+	router.GET("/.well-known/jwks.json", authHandler.GetJWKS)
+
 	// Protected routes (authentication required)
 	protected := router.Group("/auth")
-	protected.Use(utils.AuthMiddleware())
+	protected.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware())
 	{
 		protected.POST("/logout", authHandler.Logout)
 		protected.GET("/profile", authHandler.GetProfile)
+		protected.GET("/sessions", authHandler.ListSessions)
+		protected.DELETE("/sessions/:id", authHandler.RevokeSession)
 	}
 } 
\ No newline at end of file
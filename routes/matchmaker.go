@@ -4,23 +4,57 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/utils"
 )
 
 // SetupMatchmakerRoutes sets up the matchmaker routes
 func SetupMatchmakerRoutes(router *gin.Engine, matchmakerHandler *handlers.MatchmakerHandler) {
 	// Matchmaker API group
 	matchmaker := router.Group("/api/v1/matchmaker")
+	matchmaker.Use(utils.OptionalAuthMiddleware())
 	{
 		// User profile management
-		matchmaker.POST("/profiles", matchmakerHandler.CreateUserProfile)
+		matchmaker.POST("/profiles", utils.AuthMiddleware(), matchmakerHandler.CreateUserProfile)
+		matchmaker.POST("/preview", utils.AuthMiddleware(), matchmakerHandler.PreviewMatches)
 		matchmaker.GET("/profiles/:user_id", matchmakerHandler.GetUserProfile)
+		matchmaker.GET("/profiles/:user_id/completeness", utils.AuthMiddleware(), matchmakerHandler.GetProfileCompleteness)
+		matchmaker.GET("/profiles/:user_id/viewers", utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware(), matchmakerHandler.GetProfileViewers)
+		matchmaker.PUT("/profiles/:user_id/searchable", utils.AuthMiddleware(), matchmakerHandler.SetProfileSearchable)
+		matchmaker.PUT("/profiles/:user_id/pause", utils.AuthMiddleware(), matchmakerHandler.PauseMatching)
+		matchmaker.DELETE("/profiles/:user_id/pause", utils.AuthMiddleware(), matchmakerHandler.ResumeMatching)
+		matchmaker.PUT("/profiles/:user_id/boost", utils.AuthMiddleware(), utils.AdminMiddleware(), matchmakerHandler.SetProfileBoost)
 
 		// Match management
-		matchmaker.GET("/matches/:user_id", matchmakerHandler.GetMatches)
-		matchmaker.GET("/matches/details/:match_id", matchmakerHandler.GetMatchDetails)
+		matchmaker.GET("/matches/:user_id", utils.AuthMiddleware(), matchmakerHandler.GetMatches)
+		matchmaker.GET("/matches/:user_id/export", utils.AuthMiddleware(), matchmakerHandler.ExportMatches)
+		matchmaker.POST("/matches/rescore/:user_id", utils.AuthMiddleware(), matchmakerHandler.RescoreMatches)
+		matchmaker.GET("/matches/details/:match_id", utils.AuthMiddleware(), matchmakerHandler.GetMatchDetails)
+		matchmaker.PUT("/matches/status/bulk", utils.AuthMiddleware(), matchmakerHandler.BulkUpdateMatchStatus)
 		matchmaker.PUT("/matches/:match_id/status", matchmakerHandler.UpdateMatchStatus)
+		matchmaker.POST("/matches/:match_id/undo", matchmakerHandler.UndoMatchStatus)
+		matchmaker.GET("/connections/:user_id", utils.AuthMiddleware(), matchmakerHandler.GetConnections)
+		matchmaker.GET("/network/:user_id", utils.AuthMiddleware(), matchmakerHandler.GetNetwork)
 
 		// Search and discovery
-		matchmaker.POST("/search", matchmakerHandler.SearchMatches)
+		matchmaker.POST("/search", utils.AuthMiddleware(), matchmakerHandler.SearchMatches)
+		matchmaker.GET("/recommendations/:user_id", utils.AuthMiddleware(), matchmakerHandler.GetRecommendations)
+
+		// Group compatibility for event organizers
+		matchmaker.POST("/matrix", utils.AuthMiddleware(), utils.OrganizerMiddleware(), matchmakerHandler.GetCompatibilityMatrix)
+
+		// Match quality diagnostics (admin only)
+		matchmaker.GET("/diagnostics/:user_id", utils.AuthMiddleware(), utils.AdminMiddleware(), matchmakerHandler.GetMatchDiagnostics)
+
+		// Aggregate matchmaking stats (admin only)
+		matchmaker.GET("/stats", utils.AuthMiddleware(), utils.AdminMiddleware(), matchmakerHandler.GetMatchStats)
+	}
+
+	// Global match rebuild (admin only)
+	admin := router.Group("/api/v1/admin/matchmaker")
+	admin.Use(utils.AuthMiddleware(), utils.AdminMiddleware())
+	{
+		admin.POST("/rebuild", matchmakerHandler.StartMatchRebuild)
+		admin.GET("/rebuild", matchmakerHandler.GetMatchRebuild)
+		admin.DELETE("/rebuild", matchmakerHandler.CancelMatchRebuild)
 	}
 }
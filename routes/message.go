@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// SetupMessageRoutes sets up the chat message history routes
+func SetupMessageRoutes(router *gin.Engine, messageHandler *handlers.MessageHandler) {
+	messages := router.Group("/api/v1/messages")
+	messages.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware())
+	{
+		messages.GET("/search", messageHandler.SearchMessages)
+		messages.GET("/notification-preferences", messageHandler.GetNotificationPreferences)
+		messages.PUT("/notification-preferences", messageHandler.UpdateNotificationPreferences)
+		messages.PUT("/conversations/:peer_id/retention", messageHandler.SetConversationRetention)
+		messages.DELETE("/conversations/:peer_id/retention", messageHandler.ClearConversationRetention)
+		messages.GET("/:id", messageHandler.GetMessage)
+	}
+}
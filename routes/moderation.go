@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// SetupModerationRoutes sets up content reporting and moderation routes
+func SetupModerationRoutes(router *gin.Engine, moderationHandler *handlers.ModerationHandler) {
+	// Any authenticated user can report content
+	reports := router.Group("/api/v1/reports")
+	reports.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware())
+	{
+		reports.POST("", moderationHandler.CreateReport)
+	}
+
+	// Report review (admin only)
+	admin := router.Group("/api/v1/admin/reports")
+	admin.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware(), utils.AdminMiddleware())
+	{
+		admin.GET("", moderationHandler.ListReports)
+		admin.PUT("/:id/status", moderationHandler.UpdateReportStatus)
+	}
+}
@@ -11,18 +11,51 @@ import (
 func SetupShowcaseRoutes(router *gin.Engine, showcaseHandler *handlers.ShowcaseHandler) {
 	// Showcase API group with authentication middleware
 	showcase := router.Group("/api/v1/showcase")
-	showcase.Use(utils.AuthMiddleware())
+	showcase.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware())
 	{
 		// Company management (admin/investor only)
 		showcase.POST("/companies", showcaseHandler.CreateCompany)
+		showcase.POST("/companies/import", utils.AdminMiddleware(), showcaseHandler.ImportCompanies)
+		showcase.POST("/companies/batch", showcaseHandler.GetCompaniesBatch)
 		showcase.GET("/companies/:id", showcaseHandler.GetCompany)
+		showcase.GET("/companies/slug/:slug", showcaseHandler.GetCompanyBySlug)
 		showcase.PUT("/companies/:id", showcaseHandler.UpdateCompany)
 		showcase.GET("/companies", showcaseHandler.SearchCompanies)
+		showcase.GET("/facets", showcaseHandler.GetCompanyFacets)
+		showcase.GET("/companies/:id/activity", showcaseHandler.GetCompanyActivity)
+		showcase.PUT("/companies/:id/owner", showcaseHandler.TransferCompanyOwnership)
+		showcase.POST("/companies/:id/merge", utils.AdminMiddleware(), showcaseHandler.MergeCompanies)
+		showcase.POST("/companies/:id/follow", showcaseHandler.FollowCompany)
+		showcase.DELETE("/companies/:id/follow", showcaseHandler.UnfollowCompany)
+		showcase.GET("/companies/:id/followers", showcaseHandler.GetCompanyFollowers)
+
+		// Data room access to company financials (owner or admin)
+		showcase.GET("/companies/:id/access-grants", showcaseHandler.GetDataRoomAccess)
+		showcase.POST("/companies/:id/access-grants", showcaseHandler.GrantDataRoomAccess)
+		showcase.DELETE("/companies/:id/access-grants/:investor_id", showcaseHandler.RevokeDataRoomAccess)
+
+		// Financial history (owner, admin, or data room access)
+		showcase.GET("/companies/:id/financials/history", showcaseHandler.GetCompanyFinancialHistory)
 
 		// Investment management (investor only)
 		showcase.POST("/investments", showcaseHandler.CreateInvestment)
-		showcase.GET("/companies/:company_id/investments", showcaseHandler.GetInvestments)
+		showcase.GET("/investments", showcaseHandler.ListInvestments)
+		showcase.PUT("/investments/:id/status", showcaseHandler.UpdateInvestmentStatus)
+		showcase.GET("/investments/:id/receipt", showcaseHandler.GetInvestmentReceipt)
+		showcase.GET("/companies/:id/investments", showcaseHandler.GetInvestments)
 		showcase.GET("/investments/my", showcaseHandler.GetUserInvestments)
+		showcase.GET("/portfolio", showcaseHandler.GetPortfolio)
+
+		// Non-binding investment interest
+		showcase.POST("/companies/:id/interest", showcaseHandler.ExpressInvestmentInterest)
+		showcase.GET("/interests/sent", showcaseHandler.GetSentInvestmentInterests)
+		showcase.GET("/interests/received", showcaseHandler.GetReceivedInvestmentInterests)
+
+		// Introductions between investors and company owners
+		showcase.POST("/companies/:id/intro-requests", showcaseHandler.CreateIntroRequest)
+		showcase.PUT("/intro-requests/:id", showcaseHandler.RespondToIntroRequest)
+		showcase.GET("/intro-requests/sent", showcaseHandler.GetSentIntroRequests)
+		showcase.GET("/intro-requests/received", showcaseHandler.GetReceivedIntroRequests)
 
 		// Analytics tracking
 		showcase.POST("/analytics/events", showcaseHandler.TrackEvent)
@@ -30,9 +63,13 @@ func SetupShowcaseRoutes(router *gin.Engine, showcaseHandler *handlers.ShowcaseH
 
 	// Public showcase routes (no authentication required)
 	publicShowcase := router.Group("/api/v1/showcase/public")
+	publicShowcase.Use(utils.DatabaseAvailableMiddleware(), utils.OptionalAuthMiddleware(), utils.AnonymousIDMiddleware())
 	{
 		// Public company profiles
 		publicShowcase.GET("/companies", showcaseHandler.SearchCompanies)
+		publicShowcase.GET("/directory", showcaseHandler.GetCompanyDirectory)
+		publicShowcase.GET("/facets", showcaseHandler.GetCompanyFacets)
 		publicShowcase.GET("/companies/:id", showcaseHandler.GetCompany)
+		publicShowcase.GET("/companies/slug/:slug", showcaseHandler.GetCompanyBySlug)
 	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/handlers"
+	"github.com/connect-up/auth-service/utils"
+)
+
+// SetupWebhookRoutes sets up webhook subscription routes (admin only)
+func SetupWebhookRoutes(router *gin.Engine, webhookHandler *handlers.WebhookHandler) {
+	webhooks := router.Group("/api/v1/admin/webhooks")
+	webhooks.Use(utils.DatabaseAvailableMiddleware(), utils.AuthMiddleware(), utils.AdminMiddleware())
+	{
+		webhooks.POST("", webhookHandler.CreateWebhook)
+		webhooks.GET("", webhookHandler.ListWebhooks)
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+	}
+}
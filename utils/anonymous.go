@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AnonymousIDCookie holds the id that ties an anonymous visitor's requests together
+const AnonymousIDCookie = "connectup_anon_id"
+
+// anonymousIDMaxAge is how long a browser keeps its anonymous id
+const anonymousIDMaxAge = 365 * 24 * time.Hour
+
+// AnonymousIDMiddleware gives unauthenticated requests a stable anonymous id
+// for analytics, read from the AnonymousIDCookie or issued as a new one. It must
+// run after any auth middleware so signed-in users don't get a cookie.
+func AnonymousIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := c.Get("user_id"); exists {
+			c.Next()
+			return
+		}
+
+		anonID, err := c.Cookie(AnonymousIDCookie)
+		if _, parseErr := uuid.Parse(anonID); err != nil || parseErr != nil {
+			anonID = uuid.New().String()
+			http.SetCookie(c.Writer, &http.Cookie{
+				Name:     AnonymousIDCookie,
+				Value:    anonID,
+				Path:     "/",
+				MaxAge:   int(anonymousIDMaxAge / time.Second),
+				HttpOnly: true,
+				Secure:   c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https",
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		c.Set("anon_id", anonID)
+		c.Next()
+	}
+}
+
+// AnonymousID returns the request's anonymous id, or "" for signed-in users
+// and routes without AnonymousIDMiddleware
+func AnonymousID(c *gin.Context) string {
+	return c.GetString("anon_id")
+}
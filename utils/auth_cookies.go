@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie names and the CSRF header used by browser clients that opt in to
+// cookie-based auth
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+// accessTokenTTL matches the lifetime GenerateAccessToken stamps on tokens
+const accessTokenTTL = 15 * time.Minute
+
+// refreshCookiePath limits the refresh token cookie to the auth endpoints
+const refreshCookiePath = "/auth"
+
+// AuthCookieConfig controls how auth tokens are set as cookies
+type AuthCookieConfig struct {
+	Enabled     bool          // clients may ask for cookies on login, register and refresh
+	AccessToken bool          // also set the access token as a cookie, not just the refresh token
+	Secure      bool          // only send cookies over HTTPS
+	SameSite    http.SameSite // cross-site request policy
+	Domain      string        // cookie domain; empty means the request host
+}
+
+// LoadAuthCookieConfig reads the auth cookie settings from the environment
+func LoadAuthCookieConfig() AuthCookieConfig {
+	sameSite := http.SameSiteStrictMode
+	switch strings.ToLower(GetEnv("AUTH_COOKIE_SAMESITE", "strict")) {
+	case "lax":
+		sameSite = http.SameSiteLaxMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	return AuthCookieConfig{
+		Enabled:     GetEnvBool("AUTH_COOKIES_ENABLED", false),
+		AccessToken: GetEnvBool("AUTH_COOKIE_ACCESS_TOKEN", true),
+		Secure:      GetEnvBool("AUTH_COOKIE_SECURE", true),
+		SameSite:    sameSite,
+		Domain:      GetEnv("AUTH_COOKIE_DOMAIN", ""),
+	}
+}
+
+var authCookies AuthCookieConfig
+
+// SetAuthCookieConfig replaces the auth cookie settings; InitJWT loads them from the environment
+func SetAuthCookieConfig(config AuthCookieConfig) {
+	authCookies = config
+}
+
+// AuthCookies returns the configured auth cookie settings
+func AuthCookies() AuthCookieConfig {
+	return authCookies
+}
+
+// CSRFToken returns the CSRF token for a session. It is derived from the
+// session id, so it needs no storage and can't be reused across sessions.
+func CSRFToken(sessionID string) string {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("csrf:" + sessionID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SetAuthCookies sets the refresh token, and the access token if configured,
+// as HttpOnly cookies, plus a CSRF cookie scripts can read to echo back in
+// the X-CSRF-Token header
+func SetAuthCookies(c *gin.Context, accessToken, refreshToken, sessionID string) {
+	config := AuthCookies()
+	c.SetSameSite(config.SameSite)
+
+	refreshMaxAge := int(RefreshTokenTTL / time.Second)
+	c.SetCookie(RefreshTokenCookie, refreshToken, refreshMaxAge, refreshCookiePath, config.Domain, config.Secure, true)
+	if config.AccessToken {
+		c.SetCookie(AccessTokenCookie, accessToken, int(accessTokenTTL/time.Second), "/", config.Domain, config.Secure, true)
+	}
+	c.SetCookie(CSRFCookie, CSRFToken(sessionID), refreshMaxAge, "/", config.Domain, config.Secure, false)
+}
+
+// ClearAuthCookies expires every auth cookie
+func ClearAuthCookies(c *gin.Context) {
+	config := AuthCookies()
+	c.SetSameSite(config.SameSite)
+	c.SetCookie(RefreshTokenCookie, "", -1, refreshCookiePath, config.Domain, config.Secure, true)
+	c.SetCookie(AccessTokenCookie, "", -1, "/", config.Domain, config.Secure, true)
+	c.SetCookie(CSRFCookie, "", -1, "/", config.Domain, config.Secure, false)
+}
+
+// CSRFSafe reports whether a cookie-authenticated request may go ahead:
+// reads always may, while state-changing requests and WebSocket upgrades
+// must carry the session's CSRF token. Browsers can't set headers on
+// WebSocket handshakes, so those may pass it as the csrf_token query parameter.
+func CSRFSafe(c *gin.Context, sessionID string) bool {
+	isUpgrade := strings.EqualFold(c.GetHeader("Upgrade"), "websocket")
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		if !isUpgrade {
+			return true
+		}
+	}
+
+	token := c.GetHeader(CSRFHeader)
+	if token == "" && isUpgrade {
+		token = c.Query("csrf_token")
+	}
+	return token != "" && hmac.Equal([]byte(token), []byte(CSRFToken(sessionID)))
+}
+
+// accessTokenFromCookie returns the access token cookie, or "" when cookie
+// auth is off or the cookie is missing
+func accessTokenFromCookie(c *gin.Context) string {
+	if !AuthCookies().Enabled {
+		return ""
+	}
+	token, _ := c.Cookie(AccessTokenCookie)
+	return token
+}
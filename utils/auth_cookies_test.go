@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSetAuthCookiesMarksTokensHttpOnly(t *testing.T) {
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret", "AUTH_COOKIES_ENABLED": "true"})
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	SetAuthCookies(c, "access", "refresh", "s1")
+
+	cookies := map[string]*http.Cookie{}
+	for _, cookie := range rec.Result().Cookies() {
+		cookies[cookie.Name] = cookie
+	}
+
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		cookie := cookies[name]
+		if cookie == nil {
+			t.Fatalf("no %s cookie set", name)
+		}
+		if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+			t.Errorf("%s cookie = %+v, want HttpOnly, Secure and SameSite=Strict", name, cookie)
+		}
+	}
+	if cookies[RefreshTokenCookie].Path != "/auth" {
+		t.Errorf("refresh cookie path = %q, want /auth", cookies[RefreshTokenCookie].Path)
+	}
+
+	csrf := cookies[CSRFCookie]
+	if csrf == nil || csrf.HttpOnly || csrf.Value != CSRFToken("s1") {
+		t.Errorf("csrf cookie = %+v, want a script-readable cookie holding the session's token", csrf)
+	}
+}
+
+func TestAuthMiddlewareAcceptsCookieWithCSRF(t *testing.T) {
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret", "AUTH_COOKIES_ENABLED": "true"})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	whoami := func(c *gin.Context) { c.String(http.StatusOK, c.GetString("user_id")) }
+	router.GET("/me", whoami)
+	router.POST("/me", whoami)
+
+	token, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		csrf   string
+		want   int
+	}{
+		{"read needs no csrf token", http.MethodGet, "", http.StatusOK},
+		{"write without csrf token", http.MethodPost, "", http.StatusForbidden},
+		{"write with another session's csrf token", http.MethodPost, CSRFToken("s2"), http.StatusForbidden},
+		{"write with the session's csrf token", http.MethodPost, CSRFToken("s1"), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/me", nil)
+			req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+			if tt.csrf != "" {
+				req.Header.Set(CSRFHeader, tt.csrf)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != "alice" {
+				t.Errorf("authenticated as %q, want alice", rec.Body)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareIgnoresCookieWhenDisabled(t *testing.T) {
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret", "AUTH_COOKIES_ENABLED": "false"})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware())
+	router.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 with cookie auth off", rec.Code)
+	}
+}
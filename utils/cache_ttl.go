@@ -0,0 +1,50 @@
+package utils
+
+import "time"
+
+// CacheTTLConfig holds how long each kind of Redis entry lives
+type CacheTTLConfig struct {
+	Company          time.Duration // cached company profiles
+	CompanyDirectory time.Duration // cached public directory pages
+	Profile          time.Duration // matchmaker profiles that aren't resubmitted
+	Match            time.Duration // stored matches
+	FXRate           time.Duration // cached exchange rates
+	Presence         time.Duration // how long a user stays online without a refresh
+}
+
+// DefaultCacheTTLConfig returns the TTLs used when nothing is configured
+func DefaultCacheTTLConfig() CacheTTLConfig {
+	return CacheTTLConfig{
+		Company:          time.Hour,
+		CompanyDirectory: 30 * time.Second,
+		Profile:          24 * time.Hour,
+		Match:            7 * 24 * time.Hour,
+		FXRate:           time.Hour,
+		Presence:         90 * time.Second,
+	}
+}
+
+// LoadCacheTTLConfig reads the cache TTLs from the environment
+func LoadCacheTTLConfig() CacheTTLConfig {
+	defaults := DefaultCacheTTLConfig()
+	return CacheTTLConfig{
+		Company:          GetEnvDuration("COMPANY_CACHE_TTL", defaults.Company),
+		CompanyDirectory: GetEnvDuration("COMPANY_DIRECTORY_CACHE_TTL", defaults.CompanyDirectory),
+		Profile:          GetEnvDuration("MATCH_PROFILE_TTL", defaults.Profile),
+		Match:            GetEnvDuration("MATCH_TTL", defaults.Match),
+		FXRate:           GetEnvDuration("FX_RATE_TTL", defaults.FXRate),
+		Presence:         GetEnvDuration("PRESENCE_TTL", defaults.Presence),
+	}
+}
+
+var cacheTTLs = DefaultCacheTTLConfig()
+
+// SetCacheTTLs replaces the cache TTLs; InitRedis loads them from the environment
+func SetCacheTTLs(config CacheTTLConfig) {
+	cacheTTLs = config
+}
+
+// CacheTTLs returns the configured cache TTLs
+func CacheTTLs() CacheTTLConfig {
+	return cacheTTLs
+}
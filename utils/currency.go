@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// ErrRateUnavailable is returned when no exchange rate is known for a currency pair
+var ErrRateUnavailable = errors.New("exchange rate unavailable")
+
+// FXRateProvider supplies exchange rates between currencies
+type FXRateProvider interface {
+	// Rate returns how many units of `to` one unit of `from` is worth
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// StaticRateProvider serves fixed rates, each expressed as the value of one
+// unit of a currency in the base currency
+type StaticRateProvider struct {
+	Base  string
+	Rates map[string]float64
+}
+
+// Rate returns the exchange rate between two currencies via the base currency
+func (p *StaticRateProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	fromRate, ok := p.baseRate(from)
+	if !ok {
+		return 0, ErrRateUnavailable
+	}
+	toRate, ok := p.baseRate(to)
+	if !ok || toRate == 0 {
+		return 0, ErrRateUnavailable
+	}
+	return fromRate / toRate, nil
+}
+
+func (p *StaticRateProvider) baseRate(currency string) (float64, bool) {
+	if currency == p.Base {
+		return 1, true
+	}
+	rate, ok := p.Rates[currency]
+	return rate, ok
+}
+
+var (
+	// BaseCurrency is the currency aggregates are normalized to
+	BaseCurrency = "USD"
+
+	fxProvider FXRateProvider = &StaticRateProvider{Base: "USD"}
+)
+
+// InitCurrency loads the base currency and static exchange rates from the environment.
+// FX_RATES is a comma-separated list of CURRENCY:rate pairs, e.g. "EUR:1.08,GBP:1.27",
+// where each rate is the value of one unit in the base currency.
+func InitCurrency() {
+	BaseCurrency = strings.ToUpper(GetEnv("BASE_CURRENCY", "USD"))
+
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(GetEnv("FX_RATES", ""), ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate <= 0 {
+			log.Printf("Ignoring invalid FX rate %q", pair)
+			continue
+		}
+		rates[strings.ToUpper(parts[0])] = rate
+	}
+
+	fxProvider = &StaticRateProvider{Base: BaseCurrency, Rates: rates}
+}
+
+// SetFXRateProvider replaces the exchange rate provider
+func SetFXRateProvider(provider FXRateProvider) {
+	fxProvider = provider
+}
+
+// Convert converts an amount between currencies, caching rates in Redis.
+// It returns ErrRateUnavailable when the pair can't be converted.
+func Convert(amount float64, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := getFXRate(context.Background(), from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate, nil
+}
+
+// getFXRate returns a cached exchange rate, falling back to the provider
+func getFXRate(ctx context.Context, from, to string) (float64, error) {
+	key := FXRateKey(from, to)
+
+	if RedisClient != nil {
+		if cached, err := RedisClient.Get(ctx, key).Float64(); err == nil {
+			return cached, nil
+		}
+	}
+
+	rate, err := fxProvider.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	if RedisClient != nil {
+		if err := RedisClient.Set(ctx, key, rate, CacheTTLs().FXRate).Err(); err != nil {
+			log.Printf("Failed to cache FX rate: %v", err)
+		}
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis points RedisClient at an in-memory Redis for the duration of
+// the test
+func newTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	previous := RedisClient
+	RedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		RedisClient.Close()
+		RedisClient = previous
+	})
+	return server
+}
+
+// countingRateProvider serves fixed rates and counts how often it is asked
+type countingRateProvider struct {
+	StaticRateProvider
+	calls int
+}
+
+func (p *countingRateProvider) Rate(ctx context.Context, from, to string) (float64, error) {
+	p.calls++
+	return p.StaticRateProvider.Rate(ctx, from, to)
+}
+
+// useRateProvider swaps in provider for the duration of the test
+func useRateProvider(t *testing.T, provider FXRateProvider) {
+	t.Helper()
+	previous := fxProvider
+	SetFXRateProvider(provider)
+	t.Cleanup(func() { SetFXRateProvider(previous) })
+}
+
+func TestConvertWithFixedRates(t *testing.T) {
+	newTestRedis(t)
+	provider := &countingRateProvider{StaticRateProvider: StaticRateProvider{
+		Base:  "USD",
+		Rates: map[string]float64{"EUR": 1.25, "GBP": 1.5},
+	}}
+	useRateProvider(t, provider)
+
+	tests := []struct {
+		amount   float64
+		from, to string
+		want     float64
+	}{
+		{100, "EUR", "USD", 125},
+		{100, "usd", "eur", 80},
+		{100, "GBP", "EUR", 120},
+		{100, "JPY", "JPY", 100},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.amount, tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("Convert(%v, %s, %s): %v", tt.amount, tt.from, tt.to, err)
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// Rates are cached, so converting a pair again doesn't ask the provider
+	calls := provider.calls
+	if _, err := Convert(10, "EUR", "USD"); err != nil {
+		t.Fatalf("Convert: %v", err)
+	}
+	if provider.calls != calls {
+		t.Errorf("provider asked %d more times for a cached rate", provider.calls-calls)
+	}
+
+	if _, err := Convert(10, "JPY", "USD"); !errors.Is(err, ErrRateUnavailable) {
+		t.Errorf("Convert with an unknown currency = %v, want ErrRateUnavailable", err)
+	}
+}
+
+func TestInitCurrencyReadsEnvironment(t *testing.T) {
+	previousBase, previousProvider := BaseCurrency, fxProvider
+	t.Cleanup(func() { BaseCurrency, fxProvider = previousBase, previousProvider })
+
+	t.Setenv("BASE_CURRENCY", "eur")
+	t.Setenv("FX_RATES", "USD:0.8, GBP:1.2, bad, JPY:-1")
+	InitCurrency()
+
+	if BaseCurrency != "EUR" {
+		t.Errorf("base currency = %q, want EUR", BaseCurrency)
+	}
+	provider := fxProvider.(*StaticRateProvider)
+	if len(provider.Rates) != 2 || provider.Rates["USD"] != 0.8 || provider.Rates["GBP"] != 1.2 {
+		t.Errorf("rates = %v, want USD and GBP only", provider.Rates)
+	}
+}
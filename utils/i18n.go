@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/connect-up/auth-service/internal/i18n"
+)
+
+// Locale returns the request's locale, negotiated from Accept-Language on
+// first use and cached on the context
+func Locale(c *gin.Context) string {
+	if locale := c.GetString("locale"); locale != "" {
+		return locale
+	}
+
+	locale := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	c.Set("locale", locale)
+	return locale
+}
+
+// T renders a message catalog entry in the request's locale
+func T(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.T(Locale(c), key, args...)
+}
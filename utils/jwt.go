@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"time"
@@ -10,70 +12,99 @@ import (
 
 var jwtSecret []byte
 
-// InitJWT initializes JWT secret from environment
-func InitJWT() {
+// jwtIssuer and jwtAudience are stamped on issued tokens and required on
+// validated ones, so tokens minted by another service sharing the secret are
+// rejected. An empty audience is neither set nor checked.
+var (
+	jwtIssuer   = "auth-service"
+	jwtAudience string
+)
+
+// RefreshTokenTTL is how long refresh tokens (and their sessions) remain valid
+const RefreshTokenTTL = 7 * 24 * time.Hour
+
+// InitJWT initializes the JWT secret and signing keys from environment. The
+// secret is loaded even under RS256, as it also derives CSRF tokens.
+func InitJWT() error {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		secret = "your-secret-key-change-in-production"
 	}
 	jwtSecret = []byte(secret)
+	jwtIssuer = GetEnv("JWT_ISSUER", "auth-service")
+	jwtAudience = GetEnv("JWT_AUDIENCE", "")
+	SetAuthCookieConfig(LoadAuthCookieConfig())
+
+	loaded, err := loadJWTSigner()
+	if err != nil {
+		return err
+	}
+	signer = loaded
+	return nil
+}
+
+// registeredClaims returns the standard claims for a token issued to userID
+func registeredClaims(userID string, expirationTime time.Time) jwt.RegisteredClaims {
+	claims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    jwtIssuer,
+		Subject:   userID,
+	}
+	if jwtAudience != "" {
+		claims.Audience = jwt.ClaimStrings{jwtAudience}
+	}
+	return claims
 }
 
 // Claims represents the JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateAccessToken generates a new access token
-func GenerateAccessToken(userID, email string) (string, error) {
+func GenerateAccessToken(userID, email, role, sessionID string) (string, error) {
 	expirationTime := time.Now().Add(15 * time.Minute) // 15 minutes
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "auth-service",
-			Subject:   userID,
-		},
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: registeredClaims(userID, expirationTime),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signer.sign(claims)
 }
 
 // GenerateRefreshToken generates a new refresh token
-func GenerateRefreshToken(userID, email string) (string, error) {
-	expirationTime := time.Now().Add(7 * 24 * time.Hour) // 7 days
+func GenerateRefreshToken(userID, email, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(RefreshTokenTTL)
 
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "auth-service",
-			Subject:   userID,
-		},
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
+		RegisteredClaims: registeredClaims(userID, expirationTime),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return signer.sign(claims)
 }
 
-// ValidateToken validates and parses a JWT token
+// ValidateToken validates and parses a JWT token, including its issuer and,
+// when JWT_AUDIENCE is set, its audience
 func ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
+	options := []jwt.ParserOption{jwt.WithIssuer(jwtIssuer)}
+	if jwtAudience != "" {
+		options = append(options, jwt.WithAudience(jwtAudience))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, signer.verificationKey, options...)
 
 	if err != nil {
 		return nil, err
@@ -86,6 +117,12 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	return nil, fmt.Errorf("invalid token")
 }
 
+// HashToken returns a hex-encoded SHA-256 digest of a token for storage
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // GetTokenExpiration returns the expiration time of a token
 func GetTokenExpiration(tokenString string) (time.Time, error) {
 	claims, err := ValidateToken(tokenString)
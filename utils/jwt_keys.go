@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms (JWT_ALGORITHM)
+const (
+	JWTAlgorithmHS256 = "HS256" // shared secret; every verifier needs JWT_SECRET
+	JWTAlgorithmRS256 = "RS256" // private key signs, public key verifies
+)
+
+// jwtSigner holds the configured signing method and keys. For RS256 the
+// private key may be absent, in which case tokens can be verified but not issued.
+type jwtSigner struct {
+	method     jwt.SigningMethod
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	keyID      string
+}
+
+var signer = jwtSigner{method: jwt.SigningMethodHS256}
+
+// loadJWTSigner reads JWT_ALGORITHM and, for RS256, the PEM keys named by
+// JWT_PRIVATE_KEY_FILE and JWT_PUBLIC_KEY_FILE. The public key is derived
+// from the private key when only the latter is given.
+func loadJWTSigner() (jwtSigner, error) {
+	switch algorithm := strings.ToUpper(GetEnv("JWT_ALGORITHM", JWTAlgorithmHS256)); algorithm {
+	case JWTAlgorithmHS256:
+		return jwtSigner{method: jwt.SigningMethodHS256}, nil
+	case JWTAlgorithmRS256:
+		return loadRSASigner(os.Getenv("JWT_PRIVATE_KEY_FILE"), os.Getenv("JWT_PUBLIC_KEY_FILE"), os.Getenv("JWT_KEY_ID"))
+	default:
+		return jwtSigner{}, fmt.Errorf("unsupported JWT_ALGORITHM %q (use HS256 or RS256)", algorithm)
+	}
+}
+
+// loadRSASigner builds an RS256 signer from PEM key files
+func loadRSASigner(privateKeyFile, publicKeyFile, keyID string) (jwtSigner, error) {
+	var privateKey *rsa.PrivateKey
+	if privateKeyFile != "" {
+		data, err := os.ReadFile(privateKeyFile)
+		if err != nil {
+			return jwtSigner{}, fmt.Errorf("read JWT private key: %w", err)
+		}
+		if privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data); err != nil {
+			return jwtSigner{}, fmt.Errorf("parse JWT private key: %w", err)
+		}
+	}
+
+	var publicKey *rsa.PublicKey
+	if publicKeyFile != "" {
+		data, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			return jwtSigner{}, fmt.Errorf("read JWT public key: %w", err)
+		}
+		if publicKey, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+			return jwtSigner{}, fmt.Errorf("parse JWT public key: %w", err)
+		}
+	}
+
+	return newRSASigner(privateKey, publicKey, keyID)
+}
+
+// newRSASigner builds an RS256 signer; keyID defaults to a thumbprint of the public key
+func newRSASigner(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, keyID string) (jwtSigner, error) {
+	if publicKey == nil {
+		if privateKey == nil {
+			return jwtSigner{}, errors.New("RS256 needs JWT_PRIVATE_KEY_FILE or JWT_PUBLIC_KEY_FILE")
+		}
+		publicKey = &privateKey.PublicKey
+	}
+	if privateKey != nil && !privateKey.PublicKey.Equal(publicKey) {
+		return jwtSigner{}, errors.New("JWT public key does not match the private key")
+	}
+
+	if keyID == "" {
+		sum := sha256.Sum256(publicKey.N.Bytes())
+		keyID = base64.RawURLEncoding.EncodeToString(sum[:12])
+	}
+
+	return jwtSigner{
+		method:     jwt.SigningMethodRS256,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		keyID:      keyID,
+	}, nil
+}
+
+// sign signs claims with the configured algorithm, stamping the key id on RS256 tokens
+func (s jwtSigner) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.method != jwt.SigningMethodRS256 {
+		return token.SignedString(jwtSecret)
+	}
+
+	if s.privateKey == nil {
+		return "", errors.New("no JWT private key configured; this service can only verify tokens")
+	}
+	token.Header["kid"] = s.keyID
+	return token.SignedString(s.privateKey)
+}
+
+// verificationKey returns the key a token is checked against, rejecting
+// tokens signed with any algorithm other than the configured one
+func (s jwtSigner) verificationKey(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != s.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	if s.method == jwt.SigningMethodRS256 {
+		return s.publicKey, nil
+	}
+	return jwtSecret, nil
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS returns the public keys other services can verify access tokens
+// with. It is empty under HS256, whose secret must never be published.
+func JWKS() []JWK {
+	if signer.method != jwt.SigningMethodRS256 {
+		return []JWK{}
+	}
+
+	return []JWK{{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: JWTAlgorithmRS256,
+		KeyID:     signer.keyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(signer.publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(signer.publicKey.E)).Bytes()),
+	}}
+}
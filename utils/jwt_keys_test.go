@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeRSAKeys writes a fresh RSA key pair as PEM files, returning their paths
+func writeRSAKeys(t *testing.T) (privateKeyFile, publicKeyFile string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	dir := t.TempDir()
+	privateKeyFile, publicKeyFile = filepath.Join(dir, "jwt.key"), filepath.Join(dir, "jwt.pub")
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	if err := os.WriteFile(privateKeyFile, privatePEM, 0o600); err != nil {
+		t.Fatalf("write private key: %v", err)
+	}
+	if err := os.WriteFile(publicKeyFile, publicPEM, 0o644); err != nil {
+		t.Fatalf("write public key: %v", err)
+	}
+	return privateKeyFile, publicKeyFile
+}
+
+// jwkPublicKey rebuilds the RSA public key a JWK describes
+func jwkPublicKey(t *testing.T, key JWK) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString(key.Modulus)
+	if err != nil {
+		t.Fatalf("decode modulus: %v", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.Exponent)
+	if err != nil {
+		t.Fatalf("decode exponent: %v", err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+}
+
+func TestRS256TokensVerifyAgainstPublishedKey(t *testing.T) {
+	privateKeyFile, _ := writeRSAKeys(t)
+	initTestJWT(t, map[string]string{"JWT_ALGORITHM": "RS256", "JWT_PRIVATE_KEY_FILE": privateKeyFile})
+
+	token, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	if claims, err := ValidateToken(token); err != nil || claims.UserID != "alice" {
+		t.Fatalf("ValidateToken = %+v, %v; want alice's claims", claims, err)
+	}
+
+	keys := JWKS()
+	if len(keys) != 1 || keys[0].Algorithm != JWTAlgorithmRS256 || keys[0].KeyID == "" {
+		t.Fatalf("JWKS = %+v, want one RS256 key with an id", keys)
+	}
+
+	// A downstream service holding only the published key can verify the token
+	parsed, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if token.Header["kid"] != keys[0].KeyID {
+			t.Errorf("token kid = %v, want %s", token.Header["kid"], keys[0].KeyID)
+		}
+		return jwkPublicKey(t, keys[0]), nil
+	}, jwt.WithValidMethods([]string{JWTAlgorithmRS256}))
+	if err != nil {
+		t.Fatalf("verify with the published key: %v", err)
+	}
+	if parsed.Claims.(*Claims).UserID != "alice" {
+		t.Errorf("verified claims = %+v, want alice's", parsed.Claims)
+	}
+
+	// An HS256 token signed with the shared secret is refused
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID:           "mallory",
+		RegisteredClaims: registeredClaims("mallory", parsed.Claims.(*Claims).ExpiresAt.Time),
+	}).SignedString(jwtSecret)
+	if err != nil {
+		t.Fatalf("sign HS256 token: %v", err)
+	}
+	if _, err := ValidateToken(forged); err == nil {
+		t.Error("ValidateToken accepted an HS256 token under RS256")
+	}
+}
+
+func TestRS256VerifyOnlyWithPublicKey(t *testing.T) {
+	privateKeyFile, publicKeyFile := writeRSAKeys(t)
+	initTestJWT(t, map[string]string{"JWT_ALGORITHM": "RS256", "JWT_PRIVATE_KEY_FILE": privateKeyFile})
+	token, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+
+	// A verifier configured with only the public key accepts the token but can't issue any
+	initTestJWT(t, map[string]string{"JWT_ALGORITHM": "RS256", "JWT_PRIVATE_KEY_FILE": "", "JWT_PUBLIC_KEY_FILE": publicKeyFile})
+	if _, err := ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken with the public key: %v", err)
+	}
+	if _, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1"); err == nil {
+		t.Error("GenerateAccessToken signed a token without a private key")
+	}
+}
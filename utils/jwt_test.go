@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// initTestJWT initializes JWT signing from env for the duration of the test,
+// restoring the default configuration afterwards
+func initTestJWT(t *testing.T, env map[string]string) {
+	t.Helper()
+	// Registered before Setenv so it runs after the environment is restored
+	t.Cleanup(func() { InitJWT() })
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	if err := InitJWT(); err != nil {
+		t.Fatalf("InitJWT: %v", err)
+	}
+}
+
+func TestValidateTokenChecksAudience(t *testing.T) {
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret", "JWT_AUDIENCE": "connectup-web"})
+
+	token, err := GenerateAccessToken("alice", "alice@example.com", "user", "s1")
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	claims, err := ValidateToken(token)
+	if err != nil {
+		t.Fatalf("ValidateToken with the right audience: %v", err)
+	}
+	if claims.UserID != "alice" {
+		t.Errorf("user id = %q, want alice", claims.UserID)
+	}
+
+	// The same secret, but a service expecting a different audience
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret", "JWT_AUDIENCE": "billing"})
+	if _, err := ValidateToken(token); err == nil {
+		t.Error("ValidateToken accepted a token for another audience")
+	}
+}
+
+func TestValidateTokenChecksIssuer(t *testing.T) {
+	initTestJWT(t, map[string]string{"JWT_SECRET": "test-secret"})
+
+	foreign := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID: "alice",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "another-service",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	token, err := foreign.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := ValidateToken(token); err == nil {
+		t.Fatal("ValidateToken accepted a token from another issuer")
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("AuthMiddleware status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/connect-up/auth-service/internal/tracing"
 	"github.com/connect-up/auth-service/models"
 	"github.com/segmentio/kafka-go"
 )
@@ -42,10 +43,13 @@ func (kp *KafkaProducer) PublishUserUpdated(ctx context.Context, userID string,
 		return fmt.Errorf("failed to marshal event: %v", err)
 	}
 
-	err = kp.writer.WriteMessages(ctx, kafka.Message{
+	msg := kafka.Message{
 		Key:   []byte(userID),
 		Value: data,
-	})
+	}
+	spanCtx, span := tracing.StartProduce(ctx, kp.writer.Topic, &msg)
+	err = kp.writer.WriteMessages(spanCtx, msg)
+	tracing.End(span, err)
 	if err != nil {
 		return fmt.Errorf("failed to publish event: %v", err)
 	}
@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/segmentio/kafka-go"
+)
+
+var errMessageDropped = errors.New("kafka publish buffer dropped the message")
+
+// MessageWriter writes messages to Kafka; *kafka.Writer satisfies it
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// AsyncPublisherConfig controls buffering and batching for an AsyncPublisher
+type AsyncPublisherConfig struct {
+	BufferSize    int           // messages queued before the full-buffer policy applies
+	BatchSize     int           // messages written per WriteMessages call
+	FlushInterval time.Duration // longest a partial batch waits before being written
+	BlockOnFull   bool          // block publishers when the buffer is full instead of dropping
+}
+
+// LoadAsyncPublisherConfig reads the publisher configuration from the environment
+func LoadAsyncPublisherConfig() AsyncPublisherConfig {
+	return AsyncPublisherConfig{
+		BufferSize:    GetEnvInt("KAFKA_PUBLISH_BUFFER_SIZE", 1000),
+		BatchSize:     GetEnvInt("KAFKA_PUBLISH_BATCH_SIZE", 100),
+		FlushInterval: GetEnvDuration("KAFKA_PUBLISH_FLUSH_INTERVAL", 100*time.Millisecond),
+		BlockOnFull:   GetEnvBool("KAFKA_PUBLISH_BLOCK_ON_FULL", false),
+	}
+}
+
+// PublisherMetrics counts messages by outcome
+type PublisherMetrics struct {
+	Enqueued uint64 `json:"enqueued"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"` // rejected because the buffer was full or the publisher closed
+	Failed   uint64 `json:"failed"`  // lost because a batch write returned an error
+}
+
+// AsyncPublisher queues Kafka messages in a bounded buffer and writes them in
+// batches from a single worker, so callers never wait on the broker
+type AsyncPublisher struct {
+	writer MessageWriter
+	config AsyncPublisherConfig
+	queue  chan kafka.Message
+	flush  chan chan struct{}
+	done   chan struct{}
+
+	closeOnce sync.Once
+	stopped   chan struct{}
+
+	enqueued atomic.Uint64
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// NewAsyncPublisher creates a publisher and starts its write worker
+func NewAsyncPublisher(writer MessageWriter, config AsyncPublisherConfig) *AsyncPublisher {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 100 * time.Millisecond
+	}
+
+	p := &AsyncPublisher{
+		writer:  writer,
+		config:  config,
+		queue:   make(chan kafka.Message, config.BufferSize),
+		flush:   make(chan chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go p.run()
+	return p
+}
+
+// Publish queues a message for writing. It reports false if the message was
+// dropped because the buffer is full (when not blocking) or the publisher is closed.
+func (p *AsyncPublisher) Publish(msg kafka.Message) bool {
+	if p == nil {
+		return false
+	}
+
+	select {
+	case <-p.done:
+		p.dropped.Add(1)
+		return false
+	default:
+	}
+
+	if p.config.BlockOnFull {
+		select {
+		case p.queue <- msg:
+			p.enqueued.Add(1)
+			return true
+		case <-p.done:
+			p.dropped.Add(1)
+			return false
+		}
+	}
+
+	select {
+	case p.queue <- msg:
+		p.enqueued.Add(1)
+		return true
+	default:
+		if p.dropped.Add(1)%100 == 1 {
+			log.Printf("Kafka publish buffer full, dropping messages (%d dropped so far)", p.dropped.Load())
+		}
+		return false
+	}
+}
+
+// PublishContext queues msg like Publish, first recording a producer span
+// under ctx and carrying its trace context in the message headers
+func (p *AsyncPublisher) PublishContext(ctx context.Context, msg kafka.Message) bool {
+	_, span := tracing.StartProduce(ctx, msg.Topic, &msg)
+	queued := p.Publish(msg)
+	if !queued {
+		tracing.End(span, errMessageDropped)
+		return false
+	}
+	tracing.End(span, nil)
+	return true
+}
+
+// Flush waits until every message queued before the call has been written or failed
+func (p *AsyncPublisher) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flush <- ack:
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes queued messages and stops the worker. Later publishes are dropped.
+func (p *AsyncPublisher) Close(ctx context.Context) error {
+	err := p.Flush(ctx)
+	p.closeOnce.Do(func() { close(p.done) })
+
+	select {
+	case <-p.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return err
+}
+
+// Metrics returns a snapshot of the publisher's counters
+func (p *AsyncPublisher) Metrics() PublisherMetrics {
+	return PublisherMetrics{
+		Enqueued: p.enqueued.Load(),
+		Written:  p.written.Load(),
+		Dropped:  p.dropped.Load(),
+		Failed:   p.failed.Load(),
+	}
+}
+
+// run batches queued messages, writing when a batch fills, the flush interval
+// passes, or a flush is requested
+func (p *AsyncPublisher) run() {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]kafka.Message, 0, p.config.BatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := p.writer.WriteMessages(context.Background(), batch...); err != nil {
+			p.failed.Add(uint64(len(batch)))
+			log.Printf("Failed to write %d Kafka messages: %v", len(batch), err)
+		} else {
+			p.written.Add(uint64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	drain := func() {
+		for {
+			select {
+			case msg := <-p.queue:
+				batch = append(batch, msg)
+				if len(batch) >= p.config.BatchSize {
+					write()
+				}
+			default:
+				write()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case msg := <-p.queue:
+			batch = append(batch, msg)
+			if len(batch) >= p.config.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-p.flush:
+			drain()
+			close(ack)
+		case <-p.done:
+			drain()
+			return
+		}
+	}
+}
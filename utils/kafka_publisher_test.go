@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeWriter records written messages and fails while err is set
+type fakeWriter struct {
+	mu       sync.Mutex
+	err      error
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeWriter) setErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+}
+
+func (w *fakeWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.messages)
+}
+
+func TestAsyncPublisherWritesQueuedMessages(t *testing.T) {
+	writer := &fakeWriter{}
+	publisher := NewAsyncPublisher(writer, AsyncPublisherConfig{BufferSize: 100, BatchSize: 4, FlushInterval: time.Hour})
+	defer publisher.Close(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !publisher.Publish(kafka.Message{Topic: "analytics", Value: []byte("event")}) {
+				t.Error("Publish dropped a message with room in the buffer")
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := publisher.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if got := writer.count(); got != 10 {
+		t.Errorf("writer got %d messages, want 10", got)
+	}
+	if metrics := publisher.Metrics(); metrics.Enqueued != 10 || metrics.Written != 10 || metrics.Failed != 0 {
+		t.Errorf("metrics = %+v, want 10 enqueued and written", metrics)
+	}
+}
+
+func TestAsyncPublisherReportsFailedWrites(t *testing.T) {
+	writer := &fakeWriter{err: errors.New("broker unavailable")}
+	publisher := NewAsyncPublisher(writer, AsyncPublisherConfig{BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	defer publisher.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		publisher.Publish(kafka.Message{Topic: "analytics"})
+	}
+	if err := publisher.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if metrics := publisher.Metrics(); metrics.Failed != 3 || metrics.Written != 0 {
+		t.Fatalf("metrics = %+v, want 3 failed", metrics)
+	}
+
+	// Once the broker recovers, later messages are written
+	writer.setErr(nil)
+	publisher.Publish(kafka.Message{Topic: "analytics"})
+	if err := publisher.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if metrics := publisher.Metrics(); metrics.Failed != 3 || metrics.Written != 1 {
+		t.Errorf("metrics = %+v, want 3 failed and 1 written", metrics)
+	}
+}
+
+func TestAsyncPublisherDropsAfterClose(t *testing.T) {
+	publisher := NewAsyncPublisher(&fakeWriter{}, AsyncPublisherConfig{})
+	if err := publisher.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if publisher.Publish(kafka.Message{Topic: "analytics"}) {
+		t.Error("Publish accepted a message after Close")
+	}
+	if metrics := publisher.Metrics(); metrics.Dropped != 1 {
+		t.Errorf("dropped = %d, want 1", metrics.Dropped)
+	}
+}
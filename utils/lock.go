@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotHeld is returned when releasing or extending a lock this holder no longer owns
+var ErrLockNotHeld = errors.New("lock not held")
+
+// releaseLockScript deletes the lock only if it still holds our token, so an
+// expired lock re-acquired by another instance is never released by us
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// extendLockScript resets the lock TTL only if it still holds our token
+var extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Lock is a Redis lock held by this process
+type Lock struct {
+	key   string
+	token string
+	ttl   time.Duration
+}
+
+// AcquireLock tries to take the lock at key with SET NX and the given TTL.
+// It returns nil without an error when another holder already has the lock.
+func AcquireLock(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token := uuid.New().String()
+
+	ok, err := RedisClient.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return &Lock{key: key, token: token, ttl: ttl}, nil
+}
+
+// Extend resets the lock's TTL
+func (l *Lock) Extend(ctx context.Context) error {
+	res, err := extendLockScript.Run(ctx, RedisClient, []string{l.key}, l.token, l.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release frees the lock if it is still held
+func (l *Lock) Release(ctx context.Context) error {
+	res, err := releaseLockScript.Run(ctx, RedisClient, []string{l.key}, l.token).Int()
+	if err != nil {
+		return err
+	}
+	if res == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// KeepAlive extends the lock every half TTL until ctx is done, so long runs
+// don't lose the lock to expiry
+func (l *Lock) KeepAlive(ctx context.Context) {
+	ticker := time.NewTicker(l.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Extend(ctx); err != nil {
+				return
+			}
+		}
+	}
+}
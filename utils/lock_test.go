@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLockAdmitsOneHolder(t *testing.T) {
+	newTestRedis(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	locks := make(chan *Lock, 20)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lock, err := AcquireLock(ctx, MatchmakerLockKey("alice"), time.Minute)
+			if err != nil {
+				t.Errorf("AcquireLock: %v", err)
+				return
+			}
+			if lock != nil {
+				locks <- lock
+			}
+		}()
+	}
+	wg.Wait()
+	close(locks)
+
+	if len(locks) != 1 {
+		t.Fatalf("%d goroutines acquired the lock, want 1", len(locks))
+	}
+	lock := <-locks
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	again, err := AcquireLock(ctx, MatchmakerLockKey("alice"), time.Minute)
+	if err != nil || again == nil {
+		t.Fatalf("lock not free after release: %v, %v", again, err)
+	}
+}
+
+func TestReleaseLeavesAnotherHoldersLock(t *testing.T) {
+	server := newTestRedis(t)
+	ctx := context.Background()
+	key := MatchmakerLockKey("alice")
+
+	first, err := AcquireLock(ctx, key, time.Second)
+	if err != nil || first == nil {
+		t.Fatalf("AcquireLock: %v, %v", first, err)
+	}
+
+	// The first holder's lock expires and another instance takes it
+	server.FastForward(2 * time.Second)
+	second, err := AcquireLock(ctx, key, time.Minute)
+	if err != nil || second == nil {
+		t.Fatalf("lock not free after expiry: %v, %v", second, err)
+	}
+
+	if err := first.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("stale Release = %v, want ErrLockNotHeld", err)
+	}
+	if err := first.Extend(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Errorf("stale Extend = %v, want ErrLockNotHeld", err)
+	}
+	if !server.Exists(key) {
+		t.Error("stale holder released the new holder's lock")
+	}
+}
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"strings"
 
+	"github.com/connect-up/auth-service/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,14 +16,20 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			// Browser clients using cookie auth send the access token as a cookie instead
+			if token := accessTokenFromCookie(c); token != "" {
+				authenticateCookie(c, token)
+				return
+			}
+
+			c.JSON(http.StatusUnauthorized, gin.H{"error": T(c, "error.auth_header_required")})
 			c.Abort()
 			return
 		}
 
 		// Check if it's a Bearer token
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": T(c, "error.auth_header_invalid")})
 			c.Abort()
 			return
 		}
@@ -31,15 +40,156 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Validate token
 		claims, err := ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": T(c, "error.invalid_token")})
 			c.Abort()
 			return
 		}
 
 		// Set user information in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
+		setAuthClaims(c, claims)
+
+		c.Next()
+	}
+}
+
+// authenticateCookie authenticates a request by its access token cookie. A
+// state-changing request must also carry the session's CSRF token, since the
+// browser attaches the cookie to cross-site requests too.
+func authenticateCookie(c *gin.Context, token string) {
+	claims, err := ValidateToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": T(c, "error.invalid_token")})
+		c.Abort()
+		return
+	}
+
+	if !CSRFSafe(c, claims.SessionID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": T(c, "error.csrf_invalid")})
+		c.Abort()
+		return
+	}
+
+	setAuthClaims(c, claims)
+	c.Set("auth_cookie", true)
+	c.Next()
+}
+
+// setAuthClaims sets the authenticated user's information in context
+func setAuthClaims(c *gin.Context, claims *Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", claims.Role)
+	c.Set("session_id", claims.SessionID)
+}
+
+// OptionalAuthMiddleware sets user information in context when a valid token is
+// presented, but lets anonymous requests through
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			if claims, err := ValidateToken(strings.TrimPrefix(authHeader, "Bearer ")); err == nil {
+				setAuthClaims(c, claims)
+			}
+		} else if token := accessTokenFromCookie(c); authHeader == "" && token != "" {
+			// A cookie without a valid CSRF token on a state-changing request
+			// is treated as anonymous
+			if claims, err := ValidateToken(token); err == nil && CSRFSafe(c, claims.SessionID) {
+				setAuthClaims(c, claims)
+				c.Set("auth_cookie", true)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// AdminMiddleware rejects requests from users without the admin role.
+// It must run after AuthMiddleware.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": T(c, "error.admin_required")})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// OrganizerMiddleware rejects requests from users who are neither event
+// organizers nor admins. It must run after AuthMiddleware.
+func OrganizerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) && c.GetString("user_role") != models.RoleOrganizer {
+			c.JSON(http.StatusForbidden, gin.H{"error": T(c, "error.organizer_required")})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the authenticated user has the admin role
+func IsAdmin(c *gin.Context) bool {
+	return c.GetString("user_role") == models.RoleAdmin
+} 
+
+// BodySizeLimitMiddleware caps request bodies at limit bytes and responds with
+// 413 when a body is larger. overrides sets a different cap for specific
+// routes, keyed by route path as registered (e.g. "/api/v1/showcase/companies/import").
+func BodySizeLimitMiddleware(limit int64, overrides map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		max := limit
+		if override, ok := overrides[c.FullPath()]; ok {
+			max = override
+		}
+
+		if c.Request.Body == nil || c.Request.Body == http.NoBody || max <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > max {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": T(c, "error.body_too_large")})
+			return
+		}
+
+		// Without a declared length, read up to the cap so an oversized body
+		// is rejected here rather than surfacing as a decode error in the handler
+		if c.Request.ContentLength < 0 {
+			data, err := io.ReadAll(io.LimitReader(c.Request.Body, max+1))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": T(c, "error.body_unreadable")})
+				return
+			}
+			if int64(len(data)) > max {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": T(c, "error.body_too_large")})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(data))
+			c.Next()
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+		c.Next()
+	}
+}
+
+// DatabaseAvailableMiddleware fails requests fast with 503 while the database
+// circuit breaker is open, rather than letting them queue on a failing database
+func DatabaseAvailableMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !models.DatabaseAvailable() {
+			c.Header("Retry-After", "5")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": T(c, "error.service_unavailable")})
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
-} 
\ No newline at end of file
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodySizeLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodySizeLimitMiddleware(16, map[string]int64{"/import": 64}))
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/profiles", echo)
+	router.POST("/import", echo)
+
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		chunked bool
+		want    int
+	}{
+		{"under the cap", "/profiles", strings.Repeat("a", 16), false, http.StatusOK},
+		{"declared length over the cap", "/profiles", strings.Repeat("a", 17), false, http.StatusRequestEntityTooLarge},
+		{"undeclared length over the cap", "/profiles", strings.Repeat("a", 17), true, http.StatusRequestEntityTooLarge},
+		{"undeclared length under the cap", "/profiles", "small", true, http.StatusOK},
+		{"route override", "/import", strings.Repeat("a", 64), false, http.StatusOK},
+		{"over the route override", "/import", strings.Repeat("a", 65), true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("handler read %q, want %q", rec.Body, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxPageSize is the largest page any listing serves unless
+// PAGINATION_MAX_LIMIT says otherwise
+const DefaultMaxPageSize = 100
+
+var maxPageSize = DefaultMaxPageSize
+
+// SetMaxPageSize replaces the global page size cap; values below 1 are ignored
+func SetMaxPageSize(limit int) {
+	if limit >= 1 {
+		maxPageSize = limit
+	}
+}
+
+// LoadMaxPageSize reads the global page size cap from PAGINATION_MAX_LIMIT
+func LoadMaxPageSize() int {
+	return GetEnvInt("PAGINATION_MAX_LIMIT", DefaultMaxPageSize)
+}
+
+// ParsePagination reads the limit and offset query parameters. A missing,
+// malformed or non-positive limit falls back to defaultLimit, and a limit
+// above maxLimit, or above the global cap, is clamped to it. A maxLimit of 0
+// leaves only the global cap, which is what most listings want. A malformed
+// or negative offset is 0.
+func ParsePagination(c *gin.Context, defaultLimit, maxLimit int) (limit, offset int) {
+	if maxLimit <= 0 || maxLimit > maxPageSize {
+		maxLimit = maxPageSize
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset, err = strconv.Atoi(c.Query("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := maxPageSize
+	t.Cleanup(func() { maxPageSize = previous })
+	SetMaxPageSize(100)
+
+	tests := []struct {
+		name       string
+		query      string
+		maxLimit   int
+		wantLimit  int
+		wantOffset int
+	}{
+		{"defaults", "", 50, 20, 0},
+		{"within bounds", "?limit=30&offset=40", 50, 30, 40},
+		{"over the handler max", "?limit=1000000", 50, 50, 0},
+		{"over the global cap", "?limit=500", 1000, 100, 0},
+		{"global cap only", "?limit=500", 0, 100, 0},
+		{"negative offset", "?limit=10&offset=-5", 50, 10, 0},
+		{"non-positive limit", "?limit=0", 50, 20, 0},
+		{"malformed values", "?limit=ten&offset=x", 50, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/companies"+tt.query, nil)
+
+			limit, offset := ParsePagination(c, 20, tt.maxLimit)
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("ParsePagination = (%d, %d), want (%d, %d)", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestParsePaginationRaisedCap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	previous := maxPageSize
+	t.Cleanup(func() { maxPageSize = previous })
+	SetMaxPageSize(250)
+
+	tests := []struct {
+		query     string
+		wantLimit int
+	}{
+		{"?limit=200", 200},
+		{"?limit=1000", 250},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/companies"+tt.query, nil)
+
+		if limit, _ := ParsePagination(c, 20, 0); limit != tt.wantLimit {
+			t.Errorf("ParsePagination(%s) limit = %d, want %d", tt.query, limit, tt.wantLimit)
+		}
+	}
+}
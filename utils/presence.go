@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PresenceRefreshInterval is how often each instance re-marks its connected
+// users as online: often enough that a live user never outlasts the presence
+// TTL, while users of an instance that died drop out on their own
+func PresenceRefreshInterval() time.Duration {
+	return CacheTTLs().Presence / 3
+}
+
+// MarkOnline records users as online now in the shared presence set
+func MarkOnline(ctx context.Context, userIDs ...string) error {
+	if RedisClient == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	now := float64(time.Now().UnixMilli())
+	members := make([]redis.Z, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = redis.Z{Score: now, Member: userID}
+	}
+	return RedisClient.ZAdd(ctx, PresenceKey(), members...).Err()
+}
+
+// MarkOffline removes a user from the shared presence set
+func MarkOffline(ctx context.Context, userID string) error {
+	if RedisClient == nil {
+		return nil
+	}
+	return RedisClient.ZRem(ctx, PresenceKey(), userID).Err()
+}
+
+// IsOnline reports whether any instance has vouched for a user within the presence TTL
+func IsOnline(ctx context.Context, userID string) (bool, error) {
+	score, err := RedisClient.ZScore(ctx, PresenceKey(), userID).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return time.Since(time.UnixMilli(int64(score))) < CacheTTLs().Presence, nil
+}
+
+// OnlineUserIDs returns the users online on any instance. Entries that have
+// not been refreshed within the presence TTL are pruned as a side effect.
+func OnlineUserIDs(ctx context.Context) (map[string]bool, error) {
+	cutoff := strconv.FormatInt(time.Now().Add(-CacheTTLs().Presence).UnixMilli(), 10)
+
+	var ids *redis.StringSliceCmd
+	_, err := RedisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, PresenceKey(), "-inf", "("+cutoff)
+		ids = pipe.ZRangeByScore(ctx, PresenceKey(), &redis.ZRangeBy{Min: cutoff, Max: "+inf"})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	online := make(map[string]bool, len(ids.Val()))
+	for _, id := range ids.Val() {
+		online[id] = true
+	}
+	return online, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -15,17 +15,23 @@ var RedisClient *redis.Client
 // InitRedis initializes the Redis connection
 func InitRedis() error {
 	// Get Redis connection details from environment
-	redisHost := getEnv("REDIS_HOST", "localhost")
-	redisPort := getEnv("REDIS_PORT", "6379")
-	redisPassword := getEnv("REDIS_PASSWORD", "")
-	redisDB := getEnv("REDIS_DB", "0")
+	redisHost := GetEnv("REDIS_HOST", "localhost")
+	redisPort := GetEnv("REDIS_PORT", "6379")
+	redisPassword := GetEnv("REDIS_PASSWORD", "")
+	redisDB, err := strconv.Atoi(GetEnv("REDIS_DB", "0"))
+	if err != nil {
+		return fmt.Errorf("invalid REDIS_DB: %v", err)
+	}
+	SetRedisKeyPrefix(GetEnv("REDIS_KEY_PREFIX", ""))
+	SetCacheTTLs(LoadCacheTTLConfig())
 
 	// Create Redis client
 	RedisClient = redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", redisHost, redisPort),
 		Password: redisPassword,
-		DB:       0, // Use default DB
+		DB:       redisDB,
 	})
+	RedisClient.AddHook(redisTracingHook{})
 
 	// Test the connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -54,28 +60,20 @@ func DeleteToken(ctx context.Context, key string) error {
 	return RedisClient.Del(ctx, key).Err()
 }
 
-// StoreRefreshToken stores a refresh token in Redis
-func StoreRefreshToken(ctx context.Context, userID, refreshToken string, expiration time.Duration) error {
-	key := fmt.Sprintf("refresh_token:%s", userID)
+// StoreRefreshToken stores a session's refresh token in Redis
+func StoreRefreshToken(ctx context.Context, userID, sessionID, refreshToken string, expiration time.Duration) error {
+	key := RefreshTokenKey(userID, sessionID)
 	return StoreToken(ctx, key, refreshToken, expiration)
 }
 
-// GetRefreshToken retrieves a refresh token from Redis
-func GetRefreshToken(ctx context.Context, userID string) (string, error) {
-	key := fmt.Sprintf("refresh_token:%s", userID)
+// GetRefreshToken retrieves a session's refresh token from Redis
+func GetRefreshToken(ctx context.Context, userID, sessionID string) (string, error) {
+	key := RefreshTokenKey(userID, sessionID)
 	return GetToken(ctx, key)
 }
 
-// DeleteRefreshToken deletes a refresh token from Redis
-func DeleteRefreshToken(ctx context.Context, userID string) error {
-	key := fmt.Sprintf("refresh_token:%s", userID)
+// DeleteRefreshToken deletes a session's refresh token from Redis
+func DeleteRefreshToken(ctx context.Context, userID, sessionID string) error {
+	key := RefreshTokenKey(userID, sessionID)
 	return DeleteToken(ctx, key)
 }
-
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
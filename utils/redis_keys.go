@@ -0,0 +1,166 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// redisKeyPrefix namespaces every Redis key and channel so deployments can
+// share one Redis instance. Empty keeps the original bare keys.
+var redisKeyPrefix string
+
+// SetRedisKeyPrefix sets the namespace prepended to every key; a ":" separator
+// is added if the prefix doesn't end with one
+func SetRedisKeyPrefix(prefix string) {
+	if prefix != "" && !strings.HasSuffix(prefix, ":") {
+		prefix += ":"
+	}
+	redisKeyPrefix = prefix
+}
+
+// RedisKey joins key parts with ":" under the configured prefix. Every Redis
+// key and channel should be built through it, usually via a named builder below.
+func RedisKey(parts ...string) string {
+	return redisKeyPrefix + strings.Join(parts, ":")
+}
+
+// RefreshTokenKey holds a session's current refresh token
+func RefreshTokenKey(userID, sessionID string) string {
+	return RedisKey("refresh_token", userID, sessionID)
+}
+
+// UserProfileKey holds a user's matchmaking profile
+func UserProfileKey(userID string) string {
+	return RedisKey("user_profile", userID)
+}
+
+// UserProfileHashKey holds the content hash of a user's stored profile
+func UserProfileHashKey(userID string) string {
+	return RedisKey("user_profile_hash", userID)
+}
+
+// UserProfileKeyPattern matches every user profile key
+func UserProfileKeyPattern() string {
+	return RedisKey("user_profile", "*")
+}
+
+// MatchKey holds a match
+func MatchKey(matchID string) string {
+	return RedisKey("match", matchID)
+}
+
+// UserMatchesKey indexes the ids of the matches a user is part of
+func UserMatchesKey(userID string) string {
+	return RedisKey("user_matches", userID)
+}
+
+// MatchKeyPattern matches every match key
+func MatchKeyPattern() string {
+	return RedisKey("match", "*")
+}
+
+// MatchStatsKey caches aggregate matchmaking stats
+func MatchStatsKey() string {
+	return RedisKey("match_stats")
+}
+
+// MatchOutboxKey schedules pending match-created events by next attempt time
+func MatchOutboxKey() string {
+	return RedisKey("outbox", "matches_created")
+}
+
+// MatchOutboxEventsKey holds the payload of each pending match-created event
+func MatchOutboxEventsKey() string {
+	return RedisKey("outbox", "matches_created", "events")
+}
+
+// MatchOutboxAttemptsKey counts failed publish attempts per pending event
+func MatchOutboxAttemptsKey() string {
+	return RedisKey("outbox", "matches_created", "attempts")
+}
+
+// MatchmakerLockKey guards a user's match computation
+func MatchmakerLockKey(userID string) string {
+	return RedisKey("matchmaker_lock", userID)
+}
+
+// UserUpdateDedupeKey marks a user-updated event as recently processed
+func UserUpdateDedupeKey(userID, fingerprint string) string {
+	return RedisKey("matchmaker_update", userID, fingerprint)
+}
+
+// CompanyKey caches a company profile
+func CompanyKey(companyID string) string {
+	return RedisKey("company", companyID)
+}
+
+// CompanySlugKey caches the id of the company a slug belongs to
+func CompanySlugKey(slug string) string {
+	return RedisKey("company_slug", slug)
+}
+
+// CompanyDirectoryVersionKey holds the public directory cache version
+func CompanyDirectoryVersionKey() string {
+	return RedisKey("company_directory", "version")
+}
+
+// CompanyDirectoryPageKey caches one page of the public directory
+func CompanyDirectoryPageKey(version string, limit, offset int) string {
+	return RedisKey("company_directory", "v"+version, fmt.Sprint(limit), fmt.Sprint(offset))
+}
+
+// CompanyFacetsKey caches the public directory's industry and funding stage counts
+func CompanyFacetsKey(version string) string {
+	return RedisKey("company_directory", "v"+version, "facets")
+}
+
+// FXRateKey caches an exchange rate
+func FXRateKey(from, to string) string {
+	return RedisKey("fx_rate", from, to)
+}
+
+// WSReconnectKey holds the state behind a WebSocket reconnection token hash
+func WSReconnectKey(tokenHash string) string {
+	return RedisKey("ws_reconnect", tokenHash)
+}
+
+// PresenceKey scores each online user by when an instance last vouched for them
+func PresenceKey() string {
+	return RedisKey("presence", "online")
+}
+
+// WSRelayChannel is the pub/sub channel used to reach users connected to other instances
+func WSRelayChannel() string {
+	return RedisKey("ws", "relay")
+}
+
+// WSChatDeliveredKey marks a chat message as delivered to its receiver, so
+// the copy consumed from Kafka isn't delivered again
+func WSChatDeliveredKey(messageID string) string {
+	return RedisKey("ws", "chat_delivered", messageID)
+}
+
+// MatchRebuildJobKey holds the status of the latest global match rebuild
+func MatchRebuildJobKey() string {
+	return RedisKey("matchmaker_rebuild", "job")
+}
+
+// MatchRebuildLockKey is held by the instance running a global match rebuild
+func MatchRebuildLockKey() string {
+	return RedisKey("matchmaker_rebuild", "lock")
+}
+
+// MatchRebuildCancelKey holds the id of a rebuild job asked to stop
+func MatchRebuildCancelKey() string {
+	return RedisKey("matchmaker_rebuild", "cancel")
+}
+
+// PushPendingKey lists the messages waiting to go out in a user's next push notification
+func PushPendingKey(userID string) string {
+	return RedisKey("push", "pending", userID)
+}
+
+// PushWindowKey exists while a user's push notification digest window is open
+func PushWindowKey(userID string) string {
+	return RedisKey("push", "window", userID)
+}
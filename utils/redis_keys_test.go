@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestKeyBuildersHonorPrefix(t *testing.T) {
+	builders := map[string]func() string{
+		"RefreshTokenKey":            func() string { return RefreshTokenKey("u1", "s1") },
+		"UserProfileKey":             func() string { return UserProfileKey("u1") },
+		"UserProfileHashKey":         func() string { return UserProfileHashKey("u1") },
+		"UserProfileKeyPattern":      UserProfileKeyPattern,
+		"MatchKey":                   func() string { return MatchKey("m1") },
+		"UserMatchesKey":             func() string { return UserMatchesKey("u1") },
+		"MatchKeyPattern":            MatchKeyPattern,
+		"MatchStatsKey":              MatchStatsKey,
+		"MatchOutboxKey":             MatchOutboxKey,
+		"MatchOutboxEventsKey":       MatchOutboxEventsKey,
+		"MatchOutboxAttemptsKey":     MatchOutboxAttemptsKey,
+		"MatchmakerLockKey":          func() string { return MatchmakerLockKey("u1") },
+		"UserUpdateDedupeKey":        func() string { return UserUpdateDedupeKey("u1", "f1") },
+		"CompanyKey":                 func() string { return CompanyKey("c1") },
+		"CompanySlugKey":             func() string { return CompanySlugKey("acme") },
+		"CompanyDirectoryVersionKey": CompanyDirectoryVersionKey,
+		"CompanyDirectoryPageKey":    func() string { return CompanyDirectoryPageKey("3", 20, 0) },
+		"CompanyFacetsKey":           func() string { return CompanyFacetsKey("3") },
+		"FXRateKey":                  func() string { return FXRateKey("USD", "EUR") },
+		"WSReconnectKey":             func() string { return WSReconnectKey("hash") },
+		"PresenceKey":                PresenceKey,
+		"WSRelayChannel":             WSRelayChannel,
+		"WSChatDeliveredKey":         func() string { return WSChatDeliveredKey("msg1") },
+		"MatchRebuildJobKey":         MatchRebuildJobKey,
+		"MatchRebuildLockKey":        MatchRebuildLockKey,
+		"MatchRebuildCancelKey":      MatchRebuildCancelKey,
+		"PushPendingKey":             func() string { return PushPendingKey("u1") },
+		"PushWindowKey":              func() string { return PushWindowKey("u1") },
+	}
+
+	// Every builder in redis_keys.go must be covered, so a new one can't skip the prefix unnoticed
+	file, err := parser.ParseFile(token.NewFileSet(), "redis_keys.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse redis_keys.go: %v", err)
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() || fn.Name.Name == "RedisKey" || fn.Name.Name == "SetRedisKeyPrefix" {
+			continue
+		}
+		if _, ok := builders[fn.Name.Name]; !ok {
+			t.Errorf("key builder %s is not covered by this test", fn.Name.Name)
+		}
+	}
+
+	t.Cleanup(func() { SetRedisKeyPrefix("") })
+	for name, build := range builders {
+		SetRedisKeyPrefix("")
+		bare := build()
+
+		SetRedisKeyPrefix("staging")
+		if got, want := build(), "staging:"+bare; got != want {
+			t.Errorf("%s with prefix = %q, want %q", name, got, want)
+		}
+		if strings.HasPrefix(bare, ":") {
+			t.Errorf("%s without prefix = %q, want no leading separator", name, bare)
+		}
+	}
+}
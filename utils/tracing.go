@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/connect-up/auth-service/internal/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request, continuing any
+// trace the caller propagated, and puts it on the request context so
+// downstream Redis, Kafka, and matchmaker spans nest under it
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// redisTracingHook wraps every Redis command and pipeline in a client span
+type redisTracingHook struct{}
+
+func (redisTracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (redisTracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := tracing.Tracer().Start(ctx, "redis."+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.String("db.operation.name", cmd.Name()),
+			))
+
+		err := next(ctx, cmd)
+		tracing.End(span, redisSpanError(err))
+		return err
+	}
+}
+
+func (redisTracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := tracing.Tracer().Start(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "redis"),
+				attribute.String("db.operation.name", fmt.Sprintf("pipeline(%d)", len(cmds))),
+			))
+
+		err := next(ctx, cmds)
+		tracing.End(span, redisSpanError(err))
+		return err
+	}
+}
+
+// redisSpanError drops redis.Nil, which reports a missing key rather than a failure
+func redisSpanError(err error) error {
+	if err == redis.Nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useSpanRecorder installs a tracer provider recording finished spans in
+// memory for the duration of the test
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(t.Context())
+	})
+	return recorder
+}
+
+func TestTracingMiddlewareRecordsRequestSpans(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	newTestRedis(t)
+	RedisClient.AddHook(redisTracingHook{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TracingMiddleware())
+	router.GET("/companies/:id", func(c *gin.Context) {
+		RedisClient.Get(c.Request.Context(), "company:"+c.Param("id"))
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/companies/42", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	server, ok := spans["GET /companies/:id"]
+	if !ok {
+		t.Fatalf("no server span among %v", spanNames(recorder.Ended()))
+	}
+	if server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("server span kind = %v", server.SpanKind())
+	}
+	if !hasAttribute(server, attribute.Int("http.response.status_code", http.StatusOK)) {
+		t.Errorf("server span attributes %v lack the response status", server.Attributes())
+	}
+
+	// The Redis call nests under the request's span
+	get, ok := spans["redis.get"]
+	if !ok {
+		t.Fatalf("no Redis span among %v", spanNames(recorder.Ended()))
+	}
+	if get.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Error("Redis span is not a child of the request span")
+	}
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	return names
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, want attribute.KeyValue) bool {
+	for _, attr := range span.Attributes() {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}